@@ -99,6 +99,8 @@ func TestOverrideViaCLIFlags(t *testing.T) {
 		"--kong-admin-concurrency", "1",
 		"--kong-workspace", "yolo",
 		"--kong-admin-filter-tag", "foo-tag",
+		"--kong-admin-service", "kong/kong-admin",
+		"--kong-admin-service-port-name", "admin",
 		"--admin-header", "foo:bar",
 		"--kong-admin-token", "my-token",
 		"--admin-tls-skip-verify",
@@ -116,11 +118,14 @@ func TestOverrideViaCLIFlags(t *testing.T) {
 
 		"--sync-period", "10s",
 		"--sync-rate-limit", "0.9",
+		"--dry-run",
+		"--last-known-good-config-path", "/kong-config/last-known-good.json",
 
 		"--apiserver-host", "kube-apiserver.internal",
 		"--kubeconfig", "/path/to/kubeconfig",
 
 		"--profiling=false",
+		"--debug-config",
 		"--version",
 		"--anonymous-reports=false",
 	}
@@ -140,6 +145,9 @@ func TestOverrideViaCLIFlags(t *testing.T) {
 		KongAdminTLSServerName: "kong-admin.example.com",
 		KongAdminCACertPath:    "/path/to/ca-cert",
 
+		KongAdminService:         "kong/kong-admin",
+		KongAdminServicePortName: "admin",
+
 		WatchNamespace: "foons",
 		IngressClass:   "kong-internal",
 		ElectionID:     "new-election-id",
@@ -151,13 +159,17 @@ func TestOverrideViaCLIFlags(t *testing.T) {
 
 		SyncPeriod:    10 * time.Second,
 		SyncRateLimit: 0.9,
+		DryRun:        true,
+
+		LastKnownGoodConfigPath: "/kong-config/last-known-good.json",
 
 		APIServerHost:      "kube-apiserver.internal",
 		KubeConfigFilePath: "/path/to/kubeconfig",
 
-		EnableProfiling:  false,
-		ShowVersion:      true,
-		AnonymousReports: false,
+		EnableProfiling:   false,
+		EnableDebugConfig: true,
+		ShowVersion:       true,
+		AnonymousReports:  false,
 	}
 	assert.Equal(expectedConf, conf)
 	assert.Nil(err, "unexpected error parsing default flags")
@@ -176,6 +188,7 @@ func TestOverrideViaEnvVars(t *testing.T) {
 		"CONTROLLER_ADMISSION_WEBHOOK_CERT_FILE": "/new-cert-path",
 		"CONTROLLER_ADMISSION_WEBHOOK_KEY_FILE":  "/new-key-path",
 		"CONTROLLER_ANONYMOUS_REPORTS":           "false",
+		"CONTROLLER_DEBUG_CONFIG":                "true",
 		"CONTROLLER_KONG_ADMIN_CONCURRENCY":      "100",
 		"CONTROLLER_KONG_ADMIN_TOKEN":            "my-secret-token",
 	}
@@ -217,8 +230,9 @@ func TestOverrideViaEnvVars(t *testing.T) {
 
 		EnableProfiling: true,
 
-		ShowVersion:      false,
-		AnonymousReports: false,
+		EnableDebugConfig: true,
+		ShowVersion:       false,
+		AnonymousReports:  false,
 	}
 	assert.Equal(expectedConf, conf)
 	assert.Nil(err, "unexpected error parsing default flags")
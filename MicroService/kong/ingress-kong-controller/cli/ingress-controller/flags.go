@@ -52,6 +52,9 @@ type cliConfig struct {
 	KongAdminTLSServerName string
 	KongAdminCACertPath    string
 
+	KongAdminService         string
+	KongAdminServicePortName string
+
 	// Resource filtering
 	WatchNamespace string
 	IngressClass   string
@@ -66,6 +69,9 @@ type cliConfig struct {
 	// Rutnime behavior
 	SyncPeriod    time.Duration
 	SyncRateLimit float32
+	DryRun        bool
+
+	LastKnownGoodConfigPath string
 
 	// k8s connection details
 	APIServerHost      string
@@ -75,8 +81,9 @@ type cliConfig struct {
 	EnableProfiling bool
 
 	// Misc
-	ShowVersion      bool
-	AnonymousReports bool
+	EnableDebugConfig bool
+	ShowVersion       bool
+	AnonymousReports  bool
 }
 
 func flagSet() *pflag.FlagSet {
@@ -114,6 +121,14 @@ format of protocol://address:port`)
 		`The tag used to manage and filter entities in Kong
 This flag can be specified multiple times to specify multiple tags.`)
 
+	flags.String("kong-admin-service", "",
+		`Service with the Admin APIs of Kong instances running in DB-less
+mode as endpoints (in the form of namespace/name). The configuration is
+pushed to every ready endpoint, using the scheme of --kong-admin-url.`)
+	flags.String("kong-admin-service-port-name", "",
+		`Name of the Admin API port of --kong-admin-service.
+Can be omitted if the Service has a single port.`)
+
 	// deprecated
 	flags.StringSlice("admin-header", nil,
 		`DEPRECATED, use --kong-admin-header
@@ -182,6 +197,13 @@ IP/hostname when the controller is being stopped.`)
 		`Relist and confirm cloud resources this often.`)
 	flags.Float32("sync-rate-limit", 0.3,
 		`Define the sync frequency upper limit`)
+	flags.Bool("dry-run", false,
+		`Generate the configuration and log the changes it would make
+to Kong without applying them.`)
+	flags.String("last-known-good-config-path", "",
+		`Path of a file where the last configuration accepted by Kong is
+stored in DB-less mode. If the first sync after a restart is rejected by
+Kong, the stored configuration is applied instead.`)
 
 	// k8s connection details
 	flags.String("apiserver-host", "",
@@ -194,6 +216,9 @@ Kubernetes cluster and local discovery is attempted.`)
 
 	// Misc
 	flags.Bool("profiling", true, `Enable profiling via web interface host:port/debug/pprof/`)
+	flags.Bool("debug-config", false,
+		`Expose the generated configuration, including credentials, via web
+interface host:port/debug/config and host:port/debug/config/diff`)
 	flags.Bool("version", false,
 		`Shows release information about the Kong Ingress controller`)
 	flags.Bool("anonymous-reports", true,
@@ -248,6 +273,8 @@ func parseFlags() (cliConfig, error) {
 	config.KongWorkspace = viper.GetString("kong-workspace")
 	config.KongAdminConcurrency = viper.GetInt("kong-admin-concurrency")
 	config.KongAdminFilterTags = viper.GetStringSlice("kong-admin-filter-tag")
+	config.KongAdminService = viper.GetString("kong-admin-service")
+	config.KongAdminServicePortName = viper.GetString("kong-admin-service-port-name")
 
 	config.KongAdminHeaders = viper.GetStringSlice("admin-header")
 	kongAdminHeaders := viper.GetStringSlice("kong-admin-header")
@@ -293,6 +320,8 @@ func parseFlags() (cliConfig, error) {
 	// Rutnime behavior
 	config.SyncPeriod = viper.GetDuration("sync-period")
 	config.SyncRateLimit = (float32)(viper.GetFloat64("sync-rate-limit"))
+	config.DryRun = viper.GetBool("dry-run")
+	config.LastKnownGoodConfigPath = viper.GetString("last-known-good-config-path")
 
 	// k8s connection details
 	config.APIServerHost = viper.GetString("apiserver-host")
@@ -300,6 +329,7 @@ func parseFlags() (cliConfig, error) {
 
 	// Misc
 	config.EnableProfiling = viper.GetBool("profiling")
+	config.EnableDebugConfig = viper.GetBool("debug-config")
 	config.ShowVersion = viper.GetBool("version")
 	config.AnonymousReports = viper.GetBool("anonymous-reports")
 	return config, nil
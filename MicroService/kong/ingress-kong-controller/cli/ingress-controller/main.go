@@ -78,6 +78,13 @@ func controllerConfigFromCLIConfig(cliConfig cliConfig) controller.Configuration
 		UpdateStatus:           cliConfig.UpdateStatus,
 		UpdateStatusOnShutdown: cliConfig.UpdateStatusOnShutdown,
 		ElectionID:             cliConfig.ElectionID,
+
+		DryRun: cliConfig.DryRun,
+
+		LastKnownGoodConfigPath: cliConfig.LastKnownGoodConfigPath,
+
+		KongAdminService:         cliConfig.KongAdminService,
+		KongAdminServicePortName: cliConfig.KongAdminServicePortName,
 	}
 }
 
@@ -128,6 +135,18 @@ func main() {
 		}
 	}
 
+	if cliConfig.KongAdminService != "" {
+		ns, _, err := utils.ParseNameNS(cliConfig.KongAdminService)
+		if err != nil {
+			glog.Fatal(err)
+		}
+		// endpoints are read from the cache of the watched namespace
+		if cliConfig.WatchNamespace != "" && ns != cliConfig.WatchNamespace {
+			glog.Fatalf("kong-admin-service must be in the watched "+
+				"namespace '%v'", cliConfig.WatchNamespace)
+		}
+	}
+
 	if cliConfig.WatchNamespace != "" {
 		_, err = kubeClient.CoreV1().Namespaces().Get(cliConfig.WatchNamespace,
 			metav1.GetOptions{})
@@ -197,6 +216,9 @@ func main() {
 
 	if kongDB == "off" {
 		controllerConfig.Kong.InMemory = true
+	} else if cliConfig.KongAdminService != "" {
+		glog.Fatal("kong-admin-service can only be used with Kong " +
+			"running in DB-less mode")
 	}
 	req, _ := http.NewRequest("GET",
 		cliConfig.KongAdminURL+"/tags", nil)
@@ -218,6 +240,7 @@ func main() {
 		}
 	}
 	controllerConfig.Kong.Client = kongClient
+	controllerConfig.Kong.HTTPClient = c
 
 	err = discovery.ServerSupportsVersion(kubeClient.Discovery(), schema.GroupVersion{
 		Group:   "networking.k8s.io",
@@ -258,6 +281,11 @@ func main() {
 	cacheStores.Ingress = ingInformer.GetStore()
 	informers = append(informers, ingInformer)
 
+	tcpIngressInformer := kongInformerFactory.Configuration().V1().TCPIngresses().Informer()
+	tcpIngressInformer.AddEventHandler(reh)
+	cacheStores.TCPIngress = tcpIngressInformer.GetStore()
+	informers = append(informers, tcpIngressInformer)
+
 	endpointsInformer := coreInformerFactory.Core().V1().Endpoints().Informer()
 	endpointsInformer.AddEventHandler(controller.EndpointsEventHandler{
 		UpdateCh: updateChannel,
@@ -285,6 +313,11 @@ func main() {
 	cacheStores.Plugin = kongPluginInformer.GetStore()
 	informers = append(informers, kongPluginInformer)
 
+	kongClusterPluginInformer := kongInformerFactory.Configuration().V1().KongClusterPlugins().Informer()
+	kongClusterPluginInformer.AddEventHandler(reh)
+	cacheStores.ClusterPlugin = kongClusterPluginInformer.GetStore()
+	informers = append(informers, kongClusterPluginInformer)
+
 	kongConsumerInformer := kongInformerFactory.Configuration().V1().KongConsumers().Informer()
 	kongConsumerInformer.AddEventHandler(reh)
 	cacheStores.Consumer = kongConsumerInformer.GetStore()
@@ -319,7 +352,7 @@ func main() {
 	})
 
 	mux := http.NewServeMux()
-	go registerHandlers(cliConfig.EnableProfiling, 10254, kong, mux)
+	go registerHandlers(cliConfig.EnableProfiling, cliConfig.EnableDebugConfig, 10254, kong, mux)
 
 	if cliConfig.AnonymousReports {
 		hostname, err := os.Hostname()
@@ -348,7 +381,11 @@ func main() {
 	if cliConfig.AdmissionWebhookListen != "off" {
 		admissionServer := admission.Server{
 			Validator: admission.KongHTTPValidator{
-				Client: kongClient,
+				Client:        kongClient,
+				SecretGetter:  store,
+				IngressLister: store,
+				IsValidIngressClass: annotations.IngressClassValidatorFuncFromObjectMeta(
+					controllerConfig.IngressClass),
 			},
 		}
 		go func() {
@@ -477,7 +514,7 @@ func handleFatalInitError(err error) {
 		"https://github.com/kubernetes/ingress-nginx/blob/master/docs/troubleshooting.md", err)
 }
 
-func registerHandlers(enableProfiling bool, port int, ic *controller.KongController, mux *http.ServeMux) {
+func registerHandlers(enableProfiling, enableDebugConfig bool, port int, ic *controller.KongController, mux *http.ServeMux) {
 
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -498,6 +535,11 @@ func registerHandlers(enableProfiling bool, port int, ic *controller.KongControl
 		}
 	})
 
+	if enableDebugConfig {
+		mux.HandleFunc("/debug/config", ic.ConfigHandler)
+		mux.HandleFunc("/debug/config/diff", ic.ConfigDiffHandler)
+	}
+
 	if enableProfiling {
 		mux.HandleFunc("/debug/pprof/", pprof.Index)
 		mux.HandleFunc("/debug/pprof/heap", pprof.Index)
@@ -0,0 +1,192 @@
+// Command render-config builds the declarative configuration the Ingress
+// Controller would generate for a set of Kubernetes manifests, without
+// access to a cluster. If a Kong Admin API is given, the changes
+// applying the configuration would make to Kong are shown instead.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+	"github.com/hbagdi/go-kong/kong"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/controller"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/controller/parser"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/store"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+type config struct {
+	Manifests    string
+	KongAdminURL string
+	KongVersion  string
+	FilterTags   []string
+	Diff         bool
+}
+
+func flagSet(conf *config) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("render-config", pflag.ExitOnError)
+	flags.StringVar(&conf.Manifests, "manifests", "",
+		`Path to a manifest or a directory of manifests (.yaml, .yml or .json)
+to build the configuration from.`)
+	flags.StringVar(&conf.KongAdminURL, "kong-admin-url", "",
+		`The address of Kong's Admin API. If set, plugin defaults are read
+from Kong and --diff can be used.`)
+	flags.StringVar(&conf.KongVersion, "kong-version", "1.4.0",
+		`The version of Kong to render the configuration for, ignored if
+--kong-admin-url is set.`)
+	flags.StringSliceVar(&conf.FilterTags, "kong-admin-filter-tag",
+		[]string{"managed-by-ingress-controller"},
+		`The tag used to manage and filter entities in Kong.`)
+	flags.BoolVar(&conf.Diff, "diff", false,
+		`Show the changes the configuration would make to Kong instead of
+the configuration itself. Requires --kong-admin-url.`)
+	return flags
+}
+
+func main() {
+	var conf config
+	flags := flagSet(&conf)
+	flags.AddGoFlagSet(flag.CommandLine)
+	flags.Parse(os.Args[1:])
+	flag.Set("logtostderr", "true")
+	flag.CommandLine.Parse([]string{})
+
+	out, err := run(conf)
+	if err != nil {
+		glog.Fatal(err)
+	}
+	fmt.Println(string(out))
+}
+
+func run(conf config) ([]byte, error) {
+	if conf.Manifests == "" {
+		return nil, errors.New("--manifests must be specified")
+	}
+	if conf.Diff && conf.KongAdminURL == "" {
+		return nil, errors.New("--diff requires --kong-admin-url")
+	}
+
+	objects, err := loadManifests(conf.Manifests)
+	if err != nil {
+		return nil, err
+	}
+	s, err := store.NewFakeStore(objects)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating store")
+	}
+	p := parser.New(s)
+	state, err := p.Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "building Kong state")
+	}
+
+	kongConf, err := kongConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	renderer := controller.NewRenderer(kongConf)
+	content, err := renderer.Render(state)
+	if err != nil {
+		return nil, errors.Wrap(err, "rendering configuration")
+	}
+	if !conf.Diff {
+		return json.MarshalIndent(content, "", "  ")
+	}
+	diffs, err := renderer.Diff(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "computing diff")
+	}
+	return json.MarshalIndent(diffs, "", "  ")
+}
+
+// kongConfig describes the Kong node to render the configuration for.
+func kongConfig(conf config) (controller.Kong, error) {
+	kongConf := controller.Kong{
+		URL:           conf.KongAdminURL,
+		FilterTags:    conf.FilterTags,
+		HasTagSupport: true,
+		Concurrency:   10,
+	}
+	if conf.KongAdminURL == "" {
+		v, err := semver.ParseTolerant(conf.KongVersion)
+		if err != nil {
+			return kongConf, errors.Wrap(err, "parsing --kong-version")
+		}
+		kongConf.Version = v
+		return kongConf, nil
+	}
+
+	client, err := kong.NewClient(kong.String(conf.KongAdminURL), nil)
+	if err != nil {
+		return kongConf, errors.Wrap(err, "creating Kong client")
+	}
+	root, err := client.Root(nil)
+	if err != nil {
+		return kongConf, errors.Wrap(err, "fetching Kong information")
+	}
+	version, _ := root["version"].(string)
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return kongConf, errors.Wrapf(err, "parsing Kong version '%v'",
+			version)
+	}
+	kongConf.Client = client
+	kongConf.Version = v
+	kongConf.Enterprise = strings.Contains(version, "enterprise")
+	if configuration, ok := root["configuration"].(map[string]interface{}); ok {
+		kongConf.InMemory = configuration["database"] == "off"
+	}
+	return kongConf, nil
+}
+
+// loadManifests reads the objects in path, which is either a manifest
+// or a directory containing manifests.
+func loadManifests(path string) (store.FakeObjects, error) {
+	var objects store.FakeObjects
+	info, err := os.Stat(path)
+	if err != nil {
+		return objects, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return objects, err
+		}
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+		sort.Strings(files)
+	}
+
+	// documents are separated so that files without a trailing
+	// newline or document separator don't merge into the next one
+	var manifests bytes.Buffer
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return objects, err
+		}
+		manifests.WriteString("\n---\n")
+		manifests.Write(b)
+	}
+	objects, err = store.FakeObjectsFromManifests(&manifests)
+	if err != nil {
+		return objects, errors.Wrapf(err, "loading manifests from '%v'", path)
+	}
+	return objects, nil
+}
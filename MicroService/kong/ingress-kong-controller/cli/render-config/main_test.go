@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hbagdi/deck/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "render-config")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "ingress.yaml"), []byte(`
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: foo
+  annotations:
+    plugins.konghq.com: correlation-id
+spec:
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - path: /
+        backend:
+          serviceName: foo-svc
+          servicePort: 80`), 0644))
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "resources.yml"), []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo-svc
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: configuration.konghq.com/v1
+kind: KongClusterPlugin
+metadata:
+  name: correlation-id
+plugin: correlation-id
+`), 0644))
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "README.md"),
+		[]byte("not a manifest"), 0644))
+
+	t.Run("configuration is rendered offline", func(t *testing.T) {
+		out, err := run(config{Manifests: dir, KongVersion: "1.4.0"})
+		assert.Nil(err)
+		var content file.Content
+		assert.Nil(json.Unmarshal(out, &content))
+		assert.Len(content.Services, 1)
+		assert.Equal("default.foo-svc.80", *content.Services[0].Name)
+		assert.Len(content.Services[0].Routes, 1)
+		assert.Len(content.Plugins, 1)
+		assert.Equal("correlation-id", *content.Plugins[0].Name)
+		assert.Equal(*content.Services[0].Routes[0].Name,
+			*content.Plugins[0].Route.ID)
+	})
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := run(config{})
+		assert.NotNil(err)
+		_, err = run(config{Manifests: dir, Diff: true})
+		assert.NotNil(err)
+		_, err = run(config{Manifests: filepath.Join(dir, "missing")})
+		assert.NotNil(err)
+	})
+}
@@ -30,6 +30,18 @@ webhooks:
     resources:
     - kongconsumers
     - kongplugins
+    - kongclusterplugins
+    - kongingresses
+  - apiGroups:
+    - networking.k8s.io
+    - extensions
+    apiVersions:
+    - 'v1beta1'
+    operations:
+    - CREATE
+    - UPDATE
+    resources:
+    - ingresses
   - apiGroups:
     - ''
     apiVersions:
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	admission "k8s.io/api/admission/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1beta1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -79,6 +80,22 @@ var (
 		Group:    configuration.SchemeGroupVersion.Group,
 		Version:  configuration.SchemeGroupVersion.Version,
 		Resource: "kongplugins"}
+	clusterPluginGVResource = meta.GroupVersionResource{
+		Group:    configuration.SchemeGroupVersion.Group,
+		Version:  configuration.SchemeGroupVersion.Version,
+		Resource: "kongclusterplugins"}
+	kongIngressGVResource = meta.GroupVersionResource{
+		Group:    configuration.SchemeGroupVersion.Group,
+		Version:  configuration.SchemeGroupVersion.Version,
+		Resource: "kongingresses"}
+	ingressGVResource = meta.GroupVersionResource{
+		Group:    networking.SchemeGroupVersion.Group,
+		Version:  networking.SchemeGroupVersion.Version,
+		Resource: "ingresses"}
+	extensionsIngressGVResource = meta.GroupVersionResource{
+		Group:    "extensions",
+		Version:  "v1beta1",
+		Resource: "ingresses"}
 	secretGVResource = meta.GroupVersionResource{
 		Group:    corev1.SchemeGroupVersion.Group,
 		Version:  corev1.SchemeGroupVersion.Version,
@@ -142,6 +159,46 @@ func (a Server) handleValidation(request admission.AdmissionRequest) (
 		if err != nil {
 			return nil, err
 		}
+	case clusterPluginGVResource:
+		plugin := configuration.KongClusterPlugin{}
+		deserializer := codecs.UniversalDeserializer()
+		_, _, err = deserializer.Decode(request.Object.Raw,
+			nil, &plugin)
+		if err != nil {
+			return nil, err
+		}
+
+		ok, message, err = a.Validator.ValidateClusterPlugin(plugin)
+		if err != nil {
+			return nil, err
+		}
+	case kongIngressGVResource:
+		kongIngress := configuration.KongIngress{}
+		deserializer := codecs.UniversalDeserializer()
+		_, _, err = deserializer.Decode(request.Object.Raw,
+			nil, &kongIngress)
+		if err != nil {
+			return nil, err
+		}
+
+		ok, message, err = a.Validator.ValidateKongIngress(kongIngress)
+		if err != nil {
+			return nil, err
+		}
+	case ingressGVResource, extensionsIngressGVResource:
+		// both APIs share the same representation of Ingresses
+		ingress := networking.Ingress{}
+		deserializer := codecs.UniversalDeserializer()
+		_, _, err = deserializer.Decode(request.Object.Raw,
+			nil, &ingress)
+		if err != nil {
+			return nil, err
+		}
+
+		ok, message, err = a.Validator.ValidateIngress(ingress)
+		if err != nil {
+			return nil, err
+		}
 	case secretGVResource:
 		secret := corev1.Secret{}
 		deserializer := codecs.UniversalDeserializer()
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	admission "k8s.io/api/admission/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1beta1"
 )
 
 var decoder = codecs.UniversalDeserializer()
@@ -31,11 +32,26 @@ func (v KongFakeValidator) ValidatePlugin(
 	return v.Result, v.Message, v.Error
 }
 
+func (v KongFakeValidator) ValidateClusterPlugin(
+	k8sPlugin configuration.KongClusterPlugin) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
 func (v KongFakeValidator) ValidateCredential(
 	secret corev1.Secret) (bool, string, error) {
 	return v.Result, v.Message, v.Error
 }
 
+func (v KongFakeValidator) ValidateKongIngress(
+	kongIngress configuration.KongIngress) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
+func (v KongFakeValidator) ValidateIngress(
+	ingress networking.Ingress) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
 func TestServeHTTPBasic(t *testing.T) {
 	assert := assert.New(t)
 	res := httptest.NewRecorder()
@@ -380,3 +396,133 @@ func TestValidateKongPlugin(t *testing.T) {
 		string(review.Response.UID))
 	assert.True(review.Response.Allowed)
 }
+
+func TestValidateKongClusterPlugin(t *testing.T) {
+	assert := assert.New(t)
+	res := httptest.NewRecorder()
+	server := Server{
+		Validator: KongFakeValidator{
+			Result:  false,
+			Message: "plugin cannot use both config and configFrom",
+		},
+	}
+	handler := http.HandlerFunc(server.ServeHTTP)
+	body := `
+{
+  "kind": "AdmissionReview",
+  "apiVersion": "admission.k8s.io/v1beta1",
+  "request": {
+    "uid": "b2df61dd-ab5b-4cb4-9be0-878533c83892",
+    "resource": {
+      "group": "configuration.konghq.com",
+      "version": "v1",
+      "resource": "kongclusterplugins"
+    },
+    "object": {
+      "apiVersion": "configuration.konghq.com/v1",
+      "kind": "KongClusterPlugin"
+    }
+  }
+}
+	`
+	req, err := http.NewRequest("POST", "", bytes.NewBuffer([]byte(body)))
+	assert.Nil(err)
+	handler.ServeHTTP(res, req)
+	assert.Equal(200, res.Code)
+	var review admission.AdmissionReview
+	_, _, err = decoder.Decode([]byte(res.Body.String()), nil, &review)
+	assert.Nil(err)
+	assert.Equal("b2df61dd-ab5b-4cb4-9be0-878533c83892",
+		string(review.Response.UID))
+	assert.False(review.Response.Allowed)
+	assert.Equal("plugin cannot use both config and configFrom",
+		review.Response.Result.Message)
+}
+
+func TestValidateKongIngress(t *testing.T) {
+	assert := assert.New(t)
+	res := httptest.NewRecorder()
+	server := Server{
+		Validator: KongFakeValidator{
+			Result:  false,
+			Message: "canary.weight must be between 0 and 100",
+		},
+	}
+	handler := http.HandlerFunc(server.ServeHTTP)
+	body := `
+{
+  "kind": "AdmissionReview",
+  "apiVersion": "admission.k8s.io/v1beta1",
+  "request": {
+    "uid": "b2df61dd-ab5b-4cb4-9be0-878533c83892",
+    "resource": {
+      "group": "configuration.konghq.com",
+      "version": "v1",
+      "resource": "kongingresses"
+    },
+    "object": {
+      "apiVersion": "configuration.konghq.com/v1",
+      "kind": "KongIngress"
+    },
+    "operation": "CREATE"
+  }
+}
+	`
+	req, err := http.NewRequest("POST", "", bytes.NewBuffer([]byte(body)))
+	assert.Nil(err)
+	handler.ServeHTTP(res, req)
+	assert.Equal(200, res.Code)
+	var review admission.AdmissionReview
+	_, _, err = decoder.Decode([]byte(res.Body.String()), nil, &review)
+	assert.Nil(err)
+	assert.False(review.Response.Allowed)
+	assert.Equal("canary.weight must be between 0 and 100",
+		review.Response.Result.Message)
+}
+
+func TestValidateIngress(t *testing.T) {
+	assert := assert.New(t)
+	for _, group := range []string{"networking.k8s.io", "extensions"} {
+		t.Run(group, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			server := Server{
+				Validator: KongFakeValidator{
+					Result: true,
+				},
+			}
+			handler := http.HandlerFunc(server.ServeHTTP)
+			body := `
+{
+  "kind": "AdmissionReview",
+  "apiVersion": "admission.k8s.io/v1beta1",
+  "request": {
+    "uid": "b2df61dd-ab5b-4cb4-9be0-878533c83892",
+    "resource": {
+      "group": "` + group + `",
+      "version": "v1beta1",
+      "resource": "ingresses"
+    },
+    "object": {
+      "apiVersion": "` + group + `/v1beta1",
+      "kind": "Ingress",
+      "spec": {
+        "rules": [{"host": "example.com"}]
+      }
+    },
+    "operation": "UPDATE"
+  }
+}
+	`
+			req, err := http.NewRequest("POST", "",
+				bytes.NewBuffer([]byte(body)))
+			assert.Nil(err)
+			handler.ServeHTTP(res, req)
+			assert.Equal(200, res.Code)
+			var review admission.AdmissionReview
+			_, _, err = decoder.Decode([]byte(res.Body.String()), nil,
+				&review)
+			assert.Nil(err)
+			assert.True(review.Response.Allowed)
+		})
+	}
+}
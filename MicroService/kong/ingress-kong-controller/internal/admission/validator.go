@@ -1,26 +1,51 @@
 package admission
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/golang/glog"
 	"github.com/hbagdi/go-kong/kong"
 	configuration "github.com/kong/kubernetes-ingress-controller/internal/apis/configuration/v1"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/annotations"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/controller/parser"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // KongValidator validates Kong entities.
 type KongValidator interface {
 	ValidateConsumer(consumer configuration.KongConsumer) (bool, string, error)
 	ValidatePlugin(consumer configuration.KongPlugin) (bool, string, error)
+	ValidateClusterPlugin(consumer configuration.KongClusterPlugin) (bool, string, error)
 	ValidateCredential(secret corev1.Secret) (bool, string, error)
+	ValidateKongIngress(kongIngress configuration.KongIngress) (bool, string, error)
+	ValidateIngress(ingress networking.Ingress) (bool, string, error)
+}
+
+// SecretGetter fetches Secrets referenced by the entities being validated.
+type SecretGetter interface {
+	GetSecret(namespace, name string) (*corev1.Secret, error)
+}
+
+// IngressLister lists the Ingresses satisfied by the controller, against
+// which Ingresses being validated are checked for conflicts.
+type IngressLister interface {
+	ListIngresses() []*networking.Ingress
 }
 
 // KongHTTPValidator implements KongValidator interface to validate Kong
 // entities using the Admin API of Kong.
 type KongHTTPValidator struct {
-	Client *kong.Client
+	Client        *kong.Client
+	SecretGetter  SecretGetter
+	IngressLister IngressLister
+	// IsValidIngressClass reports if an Ingress is satisfied by the
+	// controller. Ingresses of other classes are not validated.
+	// All Ingresses are validated if it is nil.
+	IsValidIngressClass func(obj *metav1.ObjectMeta) bool
 }
 
 // ValidateConsumer checks if consumer has a Username and a consumer with
@@ -63,14 +88,98 @@ func (validator KongHTTPValidator) ValidatePlugin(
 	if k8sPlugin.Config != nil {
 		plugin.Config = kong.Configuration(k8sPlugin.Config)
 	}
+	ref := k8sPlugin.ConfigFrom.SecretValue
+	if ref != (configuration.SecretValueFromSource{}) {
+		if k8sPlugin.Config != nil {
+			return false, "plugin cannot use both config and configFrom", nil
+		}
+		if ref.Secret == "" || ref.Key == "" {
+			return false, "configFrom.secretKeyRef requires name and key", nil
+		}
+		config, message := validator.configFromSecret(k8sPlugin.Namespace,
+			ref.Secret, ref.Key)
+		if message != "" {
+			return false, message, nil
+		}
+		plugin.Config = kong.Configuration(config)
+	}
 	if k8sPlugin.RunOn != "" {
 		plugin.RunOn = kong.String(k8sPlugin.RunOn)
 	}
 	if len(k8sPlugin.Protocols) > 0 {
 		plugin.Protocols = kong.StringSlice(k8sPlugin.Protocols...)
 	}
-	req, err := validator.Client.NewRequest("POST", "/schemas/plugins/validate",
-		nil, &plugin)
+	return validator.validatePluginAgainstKong(plugin)
+}
+
+// ValidateClusterPlugin checks if k8sPlugin is valid in the same way
+// ValidatePlugin does for KongPlugin resources.
+func (validator KongHTTPValidator) ValidateClusterPlugin(
+	k8sPlugin configuration.KongClusterPlugin) (bool, string, error) {
+	if k8sPlugin.PluginName == "" {
+		return false, "plugin name cannot be empty", nil
+	}
+	var plugin kong.Plugin
+	plugin.Name = kong.String(k8sPlugin.PluginName)
+	if k8sPlugin.Config != nil {
+		plugin.Config = kong.Configuration(k8sPlugin.Config)
+	}
+	ref := k8sPlugin.ConfigFrom.SecretValue
+	if ref != (configuration.NamespacedSecretValueFromSource{}) {
+		if k8sPlugin.Config != nil {
+			return false, "plugin cannot use both config and configFrom", nil
+		}
+		if ref.Namespace == "" || ref.Secret == "" || ref.Key == "" {
+			return false, "configFrom.secretKeyRef requires namespace, " +
+				"name and key", nil
+		}
+		config, message := validator.configFromSecret(ref.Namespace,
+			ref.Secret, ref.Key)
+		if message != "" {
+			return false, message, nil
+		}
+		plugin.Config = kong.Configuration(config)
+	}
+	if k8sPlugin.RunOn != "" {
+		plugin.RunOn = kong.String(k8sPlugin.RunOn)
+	}
+	if len(k8sPlugin.Protocols) > 0 {
+		plugin.Protocols = kong.StringSlice(k8sPlugin.Protocols...)
+	}
+	return validator.validatePluginAgainstKong(plugin)
+}
+
+// configFromSecret reads a plugin configuration from key in the Secret
+// namespace/name. If the configuration cannot be read, a message
+// describing the problem is returned.
+func (validator KongHTTPValidator) configFromSecret(namespace, name,
+	key string) (configuration.Configuration, string) {
+	if validator.SecretGetter == nil {
+		return nil, "configFrom cannot be validated: no secret store"
+	}
+	secret, err := validator.SecretGetter.GetSecret(namespace, name)
+	if err != nil {
+		return nil, "could not read configFrom secret: " + err.Error()
+	}
+	config, err := parser.SecretToConfiguration(secret, key)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return config, ""
+}
+
+func (validator KongHTTPValidator) validatePluginAgainstKong(
+	plugin kong.Plugin) (bool, string, error) {
+	return validator.validateAgainstKong("plugins", &plugin)
+}
+
+// validateAgainstKong validates entity against the schema of the Kong
+// entities of type entityType, using the schema validation endpoint of
+// the Admin API.
+func (validator KongHTTPValidator) validateAgainstKong(entityType string,
+	entity interface{}) (bool, string, error) {
+	req, err := validator.Client.NewRequest("POST",
+		"/schemas/"+entityType+"/validate", nil, entity)
 	if err != nil {
 		return false, "", err
 	}
@@ -81,12 +190,249 @@ func (validator KongHTTPValidator) ValidatePlugin(
 	if resp.StatusCode == 201 {
 		return true, "", nil
 	}
-	if err != nil {
-		return false, "", err
+	return true, "", nil
+}
+
+// ValidateKongIngress checks if the overrides of kongIngress can be
+// applied to the Services, Routes and Upstreams generated by the
+// controller. Protocols and the canary section are checked locally, the
+// proxy, route and upstream sections against the schemas of Kong.
+// If an error occurs during validation, it is returned as the last argument.
+// The first boolean communicates if kongIngress is valid or not and string
+// holds a message if the entity is not valid.
+func (validator KongHTTPValidator) ValidateKongIngress(
+	kongIngress configuration.KongIngress) (bool, string, error) {
+	if message := validateKongIngressProtocols(kongIngress); message != "" {
+		return false, message, nil
+	}
+	if message := validateCanary(kongIngress.Canary); message != "" {
+		return false, message, nil
+	}
+
+	if proxy := kongIngress.Proxy; proxy != nil {
+		service := kong.Service{
+			Host:           kong.String("kong-ingress-validation.svc"),
+			Protocol:       proxy.Protocol,
+			Path:           proxy.Path,
+			Retries:        proxy.Retries,
+			ConnectTimeout: proxy.ConnectTimeout,
+			ReadTimeout:    proxy.ReadTimeout,
+			WriteTimeout:   proxy.WriteTimeout,
+		}
+		if isGRPC(service.Protocol) {
+			// grpc(s) doesn't accept a path
+			service.Path = nil
+		}
+		ok, message, err := validator.validateAgainstKong("services",
+			&service)
+		if err != nil || !ok {
+			return ok, prefixMessage("invalid proxy: ", message), err
+		}
+	}
+	if r := kongIngress.Route; r != nil {
+		route := kong.Route{
+			Paths:                   kong.StringSlice("/"),
+			Methods:                 r.Methods,
+			Headers:                 r.Headers,
+			Protocols:               r.Protocols,
+			RegexPriority:           r.RegexPriority,
+			StripPath:               r.StripPath,
+			PreserveHost:            r.PreserveHost,
+			HTTPSRedirectStatusCode: r.HTTPSRedirectStatusCode,
+		}
+		for _, protocol := range route.Protocols {
+			if isGRPC(protocol) {
+				// grpc(s) doesn't accept strip_path
+				route.StripPath = nil
+				break
+			}
+		}
+		ok, message, err := validator.validateAgainstKong("routes", &route)
+		if err != nil || !ok {
+			return ok, prefixMessage("invalid route: ", message), err
+		}
+	}
+	if kongIngress.Upstream != nil {
+		upstream := kongIngress.Upstream.DeepCopy()
+		upstream.Name = kong.String("kong-ingress-validation.svc")
+		ok, message, err := validator.validateAgainstKong("upstreams",
+			upstream)
+		if err != nil || !ok {
+			return ok, prefixMessage("invalid upstream: ", message), err
+		}
 	}
 	return true, "", nil
 }
 
+var (
+	httpProtocols = map[string]bool{"http": true, "https": true}
+	grpcProtocols = map[string]bool{"grpc": true, "grpcs": true}
+)
+
+func isGRPC(protocol *string) bool {
+	return protocol != nil && grpcProtocols[*protocol]
+}
+
+// validateKongIngressProtocols returns a message if the protocols of
+// kongIngress are not supported by the controller.
+func validateKongIngressProtocols(kongIngress configuration.KongIngress) string {
+	if proxy := kongIngress.Proxy; proxy != nil && proxy.Protocol != nil {
+		protocol := *proxy.Protocol
+		if !httpProtocols[protocol] && !grpcProtocols[protocol] {
+			return "invalid proxy.protocol '" + protocol + "': must be " +
+				"one of http, https, grpc or grpcs"
+		}
+	}
+	if kongIngress.Route == nil {
+		return ""
+	}
+	var protocols []string
+	for _, protocol := range kongIngress.Route.Protocols {
+		if protocol != nil {
+			protocols = append(protocols, *protocol)
+		}
+	}
+	return validateRouteProtocols("route.protocols", protocols)
+}
+
+// validateRouteProtocols returns a message if protocols are not a set of
+// HTTP or gRPC protocols. field names the origin of protocols in the
+// message.
+func validateRouteProtocols(field string, protocols []string) string {
+	var http, grpc bool
+	for _, protocol := range protocols {
+		switch {
+		case httpProtocols[protocol]:
+			http = true
+		case grpcProtocols[protocol]:
+			grpc = true
+		default:
+			return "invalid " + field + " '" + protocol + "': must be " +
+				"one of http, https, grpc or grpcs"
+		}
+	}
+	if http && grpc {
+		return field + " cannot mix http(s) and grpc(s)"
+	}
+	return ""
+}
+
+// validateCanary returns a message if canary is not valid.
+func validateCanary(canary *configuration.KongIngressCanary) string {
+	if canary == nil {
+		return ""
+	}
+	if canary.Backend.ServiceName == "" {
+		return "canary.backend.serviceName cannot be empty"
+	}
+	if canary.Backend.ServicePort < 1 || canary.Backend.ServicePort > 65535 {
+		return "canary.backend.servicePort must be between 1 and 65535"
+	}
+	if canary.Weight < 0 || canary.Weight > 100 {
+		return "canary.weight must be between 0 and 100"
+	}
+	if header := canary.Header; header != nil {
+		if header.Name == "" || len(header.Values) == 0 {
+			return "canary.header requires name and values"
+		}
+	}
+	return ""
+}
+
+func prefixMessage(prefix, message string) string {
+	if message == "" {
+		return ""
+	}
+	return prefix + message
+}
+
+// ValidateIngress checks if the protocols of ingress are supported by the
+// controller and that none of its host and path pairs is already used by
+// an Ingress in another namespace, in which case Kong would route the
+// requests to one of the two Ingresses arbitrarily.
+// If an error occurs during validation, it is returned as the last argument.
+// The first boolean communicates if ingress is valid or not and string
+// holds a message if the entity is not valid.
+func (validator KongHTTPValidator) ValidateIngress(
+	ingress networking.Ingress) (bool, string, error) {
+	if validator.IsValidIngressClass != nil &&
+		!validator.IsValidIngressClass(&ingress.ObjectMeta) {
+		return true, "", nil
+	}
+	protocols := annotations.ExtractProtocolNames(ingress.Annotations)
+	if len(protocols) > 1 || protocols[0] != "" {
+		message := validateRouteProtocols("protocols annotation", protocols)
+		if message != "" {
+			return false, message, nil
+		}
+	}
+	if validator.IngressLister == nil {
+		return true, "", nil
+	}
+
+	// hostPaths holds the host and path pairs of ingress
+	hostPaths := make(map[hostPath]bool)
+	for _, hp := range ingressHostPaths(ingress) {
+		hostPaths[hp] = true
+	}
+	var conflicts []string
+	for _, other := range validator.IngressLister.ListIngresses() {
+		if other.Namespace == ingress.Namespace {
+			continue
+		}
+		for _, hp := range ingressHostPaths(*other) {
+			if hostPaths[hp] {
+				conflicts = append(conflicts, "path '"+hp.Path+"' of "+
+					hp.description()+" is already used by Ingress '"+
+					other.Namespace+"/"+other.Name+"'")
+				// report each conflict once
+				delete(hostPaths, hp)
+			}
+		}
+	}
+	if len(conflicts) != 0 {
+		sort.Strings(conflicts)
+		return false, strings.Join(conflicts, ", "), nil
+	}
+	return true, "", nil
+}
+
+// hostPath is a host and path pair routed by an Ingress.
+type hostPath struct {
+	Host string
+	Path string
+}
+
+func (hp hostPath) description() string {
+	if hp.Host == "" {
+		return "any host"
+	}
+	return "host '" + hp.Host + "'"
+}
+
+// ingressHostPaths returns the host and path pairs ingress routes, paths
+// being normalized as done by the controller. The default backend of
+// ingress matches any host on the '/' path.
+func ingressHostPaths(ingress networking.Ingress) []hostPath {
+	var res []hostPath
+	if ingress.Spec.Backend != nil {
+		res = append(res, hostPath{Path: "/"})
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			p := path.Path
+			if p == "" {
+				p = "/"
+			}
+			res = append(res, hostPath{Host: rule.Host, Path: p})
+		}
+	}
+	return res
+}
+
 var (
 	keyAuthFields   = []string{"key"}
 	basicAuthFields = []string{"username", "password"}
@@ -1,9 +1,20 @@
 package admission
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/hbagdi/go-kong/kong"
+	configuration "github.com/kong/kubernetes-ingress-controller/internal/apis/configuration/v1"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/annotations"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/store"
+	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestKongHTTPValidator_ValidateCredential(t *testing.T) {
@@ -103,3 +114,406 @@ func TestKongHTTPValidator_ValidateCredential(t *testing.T) {
 		})
 	}
 }
+
+func TestKongHTTPValidator_ValidatePluginConfigFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	var validated kong.Plugin
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal("/schemas/plugins/validate", r.URL.Path)
+			assert.Nil(json.NewDecoder(r.Body).Decode(&validated))
+			w.WriteHeader(201)
+		}))
+	defer server.Close()
+	client, err := kong.NewClient(kong.String(server.URL), nil)
+	assert.Nil(err)
+
+	secrets, err := store.NewFakeStore(store.FakeObjects{
+		Secrets: []*corev1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "conf",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"rate-limiting": []byte(`{"minute":10}`),
+				},
+			},
+		},
+	})
+	assert.Nil(err)
+	validator := KongHTTPValidator{
+		Client:       client,
+		SecretGetter: secrets,
+	}
+
+	tests := []struct {
+		name        string
+		plugin      configuration.KongPlugin
+		wantOK      bool
+		wantMessage string
+	}{
+		{
+			name: "valid configFrom",
+			plugin: configuration.KongPlugin{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+				PluginName: "rate-limiting",
+				ConfigFrom: configuration.ConfigSource{
+					SecretValue: configuration.SecretValueFromSource{
+						Secret: "conf",
+						Key:    "rate-limiting",
+					},
+				},
+			},
+			wantOK: true,
+		},
+		{
+			name: "config and configFrom together",
+			plugin: configuration.KongPlugin{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+				PluginName: "rate-limiting",
+				Config:     configuration.Configuration{"minute": 10},
+				ConfigFrom: configuration.ConfigSource{
+					SecretValue: configuration.SecretValueFromSource{
+						Secret: "conf",
+						Key:    "rate-limiting",
+					},
+				},
+			},
+			wantMessage: "plugin cannot use both config and configFrom",
+		},
+		{
+			name: "incomplete configFrom",
+			plugin: configuration.KongPlugin{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+				PluginName: "rate-limiting",
+				ConfigFrom: configuration.ConfigSource{
+					SecretValue: configuration.SecretValueFromSource{
+						Secret: "conf",
+					},
+				},
+			},
+			wantMessage: "configFrom.secretKeyRef requires name and key",
+		},
+		{
+			name: "missing secret",
+			plugin: configuration.KongPlugin{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "other"},
+				PluginName: "rate-limiting",
+				ConfigFrom: configuration.ConfigSource{
+					SecretValue: configuration.SecretValueFromSource{
+						Secret: "conf",
+						Key:    "rate-limiting",
+					},
+				},
+			},
+			wantMessage: "could not read configFrom secret: " +
+				"secret other/conf was not found",
+		},
+		{
+			name: "missing key",
+			plugin: configuration.KongPlugin{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+				PluginName: "rate-limiting",
+				ConfigFrom: configuration.ConfigSource{
+					SecretValue: configuration.SecretValueFromSource{
+						Secret: "conf",
+						Key:    "key-auth",
+					},
+				},
+			},
+			wantMessage: "key 'key-auth' not found in secret 'default/conf'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message, err := validator.ValidatePlugin(tt.plugin)
+			assert.Nil(err)
+			assert.Equal(tt.wantOK, ok)
+			assert.Equal(tt.wantMessage, message)
+		})
+	}
+	assert.Equal(kong.Configuration{"minute": float64(10)}, validated.Config)
+
+	t.Run("KongClusterPlugin configFrom", func(t *testing.T) {
+		ok, message, err := validator.ValidateClusterPlugin(
+			configuration.KongClusterPlugin{
+				PluginName: "rate-limiting",
+				ConfigFrom: configuration.NamespacedConfigSource{
+					SecretValue: configuration.NamespacedSecretValueFromSource{
+						Secret: "conf",
+						Key:    "rate-limiting",
+					},
+				},
+			})
+		assert.Nil(err)
+		assert.False(ok)
+		assert.Equal("configFrom.secretKeyRef requires namespace, "+
+			"name and key", message)
+
+		ok, message, err = validator.ValidateClusterPlugin(
+			configuration.KongClusterPlugin{
+				PluginName: "rate-limiting",
+				ConfigFrom: configuration.NamespacedConfigSource{
+					SecretValue: configuration.NamespacedSecretValueFromSource{
+						Namespace: "default",
+						Secret:    "conf",
+						Key:       "rate-limiting",
+					},
+				},
+			})
+		assert.Nil(err)
+		assert.True(ok)
+		assert.Empty(message)
+	})
+}
+
+func TestKongHTTPValidator_ValidateKongIngress(t *testing.T) {
+	assert := assert.New(t)
+
+	validated := make(map[string]map[string]interface{})
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var entity map[string]interface{}
+			assert.Nil(json.NewDecoder(r.Body).Decode(&entity))
+			validated[r.URL.Path] = entity
+			if r.URL.Path == "/schemas/upstreams/validate" &&
+				entity["slots"] == float64(5) {
+				w.WriteHeader(400)
+				fmt.Fprint(w, `{"message":"schema violation `+
+					`(slots: value should be between 10 and 65536)"}`)
+				return
+			}
+			w.WriteHeader(201)
+		}))
+	defer server.Close()
+	client, err := kong.NewClient(kong.String(server.URL), nil)
+	assert.Nil(err)
+	validator := KongHTTPValidator{Client: client}
+
+	tests := []struct {
+		name        string
+		kongIngress configuration.KongIngress
+		wantOK      bool
+		wantMessage string
+	}{
+		{
+			name: "valid overrides",
+			kongIngress: configuration.KongIngress{
+				Proxy: &kong.Service{
+					Protocol: kong.String("grpc"),
+					Path:     kong.String("/foo"),
+				},
+				Route: &kong.Route{
+					Protocols: kong.StringSlice("grpc", "grpcs"),
+					StripPath: kong.Bool(true),
+				},
+				Upstream: &kong.Upstream{
+					Slots: kong.Int(100),
+				},
+				Canary: &configuration.KongIngressCanary{
+					Backend: configuration.IngressBackend{
+						ServiceName: "foo-canary",
+						ServicePort: 80,
+					},
+					Weight: 10,
+				},
+			},
+			wantOK: true,
+		},
+		{
+			name: "invalid route protocol",
+			kongIngress: configuration.KongIngress{
+				Route: &kong.Route{
+					Protocols: kong.StringSlice("http", "tcp"),
+				},
+			},
+			wantMessage: "invalid route.protocols 'tcp': must be one of " +
+				"http, https, grpc or grpcs",
+		},
+		{
+			name: "mixed route protocols",
+			kongIngress: configuration.KongIngress{
+				Route: &kong.Route{
+					Protocols: kong.StringSlice("https", "grpcs"),
+				},
+			},
+			wantMessage: "route.protocols cannot mix http(s) and grpc(s)",
+		},
+		{
+			name: "invalid proxy protocol",
+			kongIngress: configuration.KongIngress{
+				Proxy: &kong.Service{
+					Protocol: kong.String("tls"),
+				},
+			},
+			wantMessage: "invalid proxy.protocol 'tls': must be one of " +
+				"http, https, grpc or grpcs",
+		},
+		{
+			name: "invalid canary weight",
+			kongIngress: configuration.KongIngress{
+				Canary: &configuration.KongIngressCanary{
+					Backend: configuration.IngressBackend{
+						ServiceName: "foo-canary",
+						ServicePort: 80,
+					},
+					Weight: 110,
+				},
+			},
+			wantMessage: "canary.weight must be between 0 and 100",
+		},
+		{
+			name: "incomplete canary header",
+			kongIngress: configuration.KongIngress{
+				Canary: &configuration.KongIngressCanary{
+					Backend: configuration.IngressBackend{
+						ServiceName: "foo-canary",
+						ServicePort: 80,
+					},
+					Header: &configuration.CanaryHeader{Name: "x-canary"},
+				},
+			},
+			wantMessage: "canary.header requires name and values",
+		},
+		{
+			name: "upstream rejected by Kong",
+			kongIngress: configuration.KongIngress{
+				Upstream: &kong.Upstream{
+					Slots: kong.Int(5),
+				},
+			},
+			wantMessage: "invalid upstream: 400 Bad Request " +
+				`{"message":"schema violation (slots: value should be ` +
+				`between 10 and 65536)"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message, err := validator.ValidateKongIngress(tt.kongIngress)
+			assert.Nil(err)
+			assert.Equal(tt.wantOK, ok)
+			assert.Equal(tt.wantMessage, message)
+		})
+	}
+
+	service := validated["/schemas/services/validate"]
+	assert.Equal("grpc", service["protocol"])
+	assert.NotContains(service, "path", "grpc services have no path")
+	route := validated["/schemas/routes/validate"]
+	assert.NotContains(route, "strip_path", "grpc routes have no strip_path")
+	assert.Equal("kong-ingress-validation.svc",
+		validated["/schemas/upstreams/validate"]["name"])
+}
+
+func TestKongHTTPValidator_ValidateIngress(t *testing.T) {
+	assert := assert.New(t)
+	ingress := func(namespace, name, host string,
+		paths ...string) *networking.Ingress {
+		var httpPaths []networking.HTTPIngressPath
+		for _, path := range paths {
+			httpPaths = append(httpPaths, networking.HTTPIngressPath{
+				Path: path,
+				Backend: networking.IngressBackend{
+					ServiceName: "foo-svc",
+				},
+			})
+		}
+		return &networking.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: networking.IngressSpec{
+				Rules: []networking.IngressRule{
+					{
+						Host: host,
+						IngressRuleValue: networking.IngressRuleValue{
+							HTTP: &networking.HTTPIngressRuleValue{
+								Paths: httpPaths,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	otherClass := ingress("other", "bar", "example.com", "/bar")
+	otherClass.Annotations = map[string]string{
+		"kubernetes.io/ingress.class": "nginx",
+	}
+	ingresses, err := store.NewFakeStore(store.FakeObjects{
+		Ingresses: []*networking.Ingress{
+			ingress("default", "foo", "example.com", "/foo", ""),
+			otherClass,
+		},
+	})
+	assert.Nil(err)
+	validator := KongHTTPValidator{
+		IngressLister:       ingresses,
+		IsValidIngressClass: annotations.IngressClassValidatorFuncFromObjectMeta("kong"),
+	}
+
+	withProtocols := ingress("other", "foo", "example.com", "/baz")
+	withProtocols.Annotations = map[string]string{
+		"configuration.konghq.com/protocols": "https,grpcs",
+	}
+	withDefaultBackend := ingress("other", "foo", "other.example.com", "/")
+	withDefaultBackend.Spec.Backend = &networking.IngressBackend{
+		ServiceName: "foo-svc",
+	}
+	conflicting := ingress("other", "foo", "example.com", "/foo", "/")
+	conflicting.Annotations = map[string]string{
+		"kubernetes.io/ingress.class": "nginx",
+	}
+
+	tests := []struct {
+		name        string
+		ingress     *networking.Ingress
+		wantOK      bool
+		wantMessage string
+	}{
+		{
+			name:    "no conflict",
+			ingress: ingress("other", "foo", "example.com", "/bar", "/foo/"),
+			wantOK:  true,
+		},
+		{
+			name:    "same paths in the same namespace",
+			ingress: ingress("default", "baz", "example.com", "/foo"),
+			wantOK:  true,
+		},
+		{
+			name:    "conflicting paths in another namespace",
+			ingress: ingress("other", "foo", "example.com", "/", "/foo", "/foo"),
+			wantMessage: "path '/' of host 'example.com' is already used " +
+				"by Ingress 'default/foo', path '/foo' of host " +
+				"'example.com' is already used by Ingress 'default/foo'",
+		},
+		{
+			name:    "host-less default backend",
+			ingress: withDefaultBackend,
+			wantOK:  true,
+		},
+		{
+			name:    "invalid protocols annotation",
+			ingress: withProtocols,
+			wantMessage: "protocols annotation cannot mix http(s) " +
+				"and grpc(s)",
+		},
+		{
+			name:    "Ingress of another class",
+			ingress: conflicting,
+			wantOK:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message, err := validator.ValidateIngress(*tt.ingress)
+			assert.Nil(err)
+			assert.Equal(tt.wantOK, ok)
+			assert.Equal(tt.wantMessage, message)
+		})
+	}
+}
@@ -31,10 +31,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&KongIngressList{},
 		&KongPlugin{},
 		&KongPluginList{},
+		&KongClusterPlugin{},
+		&KongClusterPluginList{},
 		&KongConsumer{},
 		&KongConsumerList{},
 		&KongCredential{},
 		&KongCredentialList{},
+		&TCPIngress{},
+		&TCPIngressList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
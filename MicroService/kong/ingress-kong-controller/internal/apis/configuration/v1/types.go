@@ -23,6 +23,33 @@ type KongIngress struct {
 	Upstream *kong.Upstream `json:"upstream,omitempty"`
 	Proxy    *kong.Service  `json:"proxy,omitempty"`
 	Route    *kong.Route    `json:"route,omitempty"`
+
+	// Canary sends part of the requests proxied to the Service the
+	// KongIngress is associated with to a canary Service.
+	Canary *KongIngressCanary `json:"canary,omitempty"`
+}
+
+// KongIngressCanary splits requests between a Service and a canary
+// Service in the same namespace.
+type KongIngressCanary struct {
+	// Backend is the canary Service.
+	Backend IngressBackend `json:"backend"`
+
+	// Weight is the percentage of requests, from 0 to 100, sent to the
+	// canary Service. It can be overridden with the
+	// configuration.konghq.com/canary-weight annotation on the Service.
+	Weight int `json:"weight,omitempty"`
+
+	// Header, if set, sends all requests carrying it to the canary
+	// Service, regardless of Weight.
+	Header *CanaryHeader `json:"header,omitempty"`
+}
+
+// CanaryHeader matches requests carrying the header Name with one of
+// Values.
+type CanaryHeader struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
 }
 
 // KongIngressList is a top-level list type. The client methods for
@@ -56,6 +83,10 @@ type KongPlugin struct {
 	// Config contains the plugin configuration.
 	Config Configuration `json:"config,omitempty"`
 
+	// ConfigFrom references a Secret holding the plugin configuration.
+	// It is mutually exclusive with Config.
+	ConfigFrom ConfigSource `json:"configFrom,omitempty"`
+
 	// PluginName is the name of the plugin to which to apply the config
 	PluginName string `json:"plugin,omitempty"`
 
@@ -108,6 +139,113 @@ func (in *KongPlugin) DeepCopyInto(out *KongPlugin) {
 	return
 }
 
+// ConfigSource is a reference to the source of a plugin configuration.
+type ConfigSource struct {
+	SecretValue SecretValueFromSource `json:"secretKeyRef,omitempty"`
+}
+
+// SecretValueFromSource references a key of a Secret in the namespace
+// of the referencing resource.
+type SecretValueFromSource struct {
+	// Secret is the name of the Secret.
+	Secret string `json:"name,omitempty"`
+	// Key is the key in the Secret holding the value.
+	Key string `json:"key,omitempty"`
+}
+
+// NamespacedConfigSource is a reference to the source of a plugin
+// configuration for cluster-scoped resources.
+type NamespacedConfigSource struct {
+	SecretValue NamespacedSecretValueFromSource `json:"secretKeyRef,omitempty"`
+}
+
+// NamespacedSecretValueFromSource references a key of a Secret in
+// any namespace.
+type NamespacedSecretValueFromSource struct {
+	// Namespace is the namespace of the Secret.
+	Namespace string `json:"namespace,omitempty"`
+	// Secret is the name of the Secret.
+	Secret string `json:"name,omitempty"`
+	// Key is the key in the Secret holding the value.
+	Key string `json:"key,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KongClusterPlugin is a top-level type. A client is created for it.
+// It is the cluster-scoped counterpart of KongPlugin and can be
+// referenced by Ingresses and Services in any namespace.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KongClusterPlugin struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// ConsumerRef is a reference to a particular consumer
+	ConsumerRef string `json:"consumerRef,omitempty"`
+
+	// Disabled set if the plugin is disabled or not
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Config contains the plugin configuration.
+	Config Configuration `json:"config,omitempty"`
+
+	// ConfigFrom references a Secret holding the plugin configuration.
+	// It is mutually exclusive with Config.
+	ConfigFrom NamespacedConfigSource `json:"configFrom,omitempty"`
+
+	// PluginName is the name of the plugin to which to apply the config
+	PluginName string `json:"plugin,omitempty"`
+
+	// RunOn configures the plugin to run on the first or the second or both
+	// nodes in case of a service mesh deployment.
+	RunOn string `json:"run_on,omitempty"`
+
+	// Protocols configures plugin to run on requests received on specific
+	// protocols.
+	Protocols []string `json:"protocols,omitempty"`
+}
+
+// KongClusterPluginList is a top-level list type. The client methods for
+// lists are automatically created.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KongClusterPluginList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// +optional
+	Items []KongClusterPlugin `json:"items"`
+}
+
+// DeepCopyInto deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KongClusterPlugin) DeepCopyInto(out *KongClusterPlugin) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Config != nil {
+		var buf bytes.Buffer
+		enc := gob.NewEncoder(&buf)
+		dec := gob.NewDecoder(&buf)
+		err := enc.Encode(in.Config)
+		if err != nil {
+			glog.Errorf("unexpected error copying configuration: %v", err)
+		}
+		err = dec.Decode(&out.Config)
+		if err != nil {
+			glog.Errorf("unexpected error copying configuration: %v", err)
+		}
+	}
+	if in.Protocols != nil {
+		in, out := &in.Protocols, &out.Protocols
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
 // +genclient
 // +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -196,3 +334,69 @@ func (in *KongCredential) DeepCopyInto(out *KongCredential) {
 	}
 	return
 }
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TCPIngress is a top-level type. A client is created for it.
+// It routes TCP and TLS traffic received on Kong's stream listeners
+// to Kubernetes Services.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TCPIngress struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TCPIngressSpec `json:"spec,omitempty"`
+}
+
+// TCPIngressList is a top-level list type. The client methods for
+// lists are automatically created.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TCPIngressList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// +optional
+	Items []TCPIngress `json:"items"`
+}
+
+// TCPIngressSpec describes how traffic received on Kong's stream
+// listeners is routed to Services.
+type TCPIngressSpec struct {
+	// Rules are a list of port and SNI based rules, each routing
+	// to a single backend.
+	Rules []IngressRule `json:"rules,omitempty"`
+
+	// TLS configures the certificates Kong uses to terminate TLS
+	// for the hosts listed.
+	TLS []IngressTLS `json:"tls,omitempty"`
+}
+
+// IngressRule routes connections received on Port, and optionally
+// carrying Host as the TLS SNI, to Backend.
+type IngressRule struct {
+	// Host is the SNI the client must present. If empty, all
+	// connections received on Port are routed to Backend.
+	Host string `json:"host,omitempty"`
+
+	// Port is the port on which Kong's stream listener receives
+	// the connection.
+	Port int `json:"port"`
+
+	// Backend is the Service connections are proxied to.
+	Backend IngressBackend `json:"backend"`
+}
+
+// IngressBackend references a port of a Kubernetes Service.
+type IngressBackend struct {
+	ServiceName string `json:"serviceName"`
+	ServicePort int    `json:"servicePort"`
+}
+
+// IngressTLS associates the certificate in a Secret with a list of hosts.
+type IngressTLS struct {
+	Hosts      []string `json:"hosts,omitempty"`
+	SecretName string   `json:"secretName,omitempty"`
+}
@@ -26,10 +26,12 @@ import (
 
 type ConfigurationV1Interface interface {
 	RESTClient() rest.Interface
+	KongClusterPluginsGetter
 	KongConsumersGetter
 	KongCredentialsGetter
 	KongIngressesGetter
 	KongPluginsGetter
+	TCPIngressesGetter
 }
 
 // ConfigurationV1Client is used to interact with features provided by the configuration.konghq.com group.
@@ -37,6 +39,10 @@ type ConfigurationV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *ConfigurationV1Client) KongClusterPlugins() KongClusterPluginInterface {
+	return newKongClusterPlugins(c)
+}
+
 func (c *ConfigurationV1Client) KongConsumers(namespace string) KongConsumerInterface {
 	return newKongConsumers(c, namespace)
 }
@@ -53,6 +59,10 @@ func (c *ConfigurationV1Client) KongPlugins(namespace string) KongPluginInterfac
 	return newKongPlugins(c, namespace)
 }
 
+func (c *ConfigurationV1Client) TCPIngresses(namespace string) TCPIngressInterface {
+	return newTCPIngresses(c, namespace)
+}
+
 // NewForConfig creates a new ConfigurationV1Client for the given config.
 func NewForConfig(c *rest.Config) (*ConfigurationV1Client, error) {
 	config := *c
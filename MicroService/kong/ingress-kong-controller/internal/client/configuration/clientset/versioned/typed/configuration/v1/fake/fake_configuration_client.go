@@ -28,6 +28,10 @@ type FakeConfigurationV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeConfigurationV1) KongClusterPlugins() v1.KongClusterPluginInterface {
+	return &FakeKongClusterPlugins{c}
+}
+
 func (c *FakeConfigurationV1) KongConsumers(namespace string) v1.KongConsumerInterface {
 	return &FakeKongConsumers{c, namespace}
 }
@@ -44,6 +48,10 @@ func (c *FakeConfigurationV1) KongPlugins(namespace string) v1.KongPluginInterfa
 	return &FakeKongPlugins{c, namespace}
 }
 
+func (c *FakeConfigurationV1) TCPIngresses(namespace string) v1.TCPIngressInterface {
+	return &FakeTCPIngresses{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeConfigurationV1) RESTClient() rest.Interface {
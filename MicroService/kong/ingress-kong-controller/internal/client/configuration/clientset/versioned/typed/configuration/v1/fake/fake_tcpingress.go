@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Kong Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/internal/apis/configuration/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeTCPIngresses implements TCPIngressInterface
+type FakeTCPIngresses struct {
+	Fake *FakeConfigurationV1
+	ns   string
+}
+
+var tcpingressesResource = schema.GroupVersionResource{Group: "configuration.konghq.com", Version: "v1", Resource: "tcpingresses"}
+
+var tcpingressesKind = schema.GroupVersionKind{Group: "configuration.konghq.com", Version: "v1", Kind: "TCPIngress"}
+
+// Get takes name of the tCPIngress, and returns the corresponding tCPIngress object, and an error if there is any.
+func (c *FakeTCPIngresses) Get(name string, options v1.GetOptions) (result *configurationv1.TCPIngress, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(tcpingressesResource, c.ns, name), &configurationv1.TCPIngress{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*configurationv1.TCPIngress), err
+}
+
+// List takes label and field selectors, and returns the list of TCPIngresses that match those selectors.
+func (c *FakeTCPIngresses) List(opts v1.ListOptions) (result *configurationv1.TCPIngressList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(tcpingressesResource, tcpingressesKind, c.ns, opts), &configurationv1.TCPIngressList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &configurationv1.TCPIngressList{ListMeta: obj.(*configurationv1.TCPIngressList).ListMeta}
+	for _, item := range obj.(*configurationv1.TCPIngressList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested tCPIngresses.
+func (c *FakeTCPIngresses) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(tcpingressesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a tCPIngress and creates it.  Returns the server's representation of the tCPIngress, and an error, if there is any.
+func (c *FakeTCPIngresses) Create(tCPIngress *configurationv1.TCPIngress) (result *configurationv1.TCPIngress, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(tcpingressesResource, c.ns, tCPIngress), &configurationv1.TCPIngress{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*configurationv1.TCPIngress), err
+}
+
+// Update takes the representation of a tCPIngress and updates it. Returns the server's representation of the tCPIngress, and an error, if there is any.
+func (c *FakeTCPIngresses) Update(tCPIngress *configurationv1.TCPIngress) (result *configurationv1.TCPIngress, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(tcpingressesResource, c.ns, tCPIngress), &configurationv1.TCPIngress{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*configurationv1.TCPIngress), err
+}
+
+// Delete takes name of the tCPIngress and deletes it. Returns an error if one occurs.
+func (c *FakeTCPIngresses) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(tcpingressesResource, c.ns, name), &configurationv1.TCPIngress{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeTCPIngresses) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(tcpingressesResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &configurationv1.TCPIngressList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched tCPIngress.
+func (c *FakeTCPIngresses) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *configurationv1.TCPIngress, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(tcpingressesResource, c.ns, name, pt, data, subresources...), &configurationv1.TCPIngress{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*configurationv1.TCPIngress), err
+}
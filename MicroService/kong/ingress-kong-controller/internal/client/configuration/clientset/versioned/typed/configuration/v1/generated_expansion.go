@@ -18,6 +18,8 @@ limitations under the License.
 
 package v1
 
+type KongClusterPluginExpansion interface{}
+
 type KongConsumerExpansion interface{}
 
 type KongCredentialExpansion interface{}
@@ -25,3 +27,5 @@ type KongCredentialExpansion interface{}
 type KongIngressExpansion interface{}
 
 type KongPluginExpansion interface{}
+
+type TCPIngressExpansion interface{}
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// KongClusterPlugins returns a KongClusterPluginInformer.
+	KongClusterPlugins() KongClusterPluginInformer
 	// KongConsumers returns a KongConsumerInformer.
 	KongConsumers() KongConsumerInformer
 	// KongCredentials returns a KongCredentialInformer.
@@ -32,6 +34,8 @@ type Interface interface {
 	KongIngresses() KongIngressInformer
 	// KongPlugins returns a KongPluginInformer.
 	KongPlugins() KongPluginInformer
+	// TCPIngresses returns a TCPIngressInformer.
+	TCPIngresses() TCPIngressInformer
 }
 
 type version struct {
@@ -45,6 +49,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// KongClusterPlugins returns a KongClusterPluginInformer.
+func (v *version) KongClusterPlugins() KongClusterPluginInformer {
+	return &kongClusterPluginInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // KongConsumers returns a KongConsumerInformer.
 func (v *version) KongConsumers() KongConsumerInformer {
 	return &kongConsumerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -64,3 +73,8 @@ func (v *version) KongIngresses() KongIngressInformer {
 func (v *version) KongPlugins() KongPluginInformer {
 	return &kongPluginInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// TCPIngresses returns a TCPIngressInformer.
+func (v *version) TCPIngresses() TCPIngressInformer {
+	return &tCPIngressInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kong Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	time "time"
+
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/internal/apis/configuration/v1"
+	versioned "github.com/kong/kubernetes-ingress-controller/internal/client/configuration/clientset/versioned"
+	internalinterfaces "github.com/kong/kubernetes-ingress-controller/internal/client/configuration/informers/externalversions/internalinterfaces"
+	v1 "github.com/kong/kubernetes-ingress-controller/internal/client/configuration/listers/configuration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// TCPIngressInformer provides access to a shared informer and lister for
+// TCPIngresses.
+type TCPIngressInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.TCPIngressLister
+}
+
+type tCPIngressInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewTCPIngressInformer constructs a new informer for TCPIngress type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewTCPIngressInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredTCPIngressInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredTCPIngressInformer constructs a new informer for TCPIngress type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredTCPIngressInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ConfigurationV1().TCPIngresses(namespace).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ConfigurationV1().TCPIngresses(namespace).Watch(options)
+			},
+		},
+		&configurationv1.TCPIngress{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *tCPIngressInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredTCPIngressInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *tCPIngressInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&configurationv1.TCPIngress{}, f.defaultInformer)
+}
+
+func (f *tCPIngressInformer) Lister() v1.TCPIngressLister {
+	return v1.NewTCPIngressLister(f.Informer().GetIndexer())
+}
@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1
 
+// KongClusterPluginListerExpansion allows custom methods to be added to
+// KongClusterPluginLister.
+type KongClusterPluginListerExpansion interface{}
+
 // KongConsumerListerExpansion allows custom methods to be added to
 // KongConsumerLister.
 type KongConsumerListerExpansion interface{}
@@ -49,3 +53,11 @@ type KongPluginListerExpansion interface{}
 // KongPluginNamespaceListerExpansion allows custom methods to be added to
 // KongPluginNamespaceLister.
 type KongPluginNamespaceListerExpansion interface{}
+
+// TCPIngressListerExpansion allows custom methods to be added to
+// TCPIngressLister.
+type TCPIngressListerExpansion interface{}
+
+// TCPIngressNamespaceListerExpansion allows custom methods to be added to
+// TCPIngressNamespaceLister.
+type TCPIngressNamespaceListerExpansion interface{}
@@ -35,6 +35,8 @@ const (
 
 	clientCertAnnotationKey = "configuration.konghq.com/client-cert"
 
+	canaryWeightAnnotationKey = "configuration.konghq.com/canary-weight"
+
 	// DefaultIngressClass defines the default class used
 	// by Kong's ingress controller.
 	DefaultIngressClass = "kong"
@@ -116,6 +118,12 @@ func ExtractClientCertificate(anns map[string]string) string {
 	return anns[clientCertAnnotationKey]
 }
 
+// ExtractCanaryWeight extracts the percentage of requests to send to the
+// canary Service, overriding the weight set in KongIngress.
+func ExtractCanaryWeight(anns map[string]string) string {
+	return anns[canaryWeightAnnotationKey]
+}
+
 // HasServiceUpstreamAnnotation returns true if the annotation
 // ingress.kubernetes.io/service-upstream is set to "true" in anns.
 func HasServiceUpstreamAnnotation(anns map[string]string) bool {
@@ -85,6 +85,17 @@ func TestExtractClientCert(t *testing.T) {
 	}
 }
 
+func TestExtractCanaryWeight(t *testing.T) {
+	data := map[string]string{
+		"configuration.konghq.com/canary-weight": "25",
+	}
+
+	weight := ExtractCanaryWeight(data)
+	if weight != "25" {
+		t.Errorf("expected weight as 25 but got %v", weight)
+	}
+}
+
 func TestIngrssClassValidatorFunc(t *testing.T) {
 	tests := []struct {
 		ingress    string
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sort"
 	"sync"
 	"time"
@@ -35,6 +36,7 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/internal/ingress/task"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1beta1"
 	clientset "k8s.io/client-go/kubernetes"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -50,6 +52,9 @@ type Kong struct {
 	// Headers are injected into every request to Kong's Admin API
 	// to help with authorization/authentication.
 	Client *kong.Client
+	// HTTPClient is used to create clients for the Kong instances
+	// discovered through KongAdminService.
+	HTTPClient *http.Client
 
 	InMemory      bool
 	HasTagSupport bool
@@ -82,6 +87,22 @@ type Configuration struct {
 	ElectionID             string
 
 	UseNetworkingV1beta1 bool
+
+	// DryRun disables syncing of configuration to Kong; the changes
+	// a sync would make are logged instead.
+	DryRun bool
+
+	// LastKnownGoodConfigPath is the file the last configuration
+	// accepted by Kong is stored in, in DB-less mode.
+	LastKnownGoodConfigPath string
+
+	// KongAdminService is the namespace/name of a Service whose
+	// endpoints are the Admin APIs of Kong instances in DB-less mode.
+	// If set, the configuration is pushed to every instance.
+	KongAdminService string
+	// KongAdminServicePortName is the name of the Admin API port of
+	// KongAdminService. If empty, the Service must have a single port.
+	KongAdminServicePortName string
 }
 
 // sync collects all the pieces required to assemble the configuration file and
@@ -142,7 +163,10 @@ func NewKongController(config *Configuration,
 		updateCh: updateCh,
 
 		stopLock:          &sync.Mutex{},
+		lastConfigLock:    &sync.RWMutex{},
 		PluginSchemaStore: *NewPluginSchemaStore(config.Kong.Client),
+		recorder: eventBroadcaster.NewRecorder(eventScheme(),
+			apiv1.EventSource{Component: "kong-ingress-controller"}),
 	}
 
 	n.store = store
@@ -210,6 +234,16 @@ type KongController struct {
 
 	runningConfigHash [32]byte
 
+	// instances are the Kong instances discovered through
+	// KongAdminService, keyed by URL. They are only accessed by the
+	// sync loop.
+	instances map[string]*kongInstance
+
+	// lastConfig is the JSON encoded declarative configuration
+	// generated by the last sync.
+	lastConfig     []byte
+	lastConfigLock *sync.RWMutex
+
 	isShuttingDown bool
 
 	store store.Storer
@@ -217,6 +251,8 @@ type KongController struct {
 	parser parser.Parser
 
 	PluginSchemaStore PluginSchemaStore
+
+	recorder record.EventRecorder
 }
 
 // Start start a new NGINX master process running in foreground.
@@ -291,7 +327,7 @@ func (n *KongController) handleBasicAuthUpdates(event Event) error {
 	if !n.elector.IsLeader() {
 		return nil
 	}
-	if n.cfg.Kong.InMemory {
+	if n.cfg.Kong.InMemory || n.cfg.DryRun {
 		return nil
 	}
 	if event.Type != UpdateEvent {
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/hbagdi/deck/crud"
+	"github.com/hbagdi/deck/diff"
+	"github.com/hbagdi/deck/file"
+	"github.com/hbagdi/deck/state"
+	"github.com/hbagdi/deck/utils"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/controller/parser"
+	"github.com/pkg/errors"
+)
+
+// EntityDiff is a change that syncing a configuration would make
+// to an entity in Kong.
+type EntityDiff struct {
+	// Op is one of Create, Update or Delete.
+	Op   string `json:"op"`
+	Kind string `json:"kind"`
+	// Name identifies the entity in a human-readable form.
+	Name string `json:"name"`
+	// Old is the entity currently in Kong, set for updates only.
+	Old interface{} `json:"old,omitempty"`
+	// New is the entity as it would be after the sync.
+	New interface{} `json:"new,omitempty"`
+}
+
+// diffWithKong returns the changes that syncing targetContent would
+// make to the configuration in Kong, without applying them.
+func (n *KongController) diffWithKong(
+	targetContent *file.Content) ([]EntityDiff, error) {
+	syncer, err := n.newSyncer(targetContent)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock sync.Mutex
+	var diffs []EntityDiff
+	errs := syncer.Run(nil, n.cfg.Kong.Concurrency,
+		func(e diff.Event) (crud.Arg, error) {
+			d := EntityDiff{
+				Op:   e.Op.String(),
+				Kind: string(e.Kind),
+			}
+			if c, ok := e.Obj.(state.ConsoleString); ok {
+				d.Name = c.Console()
+			}
+			switch e.Op {
+			case crud.Update:
+				d.Old = e.OldObj
+				d.New = e.Obj
+			case crud.Create:
+				d.New = e.Obj
+			}
+			lock.Lock()
+			diffs = append(diffs, d)
+			lock.Unlock()
+			// return the target object as is so that dependent
+			// entities can be diffed without talking to Kong
+			return e.Obj, nil
+		})
+	if errs != nil {
+		return nil, utils.ErrArray{Errors: errs}
+	}
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].Kind != diffs[j].Kind {
+			return diffs[i].Kind < diffs[j].Kind
+		}
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs, nil
+}
+
+// setLastConfig records the declarative configuration generated by
+// the last sync.
+func (n *KongController) setLastConfig(config []byte) {
+	n.lastConfigLock.Lock()
+	defer n.lastConfigLock.Unlock()
+	n.lastConfig = config
+}
+
+// getLastConfig returns the declarative configuration generated by the
+// last sync or nil if no sync happened yet.
+func (n *KongController) getLastConfig() []byte {
+	n.lastConfigLock.RLock()
+	defer n.lastConfigLock.RUnlock()
+	return n.lastConfig
+}
+
+// ConfigHandler renders the declarative configuration generated by the
+// last sync. The output contains credentials and must not be exposed
+// outside the cluster.
+func (n *KongController) ConfigHandler(w http.ResponseWriter,
+	r *http.Request) {
+	config := n.getLastConfig()
+	if config == nil {
+		http.Error(w, "configuration not generated yet",
+			http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	w.Write(config)
+}
+
+// ConfigDiffHandler renders the changes syncing the configuration
+// generated by the last sync would make to Kong.
+func (n *KongController) ConfigDiffHandler(w http.ResponseWriter,
+	r *http.Request) {
+	config := n.getLastConfig()
+	if config == nil {
+		http.Error(w, "configuration not generated yet",
+			http.StatusServiceUnavailable)
+		return
+	}
+	var content file.Content
+	err := json.Unmarshal(config, &content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	diffs, err := n.diffWithKong(&content)
+	if err != nil {
+		glog.Errorf("computing configuration diff: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	b, err := json.Marshal(diffs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	w.Write(b)
+}
+
+// Renderer generates Kong's declarative configuration outside of a
+// running controller, for example to preview the configuration
+// generated for a set of manifests.
+type Renderer struct {
+	controller *KongController
+}
+
+// NewRenderer returns a Renderer targeting the Kong node described by
+// kong. If kong.Client is nil, defaults of plugin configurations are
+// not filled in and Diff cannot be used.
+func NewRenderer(kong Kong) *Renderer {
+	return &Renderer{
+		controller: &KongController{
+			cfg:               &Configuration{Kong: kong},
+			PluginSchemaStore: *NewPluginSchemaStore(kong.Client),
+		},
+	}
+}
+
+// Render returns the declarative configuration for state.
+func (r *Renderer) Render(state *parser.KongState) (*file.Content, error) {
+	return r.controller.toDeckContent(state)
+}
+
+// Diff returns the changes syncing content would make to Kong.
+func (r *Renderer) Diff(content *file.Content) ([]EntityDiff, error) {
+	if r.controller.cfg.Kong.Client == nil {
+		return nil, errors.New("no Kong client configured")
+	}
+	return r.controller.diffWithKong(content)
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigHandler(t *testing.T) {
+	assert := assert.New(t)
+	n := &KongController{
+		lastConfigLock: &sync.RWMutex{},
+	}
+
+	res := httptest.NewRecorder()
+	n.ConfigHandler(res, httptest.NewRequest("GET", "/debug/config", nil))
+	assert.Equal(http.StatusServiceUnavailable, res.Code)
+
+	res = httptest.NewRecorder()
+	n.ConfigDiffHandler(res,
+		httptest.NewRequest("GET", "/debug/config/diff", nil))
+	assert.Equal(http.StatusServiceUnavailable, res.Code)
+
+	n.setLastConfig([]byte(`{"_format_version":"1.1"}`))
+	res = httptest.NewRecorder()
+	n.ConfigHandler(res, httptest.NewRequest("GET", "/debug/config", nil))
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Equal("application/json", res.Header().Get("content-type"))
+	assert.Equal(`{"_format_version":"1.1"}`, res.Body.String())
+}
@@ -0,0 +1,576 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/hbagdi/deck/file"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/internal/apis/configuration/v1"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/controller/parser"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// maxConfigFallbackAttempts is the number of times a configuration
+// rejected by Kong is retried with the offending objects excluded.
+const maxConfigFallbackAttempts = 3
+
+const configRejectedReason = "KongConfigurationRejected"
+
+// eventScheme returns a scheme to look up the kind of objects events
+// are recorded for.
+func eventScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	scheme.AddToScheme(s)
+	configurationv1.AddToScheme(s)
+	return s
+}
+
+// kongConfigError is the body of the response Kong sends back when
+// a declarative configuration posted to /config is invalid.
+type kongConfigError struct {
+	Message string `json:"message"`
+	Name    string `json:"name"`
+	// Fields mirrors the structure of the configuration with the
+	// errors of each invalid entity.
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// parseConfigError extracts the error Kong returned from err.
+// The second return value is false if err doesn't hold one.
+func parseConfigError(err error) (kongConfigError, bool) {
+	var configErr kongConfigError
+	if err == nil {
+		return configErr, false
+	}
+	// the Kong client returns the status line followed by the body
+	msg := err.Error()
+	i := strings.Index(msg, "{")
+	if i < 0 {
+		return configErr, false
+	}
+	if json.Unmarshal([]byte(msg[i:]), &configErr) != nil {
+		return configErr, false
+	}
+	return configErr, len(configErr.Fields) > 0
+}
+
+// rejectedEntity is an entity of a declarative configuration Kong
+// refused to apply.
+type rejectedEntity struct {
+	// Kind is the kind of the entity in the configuration, e.g.
+	// "services" or "routes".
+	Kind string
+	// ID identifies the entity among the others of its kind: the name
+	// of services, routes and upstreams, the username of consumers,
+	// the ID of certificates and pluginString of plugins.
+	ID string
+	// Reason holds the errors reported by Kong for the entity.
+	Reason string
+}
+
+// errorsByIndex returns the errors in v, which describes an array of
+// entities, keyed by the position of the entity in the array.
+// Kong encodes the array either as a JSON array with null for valid
+// entities or, if sparse, as an object keyed by the 1-based position.
+func errorsByIndex(v interface{}) map[int]interface{} {
+	res := make(map[int]interface{})
+	switch errs := v.(type) {
+	case []interface{}:
+		for i, e := range errs {
+			if e != nil {
+				res[i] = e
+			}
+		}
+	case map[string]interface{}:
+		for k, e := range errs {
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 1 || e == nil {
+				continue
+			}
+			res[i-1] = e
+		}
+	}
+	return res
+}
+
+func errorString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// rejectedEntities returns the entities of content the error fields
+// returned by Kong refer to. Errors in entities nested under routes,
+// upstreams or consumers are reported for the parent entity.
+func rejectedEntities(fields map[string]interface{},
+	content *file.Content) []rejectedEntity {
+	var res []rejectedEntity
+	for kind, v := range fields {
+		for i, e := range errorsByIndex(v) {
+			switch kind {
+			case "services":
+				if i >= len(content.Services) {
+					continue
+				}
+				res = append(res,
+					rejectedServiceEntities(content.Services[i], e)...)
+			case "plugins":
+				if i >= len(content.Plugins) {
+					continue
+				}
+				res = append(res, rejectedEntity{
+					Kind:   kind,
+					ID:     pluginString(content.Plugins[i]),
+					Reason: errorString(e),
+				})
+			case "upstreams":
+				if i >= len(content.Upstreams) ||
+					content.Upstreams[i].Name == nil {
+					continue
+				}
+				res = append(res, rejectedEntity{
+					Kind:   kind,
+					ID:     *content.Upstreams[i].Name,
+					Reason: errorString(e),
+				})
+			case "consumers":
+				if i >= len(content.Consumers) ||
+					content.Consumers[i].Username == nil {
+					continue
+				}
+				res = append(res, rejectedEntity{
+					Kind:   kind,
+					ID:     *content.Consumers[i].Username,
+					Reason: errorString(e),
+				})
+			case "certificates":
+				if i >= len(content.Certificates) ||
+					content.Certificates[i].ID == nil {
+					continue
+				}
+				res = append(res, rejectedEntity{
+					Kind:   kind,
+					ID:     *content.Certificates[i].ID,
+					Reason: errorString(e),
+				})
+			}
+		}
+	}
+	return res
+}
+
+func rejectedServiceEntities(service file.FService,
+	e interface{}) []rejectedEntity {
+	var res []rejectedEntity
+	serviceErrs := make(map[string]interface{})
+	if errs, ok := e.(map[string]interface{}); ok {
+		for k, v := range errs {
+			if k != "routes" {
+				serviceErrs[k] = v
+				continue
+			}
+			for j, routeErr := range errorsByIndex(v) {
+				if j >= len(service.Routes) || service.Routes[j].Name == nil {
+					continue
+				}
+				res = append(res, rejectedEntity{
+					Kind:   "routes",
+					ID:     *service.Routes[j].Name,
+					Reason: errorString(routeErr),
+				})
+			}
+		}
+	} else {
+		serviceErrs["@entity"] = e
+	}
+	if len(serviceErrs) > 0 && service.Name != nil {
+		res = append(res, rejectedEntity{
+			Kind:   "services",
+			ID:     *service.Name,
+			Reason: errorString(serviceErrs),
+		})
+	}
+	return res
+}
+
+// exclusions is the set of entities and Kubernetes objects left out of
+// the configuration after Kong rejected it.
+type exclusions struct {
+	entities map[string]bool
+	objects  map[string]bool
+	// reasons holds the errors reported for each of objects.
+	reasons map[string][]string
+	// order is the order objects were excluded in.
+	order []runtime.Object
+}
+
+func newExclusions() *exclusions {
+	return &exclusions{
+		entities: make(map[string]bool),
+		objects:  make(map[string]bool),
+		reasons:  make(map[string][]string),
+	}
+}
+
+func objectKey(obj runtime.Object) string {
+	if obj == nil {
+		return ""
+	}
+	m, err := meta.Accessor(obj)
+	if err != nil || m.GetName() == "" {
+		return ""
+	}
+	return fmt.Sprintf("%T/%v/%v", obj, m.GetNamespace(), m.GetName())
+}
+
+// add excludes the entity of kind and id and obj, the Kubernetes
+// object it was generated from, if any.
+func (e *exclusions) add(kind, id string, obj runtime.Object,
+	reason string) {
+	e.entities[kind+"/"+id] = true
+	key := objectKey(obj)
+	if key == "" {
+		glog.Warningf("excluding %v '%v' rejected by Kong: %v",
+			kind, id, reason)
+		return
+	}
+	if !e.objects[key] {
+		e.objects[key] = true
+		e.order = append(e.order, obj)
+	}
+	e.reasons[key] = append(e.reasons[key],
+		fmt.Sprintf("%v '%v': %v", kind, id, reason))
+}
+
+func (e *exclusions) hasEntity(kind, id string) bool {
+	return e.entities[kind+"/"+id]
+}
+
+func (e *exclusions) hasObject(obj runtime.Object) bool {
+	key := objectKey(obj)
+	return key != "" && e.objects[key]
+}
+
+// addRejected excludes the rejected entities of state together with
+// the Kubernetes objects they were generated from.
+func (e *exclusions) addRejected(state *parser.KongState,
+	rejected []rejectedEntity) {
+	for _, r := range rejected {
+		switch r.Kind {
+		case "services":
+			for i := range state.Services {
+				s := &state.Services[i]
+				if s.Name != nil && *s.Name == r.ID {
+					e.add(r.Kind, r.ID, &s.K8sService, r.Reason)
+				}
+			}
+		case "routes":
+			for i := range state.Services {
+				for j := range state.Services[i].Routes {
+					route := &state.Services[i].Routes[j]
+					if route.Name != nil && *route.Name == r.ID {
+						e.add(r.Kind, r.ID, routeObject(route), r.Reason)
+					}
+				}
+			}
+		case "plugins":
+			for _, p := range state.Plugins {
+				if pluginString(file.FPlugin{Plugin: p.Plugin}) == r.ID {
+					e.add(r.Kind, r.ID, p.K8sObject, r.Reason)
+				}
+			}
+		case "upstreams":
+			for i := range state.Upstreams {
+				u := &state.Upstreams[i]
+				if u.Name != nil && *u.Name == r.ID {
+					e.add(r.Kind, r.ID, &u.Service.K8sService, r.Reason)
+				}
+			}
+		case "consumers":
+			for i := range state.Consumers {
+				c := &state.Consumers[i]
+				if c.Username != nil && *c.Username == r.ID {
+					e.add(r.Kind, r.ID, &c.K8sKongConsumer, r.Reason)
+				}
+			}
+		case "certificates":
+			e.add(r.Kind, r.ID, nil, r.Reason)
+		}
+	}
+}
+
+func routeObject(route *parser.Route) runtime.Object {
+	if route.IsTCP {
+		return &route.TCPIngress
+	}
+	return &route.Ingress
+}
+
+func (e *exclusions) hasPlugin(p parser.Plugin) bool {
+	return e.hasEntity("plugins", pluginString(file.FPlugin{Plugin: p.Plugin})) ||
+		e.hasObject(p.K8sObject)
+}
+
+// filter returns a copy of state without the excluded entities,
+// the entities generated from excluded objects and plugins
+// referring to entities that were left out.
+// Entities an excluded plugin applies to are left out as well, so that
+// they are never exposed without it, e.g. without authentication.
+// If an excluded plugin is global, nil is returned.
+func (e *exclusions) filter(state *parser.KongState) *parser.KongState {
+	var res parser.KongState
+	removed := make(map[string]bool)
+
+	for _, p := range state.Plugins {
+		if !e.hasPlugin(p) {
+			continue
+		}
+		global := true
+		if p.Service != nil && p.Service.ID != nil {
+			removed["services/"+*p.Service.ID] = true
+			global = false
+		}
+		if p.Route != nil && p.Route.ID != nil {
+			removed["routes/"+*p.Route.ID] = true
+			global = false
+		}
+		if p.Consumer != nil && p.Consumer.ID != nil {
+			removed["consumers/"+*p.Consumer.ID] = true
+			global = false
+		}
+		if global {
+			return nil
+		}
+	}
+
+	for _, s := range state.Services {
+		if removed["services/"+*s.Name] ||
+			e.hasEntity("services", *s.Name) || e.hasObject(&s.K8sService) {
+			removed["services/"+*s.Name] = true
+			for _, r := range s.Routes {
+				removed["routes/"+*r.Name] = true
+			}
+			continue
+		}
+		routes := s.Routes
+		s.Routes = nil
+		for i, r := range routes {
+			if removed["routes/"+*r.Name] || e.hasEntity("routes", *r.Name) ||
+				e.hasObject(routeObject(&routes[i])) {
+				removed["routes/"+*r.Name] = true
+				continue
+			}
+			s.Routes = append(s.Routes, r)
+		}
+		res.Services = append(res.Services, s)
+	}
+
+	for _, u := range state.Upstreams {
+		if e.hasEntity("upstreams", *u.Name) ||
+			e.hasObject(&u.Service.K8sService) {
+			continue
+		}
+		res.Upstreams = append(res.Upstreams, u)
+	}
+
+	for _, c := range state.Consumers {
+		if removed["consumers/"+*c.Username] ||
+			e.hasEntity("consumers", *c.Username) ||
+			e.hasObject(&c.K8sKongConsumer) {
+			removed["consumers/"+*c.Username] = true
+			continue
+		}
+		res.Consumers = append(res.Consumers, c)
+	}
+
+	for _, c := range state.Certificates {
+		if c.ID != nil && e.hasEntity("certificates", *c.ID) {
+			continue
+		}
+		res.Certificates = append(res.Certificates, c)
+	}
+
+	for _, p := range state.Plugins {
+		if e.hasPlugin(p) {
+			continue
+		}
+		if p.Service != nil && p.Service.ID != nil &&
+			removed["services/"+*p.Service.ID] {
+			continue
+		}
+		if p.Route != nil && p.Route.ID != nil &&
+			removed["routes/"+*p.Route.ID] {
+			continue
+		}
+		if p.Consumer != nil && p.Consumer.ID != nil &&
+			removed["consumers/"+*p.Consumer.ID] {
+			continue
+		}
+		res.Plugins = append(res.Plugins, p)
+	}
+	return &res
+}
+
+// recordEvents emits a warning event on each excluded object.
+func (n *KongController) recordEvents(e *exclusions) {
+	for _, obj := range e.order {
+		reasons := e.reasons[objectKey(obj)]
+		glog.Warningf("excluding %v from the configuration, rejected "+
+			"by Kong: %v", objectKey(obj), strings.Join(reasons, "; "))
+		if n.recorder == nil {
+			continue
+		}
+		n.recorder.Eventf(obj, apiv1.EventTypeWarning, configRejectedReason,
+			"configuration generated from this object was rejected by "+
+				"Kong and is not applied: %v", strings.Join(reasons, "; "))
+	}
+}
+
+// syncWithFallback posts targetContent, generated from state, to the
+// Kong instance. If nothing could be applied on the first sync of the
+// instance, the last configuration accepted by Kong is restored, if
+// stored.
+func (n *KongController) syncWithFallback(instance *kongInstance,
+	state *parser.KongState, targetContent *file.Content) error {
+	config, err := declarativeConfig(targetContent)
+	if err != nil {
+		return err
+	}
+	err = n.postConfig(instance, config)
+	config, err = n.excludeRejected(instance, state, targetContent,
+		config, err)
+	if err == nil {
+		n.storeLastKnownGoodConfig(config)
+		return nil
+	}
+	if instance.configHash == [32]byte{} {
+		n.restoreLastKnownGoodConfig(instance)
+	}
+	return err
+}
+
+// excludeRejected handles err, returned by the Kong instance for config,
+// generated from targetContent. If Kong rejected config, the objects
+// responsible are identified from the error and the remaining
+// configuration is posted instead. The configuration accepted by Kong
+// is returned.
+func (n *KongController) excludeRejected(instance *kongInstance,
+	state *parser.KongState, targetContent *file.Content, config []byte,
+	err error) ([]byte, error) {
+	excluded := newExclusions()
+	defer n.recordEvents(excluded)
+	for attempt := 0; err != nil &&
+		attempt < maxConfigFallbackAttempts; attempt++ {
+		configErr, ok := parseConfigError(err)
+		if !ok {
+			break
+		}
+		rejected := rejectedEntities(configErr.Fields, targetContent)
+		if len(rejected) == 0 {
+			break
+		}
+		excluded.addRejected(state, rejected)
+		state = excluded.filter(state)
+		if state == nil {
+			glog.Errorf("a global plugin was rejected by Kong, " +
+				"not applying the configuration without it")
+			break
+		}
+		var renderErr error
+		targetContent, renderErr = n.toDeckContent(state)
+		if renderErr != nil {
+			return nil, renderErr
+		}
+		config, renderErr = declarativeConfig(targetContent)
+		if renderErr != nil {
+			return nil, renderErr
+		}
+		err = n.postConfig(instance, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// storeLastKnownGoodConfig persists config, which was accepted by Kong,
+// to be used after a restart.
+func (n *KongController) storeLastKnownGoodConfig(config []byte) {
+	path := n.cfg.LastKnownGoodConfigPath
+	if path == "" {
+		return
+	}
+	// write to a temporary file first so that a crash never leaves a
+	// partial configuration behind
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".last-known-good")
+	if err != nil {
+		glog.Errorf("storing last known good configuration: %v", err)
+		return
+	}
+	_, err = tmp.Write(config)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		glog.Errorf("storing last known good configuration: %v", err)
+	}
+}
+
+// restoreLastKnownGoodConfig posts the stored configuration to the Kong
+// instance.
+func (n *KongController) restoreLastKnownGoodConfig(instance *kongInstance) {
+	path := n.cfg.LastKnownGoodConfigPath
+	if path == "" {
+		return
+	}
+	config, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		glog.Errorf("reading last known good configuration: %v", err)
+		return
+	}
+	err = n.postConfig(instance, config)
+	if err != nil {
+		glog.Errorf("restoring last known good configuration on %v: %v",
+			instance.URL, err)
+		return
+	}
+	glog.Warningf("restored last known good configuration from '%v' on %v",
+		path, instance.URL)
+}
+
+// declarativeConfig encodes content the way Kong expects it on /config.
+func declarativeConfig(content *file.Content) ([]byte, error) {
+	// Kong will error out if this is set
+	content.Info = nil
+	// Kong errors out if `null`s are present in `config` of plugins
+	cleanUpNullsInPluginConfigs(content)
+
+	config, err := json.Marshal(content)
+	if err != nil {
+		return nil, errors.Wrap(err,
+			"marshaling Kong declarative configuration to JSON")
+	}
+	return config, nil
+}
@@ -0,0 +1,388 @@
+package controller
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/hbagdi/deck/file"
+	"github.com/hbagdi/go-kong/kong"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/internal/apis/configuration/v1"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/controller/parser"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/store"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+)
+
+const rateLimitRejection = `{"message":"declarative config is invalid: ` +
+	`{plugins={{config={minute=\"expected a number\"}}}}",` +
+	`"name":"invalid declarative configuration",` +
+	`"fields":{"plugins":{"1":{"config":{"minute":"expected a number"}}}},` +
+	`"code":14}`
+
+func TestParseConfigError(t *testing.T) {
+	assert := assert.New(t)
+
+	configErr, ok := parseConfigError(errors.New(
+		"posting new config to /config: 400 Bad Request " + rateLimitRejection))
+	assert.True(ok)
+	assert.Equal("invalid declarative configuration", configErr.Name)
+	assert.Contains(configErr.Fields, "plugins")
+
+	_, ok = parseConfigError(errors.New("dial tcp: connection refused"))
+	assert.False(ok)
+	_, ok = parseConfigError(errors.New(`500 Internal Server Error {"message":"An unexpected error occurred"}`))
+	assert.False(ok)
+	_, ok = parseConfigError(nil)
+	assert.False(ok)
+}
+
+func TestRejectedEntities(t *testing.T) {
+	assert := assert.New(t)
+	content := &file.Content{
+		Services: []file.FService{
+			{
+				Service: kong.Service{Name: kong.String("default.foo.80")},
+				Routes: []*file.FRoute{
+					{Route: kong.Route{Name: kong.String("default.foo.00")}},
+					{Route: kong.Route{Name: kong.String("default.foo.01")}},
+				},
+			},
+			{
+				Service: kong.Service{Name: kong.String("default.bar.80")},
+			},
+		},
+		Plugins: []file.FPlugin{
+			{Plugin: kong.Plugin{Name: kong.String("key-auth")}},
+			{Plugin: kong.Plugin{
+				Name:  kong.String("rate-limiting"),
+				Route: &kong.Route{ID: kong.String("default.foo.01")},
+			}},
+		},
+		Upstreams: []file.FUpstream{
+			{Upstream: kong.Upstream{Name: kong.String("foo.default.80.svc")}},
+		},
+		Consumers: []file.FConsumer{
+			{Consumer: kong.Consumer{Username: kong.String("alice")}},
+		},
+		Certificates: []file.FCertificate{
+			{Certificate: kong.Certificate{ID: kong.String("cert-uid")}},
+		},
+	}
+
+	t.Run("errors encoded as arrays", func(t *testing.T) {
+		fields := map[string]interface{}{
+			"services": []interface{}{
+				map[string]interface{}{
+					"routes": []interface{}{
+						nil,
+						map[string]interface{}{"paths": "invalid path"},
+					},
+				},
+				map[string]interface{}{"host": "invalid host"},
+			},
+			"plugins": []interface{}{
+				nil,
+				map[string]interface{}{"config": "invalid"},
+			},
+			"upstreams": []interface{}{
+				map[string]interface{}{"targets": []interface{}{"invalid"}},
+			},
+			"consumers":    []interface{}{"invalid consumer"},
+			"certificates": []interface{}{map[string]interface{}{"key": "bad"}},
+		}
+		rejected := rejectedEntities(fields, content)
+		assert.ElementsMatch([]rejectedEntity{
+			{
+				Kind:   "routes",
+				ID:     "default.foo.01",
+				Reason: `{"paths":"invalid path"}`,
+			},
+			{
+				Kind:   "services",
+				ID:     "default.bar.80",
+				Reason: `{"host":"invalid host"}`,
+			},
+			{
+				Kind:   "plugins",
+				ID:     "rate-limitingdefault.foo.01",
+				Reason: `{"config":"invalid"}`,
+			},
+			{
+				Kind:   "upstreams",
+				ID:     "foo.default.80.svc",
+				Reason: `{"targets":["invalid"]}`,
+			},
+			{
+				Kind:   "consumers",
+				ID:     "alice",
+				Reason: "invalid consumer",
+			},
+			{
+				Kind:   "certificates",
+				ID:     "cert-uid",
+				Reason: `{"key":"bad"}`,
+			},
+		}, rejected)
+	})
+	t.Run("errors encoded as objects keyed by position", func(t *testing.T) {
+		fields := map[string]interface{}{
+			"services": map[string]interface{}{
+				"1": map[string]interface{}{
+					"routes": map[string]interface{}{
+						"1": map[string]interface{}{"paths": "invalid path"},
+					},
+				},
+			},
+			"plugins": map[string]interface{}{
+				"1":   "invalid plugin",
+				"foo": "not a position",
+				"9":   "out of range",
+			},
+		}
+		rejected := rejectedEntities(fields, content)
+		assert.ElementsMatch([]rejectedEntity{
+			{
+				Kind:   "routes",
+				ID:     "default.foo.00",
+				Reason: `{"paths":"invalid path"}`,
+			},
+			{
+				Kind:   "plugins",
+				ID:     "key-auth",
+				Reason: "invalid plugin",
+			},
+		}, rejected)
+	})
+}
+
+// fallbackState builds a state with a route of Ingress foo, rate-limited
+// by KongPlugin rate-limit, and a route of Ingress bar.
+func fallbackState(t *testing.T) *parser.KongState {
+	ingresses := []*networking.Ingress{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+				Annotations: map[string]string{
+					"plugins.konghq.com": "rate-limit",
+				},
+			},
+			Spec: networking.IngressSpec{
+				Rules: []networking.IngressRule{
+					{
+						Host: "foo.example.com",
+						IngressRuleValue: networking.IngressRuleValue{
+							HTTP: &networking.HTTPIngressRuleValue{
+								Paths: []networking.HTTPIngressPath{
+									{
+										Path: "/",
+										Backend: networking.IngressBackend{
+											ServiceName: "foo-svc",
+											ServicePort: intstr.FromInt(80),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "default",
+			},
+			Spec: networking.IngressSpec{
+				Backend: &networking.IngressBackend{
+					ServiceName: "bar-svc",
+					ServicePort: intstr.FromInt(80),
+				},
+			},
+		},
+	}
+	services := []*corev1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-svc",
+				Namespace: "default",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar-svc",
+				Namespace: "default",
+			},
+		},
+	}
+	plugins := []*configurationv1.KongPlugin{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rate-limit",
+				Namespace: "default",
+			},
+			PluginName: "rate-limiting",
+			Config: configurationv1.Configuration{
+				"minute": "ten",
+			},
+		},
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{
+		Ingresses:   ingresses,
+		Services:    services,
+		KongPlugins: plugins,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := parser.New(s)
+	state, err := p.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+// fakeDBLessKong serves /config like Kong in DB-less mode, rejecting
+// configurations that contain the rate-limiting plugin.
+type fakeDBLessKong struct {
+	lock    sync.Mutex
+	configs []string
+	// accept, if set, accepts only the configuration it holds.
+	accept string
+}
+
+func (k *fakeDBLessKong) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/plugins/schema/") {
+		w.Write([]byte(`{"fields":[]}`))
+		return
+	}
+	if r.URL.Path != "/config" {
+		http.NotFound(w, r)
+		return
+	}
+	b, _ := ioutil.ReadAll(r.Body)
+	k.lock.Lock()
+	k.configs = append(k.configs, string(b))
+	k.lock.Unlock()
+	if strings.Contains(string(b), "rate-limiting") {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(rateLimitRejection))
+		return
+	}
+	if k.accept != "" && k.accept != string(b) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"invalid","name":"syntax error"}`))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(`{}`))
+}
+
+func newFallbackController(t *testing.T, url string,
+	path string) (*KongController, *record.FakeRecorder) {
+	client, err := kong.NewClient(kong.String(url), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := record.NewFakeRecorder(10)
+	return &KongController{
+		cfg: &Configuration{
+			Kong: Kong{
+				URL:      url,
+				Client:   client,
+				InMemory: true,
+				Version:  semver.MustParse("1.4.0"),
+			},
+			LastKnownGoodConfigPath: path,
+		},
+		PluginSchemaStore: *NewPluginSchemaStore(client),
+		recorder:          recorder,
+	}, recorder
+}
+
+func TestSyncWithFallback(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "fallback")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "last-known-good.json")
+
+	t.Run("rejected objects are excluded", func(t *testing.T) {
+		fake := &fakeDBLessKong{}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+		n, recorder := newFallbackController(t, server.URL, path)
+
+		state := fallbackState(t)
+		content, err := n.toDeckContent(state)
+		assert.Nil(err)
+		assert.Nil(n.syncWithFallback(n.defaultInstance(), state, content))
+
+		assert.Len(fake.configs, 2)
+		applied := fake.configs[1]
+		assert.NotContains(applied, "rate-limiting")
+		assert.NotContains(applied, "foo.example.com",
+			"expected routes of the Ingress using the plugin to be excluded")
+		assert.Contains(applied, "default.bar-svc.80")
+
+		var events []string
+		close(recorder.Events)
+		for e := range recorder.Events {
+			events = append(events, e)
+		}
+		assert.Len(events, 1)
+		assert.Contains(events[0], "Warning "+configRejectedReason)
+		assert.Contains(events[0], "expected a number")
+
+		stored, err := ioutil.ReadFile(path)
+		assert.Nil(err)
+		assert.Equal(applied, string(stored))
+	})
+	t.Run("last known good config is restored on the first sync",
+		func(t *testing.T) {
+			stored, err := ioutil.ReadFile(path)
+			assert.Nil(err)
+			fake := &fakeDBLessKong{accept: string(stored)}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+			n, _ := newFallbackController(t, server.URL, path)
+
+			state := &parser.KongState{}
+			content, err := n.toDeckContent(state)
+			assert.Nil(err)
+			assert.NotNil(n.syncWithFallback(n.defaultInstance(), state, content))
+			assert.Len(fake.configs, 2)
+			assert.Equal(string(stored), fake.configs[1])
+
+			n.runningConfigHash = [32]byte{1}
+			assert.NotNil(n.syncWithFallback(n.defaultInstance(), state, content))
+			assert.Len(fake.configs, 3,
+				"expected no restore once a configuration was applied")
+		})
+}
+
+func TestExclusionsFilter(t *testing.T) {
+	assert := assert.New(t)
+	state := &parser.KongState{
+		Plugins: []parser.Plugin{
+			{Plugin: kong.Plugin{Name: kong.String("key-auth")}},
+		},
+	}
+	e := newExclusions()
+	e.addRejected(state, []rejectedEntity{{Kind: "plugins", ID: "key-auth"}})
+	assert.Nil(e.filter(state),
+		"expected no configuration without a rejected global plugin")
+}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/blang/semver"
@@ -52,13 +53,21 @@ func (n *KongController) OnUpdate(state *parser.KongState) error {
 		return errors.Wrap(err,
 			"marshaling Kong declarative configuration to JSON")
 	}
+	n.setLastConfig(jsonConfig)
 	shaSum := sha256.Sum256(jsonConfig)
+	if n.cfg.InMemory && n.cfg.KongAdminService != "" && !n.cfg.DryRun {
+		// each instance keeps track of the configuration it runs
+		return n.onUpdateInstances(state, targetContent, shaSum)
+	}
 	if reflect.DeepEqual(n.runningConfigHash, shaSum) {
 		glog.Info("no configuration change, skipping sync to Kong")
 		return nil
 	}
+	if n.cfg.DryRun {
+		return n.onUpdateDryRun(targetContent, shaSum)
+	}
 	if n.cfg.InMemory {
-		err = n.onUpdateInMemoryMode(targetContent)
+		err = n.syncWithFallback(n.defaultInstance(), state, targetContent)
 	} else {
 		err = n.onUpdateDBMode(targetContent)
 	}
@@ -69,6 +78,24 @@ func (n *KongController) OnUpdate(state *parser.KongState) error {
 	return err
 }
 
+// onUpdateDryRun logs the changes a sync of targetContent would make
+// to Kong without applying them.
+func (n *KongController) onUpdateDryRun(targetContent *file.Content,
+	shaSum [32]byte) error {
+	diffs, err := n.diffWithKong(targetContent)
+	if err != nil {
+		return errors.Wrap(err, "computing configuration diff")
+	}
+	if len(diffs) == 0 {
+		glog.Info("dry-run: configuration in Kong is up to date")
+	}
+	for _, d := range diffs {
+		glog.Infof("dry-run: %v %v %v", d.Op, d.Kind, d.Name)
+	}
+	n.runningConfigHash = shaSum
+	return nil
+}
+
 func cleanUpNullsInPluginConfigs(state *file.Content) {
 
 	for _, s := range state.Services {
@@ -109,20 +136,13 @@ func cleanUpNullsInPluginConfigs(state *file.Content) {
 	}
 }
 
-func (n *KongController) onUpdateInMemoryMode(state *file.Content) error {
-	client := n.cfg.Kong.Client
-
-	// Kong will error out if this is set
-	state.Info = nil
-	// Kong errors out if `null`s are present in `config` of plugins
-	cleanUpNullsInPluginConfigs(state)
+// postConfig replaces the configuration of the Kong instance in DB-less
+// mode with config.
+func (n *KongController) postConfig(instance *kongInstance,
+	config []byte) error {
+	client := instance.Client
 
-	config, err := json.Marshal(state)
-	if err != nil {
-		return errors.Wrap(err,
-			"marshaling Kong declarative configuration to JSON")
-	}
-	req, err := http.NewRequest("POST", n.cfg.Kong.URL+"/config",
+	req, err := http.NewRequest("POST", instance.URL+"/config",
 		bytes.NewReader(config))
 	if err != nil {
 		return errors.Wrap(err, "creating new HTTP request for /config")
@@ -135,6 +155,7 @@ func (n *KongController) onUpdateInMemoryMode(state *file.Content) error {
 	req.URL.RawQuery = queryString.Encode()
 
 	_, err = client.Do(nil, req, nil)
+	configPushCount.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
 	if err != nil {
 		return errors.Wrap(err, "posting new config to /config")
 	}
@@ -145,16 +166,43 @@ func (n *KongController) onUpdateInMemoryMode(state *file.Content) error {
 func (n *KongController) onUpdateDBMode(targetContent *file.Content) error {
 	client := n.cfg.Kong.Client
 
+	syncer, err := n.newSyncer(targetContent)
+	if err != nil {
+		return err
+	}
+	//client.SetDebugMode(true)
+	_, errs := solver.Solve(nil, syncer, client, n.cfg.Kong.Concurrency, false)
+	if errs != nil {
+		return utils.ErrArray{Errors: errs}
+	}
+	return nil
+}
+
+// newSyncer returns a syncer holding the difference between the
+// configuration currently in Kong and targetContent.
+func (n *KongController) newSyncer(
+	targetContent *file.Content) (*diff.Syncer, error) {
+	client := n.cfg.Kong.Client
+	selectorTags := n.getIngressControllerTags()
+	if n.cfg.Kong.InMemory {
+		// tags are stripped before the configuration is sent to Kong
+		// in DB-less mode
+		selectorTags = nil
+		content := *targetContent
+		content.Info = nil
+		targetContent = &content
+	}
+
 	// read the current state
 	rawState, err := dump.Get(client, dump.Config{
-		SelectorTags: n.getIngressControllerTags(),
+		SelectorTags: selectorTags,
 	})
 	if err != nil {
-		return errors.Wrap(err, "loading configuration from kong")
+		return nil, errors.Wrap(err, "loading configuration from kong")
 	}
 	currentState, err := state.Get(rawState)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// read the target state
@@ -163,24 +211,19 @@ func (n *KongController) onUpdateDBMode(targetContent *file.Content) error {
 		KongVersion:  n.cfg.Kong.Version,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	targetState, err := state.Get(rawState)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	syncer, err := diff.NewSyncer(currentState, targetState)
 	if err != nil {
-		return errors.Wrap(err, "creating a new syncer")
+		return nil, errors.Wrap(err, "creating a new syncer")
 	}
 	syncer.SilenceWarnings = true
-	//client.SetDebugMode(true)
-	_, errs := solver.Solve(nil, syncer, client, n.cfg.Kong.Concurrency, false)
-	if errs != nil {
-		return utils.ErrArray{Errors: errs}
-	}
-	return nil
+	return syncer, nil
 }
 
 // getIngressControllerTags returns a tag to use if the current
@@ -362,20 +405,23 @@ func (n *KongController) fillPlugin(plugin *file.FPlugin) error {
 	if plugin.Name == nil || *plugin.Name == "" {
 		return errors.New("plugin doesn't have a name")
 	}
-	schema, err := n.PluginSchemaStore.Schema(*plugin.Name)
-	if err != nil {
-		return errors.Wrapf(err, "error retrieveing schema for plugin %s",
-			*plugin.Name)
-	}
 	if plugin.Config == nil {
 		plugin.Config = make(kong.Configuration)
 	}
-	newConfig, err := fill(schema, plugin.Config)
-	if err != nil {
-		return errors.Wrapf(err, "error filling in default for plugin %s",
-			*plugin.Name)
+	// schemas can't be fetched when rendering the configuration offline
+	if n.cfg.Kong.Client != nil {
+		schema, err := n.PluginSchemaStore.Schema(*plugin.Name)
+		if err != nil {
+			return errors.Wrapf(err, "error retrieveing schema for plugin %s",
+				*plugin.Name)
+		}
+		newConfig, err := fill(schema, plugin.Config)
+		if err != nil {
+			return errors.Wrapf(err, "error filling in default for plugin %s",
+				*plugin.Name)
+		}
+		plugin.Config = newConfig
 	}
-	plugin.Config = newConfig
 	if plugin.RunOn == nil {
 		plugin.RunOn = kong.String("first")
 	}
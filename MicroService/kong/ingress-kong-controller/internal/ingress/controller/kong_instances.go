@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/hbagdi/deck/file"
+	"github.com/hbagdi/go-kong/kong"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/controller/parser"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/utils"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// kongInstance is a Kong node the configuration is pushed to in DB-less
+// mode.
+type kongInstance struct {
+	URL    string
+	Client *kong.Client
+	// configHash is the hash of the configuration last applied to the
+	// instance, zero if none was applied yet.
+	configHash [32]byte
+}
+
+// defaultInstance returns the Kong node configured with --kong-admin-url.
+func (n *KongController) defaultInstance() *kongInstance {
+	return &kongInstance{
+		URL:        n.cfg.Kong.URL,
+		Client:     n.cfg.Kong.Client,
+		configHash: n.runningConfigHash,
+	}
+}
+
+// kongAdminURLs returns the URLs of the Admin APIs of the ready
+// endpoints of KongAdminService.
+func (n *KongController) kongAdminURLs() ([]string, error) {
+	namespace, name, err := utils.ParseNameNS(n.cfg.KongAdminService)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := n.store.GetEndpointsForService(namespace, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching endpoints of '%v'",
+			n.cfg.KongAdminService)
+	}
+	// the scheme of the Admin API is the one of --kong-admin-url
+	scheme := "http"
+	if u, err := url.Parse(n.cfg.Kong.URL); err == nil && u.Scheme != "" {
+		scheme = u.Scheme
+	}
+
+	var urls []string
+	for _, subset := range endpoints.Subsets {
+		port, ok := adminPort(subset.Ports, n.cfg.KongAdminServicePortName)
+		if !ok {
+			glog.Warningf("no port '%v' found in endpoints of '%v'",
+				n.cfg.KongAdminServicePortName, n.cfg.KongAdminService)
+			continue
+		}
+		for _, address := range subset.Addresses {
+			host := net.JoinHostPort(address.IP, strconv.Itoa(int(port)))
+			urls = append(urls, scheme+"://"+host)
+		}
+	}
+	sort.Strings(urls)
+	return urls, nil
+}
+
+// adminPort returns the port named name, or the only port if name is
+// empty.
+func adminPort(ports []apiv1.EndpointPort, name string) (int32, bool) {
+	if name == "" {
+		if len(ports) == 1 {
+			return ports[0].Port, true
+		}
+		return 0, false
+	}
+	for _, port := range ports {
+		if port.Name == name {
+			return port.Port, true
+		}
+	}
+	return 0, false
+}
+
+// refreshInstances updates the set of Kong nodes the configuration is
+// pushed to with the endpoints of KongAdminService. Instances keep the
+// hash of their configuration as long as they are ready.
+func (n *KongController) refreshInstances() error {
+	urls, err := n.kongAdminURLs()
+	if err != nil {
+		return err
+	}
+	instances := make(map[string]*kongInstance)
+	for _, u := range urls {
+		if instance, ok := n.instances[u]; ok {
+			instances[u] = instance
+			continue
+		}
+		client, err := kong.NewClient(kong.String(u), n.cfg.Kong.HTTPClient)
+		if err != nil {
+			return errors.Wrapf(err, "creating Kong client for %v", u)
+		}
+		glog.Infof("discovered Kong instance %v", u)
+		instances[u] = &kongInstance{URL: u, Client: client}
+	}
+	for u := range n.instances {
+		if _, ok := instances[u]; !ok {
+			glog.Infof("Kong instance %v is gone", u)
+			instanceConfigSynced.DeleteLabelValues(u)
+		}
+	}
+	n.instances = instances
+	kongInstances.Set(float64(len(instances)))
+	return nil
+}
+
+// onUpdateInstances pushes targetContent, generated from state, to all
+// Kong instances behind KongAdminService not running it yet.
+// Configuration rejected by Kong is handled as in syncWithFallback;
+// the configuration accepted by one instance after excluding the
+// offending objects is pushed to the others that rejected it.
+func (n *KongController) onUpdateInstances(state *parser.KongState,
+	targetContent *file.Content, shaSum [32]byte) error {
+	err := n.refreshInstances()
+	if err != nil {
+		return err
+	}
+	var stale []*kongInstance
+	for _, u := range sortedInstanceURLs(n.instances) {
+		instance := n.instances[u]
+		if instance.configHash != shaSum {
+			stale = append(stale, instance)
+		}
+	}
+	if len(stale) == 0 {
+		glog.Info("no configuration change, skipping sync to Kong")
+		return nil
+	}
+
+	config, err := declarativeConfig(targetContent)
+	if err != nil {
+		return err
+	}
+	errs := n.postConfigConcurrently(stale, config)
+
+	var rejected []*kongInstance
+	for _, instance := range stale {
+		if _, ok := parseConfigError(errs[instance]); ok {
+			rejected = append(rejected, instance)
+		}
+	}
+	applied := config
+	if len(rejected) > 0 {
+		// all instances run the same version of Kong and reject the
+		// configuration for the same reasons
+		first := rejected[0]
+		var fallbackConfig []byte
+		fallbackConfig, errs[first] = n.excludeRejected(first, state,
+			targetContent, config, errs[first])
+		if errs[first] == nil {
+			applied = fallbackConfig
+			for instance, err := range n.postConfigConcurrently(
+				rejected[1:], fallbackConfig) {
+				errs[instance] = err
+			}
+		}
+	}
+
+	var failed int
+	var stored bool
+	for _, instance := range stale {
+		err := errs[instance]
+		if err == nil {
+			instance.configHash = shaSum
+			instanceConfigSynced.WithLabelValues(instance.URL).Set(1)
+			if !stored {
+				n.storeLastKnownGoodConfig(applied)
+				stored = true
+			}
+			continue
+		}
+		failed++
+		instanceConfigSynced.WithLabelValues(instance.URL).Set(0)
+		glog.Errorf("syncing configuration to %v: %v", instance.URL, err)
+		if instance.configHash == [32]byte{} {
+			n.restoreLastKnownGoodConfig(instance)
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("syncing configuration failed on %v of %v "+
+			"Kong instances", failed, len(stale))
+	}
+	glog.Infof("successfully synced configuration to %v Kong instances",
+		len(stale))
+	return nil
+}
+
+// postConfigConcurrently posts config to instances, with at most
+// Concurrency requests in flight, and returns the error of each.
+func (n *KongController) postConfigConcurrently(instances []*kongInstance,
+	config []byte) map[*kongInstance]error {
+	concurrency := n.cfg.Kong.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(map[*kongInstance]error)
+	sem := make(chan struct{}, concurrency)
+	for _, instance := range instances {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(instance *kongInstance) {
+			defer wg.Done()
+			err := n.postConfig(instance, config)
+			<-sem
+			lock.Lock()
+			errs[instance] = err
+			lock.Unlock()
+		}(instance)
+	}
+	wg.Wait()
+	return errs
+}
+
+func sortedInstanceURLs(instances map[string]*kongInstance) []string {
+	var urls []string
+	for u := range instances {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+	return urls
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/store"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdminPort(t *testing.T) {
+	assert := assert.New(t)
+	ports := []apiv1.EndpointPort{
+		{Name: "admin", Port: 8444},
+		{Name: "proxy", Port: 8443},
+	}
+	port, ok := adminPort(ports, "admin")
+	assert.True(ok)
+	assert.Equal(int32(8444), port)
+
+	_, ok = adminPort(ports, "")
+	assert.False(ok, "expected no port to be picked among several")
+	_, ok = adminPort(ports, "status")
+	assert.False(ok)
+
+	port, ok = adminPort(ports[:1], "")
+	assert.True(ok)
+	assert.Equal(int32(8444), port)
+}
+
+// adminEndpoints returns Endpoints of the kong/kong-admin Service with
+// an address per server.
+func adminEndpoints(t *testing.T,
+	servers ...*httptest.Server) *apiv1.Endpoints {
+	endpoints := &apiv1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kong-admin",
+			Namespace: "kong",
+		},
+	}
+	for _, server := range servers {
+		u, err := url.Parse(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		host, port, err := net.SplitHostPort(u.Host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, _ := strconv.Atoi(port)
+		endpoints.Subsets = append(endpoints.Subsets, apiv1.EndpointSubset{
+			Addresses: []apiv1.EndpointAddress{{IP: host}},
+			Ports: []apiv1.EndpointPort{
+				{Name: "admin", Port: int32(p)},
+				{Name: "proxy", Port: 8000},
+			},
+		})
+	}
+	return endpoints
+}
+
+func newInstancesController(t *testing.T,
+	endpoints *apiv1.Endpoints) *KongController {
+	s, err := store.NewFakeStore(store.FakeObjects{
+		Endpoints: []*apiv1.Endpoints{endpoints},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &KongController{
+		cfg: &Configuration{
+			Kong: Kong{
+				URL:         "http://localhost:8001",
+				InMemory:    true,
+				Version:     semver.MustParse("1.4.0"),
+				Concurrency: 2,
+			},
+			KongAdminService:         "kong/kong-admin",
+			KongAdminServicePortName: "admin",
+		},
+		store: s,
+	}
+}
+
+func TestOnUpdateInstances(t *testing.T) {
+	assert := assert.New(t)
+	t.Run("configuration is pushed to new instances only", func(t *testing.T) {
+		var fakes []*fakeDBLessKong
+		var servers []*httptest.Server
+		for i := 0; i < 3; i++ {
+			fake := &fakeDBLessKong{}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+			fakes = append(fakes, fake)
+			servers = append(servers, server)
+		}
+		n := newInstancesController(t, adminEndpoints(t, servers[:2]...))
+
+		state := fallbackState(t)
+		state.Plugins = nil
+		content, err := n.toDeckContent(state)
+		assert.Nil(err)
+		shaSum := sha256.Sum256([]byte("config"))
+
+		assert.Nil(n.onUpdateInstances(state, content, shaSum))
+		assert.Len(fakes[0].configs, 1)
+		assert.Len(fakes[1].configs, 1)
+		assert.Equal(fakes[0].configs[0], fakes[1].configs[0])
+		assert.Equal(float64(2), testutil.ToFloat64(kongInstances))
+
+		// a new instance is started
+		n.store, err = store.NewFakeStore(store.FakeObjects{
+			Endpoints: []*apiv1.Endpoints{adminEndpoints(t, servers...)},
+		})
+		assert.Nil(err)
+		assert.Nil(n.onUpdateInstances(state, content, shaSum))
+		assert.Len(fakes[0].configs, 1)
+		assert.Len(fakes[1].configs, 1)
+		assert.Len(fakes[2].configs, 1)
+		assert.Equal(float64(3), testutil.ToFloat64(kongInstances))
+		assert.Equal(float64(1), testutil.ToFloat64(
+			instanceConfigSynced.WithLabelValues(servers[2].URL)))
+
+		// an instance is gone
+		n.store, err = store.NewFakeStore(store.FakeObjects{
+			Endpoints: []*apiv1.Endpoints{adminEndpoints(t, servers[1:]...)},
+		})
+		assert.Nil(err)
+		assert.Nil(n.onUpdateInstances(state, content, shaSum))
+		assert.Len(n.instances, 2)
+		assert.NotContains(n.instances, servers[0].URL)
+	})
+	t.Run("configuration excluding rejected objects is pushed to all",
+		func(t *testing.T) {
+			var fakes []*fakeDBLessKong
+			var servers []*httptest.Server
+			for i := 0; i < 2; i++ {
+				fake := &fakeDBLessKong{}
+				server := httptest.NewServer(fake)
+				defer server.Close()
+				fakes = append(fakes, fake)
+				servers = append(servers, server)
+			}
+			n := newInstancesController(t, adminEndpoints(t, servers...))
+
+			state := fallbackState(t)
+			content, err := n.toDeckContent(state)
+			assert.Nil(err)
+
+			assert.Nil(n.onUpdateInstances(state, content,
+				sha256.Sum256([]byte("config"))))
+			for _, fake := range fakes {
+				assert.Len(fake.configs, 2)
+				assert.Contains(fake.configs[0], "rate-limiting")
+				assert.NotContains(fake.configs[1], "rate-limiting")
+			}
+			assert.Equal(fakes[0].configs[1], fakes[1].configs[1])
+		})
+	t.Run("missing endpoints are reported", func(t *testing.T) {
+		n := newInstancesController(t, &apiv1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other",
+				Namespace: "kong",
+			},
+		})
+		assert.NotNil(n.onUpdateInstances(fallbackState(t), nil,
+			[32]byte{}))
+	})
+}
@@ -0,0 +1,31 @@
+package controller
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const metricsNamespace = "kong_ingress_controller"
+
+var (
+	kongInstances = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "kong_instances",
+		Help: "Number of Kong instances discovered through " +
+			"--kong-admin-service.",
+	})
+	instanceConfigSynced = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "kong_instance_config_synced",
+		Help: "Whether the Kong instance runs the latest configuration " +
+			"(1) or not (0).",
+	}, []string{"instance"})
+	configPushCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "config_push_count",
+		Help: "Number of declarative configurations posted to Kong " +
+			"in DB-less mode.",
+	}, []string{"success"})
+)
+
+func init() {
+	prometheus.MustRegister(kongInstances, instanceConfigSynced,
+		configPushCount)
+}
@@ -0,0 +1,209 @@
+package parser
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/hbagdi/go-kong/kong"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/internal/apis/configuration/v1"
+	"github.com/kong/kubernetes-ingress-controller/internal/ingress/annotations"
+	networking "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// getCanary returns the canary configured for service in the
+// KongIngress associated with its Kubernetes Service, if any.
+func (p *Parser) getCanary(service Service) *configurationv1.KongIngressCanary {
+	kongIngress, err := p.getKongIngressForService(service.K8sService)
+	if err != nil || kongIngress == nil || kongIngress.Canary == nil {
+		return nil
+	}
+	canary := kongIngress.Canary
+	if canary.Backend.ServiceName == "" || canary.Backend.ServicePort <= 0 {
+		glog.Errorf("invalid canary in KongIngress '%v/%v': serviceName "+
+			"and servicePort are required", kongIngress.Namespace,
+			kongIngress.Name)
+		return nil
+	}
+	if canary.Backend.ServiceName == service.Backend.ServiceName &&
+		strconv.Itoa(canary.Backend.ServicePort) ==
+			service.Backend.ServicePort.String() {
+		glog.Errorf("invalid canary in KongIngress '%v/%v': the canary "+
+			"is the service itself", kongIngress.Namespace, kongIngress.Name)
+		return nil
+	}
+	return canary
+}
+
+// canaryWeight returns the percentage of requests proxied to svc that
+// are sent to canary. The configuration.konghq.com/canary-weight
+// annotation on svc takes precedence over the weight of the KongIngress.
+func canaryWeight(canary *configurationv1.KongIngressCanary,
+	svc Service) int {
+	weight := canary.Weight
+	if value := annotations.ExtractCanaryWeight(
+		svc.K8sService.Annotations); value != "" {
+		w, err := strconv.Atoi(value)
+		if err != nil || w < 0 || w > 100 {
+			glog.Errorf("invalid canary weight '%v' on service '%v/%v': "+
+				"must be an integer between 0 and 100", value,
+				svc.K8sService.Namespace, svc.K8sService.Name)
+		} else {
+			weight = w
+		}
+	}
+	if weight < 0 || weight > 100 {
+		glog.Errorf("invalid canary weight %v for service '%v/%v': "+
+			"must be between 0 and 100", weight, svc.K8sService.Namespace,
+			svc.K8sService.Name)
+		return 0
+	}
+	return weight
+}
+
+// addCanaryTargets adds the endpoints of the canary of service, if
+// any, to the targets of its upstream, weighted so that the canary
+// receives the configured percentage of requests.
+func (p *Parser) addCanaryTargets(service Service, targets []Target) []Target {
+	canary := p.getCanary(service)
+	if canary == nil {
+		return targets
+	}
+	weight := canaryWeight(canary, service)
+	if weight == 0 {
+		return targets
+	}
+	k8sSvc, err := p.store.GetService(service.Namespace,
+		canary.Backend.ServiceName)
+	if err != nil {
+		glog.Errorf("getting canary service '%v/%v': %v", service.Namespace,
+			canary.Backend.ServiceName, err)
+		return targets
+	}
+	canaryTargets, err := p.getServiceEndpoints(*k8sSvc,
+		strconv.Itoa(canary.Backend.ServicePort))
+	if err != nil || len(canaryTargets) == 0 {
+		glog.Warningf("canary service '%v/%v' has no endpoints, sending "+
+			"all requests to '%v'", service.Namespace,
+			canary.Backend.ServiceName, service.Backend.ServiceName)
+		return targets
+	}
+	return splitTargets(targets, canaryTargets, weight)
+}
+
+// splitTargets returns the targets of an upstream sending weight percent
+// of the requests to the canary targets and the rest to the stable ones.
+// Kong balances requests across targets according to their weight, out
+// of 1000 here, so the weight of each target is its share of the
+// requests divided by the number of targets of the same Service.
+func splitTargets(stable, canary []Target, weight int) []Target {
+	var res []Target
+	added := make(map[string]bool)
+	add := func(targets []Target, share int) {
+		if share == 0 || len(targets) == 0 {
+			return
+		}
+		targetWeight := (share*10 + len(targets)/2) / len(targets)
+		if targetWeight < 1 {
+			targetWeight = 1
+		}
+		for _, t := range targets {
+			if added[*t.Target.Target] {
+				continue
+			}
+			added[*t.Target.Target] = true
+			t.Weight = kong.Int(targetWeight)
+			res = append(res, t)
+		}
+	}
+	add(stable, 100-weight)
+	add(canary, weight)
+	return res
+}
+
+// addCanaryRoutes adds, for each HTTP route of a service with a canary
+// header, a route sending the requests carrying the header to the
+// canary service.
+func (p *Parser) addCanaryRoutes(parsed *parsedIngressRules) {
+	var names []string
+	for name := range parsed.ServiceNameToServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		service := parsed.ServiceNameToServices[name]
+		canary := p.getCanary(service)
+		if canary == nil || canary.Header == nil {
+			continue
+		}
+		if canary.Header.Name == "" || len(canary.Header.Values) == 0 {
+			glog.Errorf("invalid canary header for service '%v/%v': "+
+				"name and values are required", service.Namespace,
+				service.Backend.ServiceName)
+			continue
+		}
+
+		backend := networking.IngressBackend{
+			ServiceName: canary.Backend.ServiceName,
+			ServicePort: intstr.FromInt(canary.Backend.ServicePort),
+		}
+		canaryName := service.Namespace + "." + backend.ServiceName + "." +
+			backend.ServicePort.String()
+		canaryService, ok := parsed.ServiceNameToServices[canaryName]
+		if !ok {
+			canaryService = p.fillK8sService(Service{
+				Service: kong.Service{
+					Name: kong.String(canaryName),
+					Host: kong.String(backend.ServiceName + "." +
+						service.Namespace + "." +
+						backend.ServicePort.String() + ".svc"),
+					Port:           kong.Int(80),
+					Protocol:       kong.String("http"),
+					Path:           kong.String("/"),
+					ConnectTimeout: kong.Int(60000),
+					ReadTimeout:    kong.Int(60000),
+					WriteTimeout:   kong.Int(60000),
+					Retries:        kong.Int(5),
+				},
+				Namespace: service.Namespace,
+				Backend:   backend,
+			}, parsed.SecretNameToSNIs)
+		}
+
+		stable := service.K8sService
+		var added bool
+		for _, r := range service.Routes {
+			if r.IsTCP || r.canaryOf != nil {
+				continue
+			}
+			route := Route{
+				Ingress:      r.Ingress,
+				Route:        *r.Route.DeepCopy(),
+				canaryOf:     &stable,
+				canaryHeader: canary.Header,
+			}
+			route.Name = kong.String(*r.Name + ".canary")
+			canaryService.Routes = append(canaryService.Routes, route)
+			added = true
+		}
+		if added {
+			parsed.ServiceNameToServices[canaryName] = canaryService
+		}
+	}
+}
+
+// addCanaryHeader makes a canary route match on the canary header, in
+// addition to the headers set with KongIngress.
+func addCanaryHeader(route *Route) {
+	if route.canaryHeader == nil {
+		return
+	}
+	headers := make(map[string][]string)
+	for name, values := range route.Headers {
+		headers[name] = values
+	}
+	headers[route.canaryHeader.Name] = route.canaryHeader.Values
+	route.Headers = headers
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"bytes"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -21,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -31,7 +33,18 @@ type Route struct {
 	kong.Route
 	// Ingress object associated with this route
 	Ingress networking.Ingress
+	// TCPIngress object associated with this route, set only if IsTCP
+	// is true.
+	TCPIngress configurationv1.TCPIngress
+	// IsTCP is true for stream routes generated from a TCPIngress.
+	IsTCP   bool
 	Plugins []kong.Plugin
+
+	// canaryOf is the Kubernetes Service requests matching this route
+	// are diverted from, set for routes matching the header of a
+	// KongIngress canary.
+	canaryOf     *corev1.Service
+	canaryHeader *configurationv1.CanaryHeader
 }
 
 // Service represents a service in Kong and holds routes associated with the
@@ -70,7 +83,7 @@ type Consumer struct {
 
 	Oauth2Creds []*kong.Oauth2Credential
 
-	k8sKongConsumer configurationv1.KongConsumer
+	K8sKongConsumer configurationv1.KongConsumer
 }
 
 // KongState holds the configuration that should be applied to Kong.
@@ -90,6 +103,9 @@ type Certificate struct {
 // Plugin represetns a plugin Object in Kong.
 type Plugin struct {
 	kong.Plugin
+	// K8sObject is the KongPlugin or KongClusterPlugin the plugin
+	// was generated from.
+	K8sObject runtime.Object
 }
 
 // Parser parses Kubernetes CRDs and Ingress rules and generates a
@@ -131,36 +147,19 @@ func (p *Parser) Build() (*KongState, error) {
 		return nil, errors.Wrap(err, "error parsing ingress rules")
 	}
 
+	// parse TCPIngress rules into the same set of services
+	tcpIngresses := p.store.ListTCPIngresses()
+	p.parseTCPIngressRules(tcpIngresses, parsedInfo)
+
 	// populate Kubernetes Service
 	for key, service := range parsedInfo.ServiceNameToServices {
-		k8sSvc, err := p.store.GetService(service.Namespace, service.Backend.ServiceName)
-		if err != nil {
-			glog.Errorf("getting service: %v", err)
-		}
-		if k8sSvc != nil {
-			service.K8sService = *k8sSvc
-		}
-		secretName := annotations.ExtractClientCertificate(
-			service.K8sService.GetAnnotations())
-		if secretName != "" {
-			secret, err := p.store.GetSecret(service.K8sService.Namespace,
-				secretName)
-			secretKey := service.K8sService.Namespace + "/" + secretName
-			// ensure that the cert is loaded into Kong
-			if _, ok := parsedInfo.SecretNameToSNIs[secretKey]; !ok {
-				parsedInfo.SecretNameToSNIs[secretKey] = []string{}
-			}
-			if err == nil {
-				service.ClientCertificate = &kong.Certificate{
-					ID: kong.String(string(secret.UID)),
-				}
-			} else {
-				glog.Errorf("getting secret: %v: %v", secretKey, err)
-			}
-		}
-		parsedInfo.ServiceNameToServices[key] = service
+		parsedInfo.ServiceNameToServices[key] = p.fillK8sService(service,
+			parsedInfo.SecretNameToSNIs)
 	}
 
+	// route requests carrying canary headers to canary services
+	p.addCanaryRoutes(parsedInfo)
+
 	// add the routes and services to the state
 	for _, service := range parsedInfo.ServiceNameToServices {
 		state.Services = append(state.Services, service)
@@ -196,6 +195,38 @@ func (p *Parser) Build() (*KongState, error) {
 	return &state, nil
 }
 
+// fillK8sService sets the Kubernetes Service of service and the client
+// certificate it references.
+func (p *Parser) fillK8sService(service Service,
+	secretNameToSNIs map[string][]string) Service {
+	k8sSvc, err := p.store.GetService(service.Namespace, service.Backend.ServiceName)
+	if err != nil {
+		glog.Errorf("getting service: %v", err)
+	}
+	if k8sSvc != nil {
+		service.K8sService = *k8sSvc
+	}
+	secretName := annotations.ExtractClientCertificate(
+		service.K8sService.GetAnnotations())
+	if secretName != "" {
+		secret, err := p.store.GetSecret(service.K8sService.Namespace,
+			secretName)
+		secretKey := service.K8sService.Namespace + "/" + secretName
+		// ensure that the cert is loaded into Kong
+		if _, ok := secretNameToSNIs[secretKey]; !ok {
+			secretNameToSNIs[secretKey] = []string{}
+		}
+		if err == nil {
+			service.ClientCertificate = &kong.Certificate{
+				ID: kong.String(string(secret.UID)),
+			}
+		} else {
+			glog.Errorf("getting secret: %v: %v", secretKey, err)
+		}
+	}
+	return service
+}
+
 func processCredential(credType string, consumer *Consumer,
 	credConfig interface{}) error {
 	switch credType {
@@ -288,7 +319,7 @@ func (p *Parser) fillConsumersAndCredentials(state *KongState) error {
 		if consumer.CustomID != "" {
 			c.CustomID = kong.String(consumer.CustomID)
 		}
-		c.k8sKongConsumer = *consumer
+		c.K8sKongConsumer = *consumer
 
 		for _, cred := range consumer.Credentials {
 			secret, err := p.store.GetSecret(consumer.Namespace, cred)
@@ -557,6 +588,93 @@ func (p *Parser) parseIngressRules(
 	}, nil
 }
 
+// parseTCPIngressRules adds a Kong route for each rule of the TCPIngress
+// objects to parsed. Routes match on the port the connection was received
+// on and, if a host is given, the TLS SNI; they proxy to services using
+// the tcp protocol.
+func (p *Parser) parseTCPIngressRules(
+	ingressList []*configurationv1.TCPIngress, parsed *parsedIngressRules) {
+
+	sort.SliceStable(ingressList, func(i, j int) bool {
+		return ingressList[i].CreationTimestamp.Before(
+			&ingressList[j].CreationTimestamp)
+	})
+
+	for _, ingress := range ingressList {
+		ingressSpec := ingress.Spec
+
+		var tlsSections []networking.IngressTLS
+		for _, tls := range ingressSpec.TLS {
+			tlsSections = append(tlsSections, networking.IngressTLS{
+				Hosts:      tls.Hosts,
+				SecretName: tls.SecretName,
+			})
+		}
+		processTLSSections(tlsSections, ingress.Namespace,
+			parsed.SecretNameToSNIs)
+
+		for i, rule := range ingressSpec.Rules {
+			if rule.Port <= 0 || rule.Backend.ServiceName == "" ||
+				rule.Backend.ServicePort <= 0 {
+				glog.Errorf("invalid rule %d in TCPIngress '%v/%v': "+
+					"port, serviceName and servicePort are required",
+					i, ingress.Namespace, ingress.Name)
+				continue
+			}
+			r := Route{
+				TCPIngress: *ingress,
+				IsTCP:      true,
+				Route: kong.Route{
+					Name: kong.String(ingress.Namespace + "." +
+						ingress.Name + "." + strconv.Itoa(i) + ".tcp"),
+					Protocols: kong.StringSlice("tcp", "tls"),
+					Destinations: []*kong.CIDRPort{
+						{Port: kong.Int(rule.Port)},
+					},
+				},
+			}
+			if rule.Host != "" {
+				// an SNI can only be matched on a TLS connection
+				r.Protocols = kong.StringSlice("tls")
+				r.SNIs = kong.StringSlice(rule.Host)
+			}
+
+			backend := networking.IngressBackend{
+				ServiceName: rule.Backend.ServiceName,
+				ServicePort: intstr.FromInt(rule.Backend.ServicePort),
+			}
+			serviceName := ingress.Namespace + "." +
+				backend.ServiceName + "." + backend.ServicePort.String()
+			service, ok := parsed.ServiceNameToServices[serviceName]
+			if !ok {
+				service = Service{
+					Service: kong.Service{
+						Name: kong.String(serviceName),
+						Host: kong.String(backend.ServiceName +
+							"." + ingress.Namespace + "." +
+							backend.ServicePort.String() + ".svc"),
+						Port:           kong.Int(80),
+						Protocol:       kong.String("tcp"),
+						ConnectTimeout: kong.Int(60000),
+						ReadTimeout:    kong.Int(60000),
+						WriteTimeout:   kong.Int(60000),
+						Retries:        kong.Int(5),
+					},
+					Namespace: ingress.Namespace,
+					Backend:   backend,
+				}
+			} else if *service.Protocol != "tcp" {
+				glog.Errorf("rule %d in TCPIngress '%v/%v': service '%v' "+
+					"is already used by an Ingress, skipping",
+					i, ingress.Namespace, ingress.Name, serviceName)
+				continue
+			}
+			service.Routes = append(service.Routes, r)
+			parsed.ServiceNameToServices[serviceName] = service
+		}
+	}
+}
+
 func (p *Parser) fillOverrides(state KongState) error {
 	for i := 0; i < len(state.Services); i++ {
 		// Services
@@ -570,12 +688,18 @@ func (p *Parser) fillOverrides(state KongState) error {
 
 		// Routes
 		for j := 0; j < len(state.Services[i].Routes); j++ {
+			if state.Services[i].Routes[j].IsTCP {
+				// KongIngress and the protocol annotations only
+				// carry properties of HTTP routes.
+				continue
+			}
 			kongIngress, err := p.getKongIngressFromIngress(
 				&state.Services[i].Routes[j].Ingress)
 			if err != nil {
 				glog.Errorf("error getting kongIngress %v", err)
 			}
 			overrideRoute(&state.Services[i].Routes[j], kongIngress)
+			addCanaryHeader(&state.Services[i].Routes[j])
 		}
 	}
 
@@ -776,7 +900,7 @@ func (p *Parser) getUpstreams(serviceMap map[string]Service) ([]Upstream, error)
 			glog.Errorf("error getting endpoints for '%v' service: %v",
 				svcKey, err)
 		}
-		upstream.Targets = targets
+		upstream.Targets = p.addCanaryTargets(service, targets)
 		upstreams = append(upstreams, upstream)
 	}
 	return upstreams, nil
@@ -894,18 +1018,33 @@ func getPluginRelations(state KongState) map[string]foreignRelations {
 		}
 		// route
 		for j := range state.Services[i].Routes {
-			ingress := state.Services[i].Routes[j].Ingress
+			ingress := state.Services[i].Routes[j].Ingress.ObjectMeta
+			if state.Services[i].Routes[j].IsTCP {
+				ingress = state.Services[i].Routes[j].TCPIngress.ObjectMeta
+			}
 			pluginList := annotations.ExtractKongPluginsFromAnnotations(ingress.GetAnnotations())
 			for _, pluginName := range pluginList {
 				addRouteRelation(ingress.Namespace, pluginName, *state.Services[i].Routes[j].Name)
 			}
+			// canary routes bypass the service of the stable Service,
+			// its plugins are applied to the route instead
+			if stable := state.Services[i].Routes[j].canaryOf; stable != nil {
+				added := sets.NewString(pluginList...)
+				for _, pluginName := range annotations.ExtractKongPluginsFromAnnotations(
+					stable.GetAnnotations()) {
+					if !added.Has(pluginName) {
+						addRouteRelation(stable.Namespace, pluginName,
+							*state.Services[i].Routes[j].Name)
+					}
+				}
+			}
 		}
 	}
 	// consumer
 	for _, c := range state.Consumers {
-		pluginList := annotations.ExtractKongPluginsFromAnnotations(c.k8sKongConsumer.GetAnnotations())
+		pluginList := annotations.ExtractKongPluginsFromAnnotations(c.K8sKongConsumer.GetAnnotations())
 		for _, pluginName := range pluginList {
-			addConsumerRelation(c.k8sKongConsumer.Namespace, pluginName, *c.Username)
+			addConsumerRelation(c.K8sKongConsumer.Namespace, pluginName, *c.Username)
 		}
 	}
 	return pluginRels
@@ -962,7 +1101,7 @@ func (p *Parser) fillPlugins(state KongState) []Plugin {
 	for pluginIdentifier, relations := range pluginRels {
 		identifier := strings.Split(pluginIdentifier, ":")
 		namespace, kongPluginName := identifier[0], identifier[1]
-		plugin, err := p.getPlugin(namespace, kongPluginName)
+		k8sPlugin, err := p.getPlugin(namespace, kongPluginName)
 		if err != nil {
 			glog.Errorf("reading KongPlugin '%v/%v': %v", namespace,
 				kongPluginName, err)
@@ -970,7 +1109,7 @@ func (p *Parser) fillPlugins(state KongState) []Plugin {
 		}
 
 		for _, rel := range getCombinations(relations) {
-			plugin := *plugin.DeepCopy()
+			plugin := *k8sPlugin.DeepCopy()
 			// ID is populated because that is read by decK and in_memory
 			// translater too
 			if rel.Service != "" {
@@ -982,7 +1121,10 @@ func (p *Parser) fillPlugins(state KongState) []Plugin {
 			if rel.Consumer != "" {
 				plugin.Consumer = &kong.Consumer{ID: kong.String(rel.Consumer)}
 			}
-			plugins = append(plugins, Plugin{plugin})
+			plugins = append(plugins, Plugin{
+				Plugin:    plugin,
+				K8sObject: k8sPlugin.K8sObject,
+			})
 		}
 	}
 
@@ -1017,6 +1159,12 @@ func (p *Parser) globalPlugins() ([]Plugin, error) {
 				k8sPlugin.Name)
 			continue
 		}
+		k8sPlugin, err := p.resolveKongPlugin(k8sPlugin)
+		if err != nil {
+			glog.Errorf("reading KongPlugin '%v/%v': %v",
+				k8sPlugin.Namespace, k8sPlugin.Name, err)
+			continue
+		}
 		if _, ok := res[pluginName]; ok {
 			glog.Error("Multiple KongPlugin definitions found with"+
 				" 'global' annotation for '", pluginName,
@@ -1025,7 +1173,8 @@ func (p *Parser) globalPlugins() ([]Plugin, error) {
 			continue
 		}
 		res[pluginName] = Plugin{
-			Plugin: kongPluginFromK8SPlugin(k8sPlugin),
+			Plugin:    kongPluginFromK8SPlugin(k8sPlugin),
+			K8sObject: globalPlugins[i],
 		}
 	}
 	for _, plugin := range duplicates {
@@ -1119,20 +1268,101 @@ func (p *Parser) getKongIngressFromIngress(ing *networking.Ingress) (
 }
 
 // getPlugin constructs a plugins from a KongPlugin resource.
-func (p *Parser) getPlugin(namespace, name string) (kong.Plugin, error) {
-	var plugin kong.Plugin
-	k8sPlugin, err := p.store.GetKongPlugin(namespace, name)
+// If no KongPlugin with name exists in namespace, the KongClusterPlugin
+// with the same name is used instead.
+func (p *Parser) getPlugin(namespace, name string) (Plugin, error) {
+	var plugin Plugin
+	var k8sPlugin configurationv1.KongPlugin
+	kp, err := p.store.GetKongPlugin(namespace, name)
+	if err == nil {
+		plugin.K8sObject = kp
+		k8sPlugin, err = p.resolveKongPlugin(*kp)
+	} else {
+		kcp, clusterErr := p.store.GetKongClusterPlugin(name)
+		if clusterErr != nil {
+			return plugin, errors.Wrapf(err, "fetching KongPlugin")
+		}
+		plugin.K8sObject = kcp
+		k8sPlugin, err = p.resolveKongClusterPlugin(*kcp)
+	}
 	if err != nil {
-		return plugin, errors.Wrapf(err, "fetching KongPlugin")
+		return plugin, err
 	}
 	// ignore plugins with no name
 	if k8sPlugin.PluginName == "" {
 		return plugin, errors.Errorf("invalid empty 'plugin' property")
 	}
-	plugin = kongPluginFromK8SPlugin(*k8sPlugin)
+	plugin.Plugin = kongPluginFromK8SPlugin(k8sPlugin)
 	return plugin, nil
 }
 
+// resolveKongPlugin returns k8sPlugin with its configuration read from
+// the Secret referenced in configFrom, if any.
+func (p *Parser) resolveKongPlugin(
+	k8sPlugin configurationv1.KongPlugin) (configurationv1.KongPlugin, error) {
+	ref := k8sPlugin.ConfigFrom.SecretValue
+	if ref == (configurationv1.SecretValueFromSource{}) {
+		return k8sPlugin, nil
+	}
+	if k8sPlugin.Config != nil {
+		return k8sPlugin, errors.New("'config' and 'configFrom' " +
+			"cannot be used together")
+	}
+	secret, err := p.store.GetSecret(k8sPlugin.Namespace, ref.Secret)
+	if err != nil {
+		return k8sPlugin, errors.Wrap(err, "fetching configFrom secret")
+	}
+	k8sPlugin.Config, err = SecretToConfiguration(secret, ref.Key)
+	return k8sPlugin, err
+}
+
+// resolveKongClusterPlugin converts k8sPlugin into a KongPlugin, reading
+// its configuration from the Secret referenced in configFrom, if any.
+func (p *Parser) resolveKongClusterPlugin(
+	k8sPlugin configurationv1.KongClusterPlugin) (configurationv1.KongPlugin, error) {
+	plugin := configurationv1.KongPlugin{
+		ObjectMeta:  k8sPlugin.ObjectMeta,
+		ConsumerRef: k8sPlugin.ConsumerRef,
+		Disabled:    k8sPlugin.Disabled,
+		Config:      k8sPlugin.Config,
+		PluginName:  k8sPlugin.PluginName,
+		RunOn:       k8sPlugin.RunOn,
+		Protocols:   k8sPlugin.Protocols,
+	}
+	ref := k8sPlugin.ConfigFrom.SecretValue
+	if ref == (configurationv1.NamespacedSecretValueFromSource{}) {
+		return plugin, nil
+	}
+	if k8sPlugin.Config != nil {
+		return plugin, errors.New("'config' and 'configFrom' " +
+			"cannot be used together")
+	}
+	secret, err := p.store.GetSecret(ref.Namespace, ref.Secret)
+	if err != nil {
+		return plugin, errors.Wrap(err, "fetching configFrom secret")
+	}
+	plugin.Config, err = SecretToConfiguration(secret, ref.Key)
+	return plugin, err
+}
+
+// SecretToConfiguration parses the JSON object stored under key in
+// secret into a plugin configuration.
+func SecretToConfiguration(secret *corev1.Secret,
+	key string) (configurationv1.Configuration, error) {
+	value, ok := secret.Data[key]
+	if !ok {
+		return nil, errors.Errorf("key '%v' not found in secret '%v/%v'",
+			key, secret.Namespace, secret.Name)
+	}
+	var config configurationv1.Configuration
+	err := json.Unmarshal(value, &config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing key '%v' in secret '%v/%v'",
+			key, secret.Namespace, secret.Name)
+	}
+	return config, nil
+}
+
 func kongPluginFromK8SPlugin(k8sPlugin configurationv1.KongPlugin) kong.Plugin {
 	plugin := kong.Plugin{
 		Name:   kong.String(k8sPlugin.PluginName),
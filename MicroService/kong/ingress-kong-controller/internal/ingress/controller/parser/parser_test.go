@@ -109,6 +109,143 @@ func TestGlobalPlugin(t *testing.T) {
 	})
 }
 
+func TestGetPlugin(t *testing.T) {
+	assert := assert.New(t)
+	secrets := []*corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rate-limit-conf",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				"conf":    []byte(`{"redis_host":"redis","redis_password":"s3cr3t"}`),
+				"invalid": []byte(`{`),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shared-conf",
+				Namespace: "kong",
+			},
+			Data: map[string][]byte{
+				"conf": []byte(`{"minute":10}`),
+			},
+		},
+	}
+	kongPlugin := func(name string) *configurationv1.KongPlugin {
+		return &configurationv1.KongPlugin{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			PluginName: "rate-limiting",
+		}
+	}
+	t.Run("configFrom is read from a secret", func(t *testing.T) {
+		plugin := kongPlugin("foo")
+		plugin.ConfigFrom.SecretValue = configurationv1.SecretValueFromSource{
+			Secret: "rate-limit-conf",
+			Key:    "conf",
+		}
+		store, err := store.NewFakeStore(store.FakeObjects{
+			Secrets:     secrets,
+			KongPlugins: []*configurationv1.KongPlugin{plugin},
+		})
+		assert.Nil(err)
+		parser := New(store)
+		result, err := parser.getPlugin("default", "foo")
+		assert.Nil(err)
+		assert.Equal("rate-limiting", *result.Name)
+		assert.Equal(kong.Configuration{
+			"redis_host":     "redis",
+			"redis_password": "s3cr3t",
+		}, result.Config)
+	})
+	t.Run("invalid configFrom references are rejected", func(t *testing.T) {
+		both := kongPlugin("both")
+		both.Config = configurationv1.Configuration{"minute": 10}
+		both.ConfigFrom.SecretValue = configurationv1.SecretValueFromSource{
+			Secret: "rate-limit-conf",
+			Key:    "conf",
+		}
+		missingSecret := kongPlugin("missing-secret")
+		missingSecret.ConfigFrom.SecretValue = configurationv1.SecretValueFromSource{
+			Secret: "does-not-exist",
+			Key:    "conf",
+		}
+		missingKey := kongPlugin("missing-key")
+		missingKey.ConfigFrom.SecretValue = configurationv1.SecretValueFromSource{
+			Secret: "rate-limit-conf",
+			Key:    "does-not-exist",
+		}
+		invalidJSON := kongPlugin("invalid-json")
+		invalidJSON.ConfigFrom.SecretValue = configurationv1.SecretValueFromSource{
+			Secret: "rate-limit-conf",
+			Key:    "invalid",
+		}
+		store, err := store.NewFakeStore(store.FakeObjects{
+			Secrets: secrets,
+			KongPlugins: []*configurationv1.KongPlugin{
+				both, missingSecret, missingKey, invalidJSON,
+			},
+		})
+		assert.Nil(err)
+		parser := New(store)
+		for _, name := range []string{
+			"both", "missing-secret", "missing-key", "invalid-json",
+		} {
+			_, err := parser.getPlugin("default", name)
+			assert.NotNil(err, name)
+		}
+	})
+	t.Run("KongClusterPlugin is used as a fallback", func(t *testing.T) {
+		store, err := store.NewFakeStore(store.FakeObjects{
+			Secrets:     secrets,
+			KongPlugins: []*configurationv1.KongPlugin{kongPlugin("foo")},
+			KongClusterPlugins: []*configurationv1.KongClusterPlugin{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+					},
+					PluginName: "key-auth",
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "shared",
+					},
+					PluginName: "rate-limiting",
+					Protocols:  []string{"http"},
+					ConfigFrom: configurationv1.NamespacedConfigSource{
+						SecretValue: configurationv1.NamespacedSecretValueFromSource{
+							Namespace: "kong",
+							Secret:    "shared-conf",
+							Key:       "conf",
+						},
+					},
+				},
+			},
+		})
+		assert.Nil(err)
+		parser := New(store)
+
+		result, err := parser.getPlugin("default", "foo")
+		assert.Nil(err)
+		assert.Equal("rate-limiting", *result.Name,
+			"expected KongPlugin to take precedence")
+		assert.IsType(&configurationv1.KongPlugin{}, result.K8sObject)
+
+		result, err = parser.getPlugin("default", "shared")
+		assert.Nil(err)
+		assert.Equal("rate-limiting", *result.Name)
+		assert.Equal("http", *result.Protocols[0])
+		assert.Equal(kong.Configuration{"minute": float64(10)}, result.Config)
+		assert.IsType(&configurationv1.KongClusterPlugin{}, result.K8sObject)
+
+		_, err = parser.getPlugin("default", "does-not-exist")
+		assert.NotNil(err)
+	})
+}
+
 func TestServiceClientCertificate(t *testing.T) {
 	assert := assert.New(t)
 	t.Run("valid client-cert annotation", func(t *testing.T) {
@@ -362,6 +499,103 @@ func TestDefaultBackend(t *testing.T) {
 	})
 }
 
+func TestTCPIngress(t *testing.T) {
+	assert := assert.New(t)
+	t.Run("TCPIngress is rendered alongside Ingress", func(t *testing.T) {
+		ingresses := []*networking.Ingress{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ing-with-default-backend",
+					Namespace: "default",
+				},
+				Spec: networking.IngressSpec{
+					Backend: &networking.IngressBackend{
+						ServiceName: "default-svc",
+						ServicePort: intstr.FromInt(80),
+					},
+				},
+			},
+		}
+		tcpIngresses := []*configurationv1.TCPIngress{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tcp-ing",
+					Namespace: "default",
+					Annotations: map[string]string{
+						"plugins.konghq.com": "ip-restriction",
+					},
+				},
+				Spec: configurationv1.TCPIngressSpec{
+					Rules: []configurationv1.IngressRule{
+						{
+							Port: 9000,
+							Backend: configurationv1.IngressBackend{
+								ServiceName: "tcp-svc",
+								ServicePort: 5432,
+							},
+						},
+					},
+				},
+			},
+		}
+		services := []*corev1.Service{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-svc",
+					Namespace: "default",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tcp-svc",
+					Namespace: "default",
+				},
+			},
+		}
+		plugins := []*configurationv1.KongPlugin{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ip-restriction",
+					Namespace: "default",
+				},
+				PluginName: "ip-restriction",
+				Config: configurationv1.Configuration{
+					"whitelist": []interface{}{"10.0.0.0/8"},
+				},
+			},
+		}
+		store, err := store.NewFakeStore(store.FakeObjects{
+			Ingresses:    ingresses,
+			TCPIngresses: tcpIngresses,
+			Services:     services,
+			KongPlugins:  plugins,
+		})
+		assert.Nil(err)
+		parser := New(store)
+		state, err := parser.Build()
+		assert.Nil(err)
+		assert.NotNil(state)
+		assert.Equal(2, len(state.Services),
+			"expected two services to be rendered")
+		assert.Equal(2, len(state.Upstreams),
+			"expected two upstreams to be rendered")
+
+		var tcpService Service
+		for _, s := range state.Services {
+			if *s.Name == "default.tcp-svc.5432" {
+				tcpService = s
+			}
+		}
+		assert.Equal("tcp", *tcpService.Protocol)
+		assert.Equal(1, len(tcpService.Routes))
+		assert.Equal("default.tcp-ing.0.tcp", *tcpService.Routes[0].Name)
+
+		assert.Equal(1, len(state.Plugins),
+			"expected the TCPIngress plugin to be rendered")
+		assert.Equal("default.tcp-ing.0.tcp", *state.Plugins[0].Route.ID)
+	})
+}
+
 func TestParserSecret(t *testing.T) {
 	assert := assert.New(t)
 	t.Run("invalid TLS secret", func(t *testing.T) {
@@ -838,6 +1072,150 @@ func TestParseIngressRules(t *testing.T) {
 	})
 }
 
+func TestParseTCPIngressRules(t *testing.T) {
+	assert := assert.New(t)
+	p := Parser{}
+	tcpIngressList := []*configurationv1.TCPIngress{
+		// 0
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "foo-namespace",
+			},
+			Spec: configurationv1.TCPIngressSpec{
+				Rules: []configurationv1.IngressRule{
+					{
+						Port: 9000,
+						Backend: configurationv1.IngressBackend{
+							ServiceName: "foo-svc",
+							ServicePort: 80,
+						},
+					},
+				},
+			},
+		},
+		// 1
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "bar-namespace",
+			},
+			Spec: configurationv1.TCPIngressSpec{
+				Rules: []configurationv1.IngressRule{
+					{
+						Host: "example.com",
+						Port: 9443,
+						Backend: configurationv1.IngressBackend{
+							ServiceName: "bar-svc",
+							ServicePort: 8000,
+						},
+					},
+				},
+				TLS: []configurationv1.IngressTLS{
+					{
+						Hosts:      []string{"example.com"},
+						SecretName: "sooper-secret",
+					},
+				},
+			},
+		},
+		// 2
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "baz",
+				Namespace: "foo-namespace",
+			},
+			Spec: configurationv1.TCPIngressSpec{
+				Rules: []configurationv1.IngressRule{
+					{
+						Backend: configurationv1.IngressBackend{
+							ServiceName: "foo-svc",
+							ServicePort: 80,
+						},
+					},
+				},
+			},
+		},
+	}
+	emptyInfo := func() *parsedIngressRules {
+		return &parsedIngressRules{
+			ServiceNameToServices: make(map[string]Service),
+			SecretNameToSNIs:      make(map[string][]string),
+		}
+	}
+	t.Run("no TCPIngress leaves info empty", func(t *testing.T) {
+		parsedInfo := emptyInfo()
+		p.parseTCPIngressRules([]*configurationv1.TCPIngress{}, parsedInfo)
+		assert.Equal(emptyInfo(), parsedInfo)
+	})
+	t.Run("port based rule is parsed", func(t *testing.T) {
+		parsedInfo := emptyInfo()
+		p.parseTCPIngressRules([]*configurationv1.TCPIngress{
+			tcpIngressList[0],
+		}, parsedInfo)
+		assert.Equal(1, len(parsedInfo.ServiceNameToServices))
+		svc := parsedInfo.ServiceNameToServices["foo-namespace.foo-svc.80"]
+		assert.Equal("foo-svc.foo-namespace.80.svc", *svc.Host)
+		assert.Equal("tcp", *svc.Protocol)
+		assert.Nil(svc.Path)
+
+		assert.Equal(1, len(svc.Routes))
+		route := svc.Routes[0]
+		assert.True(route.IsTCP)
+		assert.Equal("foo-namespace.foo.0.tcp", *route.Name)
+		assert.Equal(kong.StringSlice("tcp", "tls"), route.Protocols)
+		assert.Equal([]*kong.CIDRPort{{Port: kong.Int(9000)}}, route.Destinations)
+		assert.Nil(route.SNIs)
+		assert.Nil(route.Paths)
+	})
+	t.Run("SNI based rule with TLS is parsed", func(t *testing.T) {
+		parsedInfo := emptyInfo()
+		p.parseTCPIngressRules([]*configurationv1.TCPIngress{
+			tcpIngressList[1],
+		}, parsedInfo)
+		svc := parsedInfo.ServiceNameToServices["bar-namespace.bar-svc.8000"]
+		assert.Equal(1, len(svc.Routes))
+		route := svc.Routes[0]
+		assert.Equal(kong.StringSlice("tls"), route.Protocols)
+		assert.Equal(kong.StringSlice("example.com"), route.SNIs)
+		assert.Equal([]*kong.CIDRPort{{Port: kong.Int(9443)}}, route.Destinations)
+
+		assert.Equal([]string{"example.com"},
+			parsedInfo.SecretNameToSNIs["bar-namespace/sooper-secret"])
+	})
+	t.Run("rule without a port is skipped", func(t *testing.T) {
+		parsedInfo := emptyInfo()
+		p.parseTCPIngressRules([]*configurationv1.TCPIngress{
+			tcpIngressList[2],
+		}, parsedInfo)
+		assert.Equal(0, len(parsedInfo.ServiceNameToServices))
+	})
+	t.Run("service already used by an Ingress is not reused", func(t *testing.T) {
+		parsedInfo, err := p.parseIngressRules([]*networking.Ingress{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "foo-namespace",
+				},
+				Spec: networking.IngressSpec{
+					Backend: &networking.IngressBackend{
+						ServiceName: "foo-svc",
+						ServicePort: intstr.FromInt(80),
+					},
+				},
+			},
+		})
+		assert.Nil(err)
+		p.parseTCPIngressRules([]*configurationv1.TCPIngress{
+			tcpIngressList[0],
+		}, parsedInfo)
+		svc := parsedInfo.ServiceNameToServices["foo-namespace.foo-svc.80"]
+		assert.Equal("http", *svc.Protocol)
+		assert.Equal(1, len(svc.Routes))
+		assert.False(svc.Routes[0].IsTCP)
+	})
+}
+
 func TestOverrideService(t *testing.T) {
 	assert := assert.New(t)
 
@@ -2204,7 +2582,7 @@ func Test_getPluginRelations(t *testing.T) {
 							Consumer: kong.Consumer{
 								Username: kong.String("foo-consumer"),
 							},
-							k8sKongConsumer: configurationv1.KongConsumer{
+							K8sKongConsumer: configurationv1.KongConsumer{
 								ObjectMeta: metav1.ObjectMeta{
 									Namespace: "ns1",
 									Annotations: map[string]string{
@@ -2339,7 +2717,7 @@ func Test_getPluginRelations(t *testing.T) {
 							Consumer: kong.Consumer{
 								Username: kong.String("foo-consumer"),
 							},
-							k8sKongConsumer: configurationv1.KongConsumer{
+							K8sKongConsumer: configurationv1.KongConsumer{
 								ObjectMeta: metav1.ObjectMeta{
 									Namespace: "ns1",
 									Annotations: map[string]string{
@@ -2352,7 +2730,7 @@ func Test_getPluginRelations(t *testing.T) {
 							Consumer: kong.Consumer{
 								Username: kong.String("foo-consumer"),
 							},
-							k8sKongConsumer: configurationv1.KongConsumer{
+							K8sKongConsumer: configurationv1.KongConsumer{
 								ObjectMeta: metav1.ObjectMeta{
 									Namespace: "ns2",
 									Annotations: map[string]string{
@@ -2365,7 +2743,7 @@ func Test_getPluginRelations(t *testing.T) {
 							Consumer: kong.Consumer{
 								Username: kong.String("bar-consumer"),
 							},
-							k8sKongConsumer: configurationv1.KongConsumer{
+							K8sKongConsumer: configurationv1.KongConsumer{
 								ObjectMeta: metav1.ObjectMeta{
 									Namespace: "ns1",
 									Annotations: map[string]string{
@@ -2707,3 +3085,235 @@ func Test_processTLSSections(t *testing.T) {
 		})
 	}
 }
+
+func TestCanary(t *testing.T) {
+	assert := assert.New(t)
+	service := func(name string, anns map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: anns,
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{Port: 80, TargetPort: intstr.FromInt(8080)},
+				},
+			},
+		}
+	}
+	endpoints := func(name string, ips ...string) *corev1.Endpoints {
+		var addresses []corev1.EndpointAddress
+		for _, ip := range ips {
+			addresses = append(addresses, corev1.EndpointAddress{IP: ip})
+		}
+		return &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Subsets: []corev1.EndpointSubset{
+				{
+					Addresses: addresses,
+					Ports: []corev1.EndpointPort{
+						{Port: 8080, Protocol: corev1.ProtocolTCP},
+					},
+				},
+			},
+		}
+	}
+	objects := func(canary configurationv1.KongIngressCanary,
+		anns map[string]string) store.FakeObjects {
+		anns["configuration.konghq.com"] = "canary"
+		anns["plugins.konghq.com"] = "auth"
+		return store.FakeObjects{
+			Ingresses: []*networking.Ingress{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo",
+						Namespace: "default",
+					},
+					Spec: networking.IngressSpec{
+						Rules: []networking.IngressRule{
+							{
+								Host: "example.com",
+								IngressRuleValue: networking.IngressRuleValue{
+									HTTP: &networking.HTTPIngressRuleValue{
+										Paths: []networking.HTTPIngressPath{
+											{
+												Path: "/",
+												Backend: networking.IngressBackend{
+													ServiceName: "foo-svc",
+													ServicePort: intstr.FromInt(80),
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Services: []*corev1.Service{
+				service("foo-svc", anns),
+				service("foo-canary", nil),
+			},
+			Endpoints: []*corev1.Endpoints{
+				endpoints("foo-svc", "10.0.0.1", "10.0.0.2"),
+				endpoints("foo-canary", "10.0.0.3"),
+			},
+			KongIngresses: []*configurationv1.KongIngress{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "canary",
+						Namespace: "default",
+					},
+					Canary: &canary,
+				},
+			},
+			KongPlugins: []*configurationv1.KongPlugin{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "auth",
+						Namespace: "default",
+					},
+					PluginName: "key-auth",
+				},
+			},
+		}
+	}
+	targetWeights := func(state *KongState) map[string]int {
+		res := make(map[string]int)
+		for _, u := range state.Upstreams {
+			if *u.Name != "foo-svc.default.80.svc" {
+				continue
+			}
+			for _, t := range u.Targets {
+				weight := -1
+				if t.Weight != nil {
+					weight = *t.Weight
+				}
+				res[*t.Target.Target] = weight
+			}
+		}
+		return res
+	}
+	backend := configurationv1.IngressBackend{
+		ServiceName: "foo-canary",
+		ServicePort: 80,
+	}
+
+	t.Run("requests are split by weight", func(t *testing.T) {
+		store, err := store.NewFakeStore(objects(
+			configurationv1.KongIngressCanary{Backend: backend, Weight: 20},
+			map[string]string{}))
+		assert.Nil(err)
+		parser := New(store)
+		state, err := parser.Build()
+		assert.Nil(err)
+		assert.Equal(map[string]int{
+			"10.0.0.1:8080": 400,
+			"10.0.0.2:8080": 400,
+			"10.0.0.3:8080": 200,
+		}, targetWeights(state))
+		assert.Len(state.Services, 1,
+			"expected no canary service without a canary header")
+	})
+	t.Run("annotation overrides the weight", func(t *testing.T) {
+		store, err := store.NewFakeStore(objects(
+			configurationv1.KongIngressCanary{Backend: backend, Weight: 20},
+			map[string]string{
+				"configuration.konghq.com/canary-weight": "100",
+			}))
+		assert.Nil(err)
+		parser := New(store)
+		state, err := parser.Build()
+		assert.Nil(err)
+		assert.Equal(map[string]int{"10.0.0.3:8080": 1000},
+			targetWeights(state))
+	})
+	t.Run("invalid or zero weights leave targets untouched", func(t *testing.T) {
+		s, err := store.NewFakeStore(objects(
+			configurationv1.KongIngressCanary{Backend: backend, Weight: 20},
+			map[string]string{
+				"configuration.konghq.com/canary-weight": "150",
+			}))
+		assert.Nil(err)
+		parser := New(s)
+		state, err := parser.Build()
+		assert.Nil(err)
+		assert.Equal(400, targetWeights(state)["10.0.0.1:8080"],
+			"expected the KongIngress weight to be used")
+
+		otherStore, err := store.NewFakeStore(objects(
+			configurationv1.KongIngressCanary{Backend: backend},
+			map[string]string{}))
+		assert.Nil(err)
+		parser = New(otherStore)
+		state, err = parser.Build()
+		assert.Nil(err)
+		assert.Equal(map[string]int{
+			"10.0.0.1:8080": -1,
+			"10.0.0.2:8080": -1,
+		}, targetWeights(state))
+	})
+	t.Run("requests carrying the header are routed to the canary",
+		func(t *testing.T) {
+			store, err := store.NewFakeStore(objects(
+				configurationv1.KongIngressCanary{
+					Backend: backend,
+					Header: &configurationv1.CanaryHeader{
+						Name:   "x-canary",
+						Values: []string{"always"},
+					},
+				},
+				map[string]string{}))
+			assert.Nil(err)
+			parser := New(store)
+			state, err := parser.Build()
+			assert.Nil(err)
+			assert.Len(state.Services, 2)
+
+			var canaryService Service
+			for _, s := range state.Services {
+				if *s.Name == "default.foo-canary.80" {
+					canaryService = s
+				}
+			}
+			assert.Len(canaryService.Routes, 1)
+			route := canaryService.Routes[0].Route
+			assert.Equal("default.foo.00.canary", *route.Name)
+			assert.Equal(kong.StringSlice("example.com"), route.Hosts)
+			assert.Equal(map[string][]string{"x-canary": {"always"}},
+				route.Headers)
+			assert.Equal("foo-canary", canaryService.K8sService.Name)
+
+			var routeIDs []string
+			for _, p := range state.Plugins {
+				if p.Route != nil {
+					routeIDs = append(routeIDs, *p.Route.ID)
+				}
+			}
+			assert.Equal([]string{"default.foo.00.canary"}, routeIDs,
+				"expected the plugins of the stable service on the canary route")
+		})
+}
+
+func TestSplitTargets(t *testing.T) {
+	assert := assert.New(t)
+	target := func(address string) Target {
+		return Target{Target: kong.Target{Target: kong.String(address)}}
+	}
+	stable := []Target{target("10.0.0.1:80"), target("10.0.0.2:80"),
+		target("10.0.0.3:80")}
+	canary := []Target{target("10.0.0.4:80"), target("10.0.0.1:80")}
+
+	res := splitTargets(stable, canary, 10)
+	assert.Len(res, 4, "expected targets shared with the canary once")
+	for _, t := range res[:3] {
+		assert.Equal(300, *t.Weight)
+	}
+	assert.Equal(50, *res[3].Weight)
+	assert.Nil(stable[0].Weight, "expected the targets to be copied")
+}
@@ -14,19 +14,25 @@ func keyFunc(obj interface{}) (string, error) {
 	v := reflect.Indirect(reflect.ValueOf(obj))
 	name := v.FieldByName("Name")
 	namespace := v.FieldByName("Namespace")
+	// cluster-scoped objects are keyed by name only
+	if namespace.String() == "" {
+		return name.String(), nil
+	}
 	return namespace.String() + "/" + name.String(), nil
 }
 
 // FakeObjects can be used to populate a fake Store.
 type FakeObjects struct {
-	Ingresses       []*networking.Ingress
-	Services        []*apiv1.Service
-	Endpoints       []*apiv1.Endpoints
-	Secrets         []*apiv1.Secret
-	KongPlugins     []*configurationv1.KongPlugin
-	KongIngresses   []*configurationv1.KongIngress
-	KongConsumers   []*configurationv1.KongConsumer
-	KongCredentials []*configurationv1.KongCredential
+	Ingresses          []*networking.Ingress
+	TCPIngresses       []*configurationv1.TCPIngress
+	Services           []*apiv1.Service
+	Endpoints          []*apiv1.Endpoints
+	Secrets            []*apiv1.Secret
+	KongPlugins        []*configurationv1.KongPlugin
+	KongClusterPlugins []*configurationv1.KongClusterPlugin
+	KongIngresses      []*configurationv1.KongIngress
+	KongConsumers      []*configurationv1.KongConsumer
+	KongCredentials    []*configurationv1.KongCredential
 }
 
 // NewFakeStore creates a store backed by the objects passed in as arguments.
@@ -41,6 +47,13 @@ func NewFakeStore(
 			return nil, err
 		}
 	}
+	tcpIngressStore := cache.NewStore(keyFunc)
+	for _, ingress := range objects.TCPIngresses {
+		err := tcpIngressStore.Add(ingress)
+		if err != nil {
+			return nil, err
+		}
+	}
 	serviceStore := cache.NewStore(keyFunc)
 	for _, s := range objects.Services {
 		err := serviceStore.Add(s)
@@ -90,14 +103,23 @@ func NewFakeStore(
 			return nil, err
 		}
 	}
+	kongClusterPluginsStore := cache.NewStore(keyFunc)
+	for _, p := range objects.KongClusterPlugins {
+		err := kongClusterPluginsStore.Add(p)
+		if err != nil {
+			return nil, err
+		}
+	}
 	s = Store{
 		stores: CacheStores{
-			Ingress:  ingressStore,
-			Service:  serviceStore,
-			Endpoint: endpointStore,
-			Secret:   secretsStore,
+			Ingress:    ingressStore,
+			TCPIngress: tcpIngressStore,
+			Service:    serviceStore,
+			Endpoint:   endpointStore,
+			Secret:     secretsStore,
 
 			Plugin:        kongPluginsStore,
+			ClusterPlugin: kongClusterPluginsStore,
 			Consumer:      consumerStore,
 			Credential:    kongCredentialsStore,
 			Configuration: kongIngressStore,
@@ -61,6 +61,16 @@ func Test_keyFunc(t *testing.T) {
 				},
 			},
 		},
+		{
+			want: "foo",
+			args: args{
+				obj: configurationv1.KongClusterPlugin{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -149,6 +159,54 @@ func TestFakeStoreIngress(t *testing.T) {
 	assert.Len(store.ListIngresses(), 1)
 }
 
+func TestFakeStoreTCPIngress(t *testing.T) {
+	assert := assert.New(t)
+
+	tcpIngresses := []*configurationv1.TCPIngress{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: configurationv1.TCPIngressSpec{
+				Rules: []configurationv1.IngressRule{
+					{
+						Port: 9000,
+						Backend: configurationv1.IngressBackend{
+							ServiceName: "foo-svc",
+							ServicePort: 80,
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "default",
+				Annotations: map[string]string{
+					"kubernetes.io/ingress.class": "not-kong",
+				},
+			},
+			Spec: configurationv1.TCPIngressSpec{
+				Rules: []configurationv1.IngressRule{
+					{
+						Port: 9001,
+						Backend: configurationv1.IngressBackend{
+							ServiceName: "bar-svc",
+							ServicePort: 80,
+						},
+					},
+				},
+			},
+		},
+	}
+	store, err := NewFakeStore(FakeObjects{TCPIngresses: tcpIngresses})
+	assert.Nil(err)
+	assert.NotNil(store)
+	assert.Len(store.ListTCPIngresses(), 1)
+}
+
 func TestFakeStoreService(t *testing.T) {
 	assert := assert.New(t)
 
@@ -277,6 +335,29 @@ func TestFakeStorePlugins(t *testing.T) {
 	assert.Nil(plugin)
 }
 
+func TestFakeStoreClusterPlugins(t *testing.T) {
+	assert := assert.New(t)
+
+	plugins := []*configurationv1.KongClusterPlugin{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+			},
+		},
+	}
+	store, err := NewFakeStore(FakeObjects{KongClusterPlugins: plugins})
+	assert.Nil(err)
+	assert.NotNil(store)
+
+	plugin, err := store.GetKongClusterPlugin("foo")
+	assert.NotNil(plugin)
+	assert.Nil(err)
+
+	plugin, err = store.GetKongClusterPlugin("does-not-exist")
+	assert.NotNil(err)
+	assert.Nil(plugin)
+}
+
 func TestFakeStoreCredentials(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/golang/glog"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/internal/apis/configuration/v1"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+var manifestCodecs serializer.CodecFactory
+
+func init() {
+	manifestScheme := runtime.NewScheme()
+	apiv1.AddToScheme(manifestScheme)
+	extensions.AddToScheme(manifestScheme)
+	networking.AddToScheme(manifestScheme)
+	configurationv1.AddToScheme(manifestScheme)
+	manifestCodecs = serializer.NewCodecFactory(manifestScheme)
+}
+
+// FakeObjectsFromManifests decodes the YAML or JSON documents read
+// from r into FakeObjects, which can be used to build a Store without
+// access to a cluster.
+// Documents holding kinds that are not read by the controller are
+// skipped. Namespaced objects without a namespace are placed in the
+// default namespace, as kubectl would.
+func FakeObjectsFromManifests(r io.Reader) (FakeObjects, error) {
+	var objects FakeObjects
+	reader := yaml.NewYAMLReader(bufio.NewReader(r))
+	deserializer := manifestCodecs.UniversalDeserializer()
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return objects, errors.Wrap(err, "reading manifest")
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, gvk, err := deserializer.Decode(doc, nil, nil)
+		if err != nil {
+			if runtime.IsNotRegisteredError(err) {
+				glog.V(2).Infof("skipping manifest: %v", err)
+				continue
+			}
+			return objects, errors.Wrap(err, "decoding manifest")
+		}
+
+		switch o := obj.(type) {
+		case *networking.Ingress:
+			defaultNamespace(&o.ObjectMeta)
+			objects.Ingresses = append(objects.Ingresses, o)
+		case *extensions.Ingress:
+			defaultNamespace(&o.ObjectMeta)
+			ing := networkingIngressV1Beta1(o)
+			if ing == nil {
+				return objects, errors.Errorf("converting Ingress '%v/%v'",
+					o.Namespace, o.Name)
+			}
+			objects.Ingresses = append(objects.Ingresses, ing)
+		case *configurationv1.TCPIngress:
+			defaultNamespace(&o.ObjectMeta)
+			objects.TCPIngresses = append(objects.TCPIngresses, o)
+		case *apiv1.Service:
+			defaultNamespace(&o.ObjectMeta)
+			objects.Services = append(objects.Services, o)
+		case *apiv1.Endpoints:
+			defaultNamespace(&o.ObjectMeta)
+			objects.Endpoints = append(objects.Endpoints, o)
+		case *apiv1.Secret:
+			defaultNamespace(&o.ObjectMeta)
+			// the API server merges stringData into data on writes
+			for k, v := range o.StringData {
+				if o.Data == nil {
+					o.Data = make(map[string][]byte)
+				}
+				o.Data[k] = []byte(v)
+			}
+			objects.Secrets = append(objects.Secrets, o)
+		case *configurationv1.KongPlugin:
+			defaultNamespace(&o.ObjectMeta)
+			objects.KongPlugins = append(objects.KongPlugins, o)
+		case *configurationv1.KongClusterPlugin:
+			objects.KongClusterPlugins = append(objects.KongClusterPlugins, o)
+		case *configurationv1.KongIngress:
+			defaultNamespace(&o.ObjectMeta)
+			objects.KongIngresses = append(objects.KongIngresses, o)
+		case *configurationv1.KongConsumer:
+			defaultNamespace(&o.ObjectMeta)
+			objects.KongConsumers = append(objects.KongConsumers, o)
+		case *configurationv1.KongCredential:
+			defaultNamespace(&o.ObjectMeta)
+			objects.KongCredentials = append(objects.KongCredentials, o)
+		default:
+			glog.V(2).Infof("skipping manifest of kind %v", gvk.Kind)
+		}
+	}
+	return objects, nil
+}
+
+func defaultNamespace(objectMeta *metav1.ObjectMeta) {
+	if objectMeta.Namespace == "" {
+		objectMeta.Namespace = apiv1.NamespaceDefault
+	}
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeObjectsFromManifests(t *testing.T) {
+	assert := assert.New(t)
+	t.Run("supported kinds are decoded", func(t *testing.T) {
+		manifests := `
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: foo
+  annotations:
+    plugins.konghq.com: rate-limit
+spec:
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - path: /
+        backend:
+          serviceName: foo-svc
+          servicePort: 80
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo-svc
+  namespace: team-a
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: rate-limit-conf
+stringData:
+  conf: '{"minute":10}'
+---
+apiVersion: configuration.konghq.com/v1
+kind: KongPlugin
+metadata:
+  name: rate-limit
+plugin: rate-limiting
+configFrom:
+  secretKeyRef:
+    name: rate-limit-conf
+    key: conf
+---
+apiVersion: configuration.konghq.com/v1
+kind: KongClusterPlugin
+metadata:
+  name: correlation-id
+plugin: correlation-id
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+`
+		objects, err := FakeObjectsFromManifests(strings.NewReader(manifests))
+		assert.Nil(err)
+
+		assert.Len(objects.Ingresses, 1)
+		assert.Equal("default", objects.Ingresses[0].Namespace)
+		assert.Equal("foo-svc",
+			objects.Ingresses[0].Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName)
+
+		assert.Len(objects.Services, 1)
+		assert.Equal("team-a", objects.Services[0].Namespace)
+
+		assert.Len(objects.Secrets, 1)
+		assert.Equal([]byte(`{"minute":10}`), objects.Secrets[0].Data["conf"])
+
+		assert.Len(objects.KongPlugins, 1)
+		assert.Equal("rate-limiting", objects.KongPlugins[0].PluginName)
+		assert.Equal("rate-limit-conf",
+			objects.KongPlugins[0].ConfigFrom.SecretValue.Secret)
+
+		assert.Len(objects.KongClusterPlugins, 1)
+		assert.Equal("", objects.KongClusterPlugins[0].Namespace)
+
+		store, err := NewFakeStore(objects)
+		assert.Nil(err)
+		assert.Len(store.ListIngresses(), 1)
+		plugin, err := store.GetKongClusterPlugin("correlation-id")
+		assert.Nil(err)
+		assert.NotNil(plugin)
+	})
+	t.Run("invalid manifests return an error", func(t *testing.T) {
+		_, err := FakeObjectsFromManifests(strings.NewReader(`
+apiVersion: v1
+kind: Service
+spec: [
+`))
+		assert.NotNil(err)
+	})
+}
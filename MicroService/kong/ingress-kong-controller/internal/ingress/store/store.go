@@ -39,9 +39,11 @@ type Storer interface {
 	GetEndpointsForService(namespace, name string) (*apiv1.Endpoints, error)
 	GetKongIngress(namespace, name string) (*configurationv1.KongIngress, error)
 	GetKongPlugin(namespace, name string) (*configurationv1.KongPlugin, error)
+	GetKongClusterPlugin(name string) (*configurationv1.KongClusterPlugin, error)
 	GetKongConsumer(namespace, name string) (*configurationv1.KongConsumer, error)
 
 	ListIngresses() []*networking.Ingress
+	ListTCPIngresses() []*configurationv1.TCPIngress
 	ListGlobalKongPlugins() ([]*configurationv1.KongPlugin, error)
 	ListKongConsumers() []*configurationv1.KongConsumer
 	ListKongCredentials() []*configurationv1.KongCredential
@@ -60,12 +62,14 @@ type Store struct {
 // CacheStores stores cache.Store for all Kinds of k8s objects that
 // the Ingress Controller reads.
 type CacheStores struct {
-	Ingress  cache.Store
-	Service  cache.Store
-	Secret   cache.Store
-	Endpoint cache.Store
+	Ingress    cache.Store
+	TCPIngress cache.Store
+	Service    cache.Store
+	Secret     cache.Store
+	Endpoint   cache.Store
 
 	Plugin        cache.Store
+	ClusterPlugin cache.Store
 	Consumer      cache.Store
 	Credential    cache.Store
 	Configuration cache.Store
@@ -121,6 +125,20 @@ func (s Store) ListIngresses() []*networking.Ingress {
 	return ingresses
 }
 
+// ListTCPIngresses returns the list of TCPIngresses filtered by the
+// ingress.class annotation.
+func (s Store) ListTCPIngresses() []*configurationv1.TCPIngress {
+	var ingresses []*configurationv1.TCPIngress
+	for _, item := range s.stores.TCPIngress.List() {
+		ing, ok := item.(*configurationv1.TCPIngress)
+		if ok && s.isValidIngresClass(&ing.ObjectMeta) {
+			ingresses = append(ingresses, ing)
+		}
+	}
+
+	return ingresses
+}
+
 // GetEndpointsForService returns the internal endpoints for service
 // 'namespace/name' inside k8s.
 func (s Store) GetEndpointsForService(namespace, name string) (*apiv1.Endpoints, error) {
@@ -148,6 +166,18 @@ func (s Store) GetKongPlugin(namespace, name string) (*configurationv1.KongPlugi
 	return p.(*configurationv1.KongPlugin), nil
 }
 
+// GetKongClusterPlugin returns the 'name' KongClusterPlugin resource.
+func (s Store) GetKongClusterPlugin(name string) (*configurationv1.KongClusterPlugin, error) {
+	p, exists, err := s.stores.ClusterPlugin.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("KongClusterPlugin %v was not found", name)
+	}
+	return p.(*configurationv1.KongClusterPlugin), nil
+}
+
 // GetKongIngress returns the 'name' KongIngress resource in namespace.
 func (s Store) GetKongIngress(namespace, name string) (*configurationv1.KongIngress, error) {
 	key := fmt.Sprintf("%v/%v", namespace, name)
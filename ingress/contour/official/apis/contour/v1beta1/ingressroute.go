@@ -24,6 +24,9 @@ type IngressRouteSpec struct {
 	VirtualHost *VirtualHost `json:"virtualhost,omitempty"`
 	// Routes are the ingress routes
 	Routes []Route `json:"routes"`
+	// TCPProxy holds TCP proxy information. If present, connections to the
+	// virtual host are proxied to the services at the TCP level.
+	TCPProxy *TCPProxy `json:"tcpproxy,omitempty"`
 }
 
 // VirtualHost appears at most once. If it is present, the object is considered
@@ -36,22 +39,73 @@ type VirtualHost struct {
 	// are described in fqdn, the tls.secretName secret must contain a
 	// matching certificate
 	TLS *TLS `json:"tls,omitempty"`
+	// If present, requests to the virtual host are checked by an external
+	// authorization service. Requires tls.
+	Authorization *AuthorizationServer `json:"authorization,omitempty"`
+	// CORSPolicy allows cross origin requests to the virtual host
+	CORSPolicy *CORSPolicy `json:"corsPolicy,omitempty"`
+}
+
+// CORSPolicy allows cross origin requests to a virtual host.
+type CORSPolicy struct {
+	// AllowOrigin lists the origins allowed to make requests,
+	// "*" allows any origin
+	AllowOrigin []string `json:"allowOrigin"`
+	// AllowMethods lists the methods allowed in requests
+	AllowMethods []string `json:"allowMethods"`
+	// AllowHeaders lists the headers allowed in requests
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+	// ExposeHeaders lists the response headers browsers can access
+	ExposeHeaders []string `json:"exposeHeaders,omitempty"`
+	// MaxAge is how long the response to a preflight request can be
+	// cached, for example "10m". Defaults to Envoy's default behavior.
+	MaxAge string `json:"maxAge,omitempty"`
+	// AllowCredentials allows requests with credentials
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
+}
+
+// AuthorizationServer describes an external authorization service which
+// implements the Envoy external authorization gRPC API.
+type AuthorizationServer struct {
+	// Name is the name of the Kubernetes service in the current namespace
+	Name string `json:"name"`
+	// Port (defined as Integer) to proxy traffic to since a service can have multiple defined
+	Port int `json:"port"`
+	// If FailOpen is true, requests are allowed when the authorization
+	// service cannot be reached or fails to respond.
+	FailOpen bool `json:"failOpen,omitempty"`
+	// ResponseTimeout is how long to wait for the authorization service to
+	// respond, for example "500ms". Defaults to Envoy's default of 200ms.
+	ResponseTimeout string `json:"responseTimeout,omitempty"`
 }
 
 // TLS describes tls properties. The CNI names that will be matched on
 // are described in fqdn, the tls.secretName secret must contain a
 // matching certificate
 type TLS struct {
-	// required, the name of a secret in the current namespace
-	SecretName string `json:"secretName"`
+	// the name of a secret in the current namespace, required unless Passthrough is set
+	SecretName string `json:"secretName,omitempty"`
 	// Minimum TLS version this vhost should negotiate
 	MinimumProtocolVersion string `json:"minimumProtocolVersion,omitempty"`
+	// If Passthrough is set to true, the TLS connection is not terminated
+	// but forwarded to the services of the tcpproxy. SecretName must be empty.
+	Passthrough bool `json:"passthrough,omitempty"`
+}
+
+// TCPProxy contains the set of services to proxy TCP connections.
+type TCPProxy struct {
+	// Services are the services to proxy traffic
+	Services []Service `json:"services"`
 }
 
 // Route contains the set of routes for a virtual host
 type Route struct {
 	// Match defines the prefix match
 	Match string `json:"match"`
+	// Conditions are additional match conditions, a request must satisfy all of them.
+	// Header and query parameter conditions of a delegating route apply to all routes
+	// of the delegated IngressRoute.
+	Conditions []MatchCondition `json:"conditions,omitempty"`
 	// Services are the services to proxy traffic
 	Services []Service `json:"services,omitempty"`
 	// Delegate specifies that this route should be delegated to another IngressRoute
@@ -63,6 +117,65 @@ type Route struct {
 	PermitInsecure bool `json:"permitInsecure,omitempty"`
 	// Indicates that during forwarding, the matched prefix (or path) should be swapped with this value
 	PrefixRewrite string `json:"prefixRewrite,omitempty"`
+	// HostRewrite replaces the Host header of requests forwarded to the services
+	HostRewrite string `json:"hostRewrite,omitempty"`
+	// RequestHeadersPolicy manages the headers of requests to this route
+	RequestHeadersPolicy *HeadersPolicy `json:"requestHeadersPolicy,omitempty"`
+	// ResponseHeadersPolicy manages the headers of responses from this route
+	ResponseHeadersPolicy *HeadersPolicy `json:"responseHeadersPolicy,omitempty"`
+}
+
+// HeadersPolicy defines how headers are managed during forwarding.
+type HeadersPolicy struct {
+	// Set adds these headers, replacing any existing values
+	Set []HeaderValue `json:"set,omitempty"`
+	// Remove removes these headers
+	Remove []string `json:"remove,omitempty"`
+}
+
+// HeaderValue is a header name and value.
+type HeaderValue struct {
+	// Name is the name of the header
+	Name string `json:"name"`
+	// Value is the value of the header
+	Value string `json:"value"`
+}
+
+// MatchCondition defines a single match condition of a route.
+// Exactly one of its fields must be set.
+type MatchCondition struct {
+	// Path replaces the prefix match with an exact match of the request path.
+	// It must start with the route's prefix.
+	Path string `json:"path,omitempty"`
+	// Regex replaces the prefix match with a regular expression match of the request path.
+	// It must start with the route's prefix, the remainder is only matched after the prefix.
+	Regex string `json:"regex,omitempty"`
+	// Method matches the request method
+	Method string `json:"method,omitempty"`
+	// Header matches a request header
+	Header *HeaderCondition `json:"header,omitempty"`
+	// QueryParameter matches a query parameter of the request
+	QueryParameter *QueryParameterCondition `json:"queryParameter,omitempty"`
+}
+
+// HeaderCondition matches a request header. If neither Exact nor Contains
+// is set, the header only has to be present.
+type HeaderCondition struct {
+	// Name of the header
+	Name string `json:"name"`
+	// Exact value of the header
+	Exact string `json:"exact,omitempty"`
+	// Contains matches headers which value contains this string
+	Contains string `json:"contains,omitempty"`
+}
+
+// QueryParameterCondition matches a query parameter of the request. If Exact
+// is not set, the parameter only has to be present.
+type QueryParameterCondition struct {
+	// Name of the query parameter
+	Name string `json:"name"`
+	// Exact value of the query parameter
+	Exact string `json:"exact,omitempty"`
 }
 
 // Service defines an upstream to proxy traffic to
@@ -78,6 +191,24 @@ type Service struct {
 	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
 	// LB Algorithm to apply (see https://github.com/heptio/contour/blob/master/design/ingressroute-design.md#load-balancing)
 	Strategy string `json:"strategy,omitempty"`
+	// RequestHeadersPolicy manages the headers of requests forwarded to this service
+	RequestHeadersPolicy *HeadersPolicy `json:"requestHeadersPolicy,omitempty"`
+	// ResponseHeadersPolicy manages the headers of responses from this service
+	ResponseHeadersPolicy *HeadersPolicy `json:"responseHeadersPolicy,omitempty"`
+	// UpstreamValidation verifies the certificate presented by the service.
+	// The service must use the tls or h2 upstream protocol.
+	UpstreamValidation *UpstreamValidation `json:"validation,omitempty"`
+}
+
+// UpstreamValidation verifies the certificate of an upstream service
+type UpstreamValidation struct {
+	// CACertificate is the name of a secret in the namespace of the IngressRoute
+	// holding the CA certificate, under the ca.crt key, which signs the certificate
+	// of the service
+	CACertificate string `json:"caSecret"`
+	// SubjectName is the subject alternative name the certificate must present.
+	// It is also sent as the SNI of connections to the service
+	SubjectName string `json:"subjectName"`
 }
 
 // Delegate allows for delegating VHosts to other IngressRoutes
@@ -110,6 +241,28 @@ type HealthCheck struct {
 type Status struct {
 	CurrentStatus string `json:"currentStatus"`
 	Description   string `json:"description"`
+	// Conditions lists each observation which contributed to
+	// CurrentStatus, for example every root which delegates to this
+	// IngressRoute, or every reason it was rejected.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// Condition records a single observation Contour made while
+// processing an IngressRoute.
+type Condition struct {
+	// Status is the status this observation contributes:
+	// valid, invalid or orphaned.
+	Status string `json:"status"`
+	// Reason is a machine readable explanation of Status,
+	// for example DuplicateFQDN or DelegationCycle.
+	Reason string `json:"reason"`
+	// Message is a human readable description of Status.
+	Message string `json:"message,omitempty"`
+	// VirtualHost is the fqdn of the root IngressRoute through
+	// which this observation was made, if any.
+	VirtualHost string `json:"virtualhost,omitempty"`
+	// LastTransitionTime is the time this condition was first observed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 // +genclient
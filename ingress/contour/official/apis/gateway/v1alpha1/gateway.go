@@ -0,0 +1,369 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayClass describes a class of Gateways and the controller which implements them
+type GatewayClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   GatewayClassSpec   `json:"spec"`
+	Status GatewayClassStatus `json:"status,omitempty"`
+}
+
+// GatewayClassSpec specifies the controller of a GatewayClass
+type GatewayClassSpec struct {
+	// Controller is the name of the controller managing Gateways of this class
+	Controller string `json:"controller"`
+	// ParametersRef refers to a resource containing controller specific configuration
+	ParametersRef *LocalObjectReference `json:"parametersRef,omitempty"`
+}
+
+// GatewayClassStatus is the status of a GatewayClass
+type GatewayClassStatus struct {
+	// Conditions describe the current state of the GatewayClass
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayClassList is a list of GatewayClasses
+type GatewayClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []GatewayClass `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Gateway describes a set of listeners which accept traffic for Routes
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   GatewaySpec   `json:"spec"`
+	Status GatewayStatus `json:"status,omitempty"`
+}
+
+// GatewaySpec specifies the class and listeners of a Gateway
+type GatewaySpec struct {
+	// GatewayClassName is the name of the GatewayClass of this Gateway
+	GatewayClassName string `json:"gatewayClassName"`
+	// Listeners are the logical endpoints bound to the addresses of this Gateway
+	Listeners []Listener `json:"listeners"`
+}
+
+// Listener accepts traffic for a port, protocol and optional hostname
+type Listener struct {
+	// Hostname, if set, limits the listener to requests for this hostname
+	Hostname *Hostname `json:"hostname,omitempty"`
+	// Port is the network port of the listener
+	Port PortNumber `json:"port"`
+	// Protocol is the network protocol of the listener
+	Protocol ProtocolType `json:"protocol"`
+	// TLS is the TLS configuration of HTTPS and TLS listeners
+	TLS *GatewayTLSConfig `json:"tls,omitempty"`
+	// Routes selects the Routes bound to the listener
+	Routes RouteBindingSelector `json:"routes"`
+}
+
+// Hostname is a fully qualified domain name, optionally prefixed by a wildcard label
+type Hostname string
+
+// PortNumber is a network port
+type PortNumber int32
+
+// ProtocolType is the protocol of a Listener
+type ProtocolType string
+
+const (
+	HTTPProtocolType  ProtocolType = "HTTP"
+	HTTPSProtocolType ProtocolType = "HTTPS"
+	TLSProtocolType   ProtocolType = "TLS"
+	TCPProtocolType   ProtocolType = "TCP"
+	UDPProtocolType   ProtocolType = "UDP"
+)
+
+// TLSModeType is the TLS behaviour of a Listener
+type TLSModeType string
+
+const (
+	// TLSModeTerminate terminates TLS at the Gateway
+	TLSModeTerminate TLSModeType = "Terminate"
+	// TLSModePassthrough forwards TLS connections to the backend
+	TLSModePassthrough TLSModeType = "Passthrough"
+)
+
+// GatewayTLSConfig is the TLS configuration of a Listener
+type GatewayTLSConfig struct {
+	// Mode defaults to Terminate
+	Mode *TLSModeType `json:"mode,omitempty"`
+	// CertificateRef refers to the Secret holding the certificate and key used to terminate TLS
+	CertificateRef *LocalObjectReference `json:"certificateRef,omitempty"`
+}
+
+// LocalObjectReference refers to an object in the same namespace
+type LocalObjectReference struct {
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+}
+
+// RouteBindingSelector selects the Routes bound to a Listener
+type RouteBindingSelector struct {
+	// Namespaces selects the namespaces of the Routes
+	Namespaces RouteNamespaces `json:"namespaces,omitempty"`
+	// Selector selects the Routes by label
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Group is the API group of the Routes, defaults to networking.x-k8s.io
+	Group string `json:"group,omitempty"`
+	// Kind is the kind of the Routes, defaults to HTTPRoute, or TLSRoute for TLS listeners
+	Kind string `json:"kind,omitempty"`
+}
+
+// RouteSelectType is the source of the namespaces of Routes
+type RouteSelectType string
+
+const (
+	RouteSelectAll      RouteSelectType = "All"
+	RouteSelectSelector RouteSelectType = "Selector"
+	RouteSelectSame     RouteSelectType = "Same"
+)
+
+// RouteNamespaces selects the namespaces of the Routes bound to a Listener
+type RouteNamespaces struct {
+	// From defaults to Same, the namespace of the Gateway
+	From RouteSelectType `json:"from,omitempty"`
+	// Selector selects the namespaces by label if From is Selector
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// GatewayStatus is the status of a Gateway
+type GatewayStatus struct {
+	// Conditions describe the current state of the Gateway
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Listeners are the statuses of the listeners of the Gateway
+	Listeners []ListenerStatus `json:"listeners,omitempty"`
+}
+
+// ListenerStatus is the status of a Listener
+type ListenerStatus struct {
+	Port       PortNumber   `json:"port"`
+	Protocol   ProtocolType `json:"protocol"`
+	Hostname   *Hostname    `json:"hostname,omitempty"`
+	Conditions []Condition  `json:"conditions"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayList is a list of Gateways
+type GatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Gateway `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRoute routes HTTP requests to services
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   HTTPRouteSpec `json:"spec"`
+	Status RouteStatus   `json:"status,omitempty"`
+}
+
+// HTTPRouteSpec specifies the hostnames and rules of an HTTPRoute
+type HTTPRouteSpec struct {
+	// Gateways specifies the Gateways which may bind this route
+	Gateways RouteGateways `json:"gateways,omitempty"`
+	// Hostnames match the Host header of requests, all hostnames
+	// of the Listener match if empty
+	Hostnames []Hostname `json:"hostnames,omitempty"`
+	// Rules are the match conditions and destinations of requests
+	Rules []HTTPRouteRule `json:"rules"`
+}
+
+// HTTPRouteRule forwards requests matching any of its matches
+type HTTPRouteRule struct {
+	// Matches default to a prefix match of /
+	Matches []HTTPRouteMatch `json:"matches,omitempty"`
+	// ForwardTo are the services which receive the requests
+	ForwardTo []RouteForwardTo `json:"forwardTo"`
+}
+
+// HTTPRouteMatch matches the path and headers of a request
+type HTTPRouteMatch struct {
+	Path    HTTPPathMatch    `json:"path,omitempty"`
+	Headers *HTTPHeaderMatch `json:"headers,omitempty"`
+}
+
+// PathMatchType is the type of an HTTPPathMatch
+type PathMatchType string
+
+const (
+	PathMatchExact             PathMatchType = "Exact"
+	PathMatchPrefix            PathMatchType = "Prefix"
+	PathMatchRegularExpression PathMatchType = "RegularExpression"
+)
+
+// HTTPPathMatch matches the path of a request, defaults to a prefix match of /
+type HTTPPathMatch struct {
+	Type  PathMatchType `json:"type,omitempty"`
+	Value string        `json:"value,omitempty"`
+}
+
+// HeaderMatchType is the type of an HTTPHeaderMatch
+type HeaderMatchType string
+
+const (
+	HeaderMatchExact HeaderMatchType = "Exact"
+)
+
+// HTTPHeaderMatch matches the values of request headers, all values must match
+type HTTPHeaderMatch struct {
+	// Type defaults to Exact
+	Type   HeaderMatchType   `json:"type,omitempty"`
+	Values map[string]string `json:"values"`
+}
+
+// RouteForwardTo is a destination of a route
+type RouteForwardTo struct {
+	// ServiceName is the name of a service in the namespace of the route
+	ServiceName string `json:"serviceName"`
+	// Port is the port of the service
+	Port PortNumber `json:"port"`
+	// Weight is the proportion of traffic forwarded to the service, defaults to 1
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// GatewayAllowType specifies which Gateways may bind a route
+type GatewayAllowType string
+
+const (
+	GatewayAllowAll           GatewayAllowType = "All"
+	GatewayAllowFromList      GatewayAllowType = "FromList"
+	GatewayAllowSameNamespace GatewayAllowType = "SameNamespace"
+)
+
+// RouteGateways specifies which Gateways may bind a route
+type RouteGateways struct {
+	// Allow defaults to SameNamespace
+	Allow GatewayAllowType `json:"allow,omitempty"`
+	// GatewayRefs are the Gateways which may bind the route if Allow is FromList
+	GatewayRefs []GatewayReference `json:"gatewayRefs,omitempty"`
+}
+
+// GatewayReference identifies a Gateway
+type GatewayReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// RouteStatus is the status of a route
+type RouteStatus struct {
+	// Gateways are the statuses of the route for each Gateway which binds it
+	Gateways []RouteGatewayStatus `json:"gateways,omitempty"`
+}
+
+// RouteGatewayStatus is the status of a route for a Gateway
+type RouteGatewayStatus struct {
+	GatewayRef GatewayReference `json:"gatewayRef"`
+	Conditions []Condition      `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRouteList is a list of HTTPRoutes
+type HTTPRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []HTTPRoute `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TLSRoute forwards TLS connections to services by SNI
+type TLSRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   TLSRouteSpec `json:"spec"`
+	Status RouteStatus  `json:"status,omitempty"`
+}
+
+// TLSRouteSpec specifies the rules of a TLSRoute
+type TLSRouteSpec struct {
+	// Gateways specifies the Gateways which may bind this route
+	Gateways RouteGateways `json:"gateways,omitempty"`
+	// Rules are the match conditions and destinations of connections
+	Rules []TLSRouteRule `json:"rules"`
+}
+
+// TLSRouteRule forwards connections matching any of its matches
+type TLSRouteRule struct {
+	Matches   []TLSRouteMatch  `json:"matches,omitempty"`
+	ForwardTo []RouteForwardTo `json:"forwardTo"`
+}
+
+// TLSRouteMatch matches the server name indication of a connection
+type TLSRouteMatch struct {
+	SNIs []Hostname `json:"snis,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TLSRouteList is a list of TLSRoutes
+type TLSRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []TLSRoute `json:"items"`
+}
+
+// Condition describes an aspect of the current state of a Gateway API resource
+type Condition struct {
+	// Type of the condition, such as Admitted or Ready
+	Type string `json:"type"`
+	// Status of the condition, one of True, False or Unknown
+	Status v1.ConditionStatus `json:"status"`
+	// Reason is a machine readable explanation of the status
+	Reason string `json:"reason"`
+	// Message is a human readable explanation of the status
+	Message string `json:"message"`
+	// LastTransitionTime is the last time the status changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// ObservedGeneration is the generation of the resource the condition was computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+const (
+	// ConditionAdmitted is set on GatewayClasses managed by Contour and
+	// on routes for each Gateway which binds them
+	ConditionAdmitted = "Admitted"
+	// ConditionReady is set on Gateways and their Listeners
+	ConditionReady = "Ready"
+)
@@ -20,6 +20,7 @@ package versioned
 
 import (
 	contourv1beta1 "github.com/heptio/contour/apis/generated/clientset/versioned/typed/contour/v1beta1"
+	gatewayv1alpha1 "github.com/heptio/contour/apis/generated/clientset/versioned/typed/gateway/v1alpha1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -30,13 +31,17 @@ type Interface interface {
 	ContourV1beta1() contourv1beta1.ContourV1beta1Interface
 	// Deprecated: please explicitly pick a version if possible.
 	Contour() contourv1beta1.ContourV1beta1Interface
+	GatewayV1alpha1() gatewayv1alpha1.GatewayV1alpha1Interface
+	// Deprecated: please explicitly pick a version if possible.
+	Gateway() gatewayv1alpha1.GatewayV1alpha1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
 // version included in a Clientset.
 type Clientset struct {
 	*discovery.DiscoveryClient
-	contourV1beta1 *contourv1beta1.ContourV1beta1Client
+	contourV1beta1  *contourv1beta1.ContourV1beta1Client
+	gatewayV1alpha1 *gatewayv1alpha1.GatewayV1alpha1Client
 }
 
 // ContourV1beta1 retrieves the ContourV1beta1Client
@@ -50,6 +55,17 @@ func (c *Clientset) Contour() contourv1beta1.ContourV1beta1Interface {
 	return c.contourV1beta1
 }
 
+// GatewayV1alpha1 retrieves the GatewayV1alpha1Client
+func (c *Clientset) GatewayV1alpha1() gatewayv1alpha1.GatewayV1alpha1Interface {
+	return c.gatewayV1alpha1
+}
+
+// Deprecated: Gateway retrieves the default version of GatewayClient.
+// Please explicitly pick a version.
+func (c *Clientset) Gateway() gatewayv1alpha1.GatewayV1alpha1Interface {
+	return c.gatewayV1alpha1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -70,6 +86,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.gatewayV1alpha1, err = gatewayv1alpha1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -83,6 +103,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.contourV1beta1 = contourv1beta1.NewForConfigOrDie(c)
+	cs.gatewayV1alpha1 = gatewayv1alpha1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -92,6 +113,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.contourV1beta1 = contourv1beta1.New(c)
+	cs.gatewayV1alpha1 = gatewayv1alpha1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
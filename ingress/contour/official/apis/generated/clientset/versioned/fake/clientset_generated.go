@@ -22,6 +22,8 @@ import (
 	clientset "github.com/heptio/contour/apis/generated/clientset/versioned"
 	contourv1beta1 "github.com/heptio/contour/apis/generated/clientset/versioned/typed/contour/v1beta1"
 	fakecontourv1beta1 "github.com/heptio/contour/apis/generated/clientset/versioned/typed/contour/v1beta1/fake"
+	gatewayv1alpha1 "github.com/heptio/contour/apis/generated/clientset/versioned/typed/gateway/v1alpha1"
+	fakegatewayv1alpha1 "github.com/heptio/contour/apis/generated/clientset/versioned/typed/gateway/v1alpha1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -80,3 +82,13 @@ func (c *Clientset) ContourV1beta1() contourv1beta1.ContourV1beta1Interface {
 func (c *Clientset) Contour() contourv1beta1.ContourV1beta1Interface {
 	return &fakecontourv1beta1.FakeContourV1beta1{Fake: &c.Fake}
 }
+
+// GatewayV1alpha1 retrieves the GatewayV1alpha1Client
+func (c *Clientset) GatewayV1alpha1() gatewayv1alpha1.GatewayV1alpha1Interface {
+	return &fakegatewayv1alpha1.FakeGatewayV1alpha1{Fake: &c.Fake}
+}
+
+// Gateway retrieves the GatewayV1alpha1Client
+func (c *Clientset) Gateway() gatewayv1alpha1.GatewayV1alpha1Interface {
+	return &fakegatewayv1alpha1.FakeGatewayV1alpha1{Fake: &c.Fake}
+}
@@ -20,6 +20,7 @@ package fake
 
 import (
 	contourv1beta1 "github.com/heptio/contour/apis/contour/v1beta1"
+	gatewayv1alpha1 "github.com/heptio/contour/apis/gateway/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -51,4 +52,5 @@ func init() {
 // correctly.
 func AddToScheme(scheme *runtime.Scheme) {
 	contourv1beta1.AddToScheme(scheme)
+	gatewayv1alpha1.AddToScheme(scheme)
 }
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 Heptio
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1alpha1 "github.com/heptio/contour/apis/gateway/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeTLSRoutes implements TLSRouteInterface
+type FakeTLSRoutes struct {
+	Fake *FakeGatewayV1alpha1
+	ns   string
+}
+
+var tlsroutesResource = schema.GroupVersionResource{Group: "networking.x-k8s.io", Version: "v1alpha1", Resource: "tlsroutes"}
+
+var tlsroutesKind = schema.GroupVersionKind{Group: "networking.x-k8s.io", Version: "v1alpha1", Kind: "TLSRoute"}
+
+// Get takes name of the tLSRoute, and returns the corresponding tLSRoute object, and an error if there is any.
+func (c *FakeTLSRoutes) Get(name string, options v1.GetOptions) (result *v1alpha1.TLSRoute, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(tlsroutesResource, c.ns, name), &v1alpha1.TLSRoute{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.TLSRoute), err
+}
+
+// List takes label and field selectors, and returns the list of TLSRoutes that match those selectors.
+func (c *FakeTLSRoutes) List(opts v1.ListOptions) (result *v1alpha1.TLSRouteList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(tlsroutesResource, tlsroutesKind, c.ns, opts), &v1alpha1.TLSRouteList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.TLSRouteList{ListMeta: obj.(*v1alpha1.TLSRouteList).ListMeta}
+	for _, item := range obj.(*v1alpha1.TLSRouteList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested tLSRoutes.
+func (c *FakeTLSRoutes) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(tlsroutesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a tLSRoute and creates it.  Returns the server's representation of the tLSRoute, and an error, if there is any.
+func (c *FakeTLSRoutes) Create(tLSRoute *v1alpha1.TLSRoute) (result *v1alpha1.TLSRoute, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(tlsroutesResource, c.ns, tLSRoute), &v1alpha1.TLSRoute{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.TLSRoute), err
+}
+
+// Update takes the representation of a tLSRoute and updates it. Returns the server's representation of the tLSRoute, and an error, if there is any.
+func (c *FakeTLSRoutes) Update(tLSRoute *v1alpha1.TLSRoute) (result *v1alpha1.TLSRoute, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(tlsroutesResource, c.ns, tLSRoute), &v1alpha1.TLSRoute{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.TLSRoute), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeTLSRoutes) UpdateStatus(tLSRoute *v1alpha1.TLSRoute) (*v1alpha1.TLSRoute, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(tlsroutesResource, "status", c.ns, tLSRoute), &v1alpha1.TLSRoute{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.TLSRoute), err
+}
+
+// Delete takes name of the tLSRoute and deletes it. Returns an error if one occurs.
+func (c *FakeTLSRoutes) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(tlsroutesResource, c.ns, name), &v1alpha1.TLSRoute{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeTLSRoutes) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(tlsroutesResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.TLSRouteList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched tLSRoute.
+func (c *FakeTLSRoutes) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.TLSRoute, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(tlsroutesResource, c.ns, name, data, subresources...), &v1alpha1.TLSRoute{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.TLSRoute), err
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2018 Heptio
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/heptio/contour/apis/gateway/v1alpha1"
+	scheme "github.com/heptio/contour/apis/generated/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// TLSRoutesGetter has a method to return a TLSRouteInterface.
+// A group's client should implement this interface.
+type TLSRoutesGetter interface {
+	TLSRoutes(namespace string) TLSRouteInterface
+}
+
+// TLSRouteInterface has methods to work with TLSRoute resources.
+type TLSRouteInterface interface {
+	Create(*v1alpha1.TLSRoute) (*v1alpha1.TLSRoute, error)
+	Update(*v1alpha1.TLSRoute) (*v1alpha1.TLSRoute, error)
+	UpdateStatus(*v1alpha1.TLSRoute) (*v1alpha1.TLSRoute, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.TLSRoute, error)
+	List(opts v1.ListOptions) (*v1alpha1.TLSRouteList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.TLSRoute, err error)
+	TLSRouteExpansion
+}
+
+// tLSRoutes implements TLSRouteInterface
+type tLSRoutes struct {
+	client rest.Interface
+	ns     string
+}
+
+// newTLSRoutes returns a TLSRoutes
+func newTLSRoutes(c *GatewayV1alpha1Client, namespace string) *tLSRoutes {
+	return &tLSRoutes{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the tLSRoute, and returns the corresponding tLSRoute object, and an error if there is any.
+func (c *tLSRoutes) Get(name string, options v1.GetOptions) (result *v1alpha1.TLSRoute, err error) {
+	result = &v1alpha1.TLSRoute{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("tlsroutes").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of TLSRoutes that match those selectors.
+func (c *tLSRoutes) List(opts v1.ListOptions) (result *v1alpha1.TLSRouteList, err error) {
+	result = &v1alpha1.TLSRouteList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("tlsroutes").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested tLSRoutes.
+func (c *tLSRoutes) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("tlsroutes").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a tLSRoute and creates it.  Returns the server's representation of the tLSRoute, and an error, if there is any.
+func (c *tLSRoutes) Create(tLSRoute *v1alpha1.TLSRoute) (result *v1alpha1.TLSRoute, err error) {
+	result = &v1alpha1.TLSRoute{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("tlsroutes").
+		Body(tLSRoute).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a tLSRoute and updates it. Returns the server's representation of the tLSRoute, and an error, if there is any.
+func (c *tLSRoutes) Update(tLSRoute *v1alpha1.TLSRoute) (result *v1alpha1.TLSRoute, err error) {
+	result = &v1alpha1.TLSRoute{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("tlsroutes").
+		Name(tLSRoute.Name).
+		Body(tLSRoute).
+		Do().
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *tLSRoutes) UpdateStatus(tLSRoute *v1alpha1.TLSRoute) (result *v1alpha1.TLSRoute, err error) {
+	result = &v1alpha1.TLSRoute{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("tlsroutes").
+		Name(tLSRoute.Name).
+		SubResource("status").
+		Body(tLSRoute).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the tLSRoute and deletes it. Returns an error if one occurs.
+func (c *tLSRoutes) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("tlsroutes").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *tLSRoutes) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("tlsroutes").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched tLSRoute.
+func (c *tLSRoutes) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.TLSRoute, err error) {
+	result = &v1alpha1.TLSRoute{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("tlsroutes").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
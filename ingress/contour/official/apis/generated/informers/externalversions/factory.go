@@ -25,6 +25,7 @@ import (
 
 	versioned "github.com/heptio/contour/apis/generated/clientset/versioned"
 	contour "github.com/heptio/contour/apis/generated/informers/externalversions/contour"
+	gateway "github.com/heptio/contour/apis/generated/informers/externalversions/gateway"
 	internalinterfaces "github.com/heptio/contour/apis/generated/informers/externalversions/internalinterfaces"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -173,8 +174,13 @@ type SharedInformerFactory interface {
 	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
 
 	Contour() contour.Interface
+	Gateway() gateway.Interface
 }
 
 func (f *sharedInformerFactory) Contour() contour.Interface {
 	return contour.New(f, f.namespace, f.tweakListOptions)
 }
+
+func (f *sharedInformerFactory) Gateway() gateway.Interface {
+	return gateway.New(f, f.namespace, f.tweakListOptions)
+}
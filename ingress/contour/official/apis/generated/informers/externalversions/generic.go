@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	v1beta1 "github.com/heptio/contour/apis/contour/v1beta1"
+	v1alpha1 "github.com/heptio/contour/apis/gateway/v1alpha1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -56,6 +57,16 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1beta1.SchemeGroupVersion.WithResource("ingressroutes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Contour().V1beta1().IngressRoutes().Informer()}, nil
 
+		// Group=networking.x-k8s.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("gateways"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Gateway().V1alpha1().Gateways().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("gatewayclasses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Gateway().V1alpha1().GatewayClasses().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("httproutes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Gateway().V1alpha1().HTTPRoutes().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("tlsroutes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Gateway().V1alpha1().TLSRoutes().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -29,6 +29,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
 
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -43,6 +44,7 @@ import (
 )
 
 var ingressrouteRootNamespaceFlag string
+var clientCertificateFlag string
 
 func main() {
 	log := logrus.StandardLogger()
@@ -132,8 +134,10 @@ func main() {
 	serve.Flag("envoy-http-port", "Envoy HTTP listener port").IntVar(&ch.HTTPPort)
 	serve.Flag("envoy-https-port", "Envoy HTTPS listener port").IntVar(&ch.HTTPSPort)
 	serve.Flag("use-proxy-protocol", "Use PROXY protocol for all listeners").BoolVar(&ch.UseProxyProto)
+	serve.Flag("envoy-client-certificate", "Namespace/name of the secret holding the client certificate presented by Envoy to TLS upstreams").StringVar(&clientCertificateFlag)
 	serve.Flag("ingress-class-name", "Contour IngressClass name").StringVar(&reh.IngressClass)
 	serve.Flag("ingressroute-root-namespaces", "Restrict contour to searching these namespaces for root ingress routes").StringVar(&ingressrouteRootNamespaceFlag)
+	serve.Flag("gateway-controller", "Controller name of the GatewayClasses managed by contour, enables the Gateway API").StringVar(&reh.GatewayController)
 
 	args := os.Args[1:]
 	switch kingpin.MustParse(app.Parse(args)) {
@@ -164,6 +168,10 @@ func main() {
 
 		reh.IngressRouteRootNamespaces = parseRootNamespaces(ingressrouteRootNamespaceFlag)
 
+		clientCert, err := parseClientCertificate(clientCertificateFlag)
+		check(err)
+		reh.ClientCertificate = clientCert
+
 		client, contourClient := newClient(*kubeconfig, *inCluster)
 
 		wl := log.WithField("context", "watch")
@@ -172,9 +180,20 @@ func main() {
 		k8s.WatchSecrets(&g, client, wl, &reh)
 		k8s.WatchIngressRoutes(&g, contourClient, wl, &reh)
 
+		if reh.GatewayController != "" {
+			k8s.WatchNamespaces(&g, client, wl, &reh)
+			k8s.WatchGatewayClasses(&g, contourClient, wl, &reh)
+			k8s.WatchGateways(&g, contourClient, wl, &reh)
+			k8s.WatchHTTPRoutes(&g, contourClient, wl, &reh)
+			k8s.WatchTLSRoutes(&g, contourClient, wl, &reh)
+		}
+
 		ch.IngressRouteStatus = &k8s.IngressRouteStatus{
 			Client: contourClient,
 		}
+		ch.GatewayStatus = &k8s.GatewayStatus{
+			Client: contourClient,
+		}
 
 		// Endpoints updates are handled directly by the EndpointsTranslator
 		// due to their high update rate and their orthogonal nature.
@@ -248,6 +267,18 @@ func check(err error) {
 	}
 }
 
+// parseClientCertificate parses the namespace/name of the client certificate secret.
+func parseClientCertificate(s string) (*types.NamespacedName, error) {
+	if s == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid client certificate %q, expected namespace/name", s)
+	}
+	return &types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
 func parseRootNamespaces(rn string) []string {
 	if rn == "" {
 		return nil
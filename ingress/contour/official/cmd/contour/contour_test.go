@@ -16,6 +16,8 @@ package main
 import (
 	"reflect"
 	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestParseRootNamespaces(t *testing.T) {
@@ -50,3 +52,40 @@ func TestParseRootNamespaces(t *testing.T) {
 		})
 	}
 }
+
+func TestParseClientCertificate(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    *types.NamespacedName
+		wantErr bool
+	}{
+		"empty": {
+			input: "",
+			want:  nil,
+		},
+		"namespace and name": {
+			input: "heptio-contour/envoy-client",
+			want:  &types.NamespacedName{Namespace: "heptio-contour", Name: "envoy-client"},
+		},
+		"name only": {
+			input:   "envoy-client",
+			wantErr: true,
+		},
+		"missing name": {
+			input:   "heptio-contour/",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseClientCertificate(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tc.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected: %v, got: %v", tc.want, got)
+			}
+		})
+	}
+}
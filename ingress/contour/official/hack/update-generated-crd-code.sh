@@ -22,6 +22,6 @@ ${REPO_ROOT}/vendor/k8s.io/code-generator/generate-groups.sh \
   all \
   github.com/heptio/contour/apis/generated \
   github.com/heptio/contour/apis \
-  contour:v1beta1 \
+  "contour:v1beta1 gateway:v1alpha1" \
   --go-header-file hack/boilerplate.go.tmpl \
   $@
\ No newline at end of file
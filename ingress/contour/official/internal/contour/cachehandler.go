@@ -17,6 +17,8 @@
 package contour
 
 import (
+	ingressroutev1 "github.com/heptio/contour/apis/contour/v1beta1"
+	gatewayv1 "github.com/heptio/contour/apis/gateway/v1alpha1"
 	"github.com/heptio/contour/internal/dag"
 	"github.com/heptio/contour/internal/k8s"
 	"github.com/heptio/contour/internal/metrics"
@@ -31,12 +33,18 @@ type CacheHandler struct {
 	ClusterCache
 
 	IngressRouteStatus *k8s.IngressRouteStatus
+	GatewayStatus      *k8s.GatewayStatus
 	logrus.FieldLogger
 	*metrics.Metrics
 }
 
 type statusable interface {
 	Statuses() []dag.Status
+	IngressRouteStatuses() []dag.IngressRouteStatus
+}
+
+type gatewayStatusable interface {
+	GatewayStatuses() []dag.GatewayStatus
 }
 
 func (ch *CacheHandler) OnChange(b *dag.Builder) {
@@ -44,6 +52,7 @@ func (ch *CacheHandler) OnChange(b *dag.Builder) {
 	defer timer.ObserveDuration()
 	dag := b.Build()
 	ch.setIngressRouteStatus(dag)
+	ch.setGatewayStatus(dag)
 	ch.updateListeners(dag)
 	ch.updateRoutes(dag)
 	ch.updateClusters(dag)
@@ -51,14 +60,42 @@ func (ch *CacheHandler) OnChange(b *dag.Builder) {
 }
 
 func (ch *CacheHandler) setIngressRouteStatus(st statusable) {
-	for _, s := range st.Statuses() {
-		err := ch.IngressRouteStatus.SetStatus(s.Status, s.Description, s.Object)
+	for _, s := range st.IngressRouteStatuses() {
+		var conditions []ingressroutev1.Condition
+		for _, c := range s.Conditions {
+			conditions = append(conditions, ingressroutev1.Condition{
+				Status:      c.Status,
+				Reason:      c.Reason,
+				Message:     c.Description,
+				VirtualHost: c.Vhost,
+			})
+		}
+		err := ch.IngressRouteStatus.SetStatus(s.Status, s.Description, conditions, s.Object)
 		if err != nil {
 			ch.Errorf("Error Setting Status of IngressRoute: ", err)
 		}
 	}
 }
 
+func (ch *CacheHandler) setGatewayStatus(st gatewayStatusable) {
+	for _, s := range st.GatewayStatuses() {
+		var err error
+		switch obj := s.Object.(type) {
+		case *gatewayv1.GatewayClass:
+			err = ch.GatewayStatus.SetGatewayClassStatus(s.Conditions, obj)
+		case *gatewayv1.Gateway:
+			err = ch.GatewayStatus.SetGatewayStatus(s.Conditions, s.Listeners, obj)
+		case *gatewayv1.HTTPRoute:
+			err = ch.GatewayStatus.SetHTTPRouteStatus(s.Gateways, obj)
+		case *gatewayv1.TLSRoute:
+			err = ch.GatewayStatus.SetTLSRouteStatus(s.Gateways, obj)
+		}
+		if err != nil {
+			ch.Errorf("Error Setting Status of %T: %v", s.Object, err)
+		}
+	}
+}
+
 func (ch *CacheHandler) updateListeners(v dag.Visitable) {
 	lv := listenerVisitor{
 		ListenerCache: &ch.ListenerCache,
@@ -95,6 +132,7 @@ func calculateIngressRouteMetric(st statusable) metrics.IngressRouteMetric {
 	metricInvalid := make(map[metrics.Meta]int)
 	metricOrphaned := make(map[metrics.Meta]int)
 	metricRoots := make(map[metrics.Meta]int)
+	metricConditions := make(map[metrics.ConditionMeta]int)
 
 	for _, v := range st.Statuses() {
 		switch v.Status {
@@ -112,11 +150,24 @@ func calculateIngressRouteMetric(st statusable) metrics.IngressRouteMetric {
 		}
 	}
 
+	// count each IngressRoute once per distinct reason under its aggregate status.
+	for _, v := range st.IngressRouteStatuses() {
+		seen := make(map[string]bool)
+		for _, c := range v.Conditions {
+			if c.Status != v.Status || seen[c.Reason] {
+				continue
+			}
+			seen[c.Reason] = true
+			metricConditions[metrics.ConditionMeta{Namespace: v.Object.GetNamespace(), Status: v.Status, Reason: c.Reason}]++
+		}
+	}
+
 	return metrics.IngressRouteMetric{
-		Invalid:  metricInvalid,
-		Valid:    metricValid,
-		Orphaned: metricOrphaned,
-		Total:    metricTotal,
-		Root:     metricRoots,
+		Invalid:    metricInvalid,
+		Valid:      metricValid,
+		Orphaned:   metricOrphaned,
+		Total:      metricTotal,
+		Root:       metricRoots,
+		Conditions: metricConditions,
 	}
 }
@@ -549,3 +549,111 @@ func TestIngressRouteMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestIngressRouteConditionMetrics(t *testing.T) {
+	root := func(name, fqdn, delegate string) *ingressroutev1.IngressRoute {
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "roots",
+				Name:      name,
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn: fqdn,
+				},
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Delegate: &ingressroutev1.Delegate{
+						Name: delegate,
+					},
+				}},
+			},
+		}
+	}
+	child := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "child",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+	// cycle delegates back to parent.
+	cycle := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "cycle",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Delegate: &ingressroutev1.Delegate{
+					Name: "parent",
+				},
+			}},
+		},
+	}
+
+	tests := map[string]struct {
+		objs []*ingressroutev1.IngressRoute
+		want map[metrics.ConditionMeta]int
+	}{
+		"child delegated from two roots is counted once": {
+			objs: []*ingressroutev1.IngressRoute{
+				root("a", "a.example.com", "child"),
+				root("b", "b.example.com", "child"),
+				child,
+			},
+			want: map[metrics.ConditionMeta]int{
+				{Namespace: "roots", Status: dag.StatusValid, Reason: dag.ReasonValid}: 3,
+			},
+		},
+		"orphaned child": {
+			objs: []*ingressroutev1.IngressRoute{child},
+			want: map[metrics.ConditionMeta]int{
+				{Namespace: "roots", Status: dag.StatusOrphaned, Reason: dag.ReasonOrphaned}: 1,
+			},
+		},
+		"duplicate fqdn": {
+			objs: []*ingressroutev1.IngressRoute{
+				root("a", "example.com", "child"),
+				root("b", "example.com", "child"),
+				child,
+			},
+			want: map[metrics.ConditionMeta]int{
+				{Namespace: "roots", Status: dag.StatusInvalid, Reason: dag.ReasonDuplicateFQDN}: 2,
+				{Namespace: "roots", Status: dag.StatusOrphaned, Reason: dag.ReasonOrphaned}:     1,
+			},
+		},
+		"delegation cycle": {
+			objs: []*ingressroutev1.IngressRoute{
+				root("parent", "example.com", "cycle"),
+				cycle,
+			},
+			want: map[metrics.ConditionMeta]int{
+				{Namespace: "roots", Status: dag.StatusValid, Reason: dag.ReasonValid}:             1,
+				{Namespace: "roots", Status: dag.StatusInvalid, Reason: dag.ReasonDelegationCycle}: 1,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b dag.Builder
+			for _, o := range tc.objs {
+				b.Insert(o)
+			}
+			got := calculateIngressRouteMetric(b.Build()).Conditions
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("(metrics-Conditions) expected to find: %v but got: %v", tc.want, got)
+			}
+		})
+	}
+}
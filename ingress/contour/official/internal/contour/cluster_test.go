@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/cluster"
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type"
@@ -29,6 +30,7 @@ import (
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -893,3 +895,106 @@ func clustermap(clusters ...*v2.Cluster) map[string]*v2.Cluster {
 }
 
 func duration(d time.Duration) *time.Duration { return &d }
+
+func TestClusterVisitUpstreamTLS(t *testing.T) {
+	reh := ResourceEventHandler{
+		Notifier: new(nullNotifier),
+		Metrics:  metrics.NewMetrics(prometheus.NewRegistry()),
+	}
+	reh.ClientCertificate = &types.NamespacedName{
+		Name:      "envoy-client",
+		Namespace: "contour",
+	}
+	objs := []interface{}{
+		&ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "simple",
+				Namespace: "default",
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn: "www.example.com",
+				},
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Services: []ingressroutev1.Service{{
+						Name: "backend",
+						Port: 443,
+						UpstreamValidation: &ingressroutev1.UpstreamValidation{
+							CACertificate: "backend-ca",
+							SubjectName:   "backend.default.svc",
+						},
+					}},
+				}},
+			},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "backend-ca",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				"ca.crt": []byte("ca certificate"),
+			},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "envoy-client",
+				Namespace: "contour",
+			},
+			Type: v1.SecretTypeTLS,
+			Data: map[string][]byte{
+				v1.TLSCertKey:       []byte("client certificate"),
+				v1.TLSPrivateKeyKey: []byte("client key"),
+			},
+		},
+		serviceWithAnnotations("default", "backend", map[string]string{
+			"contour.heptio.com/upstream-protocol.tls": "443",
+		}, v1.ServicePort{
+			Protocol:   "TCP",
+			Port:       443,
+			TargetPort: intstr.FromInt(8443),
+		}),
+	}
+	for _, o := range objs {
+		reh.OnAdd(o)
+	}
+	v := clusterVisitor{
+		ClusterCache: new(ClusterCache),
+		Visitable:    reh.Build(),
+	}
+	got := v.Visit()
+
+	// envoy 1.7 cannot fetch upstream secrets with SDS, so the CA and
+	// client certificate must be inlined into the cluster.
+	want := &auth.UpstreamTlsContext{
+		Sni: "backend.default.svc",
+		CommonTlsContext: &auth.CommonTlsContext{
+			TlsCertificates: []*auth.TlsCertificate{{
+				CertificateChain: inlinebytes("client certificate"),
+				PrivateKey:       inlinebytes("client key"),
+			}},
+			ValidationContextType: &auth.CommonTlsContext_ValidationContext{
+				ValidationContext: &auth.CertificateValidationContext{
+					TrustedCa:            inlinebytes("ca certificate"),
+					VerifySubjectAltName: []string{"backend.default.svc"},
+				},
+			},
+		},
+	}
+	c, ok := got["default/backend/443/2d5aa7de37"]
+	if !ok {
+		t.Fatalf("expected cluster default/backend/443/2d5aa7de37, got: %v", got)
+	}
+	if diff := cmp.Diff(want, c.TlsContext); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func inlinebytes(s string) *core.DataSource {
+	return &core.DataSource{
+		Specifier: &core.DataSource_InlineBytes{
+			InlineBytes: []byte(s),
+		},
+	}
+}
@@ -14,6 +14,8 @@
 package contour
 
 import (
+	"sort"
+	"strconv"
 	"sync"
 
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2"
@@ -188,6 +190,9 @@ const (
 	grpcWeb    = "envoy.grpc_web"
 	gzip       = "envoy.gzip"
 	httpFilter = "envoy.http_connection_manager"
+	tcpProxy   = "envoy.tcp_proxy"
+	extAuthz   = "envoy.ext_authz"
+	cors       = "envoy.cors"
 	accessLog  = "envoy.file_access_log"
 )
 
@@ -206,8 +211,9 @@ func (v *listenerVisitor) Visit() map[string]*v2.Listener {
 			envoy.TLSInspector(),
 		},
 	}
+	httpFilters := v.corsfilters()
 	filters := []listener.Filter{
-		httpfilter(ENVOY_HTTPS_LISTENER, v.httpsAccessLog()),
+		httpfilter(ENVOY_HTTPS_LISTENER, v.httpsAccessLog(), httpFilters...),
 	}
 	v.Visitable.Visit(func(vh dag.Vertex) {
 		switch vh := vh.(type) {
@@ -218,6 +224,35 @@ func (v *listenerVisitor) Visit() map[string]*v2.Listener {
 			http++
 		case *dag.SecureVirtualHost:
 			data := vh.Data()
+			if vh.TCPProxy != nil {
+				var svcs []*dag.Service
+				vh.TCPProxy.Visit(func(s dag.Vertex) {
+					if s, ok := s.(*dag.Service); ok {
+						svcs = append(svcs, s)
+					}
+				})
+				if len(svcs) < 1 {
+					// no services for this proxy, skip it.
+					return
+				}
+				fc := listener.FilterChain{
+					FilterChainMatch: &listener.FilterChainMatch{
+						ServerNames: []string{vh.Host},
+					},
+					Filters: []listener.Filter{
+						tcpproxy(ENVOY_HTTPS_LISTENER, svcs, v.httpsAccessLog()),
+					},
+				}
+				// a proxy without a secret passes TLS through to the services.
+				if data != nil {
+					fc.TlsContext = tlscontext(data, vh.MinProtoVersion)
+				}
+				if v.UseProxyProto {
+					fc.UseProxyProto = bv(true)
+				}
+				ingress_https.FilterChains = append(ingress_https.FilterChains, fc)
+				return
+			}
 			if data == nil {
 				// no secret for this vhost, skip it
 				return
@@ -229,6 +264,12 @@ func (v *listenerVisitor) Visit() map[string]*v2.Listener {
 				TlsContext: tlscontext(data, vh.MinProtoVersion, "h2", "http/1.1"),
 				Filters:    filters,
 			}
+			if vh.AuthorizationServer != nil {
+				// this vhost needs its own connection manager to check requests.
+				fc.Filters = []listener.Filter{
+					httpfilter(ENVOY_HTTPS_LISTENER, v.httpsAccessLog(), append(httpFilters, extauthz(vh.AuthorizationServer))...),
+				}
+			}
 			if v.UseProxyProto {
 				fc.UseProxyProto = bv(true)
 			}
@@ -240,7 +281,7 @@ func (v *listenerVisitor) Visit() map[string]*v2.Listener {
 			Name:    ENVOY_HTTP_LISTENER,
 			Address: socketaddress(v.httpAddress(), v.httpPort()),
 			FilterChains: []listener.FilterChain{
-				filterchain(v.UseProxyProto, httpfilter(ENVOY_HTTP_LISTENER, v.httpAccessLog())),
+				filterchain(v.UseProxyProto, httpfilter(ENVOY_HTTP_LISTENER, v.httpAccessLog(), httpFilters...)),
 			},
 		}
 	}
@@ -250,6 +291,29 @@ func (v *listenerVisitor) Visit() map[string]*v2.Listener {
 	return m
 }
 
+// corsfilters returns the CORS HTTP filter of the connection managers.
+// The filter is only added if a virtual host has a CORS policy, and runs
+// before authorization so preflight requests are answered.
+func (v *listenerVisitor) corsfilters() []*types.Value {
+	var found bool
+	v.Visitable.Visit(func(vh dag.Vertex) {
+		switch vh := vh.(type) {
+		case *dag.VirtualHost:
+			found = found || vh.CORSPolicy != nil
+		case *dag.SecureVirtualHost:
+			found = found || vh.CORSPolicy != nil
+		}
+	})
+	if !found {
+		return nil
+	}
+	return []*types.Value{
+		st(map[string]*types.Value{
+			"name": sv(cors),
+		}),
+	}
+}
+
 func socketaddress(address string, port uint32) core.Address {
 	return core.Address{
 		Address: &core.Address_SocketAddress{
@@ -274,7 +338,21 @@ func filterchain(useproxy bool, filters ...listener.Filter) listener.FilterChain
 	return fc
 }
 
-func httpfilter(routename, accessLogPath string) listener.Filter {
+// httpfilter returns a HTTP connection manager filter. The supplied
+// HTTP filters are added to the filter chain before the router.
+func httpfilter(routename, accessLogPath string, filters ...*types.Value) listener.Filter {
+	httpFilters := []*types.Value{
+		st(map[string]*types.Value{
+			"name": sv(gzip),
+		}),
+		st(map[string]*types.Value{
+			"name": sv(grpcWeb),
+		}),
+	}
+	httpFilters = append(httpFilters, filters...)
+	httpFilters = append(httpFilters, st(map[string]*types.Value{
+		"name": sv(router),
+	}))
 	return listener.Filter{
 		Name: httpFilter,
 		Config: &types.Struct{
@@ -295,17 +373,7 @@ func httpfilter(routename, accessLogPath string) listener.Filter {
 						}),
 					}),
 				}),
-				"http_filters": lv(
-					st(map[string]*types.Value{
-						"name": sv(gzip),
-					}),
-					st(map[string]*types.Value{
-						"name": sv(grpcWeb),
-					}),
-					st(map[string]*types.Value{
-						"name": sv(router),
-					}),
-				),
+				"http_filters":       lv(httpFilters...),
 				"use_remote_address": {Kind: &types.Value_BoolValue{BoolValue: true}}, // TODO(jbeda) should this ever be false?
 				"access_log":         accesslog(accessLogPath),
 			},
@@ -313,6 +381,73 @@ func httpfilter(routename, accessLogPath string) listener.Filter {
 	}
 }
 
+// extauthz returns an external authorization HTTP filter checking
+// requests with the supplied authorization server.
+func extauthz(auth *dag.AuthorizationServer) *types.Value {
+	grpcService := map[string]*types.Value{
+		"envoy_grpc": st(map[string]*types.Value{
+			"cluster_name": sv(envoy.Clustername(auth.Service)),
+		}),
+	}
+	if auth.ResponseTimeout > 0 {
+		grpcService["timeout"] = sv(strconv.FormatFloat(auth.ResponseTimeout.Seconds(), 'f', -1, 64) + "s")
+	}
+	config := map[string]*types.Value{
+		"grpc_service":       st(grpcService),
+		"failure_mode_allow": {Kind: &types.Value_BoolValue{BoolValue: auth.FailOpen}},
+	}
+	return st(map[string]*types.Value{
+		"name":   sv(extAuthz),
+		"config": st(config),
+	})
+}
+
+// tcpproxy returns a TCP proxy filter forwarding connections to services.
+// If there is more than one service, connections are distributed by weight.
+func tcpproxy(statPrefix string, services []*dag.Service, accessLogPath string) listener.Filter {
+	config := map[string]*types.Value{
+		"stat_prefix": sv(statPrefix),
+		"access_log":  accesslog(accessLogPath),
+	}
+	switch len(services) {
+	case 1:
+		config["cluster"] = sv(envoy.Clustername(services[0]))
+	default:
+		sort.Stable(servicesByClustername(services))
+		var total int
+		for _, svc := range services {
+			total += svc.Weight
+		}
+		var clusters []*types.Value
+		for _, svc := range services {
+			weight := svc.Weight
+			if total == 0 {
+				// no weights were defined, default to even distribution
+				weight = 1
+			}
+			clusters = append(clusters, st(map[string]*types.Value{
+				"name":   sv(envoy.Clustername(svc)),
+				"weight": nv(weight),
+			}))
+		}
+		config["weighted_clusters"] = st(map[string]*types.Value{
+			"clusters": lv(clusters...),
+		})
+	}
+	return listener.Filter{
+		Name:   tcpProxy,
+		Config: &types.Struct{Fields: config},
+	}
+}
+
+type servicesByClustername []*dag.Service
+
+func (s servicesByClustername) Len() int      { return len(s) }
+func (s servicesByClustername) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s servicesByClustername) Less(i, j int) bool {
+	return envoy.Clustername(s[i]) < envoy.Clustername(s[j])
+}
+
 func tlscontext(data map[string][]byte, tlsMinProtoVersion auth.TlsParameters_TlsProtocol, alpnprotos ...string) *auth.DownstreamTlsContext {
 	return &auth.DownstreamTlsContext{
 		CommonTlsContext: &auth.CommonTlsContext{
@@ -352,6 +487,10 @@ func sv(s string) *types.Value {
 	return &types.Value{Kind: &types.Value_StringValue{StringValue: s}}
 }
 
+func nv(n int) *types.Value {
+	return &types.Value{Kind: &types.Value_NumberValue{NumberValue: float64(n)}}
+}
+
 func st(m map[string]*types.Value) *types.Value {
 	return &types.Value{Kind: &types.Value_StructValue{StructValue: &types.Struct{Fields: m}}}
 }
@@ -16,11 +16,14 @@ package contour
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/listener"
+	"github.com/gogo/protobuf/types"
 	ingressroutev1 "github.com/heptio/contour/apis/contour/v1beta1"
+	"github.com/heptio/contour/internal/dag"
 	"github.com/heptio/contour/internal/envoy"
 	"github.com/heptio/contour/internal/metrics"
 	"github.com/prometheus/client_golang/prometheus"
@@ -99,6 +102,45 @@ func TestListenerVisit(t *testing.T) {
 				},
 			},
 		},
+		"http ingressroute with cors policy": {
+			objs: []interface{}{
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn: "www.example.com",
+							CORSPolicy: &ingressroutev1.CORSPolicy{
+								AllowOrigin:  []string{"*"},
+								AllowMethods: []string{"GET"},
+							},
+						},
+						Routes: []ingressroutev1.Route{{
+							Match: "/",
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+			},
+			want: map[string]*v2.Listener{
+				ENVOY_HTTP_LISTENER: {
+					Name:    ENVOY_HTTP_LISTENER,
+					Address: socketaddress("0.0.0.0", 8080),
+					FilterChains: []listener.FilterChain{
+						filterchain(false, httpfilter(ENVOY_HTTP_LISTENER, DEFAULT_HTTP_ACCESS_LOG,
+							st(map[string]*types.Value{
+								"name": sv("envoy.cors"),
+							}),
+						)),
+					},
+				},
+			},
+		},
 		"simple ingress with secret": {
 			objs: []interface{}{
 				&v1beta1.Ingress{
@@ -247,6 +289,230 @@ func TestListenerVisit(t *testing.T) {
 				},
 			},
 		},
+		"ingressroute with tcpproxy and tls passthrough": {
+			objs: []interface{}{
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn: "db.example.com",
+							TLS: &ingressroutev1.TLS{
+								Passthrough: true,
+							},
+						},
+						TCPProxy: &ingressroutev1.TCPProxy{
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 443,
+							}},
+						},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       443,
+							TargetPort: intstr.FromInt(8443),
+						}},
+					},
+				},
+			},
+			want: map[string]*v2.Listener{
+				ENVOY_HTTPS_LISTENER: {
+					Name:    ENVOY_HTTPS_LISTENER,
+					Address: socketaddress("0.0.0.0", 8443),
+					FilterChains: []listener.FilterChain{{
+						FilterChainMatch: &listener.FilterChainMatch{
+							ServerNames: []string{"db.example.com"},
+						},
+						Filters: []listener.Filter{
+							tcpproxy(ENVOY_HTTPS_LISTENER, []*dag.Service{{
+								Object: &v1.Service{
+									ObjectMeta: metav1.ObjectMeta{
+										Name:      "backend",
+										Namespace: "default",
+									},
+								},
+								ServicePort: &v1.ServicePort{
+									Port: 443,
+								},
+							}}, DEFAULT_HTTPS_ACCESS_LOG),
+						},
+					}},
+					ListenerFilters: []listener.ListenerFilter{
+						envoy.TLSInspector(),
+					},
+				},
+			},
+		},
+		"ingressroute with tcpproxy terminating tls": {
+			objs: []interface{}{
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn: "db.example.com",
+							TLS: &ingressroutev1.TLS{
+								SecretName:             "secret",
+								MinimumProtocolVersion: "1.2",
+							},
+						},
+						TCPProxy: &ingressroutev1.TCPProxy{
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 443,
+							}},
+						},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       443,
+							TargetPort: intstr.FromInt(8443),
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Data: secretdata("certificate", "key"),
+				},
+			},
+			want: map[string]*v2.Listener{
+				ENVOY_HTTPS_LISTENER: {
+					Name:    ENVOY_HTTPS_LISTENER,
+					Address: socketaddress("0.0.0.0", 8443),
+					FilterChains: []listener.FilterChain{{
+						FilterChainMatch: &listener.FilterChainMatch{
+							ServerNames: []string{"db.example.com"},
+						},
+						TlsContext: tlscontext(secretdata("certificate", "key"), auth.TlsParameters_TLSv1_2),
+						Filters: []listener.Filter{
+							tcpproxy(ENVOY_HTTPS_LISTENER, []*dag.Service{{
+								Object: &v1.Service{
+									ObjectMeta: metav1.ObjectMeta{
+										Name:      "backend",
+										Namespace: "default",
+									},
+								},
+								ServicePort: &v1.ServicePort{
+									Port: 443,
+								},
+							}}, DEFAULT_HTTPS_ACCESS_LOG),
+						},
+					}},
+					ListenerFilters: []listener.ListenerFilter{
+						envoy.TLSInspector(),
+					},
+				},
+			},
+		},
+		"ingressroute with authorization": {
+			objs: []interface{}{
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &ingressroutev1.TLS{
+								SecretName: "secret",
+							},
+							Authorization: &ingressroutev1.AuthorizationServer{
+								Name: "auth",
+								Port: 9000,
+							},
+						},
+						Routes: []ingressroutev1.Route{{
+							Match: "/",
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "auth",
+						Namespace: "default",
+						Annotations: map[string]string{
+							"contour.heptio.com/upstream-protocol.h2c": "9000",
+						},
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       9000,
+							TargetPort: intstr.FromInt(9000),
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Data: secretdata("certificate", "key"),
+				},
+			},
+			want: map[string]*v2.Listener{
+				ENVOY_HTTP_LISTENER: {
+					Name:    ENVOY_HTTP_LISTENER,
+					Address: socketaddress("0.0.0.0", 8080),
+					FilterChains: []listener.FilterChain{
+						filterchain(false, httpfilter(ENVOY_HTTP_LISTENER, DEFAULT_HTTP_ACCESS_LOG)),
+					},
+				},
+				ENVOY_HTTPS_LISTENER: {
+					Name:    ENVOY_HTTPS_LISTENER,
+					Address: socketaddress("0.0.0.0", 8443),
+					FilterChains: []listener.FilterChain{{
+						FilterChainMatch: &listener.FilterChainMatch{
+							ServerNames: []string{"www.example.com"},
+						},
+						TlsContext: tlscontext(secretdata("certificate", "key"), auth.TlsParameters_TLSv1_1, "h2", "http/1.1"),
+						Filters: []listener.Filter{
+							httpfilter(ENVOY_HTTPS_LISTENER, DEFAULT_HTTPS_ACCESS_LOG, st(map[string]*types.Value{
+								"name": sv("envoy.ext_authz"),
+								"config": st(map[string]*types.Value{
+									"grpc_service": st(map[string]*types.Value{
+										"envoy_grpc": st(map[string]*types.Value{
+											"cluster_name": sv("default/auth/9000/da39a3ee5e"),
+										}),
+									}),
+									"failure_mode_allow": {Kind: &types.Value_BoolValue{BoolValue: false}},
+								}),
+							})),
+						},
+					}},
+					ListenerFilters: []listener.ListenerFilter{
+						envoy.TLSInspector(),
+					},
+				},
+			},
+		},
 		"ingress with allow-http: false": {
 			objs: []interface{}{
 				&v1beta1.Ingress{
@@ -456,6 +722,150 @@ func TestListenerVisit(t *testing.T) {
 	}
 }
 
+func TestTCPProxy(t *testing.T) {
+	service := func(name string, weight int) *dag.Service {
+		return &dag.Service{
+			Object: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+			},
+			ServicePort: &v1.ServicePort{
+				Port: 443,
+			},
+			Weight: weight,
+		}
+	}
+
+	tests := map[string]struct {
+		services []*dag.Service
+		want     map[string]*types.Value
+	}{
+		"single service": {
+			services: []*dag.Service{service("kuard", 0)},
+			want: map[string]*types.Value{
+				"stat_prefix": sv(ENVOY_HTTPS_LISTENER),
+				"access_log":  accesslog(DEFAULT_HTTPS_ACCESS_LOG),
+				"cluster":     sv("default/kuard/443/da39a3ee5e"),
+			},
+		},
+		"multiple services w/o weights": {
+			services: []*dag.Service{service("nginx", 0), service("kuard", 0)},
+			want: map[string]*types.Value{
+				"stat_prefix": sv(ENVOY_HTTPS_LISTENER),
+				"access_log":  accesslog(DEFAULT_HTTPS_ACCESS_LOG),
+				"weighted_clusters": st(map[string]*types.Value{
+					"clusters": lv(
+						st(map[string]*types.Value{
+							"name":   sv("default/kuard/443/da39a3ee5e"),
+							"weight": nv(1),
+						}),
+						st(map[string]*types.Value{
+							"name":   sv("default/nginx/443/da39a3ee5e"),
+							"weight": nv(1),
+						}),
+					),
+				}),
+			},
+		},
+		"multiple weighted services": {
+			services: []*dag.Service{service("nginx", 20), service("kuard", 80)},
+			want: map[string]*types.Value{
+				"stat_prefix": sv(ENVOY_HTTPS_LISTENER),
+				"access_log":  accesslog(DEFAULT_HTTPS_ACCESS_LOG),
+				"weighted_clusters": st(map[string]*types.Value{
+					"clusters": lv(
+						st(map[string]*types.Value{
+							"name":   sv("default/kuard/443/da39a3ee5e"),
+							"weight": nv(80),
+						}),
+						st(map[string]*types.Value{
+							"name":   sv("default/nginx/443/da39a3ee5e"),
+							"weight": nv(20),
+						}),
+					),
+				}),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tcpproxy(ENVOY_HTTPS_LISTENER, tc.services, DEFAULT_HTTPS_ACCESS_LOG)
+			want := listener.Filter{
+				Name:   tcpProxy,
+				Config: &types.Struct{Fields: tc.want},
+			}
+			if !reflect.DeepEqual(want, got) {
+				t.Fatalf("expected:\n%+v\ngot:\n%+v", want, got)
+			}
+		})
+	}
+}
+
+func TestExtAuthz(t *testing.T) {
+	service := &dag.Service{
+		Object: &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "auth",
+				Namespace: "default",
+			},
+		},
+		ServicePort: &v1.ServicePort{
+			Port: 9000,
+		},
+	}
+
+	tests := map[string]struct {
+		auth *dag.AuthorizationServer
+		want map[string]*types.Value
+	}{
+		"defaults": {
+			auth: &dag.AuthorizationServer{
+				Service: service,
+			},
+			want: map[string]*types.Value{
+				"grpc_service": st(map[string]*types.Value{
+					"envoy_grpc": st(map[string]*types.Value{
+						"cluster_name": sv("default/auth/9000/da39a3ee5e"),
+					}),
+				}),
+				"failure_mode_allow": {Kind: &types.Value_BoolValue{BoolValue: false}},
+			},
+		},
+		"fail open with timeout": {
+			auth: &dag.AuthorizationServer{
+				Service:         service,
+				FailOpen:        true,
+				ResponseTimeout: 1500 * time.Millisecond,
+			},
+			want: map[string]*types.Value{
+				"grpc_service": st(map[string]*types.Value{
+					"envoy_grpc": st(map[string]*types.Value{
+						"cluster_name": sv("default/auth/9000/da39a3ee5e"),
+					}),
+					"timeout": sv("1.5s"),
+				}),
+				"failure_mode_allow": {Kind: &types.Value_BoolValue{BoolValue: true}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := extauthz(tc.auth)
+			want := st(map[string]*types.Value{
+				"name":   sv(extAuthz),
+				"config": st(tc.want),
+			})
+			if !reflect.DeepEqual(want, got) {
+				t.Fatalf("expected:\n%+v\ngot:\n%+v", want, got)
+			}
+		})
+	}
+}
+
 func secretdata(cert, key string) map[string][]byte {
 	return map[string][]byte{
 		v1.TLSCertKey:       []byte(cert),
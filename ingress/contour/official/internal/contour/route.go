@@ -118,6 +118,7 @@ func (v *routeVisitor) Visit() map[string]*v2.RouteConfiguration {
 				Name:    envoy.Hashname(60, hostname),
 				Domains: domains,
 			}
+			vhost.Cors = envoy.CORSPolicy(vh.CORSPolicy)
 			vh.Visit(func(r dag.Vertex) {
 				switch r := r.(type) {
 				case *dag.Route:
@@ -132,7 +133,7 @@ func (v *routeVisitor) Visit() map[string]*v2.RouteConfiguration {
 						return
 					}
 					rr := route.Route{
-						Match:  prefixmatch(r.Prefix),
+						Match:  envoy.RouteMatch(r),
 						Action: actionroute(r, svcs),
 					}
 
@@ -142,6 +143,8 @@ func (v *routeVisitor) Visit() map[string]*v2.RouteConfiguration {
 								HttpsRedirect: true,
 							},
 						}
+					} else {
+						routeheaders(&rr, r)
 					}
 					vhost.Routes = append(vhost.Routes, rr)
 				}
@@ -161,6 +164,7 @@ func (v *routeVisitor) Visit() map[string]*v2.RouteConfiguration {
 				Name:    envoy.Hashname(60, hostname),
 				Domains: domains,
 			}
+			vhost.Cors = envoy.CORSPolicy(vh.CORSPolicy)
 			vh.Visit(func(r dag.Vertex) {
 				switch r := r.(type) {
 				case *dag.Route:
@@ -174,10 +178,12 @@ func (v *routeVisitor) Visit() map[string]*v2.RouteConfiguration {
 						// no services for this route, skip it.
 						return
 					}
-					vhost.Routes = append(vhost.Routes, route.Route{
-						Match:  prefixmatch(r.Prefix),
+					rr := route.Route{
+						Match:  envoy.RouteMatch(r),
 						Action: actionroute(r, svcs),
-					})
+					}
+					routeheaders(&rr, r)
+					vhost.Routes = append(vhost.Routes, rr)
 				}
 			})
 			if len(vhost.Routes) < 1 {
@@ -200,24 +206,52 @@ func (v virtualHostsByName) Len() int           { return len(v) }
 func (v virtualHostsByName) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
 func (v virtualHostsByName) Less(i, j int) bool { return v[i].Name < v[j].Name }
 
+// longestRouteFirst orders routes from the least to the most specific,
+// it is sorted in reverse so that envoy evaluates the most specific
+// routes first. Exact path matches are more specific than regex matches,
+// which are more specific than prefix matches. Routes with the same path
+// match are ordered by their number of header and query parameter conditions.
 type longestRouteFirst []route.Route
 
 func (l longestRouteFirst) Len() int      { return len(l) }
 func (l longestRouteFirst) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
 func (l longestRouteFirst) Less(i, j int) bool {
-	a, ok := l[i].Match.PathSpecifier.(*route.RouteMatch_Prefix)
-	if !ok {
-		// ignore non prefix matches
-		return false
+	a, b := l[i].Match, l[j].Match
+	ra, rb := pathRank(a), pathRank(b)
+	if ra != rb {
+		return ra < rb
 	}
+	pa, pb := pathValue(a), pathValue(b)
+	if pa != pb {
+		return pa < pb
+	}
+	return len(a.Headers)+len(a.QueryParameters) < len(b.Headers)+len(b.QueryParameters)
+}
 
-	b, ok := l[j].Match.PathSpecifier.(*route.RouteMatch_Prefix)
-	if !ok {
-		// ignore non prefix matches
-		return false
+// pathRank returns the specificity of the path match of rm.
+func pathRank(rm route.RouteMatch) int {
+	switch rm.PathSpecifier.(type) {
+	case *route.RouteMatch_Path:
+		return 2
+	case *route.RouteMatch_Regex:
+		return 1
+	default:
+		return 0
 	}
+}
 
-	return a.Prefix < b.Prefix
+// pathValue returns the path, regex, or prefix of rm.
+func pathValue(rm route.RouteMatch) string {
+	switch ps := rm.PathSpecifier.(type) {
+	case *route.RouteMatch_Path:
+		return ps.Path
+	case *route.RouteMatch_Regex:
+		return ps.Regex
+	case *route.RouteMatch_Prefix:
+		return ps.Prefix
+	default:
+		return ""
+	}
 }
 
 // prefixmatch returns a RouteMatch for the supplied prefix.
@@ -229,10 +263,19 @@ func prefixmatch(prefix string) route.RouteMatch {
 	}
 }
 
+// routeheaders applies the request and response headers policies of r to rr.
+func routeheaders(rr *route.Route, r *dag.Route) {
+	rr.RequestHeadersToAdd = envoy.HeadersToAdd(r.RequestHeadersPolicy)
+	rr.RequestHeadersToRemove = envoy.HeadersToRemove(r.RequestHeadersPolicy)
+	rr.ResponseHeadersToAdd = envoy.HeadersToAdd(r.ResponseHeadersPolicy)
+	rr.ResponseHeadersToRemove = envoy.HeadersToRemove(r.ResponseHeadersPolicy)
+}
+
 // action computes the cluster route action, a *route.Route_route for the
 // supplied ingress and backend.
 func actionroute(r *dag.Route, services []*dag.Service) *route.Route_Route {
 	rr := envoy.RouteRoute(services)
+	rr.Route.HashPolicy = envoy.HashPolicy(services)
 
 	if r.Websocket {
 		rr.Route.UseWebsocket = bv(true)
@@ -267,6 +310,12 @@ func actionroute(r *dag.Route, services []*dag.Service) *route.Route_Route {
 		rr.Route.PrefixRewrite = r.PrefixRewrite
 	}
 
+	if r.HostRewrite != "" {
+		rr.Route.HostRewriteSpecifier = &route.RouteAction_HostRewrite{
+			HostRewrite: r.HostRewrite,
+		}
+	}
+
 	return &rr
 }
 
@@ -20,6 +20,7 @@ import (
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	"github.com/gogo/protobuf/types"
 	"github.com/google/go-cmp/cmp"
 	ingressroutev1 "github.com/heptio/contour/apis/contour/v1beta1"
 	"github.com/heptio/contour/internal/dag"
@@ -351,6 +352,250 @@ func TestRouteVisit(t *testing.T) {
 				},
 			},
 		},
+		"ingressroute with authorization": {
+			objs: []interface{}{
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &ingressroutev1.TLS{
+								SecretName: "secret",
+							},
+							Authorization: &ingressroutev1.AuthorizationServer{
+								Name: "auth",
+								Port: 9000,
+							},
+						},
+						Routes: []ingressroutev1.Route{{
+							Match: "/",
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 8080,
+							}},
+						}},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Data: secretdata("certificate", "key"),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       8080,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "auth",
+						Namespace: "default",
+						Annotations: map[string]string{
+							"contour.heptio.com/upstream-protocol.h2c": "9000",
+						},
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       9000,
+							TargetPort: intstr.FromInt(9000),
+						}},
+					},
+				},
+			},
+			want: map[string]*v2.RouteConfiguration{
+				"ingress_http": {
+					Name: "ingress_http",
+					VirtualHosts: []route.VirtualHost{{
+						Name:    "www.example.com",
+						Domains: []string{"www.example.com", "www.example.com:80"},
+						Routes: []route.Route{{
+							Match: prefixmatch("/"),
+							Action: &route.Route_Redirect{
+								Redirect: &route.RedirectAction{
+									HttpsRedirect: true,
+								},
+							},
+						}},
+					}},
+				},
+				"ingress_https": {
+					Name: "ingress_https",
+					VirtualHosts: []route.VirtualHost{{
+						Name:    "www.example.com",
+						Domains: []string{"www.example.com", "www.example.com:443"},
+						Routes: []route.Route{{
+							Match:  prefixmatch("/"),
+							Action: routecluster("default/backend/8080/da39a3ee5e"),
+						}},
+					}},
+				},
+			},
+		},
+		"ingressroute with cors policy and cookie affinity": {
+			objs: []interface{}{
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn: "www.example.com",
+							CORSPolicy: &ingressroutev1.CORSPolicy{
+								AllowOrigin:  []string{"https://example.com"},
+								AllowMethods: []string{"GET", "POST"},
+								MaxAge:       "1m",
+							},
+						},
+						Routes: []ingressroutev1.Route{{
+							Match: "/",
+							Services: []ingressroutev1.Service{{
+								Name:     "backend",
+								Port:     8080,
+								Strategy: "Cookie",
+							}},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       8080,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: map[string]*v2.RouteConfiguration{
+				"ingress_http": {
+					Name: "ingress_http",
+					VirtualHosts: []route.VirtualHost{{
+						Name:    "www.example.com",
+						Domains: []string{"www.example.com", "www.example.com:80"},
+						Routes: []route.Route{{
+							Match: prefixmatch("/"),
+							Action: func() *route.Route_Route {
+								r := routecluster("default/backend/8080/e4f81994fe")
+								ttl := 24 * time.Hour
+								r.Route.HashPolicy = []*route.RouteAction_HashPolicy{{
+									PolicySpecifier: &route.RouteAction_HashPolicy_Cookie_{
+										Cookie: &route.RouteAction_HashPolicy_Cookie{
+											Name: "X-Contour-Session-Affinity",
+											Ttl:  &ttl,
+											Path: "/",
+										},
+									},
+								}}
+								return r
+							}(),
+						}},
+						Cors: &route.CorsPolicy{
+							AllowOrigin:      []string{"https://example.com"},
+							AllowMethods:     "GET,POST",
+							MaxAge:           "60",
+							AllowCredentials: &types.BoolValue{Value: false},
+						},
+					}},
+				},
+				"ingress_https": {
+					Name: "ingress_https",
+				},
+			},
+		},
+		"ingressroute with host rewrite and headers policies": {
+			objs: []interface{}{
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []ingressroutev1.Route{{
+							Match:       "/",
+							HostRewrite: "backend.example.com",
+							RequestHeadersPolicy: &ingressroutev1.HeadersPolicy{
+								Set: []ingressroutev1.HeaderValue{{
+									Name:  "x-forwarded-prefix",
+									Value: "/",
+								}},
+							},
+							ResponseHeadersPolicy: &ingressroutev1.HeadersPolicy{
+								Remove: []string{"x-powered-by"},
+							},
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 8080,
+							}},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       8080,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: map[string]*v2.RouteConfiguration{
+				"ingress_http": {
+					Name: "ingress_http",
+					VirtualHosts: []route.VirtualHost{{
+						Name:    "www.example.com",
+						Domains: []string{"www.example.com", "www.example.com:80"},
+						Routes: []route.Route{{
+							Match: prefixmatch("/"),
+							Action: func() *route.Route_Route {
+								r := routecluster("default/backend/8080/da39a3ee5e")
+								r.Route.HostRewriteSpecifier = &route.RouteAction_HostRewrite{
+									HostRewrite: "backend.example.com",
+								}
+								return r
+							}(),
+							RequestHeadersToAdd: []*core.HeaderValueOption{{
+								Header: &core.HeaderValue{
+									Key:   "x-forwarded-prefix",
+									Value: "/",
+								},
+								Append: bv(false),
+							}},
+							ResponseHeadersToRemove: []string{"x-powered-by"},
+						}},
+					}},
+				},
+				"ingress_https": {
+					Name: "ingress_https",
+				},
+			},
+		},
 		"simple tls ingress with allow-http:false": {
 			objs: []interface{}{
 				&v1beta1.Ingress{
@@ -1647,6 +1892,145 @@ func TestRouteVisit(t *testing.T) {
 				},
 			},
 		},
+		"ingressroute with match conditions": {
+			objs: []interface{}{
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []ingressroutev1.Route{{
+							Match: "/",
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}, {
+							Match: "/",
+							Conditions: []ingressroutev1.MatchCondition{{
+								Header: &ingressroutev1.HeaderCondition{
+									Name:  "x-canary",
+									Exact: "true",
+								},
+							}},
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}, {
+							Match: "/",
+							Conditions: []ingressroutev1.MatchCondition{{
+								Regex: "/[a-z]+/edit",
+							}},
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}, {
+							Match: "/api",
+							Conditions: []ingressroutev1.MatchCondition{{
+								Path: "/api/v1",
+							}, {
+								QueryParameter: &ingressroutev1.QueryParameterCondition{
+									Name:  "debug",
+									Exact: "true",
+								},
+							}},
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}, {
+							Match: "/api",
+							Conditions: []ingressroutev1.MatchCondition{{
+								Method: "POST",
+							}},
+							Services: []ingressroutev1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: map[string]*v2.RouteConfiguration{
+				"ingress_http": {
+					Name: "ingress_http",
+					VirtualHosts: []route.VirtualHost{{
+						Name:    "www.example.com",
+						Domains: []string{"www.example.com", "www.example.com:80"},
+						Routes: []route.Route{{
+							Match: route.RouteMatch{
+								PathSpecifier: &route.RouteMatch_Path{
+									Path: "/api/v1",
+								},
+								QueryParameters: []*route.QueryParameterMatcher{{
+									Name:  "debug",
+									Value: "true",
+								}},
+							},
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						}, {
+							Match: route.RouteMatch{
+								PathSpecifier: &route.RouteMatch_Regex{
+									Regex: "/(?:[a-z]+/edit)",
+								},
+							},
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						}, {
+							Match: route.RouteMatch{
+								PathSpecifier: &route.RouteMatch_Prefix{
+									Prefix: "/api",
+								},
+								Headers: []*route.HeaderMatcher{{
+									Name: ":method",
+									HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{
+										ExactMatch: "POST",
+									},
+								}},
+							},
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						}, {
+							Match: route.RouteMatch{
+								PathSpecifier: &route.RouteMatch_Prefix{
+									Prefix: "/",
+								},
+								Headers: []*route.HeaderMatcher{{
+									Name: "x-canary",
+									HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{
+										ExactMatch: "true",
+									},
+								}},
+							},
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						}, {
+							Match:  prefixmatch("/"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						}},
+					}},
+				},
+				"ingress_https": {
+					Name: "ingress_https",
+				},
+			},
+		},
 		"ingressroute w/ missing fqdn": {
 			objs: []interface{}{
 				&ingressroutev1.IngressRoute{
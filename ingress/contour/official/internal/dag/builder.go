@@ -17,6 +17,7 @@ package dag
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -25,11 +26,13 @@ import (
 
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	ingressroutev1 "github.com/heptio/contour/apis/contour/v1beta1"
+	gatewayv1 "github.com/heptio/contour/apis/gateway/v1alpha1"
 )
 
 // A KubernetesCache holds Kubernetes objects and associated configuration and produces
@@ -40,12 +43,27 @@ type KubernetesCache struct {
 	// namespace.
 	IngressRouteRootNamespaces []string
 
+	// ClientCertificate is the secret holding the certificate and key
+	// Envoy presents to services which use TLS. If nil, no client
+	// certificate is presented.
+	ClientCertificate *types.NamespacedName
+
+	// GatewayController is the controller name of the GatewayClasses
+	// managed by Contour. If empty, Gateway API resources are ignored.
+	GatewayController string
+
 	mu sync.RWMutex
 
 	ingresses     map[meta]*v1beta1.Ingress
 	ingressroutes map[meta]*ingressroutev1.IngressRoute
 	secrets       map[meta]*v1.Secret
 	services      map[meta]*v1.Service
+	namespaces    map[string]*v1.Namespace
+
+	gatewayclasses map[string]*gatewayv1.GatewayClass
+	gateways       map[meta]*gatewayv1.Gateway
+	httproutes     map[meta]*gatewayv1.HTTPRoute
+	tlsroutes      map[meta]*gatewayv1.TLSRoute
 }
 
 // meta holds the name and namespace of a Kubernetes object.
@@ -59,6 +77,17 @@ const (
 	StatusOrphaned = "orphaned"
 )
 
+// Reasons recorded against an IngressRoute Status.
+const (
+	ReasonValid                   = "Valid"
+	ReasonOrphaned                = "Orphaned"
+	ReasonInvalidSpec             = "InvalidSpec"
+	ReasonRootNamespaceNotAllowed = "RootNamespaceNotAllowed"
+	ReasonDuplicateFQDN           = "DuplicateFQDN"
+	ReasonDelegationCycle         = "DelegationCycle"
+	ReasonPrefixMismatch          = "PrefixMismatch"
+)
+
 // Insert inserts obj into the KubernetesCache.
 // If an object with a matching type, name, and namespace exists, it will be overwritten.
 func (kc *KubernetesCache) Insert(obj interface{}) {
@@ -89,6 +118,34 @@ func (kc *KubernetesCache) Insert(obj interface{}) {
 			kc.ingressroutes = make(map[meta]*ingressroutev1.IngressRoute)
 		}
 		kc.ingressroutes[m] = obj
+	case *v1.Namespace:
+		if kc.namespaces == nil {
+			kc.namespaces = make(map[string]*v1.Namespace)
+		}
+		kc.namespaces[obj.Name] = obj
+	case *gatewayv1.GatewayClass:
+		if kc.gatewayclasses == nil {
+			kc.gatewayclasses = make(map[string]*gatewayv1.GatewayClass)
+		}
+		kc.gatewayclasses[obj.Name] = obj
+	case *gatewayv1.Gateway:
+		m := meta{name: obj.Name, namespace: obj.Namespace}
+		if kc.gateways == nil {
+			kc.gateways = make(map[meta]*gatewayv1.Gateway)
+		}
+		kc.gateways[m] = obj
+	case *gatewayv1.HTTPRoute:
+		m := meta{name: obj.Name, namespace: obj.Namespace}
+		if kc.httproutes == nil {
+			kc.httproutes = make(map[meta]*gatewayv1.HTTPRoute)
+		}
+		kc.httproutes[m] = obj
+	case *gatewayv1.TLSRoute:
+		m := meta{name: obj.Name, namespace: obj.Namespace}
+		if kc.tlsroutes == nil {
+			kc.tlsroutes = make(map[meta]*gatewayv1.TLSRoute)
+		}
+		kc.tlsroutes[m] = obj
 	default:
 		// not an interesting object
 	}
@@ -121,6 +178,19 @@ func (kc *KubernetesCache) remove(obj interface{}) {
 	case *ingressroutev1.IngressRoute:
 		m := meta{name: obj.Name, namespace: obj.Namespace}
 		delete(kc.ingressroutes, m)
+	case *v1.Namespace:
+		delete(kc.namespaces, obj.Name)
+	case *gatewayv1.GatewayClass:
+		delete(kc.gatewayclasses, obj.Name)
+	case *gatewayv1.Gateway:
+		m := meta{name: obj.Name, namespace: obj.Namespace}
+		delete(kc.gateways, m)
+	case *gatewayv1.HTTPRoute:
+		m := meta{name: obj.Name, namespace: obj.Namespace}
+		delete(kc.httproutes, m)
+	case *gatewayv1.TLSRoute:
+		m := meta{name: obj.Name, namespace: obj.Namespace}
+		delete(kc.tlsroutes, m)
 	default:
 		// not interesting
 	}
@@ -149,12 +219,13 @@ type builder struct {
 
 	orphaned map[meta]bool
 
-	statuses []Status
+	statuses        []Status
+	gatewayStatuses []GatewayStatus
 }
 
 // lookupService returns a Service that matches the meta and port supplied.
 // If no matching Service is found lookup returns nil.
-func (b *builder) lookupService(m meta, port intstr.IntOrString, weight int, strategy string, hc *ingressroutev1.HealthCheck) *Service {
+func (b *builder) lookupService(m meta, port intstr.IntOrString, weight int, strategy string, hc *ingressroutev1.HealthCheck, reqhp, resphp *HeadersPolicy, uv *UpstreamValidation) *Service {
 	if port.Type == intstr.Int {
 		m := servicemeta{
 			name:        m.name,
@@ -163,6 +234,8 @@ func (b *builder) lookupService(m meta, port intstr.IntOrString, weight int, str
 			weight:      weight,
 			strategy:    strategy,
 			healthcheck: healthcheckToString(hc),
+			headers:     headersToString(reqhp, resphp),
+			validation:  validationToString(uv),
 		}
 		if s, ok := b.services[m]; ok {
 			return s
@@ -175,10 +248,10 @@ func (b *builder) lookupService(m meta, port intstr.IntOrString, weight int, str
 	for i := range svc.Spec.Ports {
 		p := &svc.Spec.Ports[i]
 		if int(p.Port) == port.IntValue() {
-			return b.addService(svc, p, weight, strategy, hc)
+			return b.addService(svc, p, weight, strategy, hc, reqhp, resphp, uv)
 		}
 		if port.String() == p.Name {
-			return b.addService(svc, p, weight, strategy, hc)
+			return b.addService(svc, p, weight, strategy, hc, reqhp, resphp, uv)
 		}
 	}
 	return nil
@@ -188,11 +261,22 @@ func healthcheckToString(hc *ingressroutev1.HealthCheck) string {
 	return fmt.Sprintf("%#v", hc)
 }
 
-func (b *builder) addService(svc *v1.Service, port *v1.ServicePort, weight int, strategy string, hc *ingressroutev1.HealthCheck) *Service {
+func headersToString(reqhp, resphp *HeadersPolicy) string {
+	return fmt.Sprintf("%#v %#v", reqhp, resphp)
+}
+
+func validationToString(uv *UpstreamValidation) string {
+	if uv == nil {
+		return ""
+	}
+	return uv.CACertificate.Namespace() + "/" + uv.CACertificate.Name() + " " + uv.SubjectName
+}
+
+func (b *builder) addService(svc *v1.Service, port *v1.ServicePort, weight int, strategy string, hc *ingressroutev1.HealthCheck, reqhp, resphp *HeadersPolicy, uv *UpstreamValidation) *Service {
 	if b.services == nil {
 		b.services = make(map[servicemeta]*Service)
 	}
-	up := parseUpstreamProtocols(svc.Annotations, annotationUpstreamProtocol, "h2", "h2c")
+	up := parseUpstreamProtocols(svc.Annotations, annotationUpstreamProtocol, "h2", "h2c", "tls")
 	protocol := up[port.Name]
 	if protocol == "" {
 		protocol = up[strconv.Itoa(int(port.Port))]
@@ -210,11 +294,26 @@ func (b *builder) addService(svc *v1.Service, port *v1.ServicePort, weight int,
 		MaxPendingRequests: parseAnnotation(svc.Annotations, annotationMaxPendingRequests),
 		MaxRequests:        parseAnnotation(svc.Annotations, annotationMaxRequests),
 		MaxRetries:         parseAnnotation(svc.Annotations, annotationMaxRetries),
+
+		RequestHeadersPolicy:  reqhp,
+		ResponseHeadersPolicy: resphp,
+		UpstreamValidation:    uv,
+	}
+	if (protocol == "tls" || protocol == "h2") && b.source.ClientCertificate != nil {
+		m := meta{name: b.source.ClientCertificate.Name, namespace: b.source.ClientCertificate.Namespace}
+		if sec := b.lookupSecret(m); sec != nil && validClientCertificate(sec) {
+			s.ClientCertificate = sec
+		}
 	}
 	b.services[s.toMeta()] = s
 	return s
 }
 
+// validClientCertificate returns true if the secret holds a certificate and key.
+func validClientCertificate(s *Secret) bool {
+	return len(s.Data()[v1.TLSCertKey]) > 0 && len(s.Data()[v1.TLSPrivateKeyKey]) > 0
+}
+
 func (b *builder) lookupSecret(m meta) *Secret {
 	if s, ok := b.secrets[m]; ok {
 		return s
@@ -325,7 +424,7 @@ func (b *builder) compute() *DAG {
 
 				r := prefixRoute(ing, prefix)
 				m := meta{name: httppath.Backend.ServiceName, namespace: ing.Namespace}
-				if s := b.lookupService(m, httppath.Backend.ServicePort, 0, "", nil); s != nil {
+				if s := b.lookupService(m, httppath.Backend.ServicePort, 0, "", nil, nil, nil, nil); s != nil {
 					r.addService(s)
 				}
 
@@ -350,7 +449,7 @@ func (b *builder) compute() *DAG {
 
 		// ensure root ingressroute lives in allowed namespace
 		if !b.rootAllowed(ir) {
-			b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: "root IngressRoute cannot be defined in this namespace"})
+			b.setStatus(Status{Object: ir, Status: StatusInvalid, Reason: ReasonRootNamespaceNotAllowed, Description: "root IngressRoute cannot be defined in this namespace"})
 			continue
 		}
 
@@ -360,7 +459,33 @@ func (b *builder) compute() *DAG {
 			continue
 		}
 
+		if ir.Spec.TCPProxy != nil {
+			b.processTCPProxy(ir, host)
+			continue
+		}
+
+		if cp := ir.Spec.VirtualHost.CORSPolicy; cp != nil {
+			policy, err := corsPolicy(cp)
+			if err != nil {
+				b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("virtualhost cors policy: %s", err), Vhost: host})
+				continue
+			}
+			b.lookupVirtualHost(host, 80).CORSPolicy = policy
+			b.lookupSecureVirtualHost(host, 443).CORSPolicy = policy
+		}
+
 		enforceTLS := false
+		if auth := ir.Spec.VirtualHost.Authorization; auth != nil {
+			authz, err := b.authorizationServer(ir, auth)
+			if err != nil {
+				b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: err.Error(), Vhost: host})
+				continue
+			}
+			b.lookupSecureVirtualHost(host, 443).AuthorizationServer = authz
+			// requests must not bypass authorization over insecure HTTP.
+			enforceTLS = true
+		}
+
 		if tls := ir.Spec.VirtualHost.TLS; tls != nil {
 			// attach secrets to TLS enabled vhosts
 			m := meta{name: tls.SecretName, namespace: ir.Namespace}
@@ -369,22 +494,16 @@ func (b *builder) compute() *DAG {
 				svhost.secret = sec
 				enforceTLS = true
 
-				// process min protocol version
-				switch ir.Spec.VirtualHost.TLS.MinimumProtocolVersion {
-				case "1.3":
-					svhost.MinProtoVersion = auth.TlsParameters_TLSv1_3
-				case "1.2":
-					svhost.MinProtoVersion = auth.TlsParameters_TLSv1_2
-				default:
-					// any other value is interpreted as TLS/1.1
-					svhost.MinProtoVersion = auth.TlsParameters_TLSv1_1
-				}
+				svhost.MinProtoVersion = tlsMinProtoVersion(tls)
 			}
 		}
 
-		b.processIngressRoute(ir, "", nil, host, enforceTLS)
+		b.processIngressRoute(ir, "", nil, nil, host, enforceTLS)
 	}
 
+	// process gateway api documents
+	b.processGateways()
+
 	return b.DAG()
 }
 
@@ -461,7 +580,7 @@ func (b *builder) validIngressRoutes() []*ingressroutev1.IngressRoute {
 		sort.Strings(conflicting) // sort for test stability
 		msg := fmt.Sprintf("fqdn %q is used in multiple IngressRoutes: %s", fqdn, strings.Join(conflicting, ", "))
 		for _, ir := range irs {
-			b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: msg, Vhost: fqdn})
+			b.setStatus(Status{Object: ir, Status: StatusInvalid, Reason: ReasonDuplicateFQDN, Description: msg, Vhost: fqdn})
 		}
 	}
 	return valid
@@ -477,22 +596,34 @@ func (b *builder) DAG() *DAG {
 		}
 	}
 	for _, svh := range b.svhosts {
-		if svh.secret != nil {
+		if svh.secret != nil || svh.TCPProxy != nil {
 			dag.roots = append(dag.roots, svh)
 		}
 	}
 	for meta := range b.orphaned {
 		ir, ok := b.source.ingressroutes[meta]
 		if ok {
-			b.setStatus(Status{Object: ir, Status: StatusOrphaned, Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"})
+			b.setStatus(Status{Object: ir, Status: StatusOrphaned, Reason: ReasonOrphaned, Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"})
 		}
 	}
 	dag.statuses = b.statuses
+	dag.gatewayStatuses = b.gatewayStatuses
 	return &dag
 }
 
-// setStatus assigns a status to an object.
+// setStatus assigns a status to an object. If st does not carry a
+// Reason, a generic one is derived from its Status.
 func (b *builder) setStatus(st Status) {
+	if st.Reason == "" {
+		switch st.Status {
+		case StatusValid:
+			st.Reason = ReasonValid
+		case StatusOrphaned:
+			st.Reason = ReasonOrphaned
+		default:
+			st.Reason = ReasonInvalidSpec
+		}
+	}
 	b.statuses = append(b.statuses, st)
 }
 
@@ -518,7 +649,9 @@ func (b *builder) rootAllowed(ir *ingressroutev1.IngressRoute) bool {
 	return false
 }
 
-func (b *builder) processIngressRoute(ir *ingressroutev1.IngressRoute, prefixMatch string, visited []*ingressroutev1.IngressRoute, host string, enforceTLS bool) {
+// processIngressRoute adds the routes of ir to the virtual host. prefixMatch and conditions
+// are the path prefix and the header and query parameter conditions of the delegating route.
+func (b *builder) processIngressRoute(ir *ingressroutev1.IngressRoute, prefixMatch string, conditions []ingressroutev1.MatchCondition, visited []*ingressroutev1.IngressRoute, host string, enforceTLS bool) {
 	visited = append(visited, ir)
 
 	for _, route := range ir.Spec.Routes {
@@ -527,10 +660,18 @@ func (b *builder) processIngressRoute(ir *ingressroutev1.IngressRoute, prefixMat
 			b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: cannot specify services and delegate in the same route", route.Match), Vhost: host})
 			return
 		}
+		if route.PermitInsecure && b.lookupSecureVirtualHost(host, 443).AuthorizationServer != nil {
+			b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: cannot specify permitInsecure on a route which requires authorization", route.Match), Vhost: host})
+			return
+		}
+		if err := validateConditions(route); err != nil {
+			b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: %s", route.Match, err), Vhost: host})
+			return
+		}
 		// base case: The route points to services, so we add them to the vhost
 		if len(route.Services) > 0 {
 			if !matchesPathPrefix(route.Match, prefixMatch) {
-				b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("the path prefix %q does not match the parent's path prefix %q", route.Match, prefixMatch), Vhost: host})
+				b.setStatus(Status{Object: ir, Status: StatusInvalid, Reason: ReasonPrefixMismatch, Description: fmt.Sprintf("the path prefix %q does not match the parent's path prefix %q", route.Match, prefixMatch), Vhost: host})
 				return
 			}
 
@@ -544,6 +685,25 @@ func (b *builder) processIngressRoute(ir *ingressroutev1.IngressRoute, prefixMat
 				HTTPSUpgrade:  enforceTLSRoute,
 				PrefixRewrite: route.PrefixRewrite,
 			}
+			applyConditions(r, append(conditions, route.Conditions...))
+			if route.HostRewrite != "" {
+				if isBlank(route.HostRewrite) {
+					b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: hostRewrite cannot be blank", route.Match), Vhost: host})
+					return
+				}
+				r.HostRewrite = route.HostRewrite
+			}
+			reqhp, err := headersPolicy(route.RequestHeadersPolicy, true)
+			if err != nil {
+				b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: request headers policy: %s", route.Match, err), Vhost: host})
+				return
+			}
+			resphp, err := headersPolicy(route.ResponseHeadersPolicy, false)
+			if err != nil {
+				b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: response headers policy: %s", route.Match, err), Vhost: host})
+				return
+			}
+			r.RequestHeadersPolicy, r.ResponseHeadersPolicy = reqhp, resphp
 			for _, s := range route.Services {
 				if s.Port < 1 || s.Port > 65535 {
 					b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: service %q: port must be in the range 1-65535", route.Match, s.Name), Vhost: host})
@@ -553,8 +713,27 @@ func (b *builder) processIngressRoute(ir *ingressroutev1.IngressRoute, prefixMat
 					b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: service %q: weight must be greater than or equal to zero", route.Match, s.Name), Vhost: host})
 					return
 				}
+				reqhp, err := headersPolicy(s.RequestHeadersPolicy, true)
+				if err != nil {
+					b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: service %q: request headers policy: %s", route.Match, s.Name, err), Vhost: host})
+					return
+				}
+				resphp, err := headersPolicy(s.ResponseHeadersPolicy, false)
+				if err != nil {
+					b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: service %q: response headers policy: %s", route.Match, s.Name, err), Vhost: host})
+					return
+				}
+				uv, err := b.upstreamValidation(ir, s)
+				if err != nil {
+					b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: service %q: %s", route.Match, s.Name, err), Vhost: host})
+					return
+				}
 				m := meta{name: s.Name, namespace: ir.Namespace}
-				if svc := b.lookupService(m, intstr.FromInt(s.Port), s.Weight, s.Strategy, s.HealthCheck); svc != nil {
+				if svc := b.lookupService(m, intstr.FromInt(s.Port), s.Weight, s.Strategy, s.HealthCheck, reqhp, resphp, uv); svc != nil {
+					if err := validateUpstreamProtocol(svc); err != nil {
+						b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("route %q: service %q: %s", route.Match, s.Name, err), Vhost: host})
+						return
+					}
 					r.addService(svc)
 				}
 			}
@@ -589,18 +768,350 @@ func (b *builder) processIngressRoute(ir *ingressroutev1.IngressRoute, prefixMat
 				if dest.Name == vir.Name && dest.Namespace == vir.Namespace {
 					path = append(path, fmt.Sprintf("%s/%s", dest.Namespace, dest.Name))
 					description := fmt.Sprintf("route creates a delegation cycle: %s", strings.Join(path, " -> "))
-					b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: description, Vhost: host})
+					b.setStatus(Status{Object: ir, Status: StatusInvalid, Reason: ReasonDelegationCycle, Description: description, Vhost: host})
 					return
 				}
 			}
 
-			// follow the link and process the target ingress route
-			b.processIngressRoute(dest, route.Match, visited, host, enforceTLS)
+			// follow the link and process the target ingress route,
+			// routes of the target inherit conditions of this route.
+			inherited := append(conditions[:len(conditions):len(conditions)], route.Conditions...)
+			b.processIngressRoute(dest, route.Match, inherited, visited, host, enforceTLS)
 		}
 	}
 	b.setStatus(Status{Object: ir, Status: StatusValid, Description: "valid IngressRoute", Vhost: host})
 }
 
+// processTCPProxy adds a TCP proxy for the root IngressRoute ir to the
+// secure virtual host for host.
+func (b *builder) processTCPProxy(ir *ingressroutev1.IngressRoute, host string) {
+	tls := ir.Spec.VirtualHost.TLS
+	switch {
+	case len(ir.Spec.Routes) > 0:
+		b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: "cannot specify both tcpproxy and routes", Vhost: host})
+		return
+	case tls == nil:
+		b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: "tcpproxy requires that the virtualhost specify tls", Vhost: host})
+		return
+	case tls.Passthrough && tls.SecretName != "":
+		b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: "cannot specify both tls.passthrough and tls.secretName", Vhost: host})
+		return
+	case !tls.Passthrough && tls.SecretName == "":
+		b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: "tcpproxy requires either tls.passthrough or tls.secretName", Vhost: host})
+		return
+	case len(ir.Spec.TCPProxy.Services) == 0:
+		b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: "tcpproxy must specify at least one service", Vhost: host})
+		return
+	}
+
+	var sec *Secret
+	if !tls.Passthrough {
+		sec = b.lookupSecret(meta{name: tls.SecretName, namespace: ir.Namespace})
+		if sec == nil {
+			// the secret is not present yet, the proxy is added once it is.
+			b.setStatus(Status{Object: ir, Status: StatusValid, Description: "valid IngressRoute", Vhost: host})
+			return
+		}
+	}
+
+	var proxy TCPProxy
+	for _, s := range ir.Spec.TCPProxy.Services {
+		if s.Port < 1 || s.Port > 65535 {
+			b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("tcpproxy: service %q: port must be in the range 1-65535", s.Name), Vhost: host})
+			return
+		}
+		if s.Weight < 0 {
+			b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("tcpproxy: service %q: weight must be greater than or equal to zero", s.Name), Vhost: host})
+			return
+		}
+		if s.RequestHeadersPolicy != nil || s.ResponseHeadersPolicy != nil {
+			b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("tcpproxy: service %q: cannot specify headers policies", s.Name), Vhost: host})
+			return
+		}
+		uv, err := b.upstreamValidation(ir, s)
+		if err != nil {
+			b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("tcpproxy: service %q: %s", s.Name, err), Vhost: host})
+			return
+		}
+		m := meta{name: s.Name, namespace: ir.Namespace}
+		if svc := b.lookupService(m, intstr.FromInt(s.Port), s.Weight, s.Strategy, s.HealthCheck, nil, nil, uv); svc != nil {
+			if err := validateUpstreamProtocol(svc); err != nil {
+				b.setStatus(Status{Object: ir, Status: StatusInvalid, Description: fmt.Sprintf("tcpproxy: service %q: %s", s.Name, err), Vhost: host})
+				return
+			}
+			proxy.addService(svc)
+		}
+	}
+
+	svhost := b.lookupSecureVirtualHost(host, 443)
+	svhost.secret = sec
+	svhost.MinProtoVersion = tlsMinProtoVersion(tls)
+	svhost.TCPProxy = &proxy
+	b.setStatus(Status{Object: ir, Status: StatusValid, Description: "valid IngressRoute", Vhost: host})
+}
+
+// authorizationServer returns the AuthorizationServer of the root IngressRoute ir.
+func (b *builder) authorizationServer(ir *ingressroutev1.IngressRoute, auth *ingressroutev1.AuthorizationServer) (*AuthorizationServer, error) {
+	if ir.Spec.VirtualHost.TLS == nil {
+		return nil, fmt.Errorf("authorization requires that the virtualhost specify tls")
+	}
+	if auth.Port < 1 || auth.Port > 65535 {
+		return nil, fmt.Errorf("authorization service %q: port must be in the range 1-65535", auth.Name)
+	}
+	var timeout time.Duration
+	if auth.ResponseTimeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(auth.ResponseTimeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("authorization service %q: invalid response timeout %q", auth.Name, auth.ResponseTimeout)
+		}
+	}
+	svc := b.lookupService(meta{name: auth.Name, namespace: ir.Namespace}, intstr.FromInt(auth.Port), 0, "", nil, nil, nil, nil)
+	if svc == nil {
+		return nil, fmt.Errorf("authorization service %q not found", auth.Name)
+	}
+	if svc.Protocol != "h2" && svc.Protocol != "h2c" {
+		return nil, fmt.Errorf("authorization service %q: port %d must use the h2 or h2c upstream protocol", auth.Name, auth.Port)
+	}
+	return &AuthorizationServer{
+		Service:         svc,
+		FailOpen:        auth.FailOpen,
+		ResponseTimeout: timeout,
+	}, nil
+}
+
+// upstreamValidation returns the UpstreamValidation of the service s of ir.
+func (b *builder) upstreamValidation(ir *ingressroutev1.IngressRoute, s ingressroutev1.Service) (*UpstreamValidation, error) {
+	uv := s.UpstreamValidation
+	if uv == nil {
+		return nil, nil
+	}
+	if isBlank(uv.CACertificate) {
+		return nil, fmt.Errorf("upstream validation must specify caSecret")
+	}
+	if isBlank(uv.SubjectName) {
+		return nil, fmt.Errorf("upstream validation must specify subjectName")
+	}
+	sec := b.lookupSecret(meta{name: uv.CACertificate, namespace: ir.Namespace})
+	if sec == nil {
+		return nil, fmt.Errorf("upstream validation: secret %q not found", uv.CACertificate)
+	}
+	if len(sec.Data()[CACertificateKey]) == 0 {
+		return nil, fmt.Errorf("upstream validation: secret %q has no %s", uv.CACertificate, CACertificateKey)
+	}
+	return &UpstreamValidation{
+		CACertificate: sec,
+		SubjectName:   uv.SubjectName,
+	}, nil
+}
+
+// validateUpstreamProtocol checks that a service with upstream validation uses TLS.
+func validateUpstreamProtocol(svc *Service) error {
+	if svc.UpstreamValidation != nil && svc.Protocol != "tls" && svc.Protocol != "h2" {
+		return fmt.Errorf("upstream validation requires the tls or h2 upstream protocol")
+	}
+	return nil
+}
+
+// tlsMinProtoVersion returns the minimum TLS protocol version of an IngressRoute's TLS config.
+func tlsMinProtoVersion(tls *ingressroutev1.TLS) auth.TlsParameters_TlsProtocol {
+	switch tls.MinimumProtocolVersion {
+	case "1.3":
+		return auth.TlsParameters_TLSv1_3
+	case "1.2":
+		return auth.TlsParameters_TLSv1_2
+	default:
+		// any other value is interpreted as TLS/1.1
+		return auth.TlsParameters_TLSv1_1
+	}
+}
+
+// corsPolicy validates an IngressRoute CORS policy and
+// returns the corresponding CORSPolicy.
+func corsPolicy(in *ingressroutev1.CORSPolicy) (*CORSPolicy, error) {
+	if len(in.AllowOrigin) == 0 {
+		return nil, fmt.Errorf("allowOrigin must specify at least one origin")
+	}
+	if len(in.AllowMethods) == 0 {
+		return nil, fmt.Errorf("allowMethods must specify at least one method")
+	}
+	for _, values := range [][]string{in.AllowOrigin, in.AllowMethods, in.AllowHeaders, in.ExposeHeaders} {
+		for _, v := range values {
+			if isBlank(v) {
+				return nil, fmt.Errorf("allowed origins, methods and headers cannot be blank")
+			}
+		}
+	}
+	policy := &CORSPolicy{
+		AllowOrigin:      in.AllowOrigin,
+		AllowMethods:     in.AllowMethods,
+		AllowHeaders:     in.AllowHeaders,
+		ExposeHeaders:    in.ExposeHeaders,
+		AllowCredentials: in.AllowCredentials,
+	}
+	if in.MaxAge != "" {
+		d, err := time.ParseDuration(in.MaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("maxAge %q is not a valid duration", in.MaxAge)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("maxAge %q cannot be negative", in.MaxAge)
+		}
+		policy.MaxAge = d
+	}
+	return policy, nil
+}
+
+// headersPolicy validates an IngressRoute headers policy and returns
+// the corresponding HeadersPolicy. If request is true, the policy applies
+// to requests, which may not manage the Host header.
+func headersPolicy(in *ingressroutev1.HeadersPolicy, request bool) (*HeadersPolicy, error) {
+	if in == nil {
+		return nil, nil
+	}
+	valid := func(name string) error {
+		switch {
+		case isBlank(name):
+			return fmt.Errorf("header name cannot be blank")
+		case strings.HasPrefix(name, ":"):
+			return fmt.Errorf("pseudo header %q cannot be managed", name)
+		case request && strings.EqualFold(name, "host"):
+			return fmt.Errorf("the Host header can only be rewritten with hostRewrite")
+		}
+		return nil
+	}
+	var policy HeadersPolicy
+	seen := make(map[string]bool)
+	for _, h := range in.Set {
+		if err := valid(h.Name); err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(h.Name)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate header %q", h.Name)
+		}
+		seen[key] = true
+		policy.Set = append(policy.Set, HeaderValue{Name: key, Value: h.Value})
+	}
+	for _, name := range in.Remove {
+		if err := valid(name); err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate header %q", name)
+		}
+		seen[key] = true
+		policy.Remove = append(policy.Remove, key)
+	}
+	return &policy, nil
+}
+
+// validateConditions checks the match conditions of an IngressRoute route.
+func validateConditions(route ingressroutev1.Route) error {
+	var pathConditions int
+	for _, c := range route.Conditions {
+		var set int
+		for _, ok := range []bool{c.Path != "", c.Regex != "", c.Method != "", c.Header != nil, c.QueryParameter != nil} {
+			if ok {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("condition must specify exactly one of path, regex, method, header or queryParameter")
+		}
+
+		switch {
+		case c.Path != "":
+			pathConditions++
+			if !matchesPathPrefix(c.Path, route.Match) {
+				return fmt.Errorf("path condition %q does not match the route's path prefix", c.Path)
+			}
+		case c.Regex != "":
+			pathConditions++
+			prefix := regexp.QuoteMeta(route.Match)
+			if !strings.HasPrefix(c.Regex, prefix) {
+				return fmt.Errorf("regex condition %q must start with the route's path prefix", c.Regex)
+			}
+			if _, err := regexp.Compile(c.Regex); err != nil {
+				return fmt.Errorf("regex condition %q is invalid: %v", c.Regex, err)
+			}
+			// the remainder is grouped after the prefix, see prefixRegex, so it
+			// must be a valid expression on its own to stay within the group.
+			if _, err := regexp.Compile(strings.TrimPrefix(c.Regex, prefix)); err != nil {
+				return fmt.Errorf("regex condition %q is invalid after the route's path prefix: %v", c.Regex, err)
+			}
+		case c.Method != "":
+			if !httpMethod.MatchString(c.Method) {
+				return fmt.Errorf("method condition %q must be an upper case HTTP method", c.Method)
+			}
+		case c.Header != nil:
+			if isBlank(c.Header.Name) {
+				return fmt.Errorf("header condition must specify a header name")
+			}
+			if c.Header.Exact != "" && c.Header.Contains != "" {
+				return fmt.Errorf("header condition %q cannot specify both exact and contains", c.Header.Name)
+			}
+		case c.QueryParameter != nil:
+			if isBlank(c.QueryParameter.Name) {
+				return fmt.Errorf("query parameter condition must specify a parameter name")
+			}
+		}
+	}
+	if pathConditions > 1 {
+		return fmt.Errorf("cannot specify more than one path or regex condition")
+	}
+	if pathConditions > 0 && route.Delegate != nil {
+		return fmt.Errorf("cannot specify path or regex conditions on a delegating route")
+	}
+	return nil
+}
+
+// httpMethod matches the request methods of method conditions.
+var httpMethod = regexp.MustCompile(`^[A-Z]+$`)
+
+// prefixRegex returns the regex condition of a route with the supplied prefix.
+// Envoy matches the regex against the whole path, so the remainder after the
+// prefix is grouped, an alternation in it cannot match paths outside the prefix.
+func prefixRegex(prefix, regex string) string {
+	quoted := regexp.QuoteMeta(prefix)
+	return quoted + "(?:" + strings.TrimPrefix(regex, quoted) + ")"
+}
+
+// applyConditions translates IngressRoute match conditions to the matches of r.
+func applyConditions(r *Route, conditions []ingressroutev1.MatchCondition) {
+	for _, c := range conditions {
+		switch {
+		case c.Path != "":
+			r.Path = c.Path
+		case c.Regex != "":
+			r.Regex = prefixRegex(r.Prefix, c.Regex)
+		case c.Method != "":
+			r.HeaderConditions = append(r.HeaderConditions, HeaderCondition{
+				Name:      ":method",
+				MatchType: HeaderMatchTypeExact,
+				Value:     c.Method,
+			})
+		case c.Header != nil:
+			hc := HeaderCondition{Name: c.Header.Name, MatchType: HeaderMatchTypePresent}
+			switch {
+			case c.Header.Exact != "":
+				hc.MatchType = HeaderMatchTypeExact
+				hc.Value = c.Header.Exact
+			case c.Header.Contains != "":
+				hc.MatchType = HeaderMatchTypeContains
+				hc.Value = c.Header.Contains
+			}
+			r.HeaderConditions = append(r.HeaderConditions, hc)
+		case c.QueryParameter != nil:
+			r.QueryParameterConditions = append(r.QueryParameterConditions, QueryParameterCondition{
+				Name:  c.QueryParameter.Name,
+				Value: c.QueryParameter.Exact,
+			})
+		}
+	}
+}
+
 // routeEnforceTLS determines if the route should redirect the user to a secure TLS listener
 func routeEnforceTLS(enforceTLS, permitInsecure bool) bool {
 	return enforceTLS && !permitInsecure
@@ -639,6 +1150,47 @@ func matchesPathPrefix(path, prefix string) bool {
 type Status struct {
 	Object      *ingressroutev1.IngressRoute
 	Status      string
+	Reason      string
 	Description string
 	Vhost       string
 }
+
+// IngressRouteStatus is the status of a single IngressRoute, aggregated
+// from every Status recorded against it while building the DAG.
+// An IngressRoute delegated to from several roots, or rejected for
+// several reasons, carries one Condition for each.
+type IngressRouteStatus struct {
+	Object *ingressroutev1.IngressRoute
+
+	// Status is invalid if any Condition is invalid, otherwise
+	// valid if any Condition is valid, otherwise orphaned.
+	Status string
+
+	// Description is the Description of the first Condition
+	// which matches Status.
+	Description string
+
+	Conditions []Status
+}
+
+// statusRank orders statuses by precedence when aggregating.
+var statusRank = map[string]int{
+	StatusOrphaned: 1,
+	StatusValid:    2,
+	StatusInvalid:  3,
+}
+
+// add records st as a Condition of irs, unless an identical
+// Condition has already been recorded.
+func (irs *IngressRouteStatus) add(st Status) {
+	for _, c := range irs.Conditions {
+		if c == st {
+			return
+		}
+	}
+	irs.Conditions = append(irs.Conditions, st)
+	if statusRank[st.Status] > statusRank[irs.Status] {
+		irs.Status = st.Status
+		irs.Description = st.Description
+	}
+}
@@ -15,6 +15,7 @@ package dag
 
 import (
 	"reflect"
+	"regexp"
 	"sort"
 	"testing"
 	"time"
@@ -25,6 +26,7 @@ import (
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -1020,6 +1022,113 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// ir15 delegates a route with header and method conditions to ir16
+	ir15 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/blog",
+				Conditions: []ingressroutev1.MatchCondition{{
+					Header: &ingressroutev1.HeaderCondition{
+						Name:  "x-canary",
+						Exact: "true",
+					},
+				}, {
+					Method: "GET",
+				}},
+				Delegate: &ingressroutev1.Delegate{
+					Name:      "blog",
+					Namespace: "marketing",
+				},
+			}},
+		},
+	}
+
+	// ir16 is a delegate ingressroute with path and query parameter conditions
+	ir16 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "blog",
+			Namespace: "marketing",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			Routes: []ingressroutev1.Route{{
+				Match: "/blog",
+				Services: []ingressroutev1.Service{{
+					Name: "blog",
+					Port: 8080,
+				}},
+			}, {
+				Match: "/blog",
+				Conditions: []ingressroutev1.MatchCondition{{
+					Path: "/blog/index",
+				}, {
+					QueryParameter: &ingressroutev1.QueryParameterCondition{
+						Name: "page",
+					},
+				}, {
+					Header: &ingressroutev1.HeaderCondition{
+						Name:     "user-agent",
+						Contains: "Firefox",
+					},
+				}},
+				Services: []ingressroutev1.Service{{
+					Name: "blog",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// ir17 proxies TCP connections and passes TLS through
+	ir17 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard-tcp",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "kuard.example.com",
+				TLS: &ingressroutev1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &ingressroutev1.TCPProxy{
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	// ir18 terminates TLS and proxies TCP connections
+	ir18 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard-tcp",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "kuard.example.com",
+				TLS: &ingressroutev1.TLS{
+					SecretName: "secret",
+				},
+			},
+			TCPProxy: &ingressroutev1.TCPProxy{
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
 	sec1 := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "secret",
@@ -2251,6 +2360,108 @@ func TestDAGInsert(t *testing.T) {
 				},
 			},
 		},
+		"insert root ingress route and delegate ingress route with conditions": {
+			objs: []interface{}{
+				ir16, s4, ir15,
+			},
+			want: []Vertex{
+				&VirtualHost{
+					Host: "example.com",
+					Port: 80,
+					routes: routemap(
+						&Route{
+							Prefix: "/blog",
+							HeaderConditions: []HeaderCondition{{
+								Name: "x-canary", MatchType: HeaderMatchTypeExact, Value: "true",
+							}, {
+								Name: ":method", MatchType: HeaderMatchTypeExact, Value: "GET",
+							}},
+							object: ir16,
+							services: servicemap(
+								&Service{
+									Object:      s4,
+									ServicePort: &s4.Spec.Ports[0],
+								},
+							),
+						},
+						&Route{
+							Prefix: "/blog",
+							Path:   "/blog/index",
+							HeaderConditions: []HeaderCondition{{
+								Name: "x-canary", MatchType: HeaderMatchTypeExact, Value: "true",
+							}, {
+								Name: ":method", MatchType: HeaderMatchTypeExact, Value: "GET",
+							}, {
+								Name: "user-agent", MatchType: HeaderMatchTypeContains, Value: "Firefox",
+							}},
+							QueryParameterConditions: []QueryParameterCondition{{
+								Name: "page",
+							}},
+							object: ir16,
+							services: servicemap(
+								&Service{
+									Object:      s4,
+									ServicePort: &s4.Spec.Ports[0],
+								},
+							),
+						},
+					),
+				},
+			},
+		},
+		"insert ingressroute with tcpproxy and tls passthrough": {
+			objs: []interface{}{
+				ir17, s1,
+			},
+			want: []Vertex{
+				&SecureVirtualHost{
+					VirtualHost: VirtualHost{
+						Host: "kuard.example.com",
+						Port: 443,
+					},
+					MinProtoVersion: auth.TlsParameters_TLSv1_1,
+					TCPProxy: &TCPProxy{
+						services: servicemap(
+							&Service{
+								Object:      s1,
+								ServicePort: &s1.Spec.Ports[0],
+							},
+						),
+					},
+				},
+			},
+		},
+		"insert ingressroute with tcpproxy terminating tls": {
+			objs: []interface{}{
+				ir18, s1, sec1,
+			},
+			want: []Vertex{
+				&SecureVirtualHost{
+					VirtualHost: VirtualHost{
+						Host: "kuard.example.com",
+						Port: 443,
+					},
+					MinProtoVersion: auth.TlsParameters_TLSv1_1,
+					TCPProxy: &TCPProxy{
+						services: servicemap(
+							&Service{
+								Object:      s1,
+								ServicePort: &s1.Spec.Ports[0],
+							},
+						),
+					},
+					secret: &Secret{
+						object: sec1,
+					},
+				},
+			},
+		},
+		"insert ingressroute with tcpproxy terminating tls w/o secret": {
+			objs: []interface{}{
+				ir18, s1,
+			},
+			want: []Vertex{},
+		},
 		"insert ingress with retry annotations": {
 			objs: []interface{}{
 				i14,
@@ -2484,7 +2695,7 @@ func TestDAGInsert(t *testing.T) {
 			}
 
 			opts := []cmp.Option{
-				cmp.AllowUnexported(VirtualHost{}, SecureVirtualHost{}, Route{}, Secret{}),
+				cmp.AllowUnexported(VirtualHost{}, SecureVirtualHost{}, Route{}, Secret{}, TCPProxy{}),
 			}
 			if diff := cmp.Diff(want, got, opts...); diff != "" {
 				t.Fatal(diff)
@@ -2587,7 +2798,7 @@ func TestBuilderLookupService(t *testing.T) {
 					},
 				},
 			}
-			got := b.lookupService(tc.meta, tc.port, tc.weight, tc.strategy, tc.healthcheck)
+			got := b.lookupService(tc.meta, tc.port, tc.weight, tc.strategy, tc.healthcheck, nil, nil, nil)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Fatal(diff)
 			}
@@ -2799,6 +3010,137 @@ func TestDAGIngressRouteDelegatePrefixDoesntMatch(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+func TestDAGIngressRouteDelegateRegexCannotEscapePrefix(t *testing.T) {
+	service := func(ns, name string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns,
+				Name:      name,
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Protocol: "TCP",
+					Port:     8080,
+				}},
+			},
+		}
+	}
+	// root serves /admin itself and delegates /api to the team namespace
+	root := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "example-com",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/admin",
+				Services: []ingressroutev1.Service{{
+					Name: "admin",
+					Port: 8080,
+				}},
+			}, {
+				Match: "/api",
+				Delegate: &ingressroutev1.Delegate{
+					Name:      "api",
+					Namespace: "team",
+				},
+			}},
+		},
+	}
+	// child returns an IngressRoute delegated /api, which tries to match /admin with regex
+	child := func(regex string) *ingressroutev1.IngressRoute {
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "team",
+				Name:      "api",
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				Routes: []ingressroutev1.Route{{
+					Match: "/api",
+					Conditions: []ingressroutev1.MatchCondition{{
+						Regex: regex,
+					}},
+					Services: []ingressroutev1.Service{{
+						Name: "api",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		regex      string
+		wantRegex  string
+		wantStatus string
+	}{
+		"alternation is grouped after the prefix": {
+			regex:      "/api|/admin.*",
+			wantRegex:  "/api(?:|/admin.*)",
+			wantStatus: StatusValid,
+		},
+		"group is grouped after the prefix": {
+			regex:      "/api(/v1)|(/admin.*)",
+			wantRegex:  "/api(?:(/v1)|(/admin.*))",
+			wantStatus: StatusValid,
+		},
+		"quantifier applied to the prefix": {
+			regex:      "/api?|/admin.*",
+			wantStatus: StatusInvalid,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ir := child(tc.regex)
+			var b Builder
+			for _, o := range []interface{}{root, ir, service("default", "admin"), service("team", "api")} {
+				b.Insert(o)
+			}
+			dag := b.Build()
+
+			var regexes []string
+			dag.Visit(func(v Vertex) {
+				if v, ok := v.(*VirtualHost); ok {
+					v.Visit(func(r Vertex) {
+						if r, ok := r.(*Route); ok && r.Regex != "" {
+							regexes = append(regexes, r.Regex)
+						}
+					})
+				}
+			})
+			for _, regex := range regexes {
+				// envoy matches the regex against the whole path
+				if regexp.MustCompile("^(?:" + regex + ")$").MatchString("/admin") {
+					t.Errorf("delegated regex %q matches /admin", regex)
+				}
+			}
+
+			var status string
+			for _, s := range dag.Statuses() {
+				if s.Object == ir {
+					status = s.Status
+				}
+			}
+			if status != tc.wantStatus {
+				t.Fatalf("expected status %q, got %q: %v", tc.wantStatus, status, dag.Statuses())
+			}
+			if tc.wantRegex == "" {
+				if len(regexes) != 0 {
+					t.Fatalf("expected no regex routes, got: %q", regexes)
+				}
+				return
+			}
+			if len(regexes) != 1 || regexes[0] != tc.wantRegex {
+				t.Fatalf("expected regex %q, got: %q", tc.wantRegex, regexes)
+			}
+		})
+	}
+}
+
 func TestDAGRootNamespaces(t *testing.T) {
 	ir1 := &ingressroutev1.IngressRoute{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3331,77 +3673,1402 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		},
 	}
 
-	tests := map[string]struct {
-		objs []*ingressroutev1.IngressRoute
-		want []Status
-	}{
-		"valid ingressroute": {
-			objs: []*ingressroutev1.IngressRoute{ir1},
-			want: []Status{{Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"}},
-		},
-		"invalid port in service": {
-			objs: []*ingressroutev1.IngressRoute{ir2},
-			want: []Status{{Object: ir2, Status: "invalid", Description: `route "/foo": service "home": port must be in the range 1-65535`, Vhost: "example.com"}},
-		},
-		"root ingressroute outside of roots namespace": {
-			objs: []*ingressroutev1.IngressRoute{ir3},
-			want: []Status{{Object: ir3, Status: "invalid", Description: "root IngressRoute cannot be defined in this namespace"}},
+	// ir15 has a path condition outside of the route's prefix
+	ir15 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
 		},
-		"delegated route's match prefix does not match parent's prefix": {
-			objs: []*ingressroutev1.IngressRoute{ir1, ir4},
-			want: []Status{
-				{Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
-				{Object: ir4, Status: "invalid", Description: `the path prefix "/doesnotmatch" does not match the parent's path prefix "/prefix"`, Vhost: "example.com"},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/foo",
+				Conditions: []ingressroutev1.MatchCondition{{
+					Path: "/bar",
+				}},
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
 		},
-		"invalid weight in service": {
-			objs: []*ingressroutev1.IngressRoute{ir5},
-			want: []Status{{Object: ir5, Status: "invalid", Description: `route "/foo": service "home": weight must be greater than or equal to zero`, Vhost: "example.com"}},
-		},
-		"root ingressroute does not specify FQDN": {
-			objs: []*ingressroutev1.IngressRoute{ir13},
-			want: []Status{{Object: ir13, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"}},
-		},
-		"self-edge produces a cycle": {
-			objs: []*ingressroutev1.IngressRoute{ir6},
-			want: []Status{{Object: ir6, Status: "invalid", Description: "route creates a delegation cycle: roots/self -> roots/self", Vhost: "example.com"}},
+	}
+
+	// ir16 has a header condition with both exact and contains
+	ir16 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
 		},
-		"child delegates to parent, producing a cycle": {
-			objs: []*ingressroutev1.IngressRoute{ir7, ir8},
-			want: []Status{
-				{Object: ir7, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
-				{Object: ir8, Status: "invalid", Description: "route creates a delegation cycle: roots/parent -> roots/child -> roots/parent", Vhost: "example.com"},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
 			},
-		},
-		"route has a list of services and also delegates": {
+			Routes: []ingressroutev1.Route{{
+				Match: "/foo",
+				Conditions: []ingressroutev1.MatchCondition{{
+					Header: &ingressroutev1.HeaderCondition{
+						Name:     "x-header",
+						Exact:    "abc",
+						Contains: "b",
+					},
+				}},
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// ir17 has an invalid regex condition
+	ir17 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/foo",
+				Conditions: []ingressroutev1.MatchCondition{{
+					Regex: "/foo/[a-z",
+				}},
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// ir18 delegates a route with a path condition
+	ir18 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/foo",
+				Conditions: []ingressroutev1.MatchCondition{{
+					Path: "/foo/bar",
+				}},
+				Delegate: &ingressroutev1.Delegate{
+					Name: "child",
+				},
+			}},
+		},
+	}
+
+	// ir19 has a condition which sets more than one match
+	ir19 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/foo",
+				Conditions: []ingressroutev1.MatchCondition{{
+					Path: "/foo/bar",
+					QueryParameter: &ingressroutev1.QueryParameterCondition{
+						Name: "page",
+					},
+				}},
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// ir20 specifies tcpproxy without tls
+	ir20 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			TCPProxy: &ingressroutev1.TCPProxy{
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	// ir21 specifies both tls passthrough and a secret
+	ir21 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &ingressroutev1.TLS{
+					SecretName:  "secret",
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &ingressroutev1.TCPProxy{
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	// ir22 specifies tcpproxy and routes
+	ir22 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &ingressroutev1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &ingressroutev1.TCPProxy{
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// ir23 has an invalid port in a tcpproxy service
+	ir23 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &ingressroutev1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &ingressroutev1.TCPProxy{
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 80000,
+				}},
+			},
+		},
+	}
+
+	// ir24 is a valid tls passthrough tcpproxy
+	ir24 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &ingressroutev1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &ingressroutev1.TCPProxy{
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	// ir25 has a method condition which is not an upper case http method
+	ir25 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/foo",
+				Conditions: []ingressroutev1.MatchCondition{{
+					Method: "post",
+				}},
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	tests := map[string]struct {
+		objs []*ingressroutev1.IngressRoute
+		want []Status
+	}{
+		"valid ingressroute": {
+			objs: []*ingressroutev1.IngressRoute{ir1},
+			want: []Status{{Object: ir1, Status: "valid", Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "example.com"}},
+		},
+		"invalid port in service": {
+			objs: []*ingressroutev1.IngressRoute{ir2},
+			want: []Status{{Object: ir2, Status: "invalid", Reason: ReasonInvalidSpec, Description: `route "/foo": service "home": port must be in the range 1-65535`, Vhost: "example.com"}},
+		},
+		"root ingressroute outside of roots namespace": {
+			objs: []*ingressroutev1.IngressRoute{ir3},
+			want: []Status{{Object: ir3, Status: "invalid", Reason: ReasonRootNamespaceNotAllowed, Description: "root IngressRoute cannot be defined in this namespace"}},
+		},
+		"delegated route's match prefix does not match parent's prefix": {
+			objs: []*ingressroutev1.IngressRoute{ir1, ir4},
+			want: []Status{
+				{Object: ir1, Status: "valid", Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "example.com"},
+				{Object: ir4, Status: "invalid", Reason: ReasonPrefixMismatch, Description: `the path prefix "/doesnotmatch" does not match the parent's path prefix "/prefix"`, Vhost: "example.com"},
+			},
+		},
+		"invalid weight in service": {
+			objs: []*ingressroutev1.IngressRoute{ir5},
+			want: []Status{{Object: ir5, Status: "invalid", Reason: ReasonInvalidSpec, Description: `route "/foo": service "home": weight must be greater than or equal to zero`, Vhost: "example.com"}},
+		},
+		"root ingressroute does not specify FQDN": {
+			objs: []*ingressroutev1.IngressRoute{ir13},
+			want: []Status{{Object: ir13, Status: "invalid", Reason: ReasonInvalidSpec, Description: "Spec.VirtualHost.Fqdn must be specified"}},
+		},
+		"self-edge produces a cycle": {
+			objs: []*ingressroutev1.IngressRoute{ir6},
+			want: []Status{{Object: ir6, Status: "invalid", Reason: ReasonDelegationCycle, Description: "route creates a delegation cycle: roots/self -> roots/self", Vhost: "example.com"}},
+		},
+		"child delegates to parent, producing a cycle": {
+			objs: []*ingressroutev1.IngressRoute{ir7, ir8},
+			want: []Status{
+				{Object: ir7, Status: "valid", Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "example.com"},
+				{Object: ir8, Status: "invalid", Reason: ReasonDelegationCycle, Description: "route creates a delegation cycle: roots/parent -> roots/child -> roots/parent", Vhost: "example.com"},
+			},
+		},
+		"route has a list of services and also delegates": {
 			objs: []*ingressroutev1.IngressRoute{ir9},
-			want: []Status{{Object: ir9, Status: "invalid", Description: `route "/foo": cannot specify services and delegate in the same route`, Vhost: "example.com"}},
+			want: []Status{{Object: ir9, Status: "invalid", Reason: ReasonInvalidSpec, Description: `route "/foo": cannot specify services and delegate in the same route`, Vhost: "example.com"}},
+		},
+		"ingressroute is an orphaned route": {
+			objs: []*ingressroutev1.IngressRoute{ir8},
+			want: []Status{{Object: ir8, Status: "orphaned", Reason: ReasonOrphaned, Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"}},
+		},
+		"ingressroute delegates to multiple ingressroutes, one is invalid": {
+			objs: []*ingressroutev1.IngressRoute{ir10, ir11, ir12},
+			want: []Status{
+				{Object: ir11, Status: "valid", Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "example.com"},
+				{Object: ir12, Status: "invalid", Reason: ReasonInvalidSpec, Description: `route "/bar": service "foo": port must be in the range 1-65535`, Vhost: "example.com"},
+				{Object: ir10, Status: "valid", Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "example.com"},
+			},
+		},
+		"invalid parent orphans children": {
+			objs: []*ingressroutev1.IngressRoute{ir14, ir11},
+			want: []Status{
+				{Object: ir14, Status: "invalid", Reason: ReasonInvalidSpec, Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{Object: ir11, Status: "orphaned", Reason: ReasonOrphaned, Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"},
+			},
+		},
+		"path condition does not match the route's prefix": {
+			objs: []*ingressroutev1.IngressRoute{ir15},
+			want: []Status{{Object: ir15, Status: "invalid", Reason: ReasonInvalidSpec, Description: `route "/foo": path condition "/bar" does not match the route's path prefix`, Vhost: "example.com"}},
+		},
+		"header condition specifies exact and contains": {
+			objs: []*ingressroutev1.IngressRoute{ir16},
+			want: []Status{{Object: ir16, Status: "invalid", Reason: ReasonInvalidSpec, Description: `route "/foo": header condition "x-header" cannot specify both exact and contains`, Vhost: "example.com"}},
+		},
+		"invalid regex condition": {
+			objs: []*ingressroutev1.IngressRoute{ir17},
+			want: []Status{{Object: ir17, Status: "invalid", Reason: ReasonInvalidSpec, Description: "route \"/foo\": regex condition \"/foo/[a-z\" is invalid: error parsing regexp: missing closing ]: `[a-z`", Vhost: "example.com"}},
+		},
+		"delegating route specifies a path condition": {
+			objs: []*ingressroutev1.IngressRoute{ir18},
+			want: []Status{{Object: ir18, Status: "invalid", Reason: ReasonInvalidSpec, Description: `route "/foo": cannot specify path or regex conditions on a delegating route`, Vhost: "example.com"}},
+		},
+		"condition specifies more than one match": {
+			objs: []*ingressroutev1.IngressRoute{ir19},
+			want: []Status{{Object: ir19, Status: "invalid", Reason: ReasonInvalidSpec, Description: `route "/foo": condition must specify exactly one of path, regex, method, header or queryParameter`, Vhost: "example.com"}},
+		},
+		"tcpproxy without tls": {
+			objs: []*ingressroutev1.IngressRoute{ir20},
+			want: []Status{{Object: ir20, Status: "invalid", Reason: ReasonInvalidSpec, Description: "tcpproxy requires that the virtualhost specify tls", Vhost: "example.com"}},
+		},
+		"tls passthrough with secret": {
+			objs: []*ingressroutev1.IngressRoute{ir21},
+			want: []Status{{Object: ir21, Status: "invalid", Reason: ReasonInvalidSpec, Description: "cannot specify both tls.passthrough and tls.secretName", Vhost: "example.com"}},
+		},
+		"tcpproxy and routes": {
+			objs: []*ingressroutev1.IngressRoute{ir22},
+			want: []Status{{Object: ir22, Status: "invalid", Reason: ReasonInvalidSpec, Description: "cannot specify both tcpproxy and routes", Vhost: "example.com"}},
+		},
+		"invalid port in tcpproxy service": {
+			objs: []*ingressroutev1.IngressRoute{ir23},
+			want: []Status{{Object: ir23, Status: "invalid", Reason: ReasonInvalidSpec, Description: `tcpproxy: service "home": port must be in the range 1-65535`, Vhost: "example.com"}},
+		},
+		"invalid method condition": {
+			objs: []*ingressroutev1.IngressRoute{ir25},
+			want: []Status{{Object: ir25, Status: "invalid", Reason: ReasonInvalidSpec, Description: `route "/foo": method condition "post" must be an upper case HTTP method`, Vhost: "example.com"}},
+		},
+		"valid tcpproxy": {
+			objs: []*ingressroutev1.IngressRoute{ir24},
+			want: []Status{{Object: ir24, Status: "valid", Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "example.com"}},
+		},
+		"multi-parent children is not orphaned when one of the parents is invalid": {
+			objs: []*ingressroutev1.IngressRoute{ir14, ir11, ir10},
+			want: []Status{
+				{Object: ir14, Status: "invalid", Reason: ReasonInvalidSpec, Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{Object: ir11, Status: "valid", Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "example.com"},
+				{Object: ir10, Status: "valid", Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "example.com"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := Builder{
+				KubernetesCache: KubernetesCache{
+					IngressRouteRootNamespaces: []string{"roots"},
+				},
+			}
+			for _, o := range tc.objs {
+				b.Insert(o)
+			}
+			got := b.Build().Statuses()
+			if len(tc.want) != len(got) {
+				t.Fatalf("expected:\n%v\ngot\n%v", tc.want, got)
+			}
+
+			for _, ex := range tc.want {
+				var found bool
+				for _, g := range got {
+					if reflect.DeepEqual(ex, g) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Fatalf("expected to find:\n%v\nbut did not find it in:\n%v", ex, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDAGIngressRouteStatuses(t *testing.T) {
+	root := func(name, fqdn, match string) *ingressroutev1.IngressRoute {
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "roots",
+				Name:      name,
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn: fqdn,
+				},
+				Routes: []ingressroutev1.Route{{
+					Match: match,
+					Delegate: &ingressroutev1.Delegate{
+						Name: "child",
+					},
+				}},
+			},
+		}
+	}
+	a := root("a", "a.example.com", "/")
+	b := root("b", "b.example.com", "/")
+	c := root("c", "c.example.com", "/bar")
+
+	child := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "child",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	tests := map[string]struct {
+		objs []*ingressroutev1.IngressRoute
+		want []IngressRouteStatus
+	}{
+		"child delegated from two roots": {
+			objs: []*ingressroutev1.IngressRoute{a, b, child},
+			want: []IngressRouteStatus{{
+				Object:      a,
+				Status:      StatusValid,
+				Description: "valid IngressRoute",
+				Conditions: []Status{
+					{Object: a, Status: StatusValid, Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "a.example.com"},
+				},
+			}, {
+				Object:      b,
+				Status:      StatusValid,
+				Description: "valid IngressRoute",
+				Conditions: []Status{
+					{Object: b, Status: StatusValid, Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "b.example.com"},
+				},
+			}, {
+				Object:      child,
+				Status:      StatusValid,
+				Description: "valid IngressRoute",
+				Conditions: []Status{
+					{Object: child, Status: StatusValid, Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "a.example.com"},
+					{Object: child, Status: StatusValid, Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "b.example.com"},
+				},
+			}},
+		},
+		"child invalid under one of its roots": {
+			objs: []*ingressroutev1.IngressRoute{a, c, child},
+			want: []IngressRouteStatus{{
+				Object:      a,
+				Status:      StatusValid,
+				Description: "valid IngressRoute",
+				Conditions: []Status{
+					{Object: a, Status: StatusValid, Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "a.example.com"},
+				},
+			}, {
+				Object:      c,
+				Status:      StatusValid,
+				Description: "valid IngressRoute",
+				Conditions: []Status{
+					{Object: c, Status: StatusValid, Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "c.example.com"},
+				},
+			}, {
+				Object:      child,
+				Status:      StatusInvalid,
+				Description: `the path prefix "/" does not match the parent's path prefix "/bar"`,
+				Conditions: []Status{
+					{Object: child, Status: StatusValid, Reason: ReasonValid, Description: "valid IngressRoute", Vhost: "a.example.com"},
+					{Object: child, Status: StatusInvalid, Reason: ReasonPrefixMismatch, Description: `the path prefix "/" does not match the parent's path prefix "/bar"`, Vhost: "c.example.com"},
+				},
+			}},
+		},
+		"orphaned child": {
+			objs: []*ingressroutev1.IngressRoute{child},
+			want: []IngressRouteStatus{{
+				Object:      child,
+				Status:      StatusOrphaned,
+				Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute",
+				Conditions: []Status{
+					{Object: child, Status: StatusOrphaned, Reason: ReasonOrphaned, Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"},
+				},
+			}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b Builder
+			for _, o := range tc.objs {
+				b.Insert(o)
+			}
+			got := b.Build().IngressRouteStatuses()
+			// ingressroutes are visited in map order, sort for test stability.
+			sort.Slice(got, func(i, j int) bool { return got[i].Object.Name < got[j].Object.Name })
+			for _, st := range got {
+				sort.Slice(st.Conditions, func(i, j int) bool { return st.Conditions[i].Vhost < st.Conditions[j].Vhost })
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestDAGIngressRouteUniqueFQDNs(t *testing.T) {
+	ir1 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// ir2 reuses the fqdn used in ir1
+	ir2 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-example",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	tests := map[string]struct {
+		objs       []interface{}
+		want       []Vertex
+		wantStatus []Status
+	}{
+		"insert ingressroute": {
+			objs: []interface{}{
+				ir1,
+			},
+			want: []Vertex{
+				&VirtualHost{
+					Host: "example.com",
+					Port: 80,
+					routes: routemap(
+						route("/", ir1),
+					),
+				},
+			},
+			wantStatus: []Status{
+				{
+					Object:      ir1,
+					Status:      StatusValid,
+					Reason:      ReasonValid,
+					Description: "valid IngressRoute",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert conflicting ingressroutes due to fqdn reuse": {
+			objs: []interface{}{
+				ir1, ir2,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir1,
+					Status:      StatusInvalid,
+					Reason:      ReasonDuplicateFQDN,
+					Description: `fqdn "example.com" is used in multiple IngressRoutes: default/example-com, default/other-example`,
+					Vhost:       "example.com",
+				},
+				{
+					Object:      ir2,
+					Status:      StatusInvalid,
+					Reason:      ReasonDuplicateFQDN,
+					Description: `fqdn "example.com" is used in multiple IngressRoutes: default/example-com, default/other-example`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b Builder
+			for _, o := range tc.objs {
+				b.Insert(o)
+			}
+			dag := b.Build()
+
+			got := make(map[hostport]Vertex)
+			dag.Visit(func(v Vertex) {
+				switch v := v.(type) {
+				case *VirtualHost:
+					got[hostport{host: v.Host, port: v.Port}] = v
+				case *SecureVirtualHost:
+					got[hostport{host: v.Host, port: v.Port}] = v
+				}
+			})
+
+			want := make(map[hostport]Vertex)
+			for _, v := range tc.want {
+				switch v := v.(type) {
+				case *VirtualHost:
+					want[hostport{host: v.Host, port: v.Port}] = v
+				case *SecureVirtualHost:
+					want[hostport{host: v.Host, port: v.Port}] = v
+				}
+			}
+
+			if !reflect.DeepEqual(want, got) {
+				t.Fatal("expected:\n", want, "\ngot:\n", got)
+			}
+
+			gotStatus := dag.statuses
+			sort.Stable(statusByNamespaceAndName(gotStatus))
+			if !reflect.DeepEqual(tc.wantStatus, gotStatus) {
+				t.Fatal("expected:\n", tc.wantStatus, "\ngot:\n", dag.statuses)
+			}
+		})
+	}
+}
+
+func TestDAGIngressRouteAuthorization(t *testing.T) {
+	// sauth is a gRPC authorization service
+	sauth := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"contour.heptio.com/upstream-protocol.h2c": "9000",
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       9000,
+				TargetPort: intstr.FromInt(9000),
+			}},
+		},
+	}
+
+	// shttp is a http/1.1 service
+	shttp := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	sec1 := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Data: secretdata("certificate", "key"),
+	}
+
+	// ir1 checks requests with the auth service
+	ir1 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &ingressroutev1.TLS{
+					SecretName: "secret",
+				},
+				Authorization: &ingressroutev1.AuthorizationServer{
+					Name:            "auth",
+					Port:            9000,
+					FailOpen:        true,
+					ResponseTimeout: "1s",
+				},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// ir2 uses a http/1.1 service as authorization service
+	ir2 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &ingressroutev1.TLS{
+					SecretName: "secret",
+				},
+				Authorization: &ingressroutev1.AuthorizationServer{
+					Name: "kuard",
+					Port: 8080,
+				},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// ir3 permits insecure requests to a route which requires authorization
+	ir3 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &ingressroutev1.TLS{
+					SecretName: "secret",
+				},
+				Authorization: &ingressroutev1.AuthorizationServer{
+					Name: "auth",
+					Port: 9000,
+				},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match:          "/",
+				PermitInsecure: true,
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// ir4 specifies authorization without tls
+	ir4 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				Authorization: &ingressroutev1.AuthorizationServer{
+					Name: "auth",
+					Port: 9000,
+				},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	kuard := &Service{
+		Object:      shttp,
+		ServicePort: &shttp.Spec.Ports[0],
+	}
+	root := &Route{
+		Prefix:       "/",
+		object:       ir1,
+		HTTPSUpgrade: true,
+		services:     servicemap(kuard),
+	}
+
+	tests := map[string]struct {
+		objs       []interface{}
+		want       []Vertex
+		wantStatus []Status
+	}{
+		"insert ingressroute with authorization": {
+			objs: []interface{}{
+				sauth, shttp, sec1, ir1,
+			},
+			want: []Vertex{
+				&VirtualHost{
+					Host:   "example.com",
+					Port:   80,
+					routes: routemap(root),
+				},
+				&SecureVirtualHost{
+					VirtualHost: VirtualHost{
+						Host:   "example.com",
+						Port:   443,
+						routes: routemap(root),
+					},
+					MinProtoVersion: auth.TlsParameters_TLSv1_1,
+					AuthorizationServer: &AuthorizationServer{
+						Service: &Service{
+							Object:      sauth,
+							ServicePort: &sauth.Spec.Ports[0],
+							Protocol:    "h2c",
+						},
+						FailOpen:        true,
+						ResponseTimeout: time.Second,
+					},
+					secret: &Secret{
+						object: sec1,
+					},
+				},
+			},
+			wantStatus: []Status{
+				{
+					Object:      ir1,
+					Status:      StatusValid,
+					Reason:      ReasonValid,
+					Description: "valid IngressRoute",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert ingressroute with http/1.1 authorization service": {
+			objs: []interface{}{
+				shttp, sec1, ir2,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir2,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: `authorization service "kuard": port 8080 must use the h2 or h2c upstream protocol`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert ingressroute permitting insecure requests which require authorization": {
+			objs: []interface{}{
+				sauth, shttp, ir3,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir3,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: `route "/": cannot specify permitInsecure on a route which requires authorization`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert ingressroute with authorization without tls": {
+			objs: []interface{}{
+				sauth, shttp, ir4,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir4,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: "authorization requires that the virtualhost specify tls",
+					Vhost:       "example.com",
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b Builder
+			for _, o := range tc.objs {
+				b.Insert(o)
+			}
+			dag := b.Build()
+
+			got := make(map[hostport]Vertex)
+			dag.Visit(func(v Vertex) {
+				switch v := v.(type) {
+				case *VirtualHost:
+					got[hostport{host: v.Host, port: v.Port}] = v
+				case *SecureVirtualHost:
+					got[hostport{host: v.Host, port: v.Port}] = v
+				}
+			})
+
+			want := make(map[hostport]Vertex)
+			for _, v := range tc.want {
+				switch v := v.(type) {
+				case *VirtualHost:
+					want[hostport{host: v.Host, port: v.Port}] = v
+				case *SecureVirtualHost:
+					want[hostport{host: v.Host, port: v.Port}] = v
+				}
+			}
+
+			opts := []cmp.Option{
+				cmp.AllowUnexported(VirtualHost{}, SecureVirtualHost{}, Route{}, Secret{}),
+			}
+			if diff := cmp.Diff(want, got, opts...); diff != "" {
+				t.Fatal(diff)
+			}
+			if diff := cmp.Diff(tc.wantStatus, dag.statuses); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestDAGIngressRouteHeaders(t *testing.T) {
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	// ingressroute returns an IngressRoute with a single route to kuard
+	ingressroute := func(route ingressroutev1.Route) *ingressroutev1.IngressRoute {
+		route.Match = "/"
+		if route.Services == nil {
+			route.Services = []ingressroutev1.Service{{
+				Name: "kuard",
+				Port: 8080,
+			}}
+		}
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-com",
+				Namespace: "default",
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn: "example.com",
+				},
+				Routes: []ingressroutev1.Route{route},
+			},
+		}
+	}
+
+	// ir1 rewrites the host and manages headers of the route and service
+	ir1 := ingressroute(ingressroutev1.Route{
+		HostRewrite: "backend.example.com",
+		RequestHeadersPolicy: &ingressroutev1.HeadersPolicy{
+			Set: []ingressroutev1.HeaderValue{{
+				Name:  "X-Forwarded-Prefix",
+				Value: "/",
+			}},
+		},
+		ResponseHeadersPolicy: &ingressroutev1.HeadersPolicy{
+			Remove: []string{"Server"},
+		},
+		Services: []ingressroutev1.Service{{
+			Name: "kuard",
+			Port: 8080,
+			RequestHeadersPolicy: &ingressroutev1.HeadersPolicy{
+				Set: []ingressroutev1.HeaderValue{{
+					Name:  "x-backend",
+					Value: "kuard",
+				}},
+			},
+		}},
+	})
+	ir2 := ingressroute(ingressroutev1.Route{
+		RequestHeadersPolicy: &ingressroutev1.HeadersPolicy{
+			Set: []ingressroutev1.HeaderValue{{
+				Name:  "Host",
+				Value: "backend.example.com",
+			}},
+		},
+	})
+	ir3 := ingressroute(ingressroutev1.Route{
+		ResponseHeadersPolicy: &ingressroutev1.HeadersPolicy{
+			Set: []ingressroutev1.HeaderValue{{
+				Name:  "x-cache",
+				Value: "hit",
+			}},
+			Remove: []string{"X-Cache"},
+		},
+	})
+	ir4 := ingressroute(ingressroutev1.Route{
+		HostRewrite: " ",
+	})
+	ir5 := ingressroute(ingressroutev1.Route{
+		Services: []ingressroutev1.Service{{
+			Name: "kuard",
+			Port: 8080,
+			RequestHeadersPolicy: &ingressroutev1.HeadersPolicy{
+				Remove: []string{":path"},
+			},
+		}},
+	})
+
+	root := &Route{
+		Prefix:      "/",
+		object:      ir1,
+		HostRewrite: "backend.example.com",
+		RequestHeadersPolicy: &HeadersPolicy{
+			Set: []HeaderValue{{Name: "x-forwarded-prefix", Value: "/"}},
+		},
+		ResponseHeadersPolicy: &HeadersPolicy{
+			Remove: []string{"server"},
+		},
+		services: servicemap(&Service{
+			Object:      s1,
+			ServicePort: &s1.Spec.Ports[0],
+			RequestHeadersPolicy: &HeadersPolicy{
+				Set: []HeaderValue{{Name: "x-backend", Value: "kuard"}},
+			},
+		}),
+	}
+
+	tests := map[string]struct {
+		objs       []interface{}
+		want       []Vertex
+		wantStatus []Status
+	}{
+		"insert ingressroute with headers policies": {
+			objs: []interface{}{
+				s1, ir1,
+			},
+			want: []Vertex{
+				&VirtualHost{
+					Host:   "example.com",
+					Port:   80,
+					routes: routemap(root),
+				},
+			},
+			wantStatus: []Status{
+				{
+					Object:      ir1,
+					Status:      StatusValid,
+					Reason:      ReasonValid,
+					Description: "valid IngressRoute",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert ingressroute setting the host header": {
+			objs: []interface{}{
+				s1, ir2,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir2,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: `route "/": request headers policy: the Host header can only be rewritten with hostRewrite`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert ingressroute with duplicate header": {
+			objs: []interface{}{
+				s1, ir3,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir3,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: `route "/": response headers policy: duplicate header "X-Cache"`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert ingressroute with blank host rewrite": {
+			objs: []interface{}{
+				s1, ir4,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir4,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: `route "/": hostRewrite cannot be blank`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert ingressroute removing a pseudo header": {
+			objs: []interface{}{
+				s1, ir5,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir5,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: `route "/": service "kuard": request headers policy: pseudo header ":path" cannot be managed`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b Builder
+			for _, o := range tc.objs {
+				b.Insert(o)
+			}
+			dag := b.Build()
+
+			got := make(map[hostport]Vertex)
+			dag.Visit(func(v Vertex) {
+				switch v := v.(type) {
+				case *VirtualHost:
+					got[hostport{host: v.Host, port: v.Port}] = v
+				case *SecureVirtualHost:
+					got[hostport{host: v.Host, port: v.Port}] = v
+				}
+			})
+
+			want := make(map[hostport]Vertex)
+			for _, v := range tc.want {
+				switch v := v.(type) {
+				case *VirtualHost:
+					want[hostport{host: v.Host, port: v.Port}] = v
+				case *SecureVirtualHost:
+					want[hostport{host: v.Host, port: v.Port}] = v
+				}
+			}
+
+			opts := []cmp.Option{
+				cmp.AllowUnexported(VirtualHost{}, SecureVirtualHost{}, Route{}, Secret{}),
+			}
+			if diff := cmp.Diff(want, got, opts...); diff != "" {
+				t.Fatal(diff)
+			}
+			if diff := cmp.Diff(tc.wantStatus, dag.statuses); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestDAGIngressRouteUpstreamValidation(t *testing.T) {
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"contour.heptio.com/upstream-protocol.tls": "8443",
+			},
 		},
-		"ingressroute is an orphaned route": {
-			objs: []*ingressroutev1.IngressRoute{ir8},
-			want: []Status{{Object: ir8, Status: "orphaned", Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"}},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       8443,
+				TargetPort: intstr.FromInt(8443),
+			}},
 		},
-		"ingressroute delegates to multiple ingressroutes, one is invalid": {
-			objs: []*ingressroutev1.IngressRoute{ir10, ir11, ir12},
-			want: []Status{
-				{Object: ir11, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
-				{Object: ir12, Status: "invalid", Description: `route "/bar": service "foo": port must be in the range 1-65535`, Vhost: "example.com"},
-				{Object: ir10, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
+	}
+	s2 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: s1.Spec,
+	}
+	ca := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			CACertificateKey: []byte("ca"),
+		},
+	}
+	client := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "envoy-client",
+			Namespace: "heptio-contour",
+		},
+		Data: secretdata("certificate", "key"),
+	}
+
+	// ingressroute returns an IngressRoute with a single route to kuard
+	ingressroute := func(uv *ingressroutev1.UpstreamValidation) *ingressroutev1.IngressRoute {
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-com",
+				Namespace: "default",
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn: "example.com",
+				},
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Services: []ingressroutev1.Service{{
+						Name:               "kuard",
+						Port:               8443,
+						UpstreamValidation: uv,
+					}},
+				}},
+			},
+		}
+	}
+
+	ir1 := ingressroute(&ingressroutev1.UpstreamValidation{
+		CACertificate: "ca",
+		SubjectName:   "kuard.default.svc",
+	})
+	ir2 := ingressroute(&ingressroutev1.UpstreamValidation{
+		CACertificate: "missing",
+		SubjectName:   "kuard.default.svc",
+	})
+	ir3 := ingressroute(&ingressroutev1.UpstreamValidation{
+		CACertificate: "ca",
+	})
+
+	uv := &UpstreamValidation{
+		CACertificate: &Secret{object: ca},
+		SubjectName:   "kuard.default.svc",
+	}
+
+	tests := map[string]struct {
+		clientCert *types.NamespacedName
+		objs       []interface{}
+		want       []Vertex
+		wantStatus []Status
+	}{
+		"insert ingressroute with upstream validation": {
+			objs: []interface{}{
+				s1, ca, ir1,
+			},
+			want: []Vertex{
+				&VirtualHost{
+					Host: "example.com",
+					Port: 80,
+					routes: routemap(&Route{
+						Prefix: "/",
+						object: ir1,
+						services: servicemap(&Service{
+							Object:             s1,
+							ServicePort:        &s1.Spec.Ports[0],
+							Protocol:           "tls",
+							UpstreamValidation: uv,
+						}),
+					}),
+				},
+			},
+			wantStatus: []Status{
+				{
+					Object:      ir1,
+					Status:      StatusValid,
+					Reason:      ReasonValid,
+					Description: "valid IngressRoute",
+					Vhost:       "example.com",
+				},
 			},
 		},
-		"invalid parent orphans children": {
-			objs: []*ingressroutev1.IngressRoute{ir14, ir11},
-			want: []Status{
-				{Object: ir14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
-				{Object: ir11, Status: "orphaned", Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"},
+		"insert ingressroute with upstream validation and client certificate": {
+			clientCert: &types.NamespacedName{Namespace: "heptio-contour", Name: "envoy-client"},
+			objs: []interface{}{
+				s1, ca, client, ir1,
+			},
+			want: []Vertex{
+				&VirtualHost{
+					Host: "example.com",
+					Port: 80,
+					routes: routemap(&Route{
+						Prefix: "/",
+						object: ir1,
+						services: servicemap(&Service{
+							Object:             s1,
+							ServicePort:        &s1.Spec.Ports[0],
+							Protocol:           "tls",
+							UpstreamValidation: uv,
+							ClientCertificate:  &Secret{object: client},
+						}),
+					}),
+				},
+			},
+			wantStatus: []Status{
+				{
+					Object:      ir1,
+					Status:      StatusValid,
+					Reason:      ReasonValid,
+					Description: "valid IngressRoute",
+					Vhost:       "example.com",
+				},
 			},
 		},
-		"multi-parent children is not orphaned when one of the parents is invalid": {
-			objs: []*ingressroutev1.IngressRoute{ir14, ir11, ir10},
-			want: []Status{
-				{Object: ir14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
-				{Object: ir11, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
-				{Object: ir10, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
+		"insert ingressroute with missing ca secret": {
+			objs: []interface{}{
+				s1, ca, ir2,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir2,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: `route "/": service "kuard": upstream validation: secret "missing" not found`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert ingressroute without subject name": {
+			objs: []interface{}{
+				s1, ca, ir3,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir3,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: `route "/": service "kuard": upstream validation must specify subjectName`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert ingressroute with upstream validation of plaintext service": {
+			objs: []interface{}{
+				s2, ca, ir1,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir1,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: `route "/": service "kuard": upstream validation requires the tls or h2 upstream protocol`,
+					Vhost:       "example.com",
+				},
 			},
 		},
 	}
@@ -3410,116 +5077,182 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			b := Builder{
 				KubernetesCache: KubernetesCache{
-					IngressRouteRootNamespaces: []string{"roots"},
+					ClientCertificate: tc.clientCert,
 				},
 			}
 			for _, o := range tc.objs {
 				b.Insert(o)
 			}
-			got := b.Build().Statuses()
-			if len(tc.want) != len(got) {
-				t.Fatalf("expected:\n%v\ngot\n%v", tc.want, got)
-			}
+			dag := b.Build()
 
-			for _, ex := range tc.want {
-				var found bool
-				for _, g := range got {
-					if reflect.DeepEqual(ex, g) {
-						found = true
-						break
-					}
+			got := make(map[hostport]Vertex)
+			dag.Visit(func(v Vertex) {
+				if v, ok := v.(*VirtualHost); ok {
+					got[hostport{host: v.Host, port: v.Port}] = v
 				}
-				if !found {
-					t.Fatalf("expected to find:\n%v\nbut did not find it in:\n%v", ex, got)
+			})
+
+			want := make(map[hostport]Vertex)
+			for _, v := range tc.want {
+				if v, ok := v.(*VirtualHost); ok {
+					want[hostport{host: v.Host, port: v.Port}] = v
 				}
 			}
+
+			opts := []cmp.Option{
+				cmp.AllowUnexported(VirtualHost{}, Route{}, Secret{}),
+			}
+			if diff := cmp.Diff(want, got, opts...); diff != "" {
+				t.Fatal(diff)
+			}
+			if diff := cmp.Diff(tc.wantStatus, dag.statuses); diff != "" {
+				t.Fatal(diff)
+			}
 		})
 	}
 }
 
-func TestDAGIngressRouteUniqueFQDNs(t *testing.T) {
-	ir1 := &ingressroutev1.IngressRoute{
+func TestDAGIngressRouteCORS(t *testing.T) {
+	s1 := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "example-com",
+			Name:      "kuard",
 			Namespace: "default",
 		},
-		Spec: ingressroutev1.IngressRouteSpec{
-			VirtualHost: &ingressroutev1.VirtualHost{
-				Fqdn: "example.com",
-			},
-			Routes: []ingressroutev1.Route{{
-				Match: "/",
-				Services: []ingressroutev1.Service{{
-					Name: "kuard",
-					Port: 8080,
-				}},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
 			}},
 		},
 	}
 
-	// ir2 reuses the fqdn used in ir1
-	ir2 := &ingressroutev1.IngressRoute{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "other-example",
-			Namespace: "default",
-		},
-		Spec: ingressroutev1.IngressRouteSpec{
-			VirtualHost: &ingressroutev1.VirtualHost{
-				Fqdn: "example.com",
-			},
-			Routes: []ingressroutev1.Route{{
-				Match: "/",
-				Services: []ingressroutev1.Service{{
-					Name: "kuard",
-					Port: 8080,
+	// ingressroute returns an IngressRoute for example.com with the supplied CORS policy
+	ingressroute := func(cp *ingressroutev1.CORSPolicy) *ingressroutev1.IngressRoute {
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-com",
+				Namespace: "default",
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn:       "example.com",
+					CORSPolicy: cp,
+				},
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Services: []ingressroutev1.Service{{
+						Name:     "kuard",
+						Port:     8080,
+						Strategy: "Cookie",
+					}},
 				}},
-			}},
-		},
+			},
+		}
 	}
 
+	ir1 := ingressroute(&ingressroutev1.CORSPolicy{
+		AllowOrigin:      []string{"https://example.com"},
+		AllowMethods:     []string{"GET", "POST"},
+		AllowHeaders:     []string{"authorization"},
+		AllowCredentials: true,
+		MaxAge:           "10m",
+	})
+	ir2 := ingressroute(&ingressroutev1.CORSPolicy{
+		AllowOrigin: []string{"*"},
+	})
+	ir3 := ingressroute(&ingressroutev1.CORSPolicy{
+		AllowOrigin:  []string{"*"},
+		AllowMethods: []string{"GET"},
+		MaxAge:       "forever",
+	})
+	ir4 := ingressroute(&ingressroutev1.CORSPolicy{
+		AllowOrigin:  []string{" "},
+		AllowMethods: []string{"GET"},
+	})
+
 	tests := map[string]struct {
 		objs       []interface{}
 		want       []Vertex
 		wantStatus []Status
 	}{
-		"insert ingressroute": {
+		"insert ingressroute with cors policy": {
 			objs: []interface{}{
-				ir1,
+				s1, ir1,
 			},
 			want: []Vertex{
 				&VirtualHost{
 					Host: "example.com",
 					Port: 80,
-					routes: routemap(
-						route("/", ir1),
-					),
+					CORSPolicy: &CORSPolicy{
+						AllowOrigin:      []string{"https://example.com"},
+						AllowMethods:     []string{"GET", "POST"},
+						AllowHeaders:     []string{"authorization"},
+						AllowCredentials: true,
+						MaxAge:           10 * time.Minute,
+					},
+					routes: routemap(&Route{
+						Prefix: "/",
+						object: ir1,
+						services: servicemap(&Service{
+							Object:               s1,
+							ServicePort:          &s1.Spec.Ports[0],
+							LoadBalancerStrategy: "Cookie",
+						}),
+					}),
 				},
 			},
 			wantStatus: []Status{
 				{
 					Object:      ir1,
 					Status:      StatusValid,
+					Reason:      ReasonValid,
 					Description: "valid IngressRoute",
 					Vhost:       "example.com",
 				},
 			},
 		},
-		"insert conflicting ingressroutes due to fqdn reuse": {
+		"insert ingressroute with cors policy without methods": {
 			objs: []interface{}{
-				ir1, ir2,
+				s1, ir2,
 			},
 			want: []Vertex{},
 			wantStatus: []Status{
 				{
-					Object:      ir1,
+					Object:      ir2,
 					Status:      StatusInvalid,
-					Description: `fqdn "example.com" is used in multiple IngressRoutes: default/example-com, default/other-example`,
+					Reason:      ReasonInvalidSpec,
+					Description: "virtualhost cors policy: allowMethods must specify at least one method",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert ingressroute with invalid cors max age": {
+			objs: []interface{}{
+				s1, ir3,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
+				{
+					Object:      ir3,
+					Status:      StatusInvalid,
+					Reason:      ReasonInvalidSpec,
+					Description: `virtualhost cors policy: maxAge "forever" is not a valid duration`,
 					Vhost:       "example.com",
 				},
+			},
+		},
+		"insert ingressroute with blank cors origin": {
+			objs: []interface{}{
+				s1, ir4,
+			},
+			want: []Vertex{},
+			wantStatus: []Status{
 				{
-					Object:      ir2,
+					Object:      ir4,
 					Status:      StatusInvalid,
-					Description: `fqdn "example.com" is used in multiple IngressRoutes: default/example-com, default/other-example`,
+					Reason:      ReasonInvalidSpec,
+					Description: "virtualhost cors policy: allowed origins, methods and headers cannot be blank",
 					Vhost:       "example.com",
 				},
 			},
@@ -3536,32 +5269,26 @@ func TestDAGIngressRouteUniqueFQDNs(t *testing.T) {
 
 			got := make(map[hostport]Vertex)
 			dag.Visit(func(v Vertex) {
-				switch v := v.(type) {
-				case *VirtualHost:
-					got[hostport{host: v.Host, port: v.Port}] = v
-				case *SecureVirtualHost:
+				if v, ok := v.(*VirtualHost); ok {
 					got[hostport{host: v.Host, port: v.Port}] = v
 				}
 			})
 
 			want := make(map[hostport]Vertex)
 			for _, v := range tc.want {
-				switch v := v.(type) {
-				case *VirtualHost:
-					want[hostport{host: v.Host, port: v.Port}] = v
-				case *SecureVirtualHost:
+				if v, ok := v.(*VirtualHost); ok {
 					want[hostport{host: v.Host, port: v.Port}] = v
 				}
 			}
 
-			if !reflect.DeepEqual(want, got) {
-				t.Fatal("expected:\n", want, "\ngot:\n", got)
+			opts := []cmp.Option{
+				cmp.AllowUnexported(VirtualHost{}, Route{}),
 			}
-
-			gotStatus := dag.statuses
-			sort.Stable(statusByNamespaceAndName(gotStatus))
-			if !reflect.DeepEqual(tc.wantStatus, gotStatus) {
-				t.Fatal("expected:\n", tc.wantStatus, "\ngot:\n", dag.statuses)
+			if diff := cmp.Diff(want, got, opts...); diff != "" {
+				t.Fatal(diff)
+			}
+			if diff := cmp.Diff(tc.wantStatus, dag.statuses); diff != "" {
+				t.Fatal(diff)
 			}
 		})
 	}
@@ -3667,7 +5394,7 @@ func TestEnforceRoute(t *testing.T) {
 func routemap(routes ...*Route) map[string]*Route {
 	m := make(map[string]*Route)
 	for _, r := range routes {
-		m[r.Prefix] = r
+		m[r.key()] = r
 	}
 	return m
 }
@@ -16,6 +16,8 @@
 package dag
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"k8s.io/api/core/v1"
@@ -32,7 +34,8 @@ type DAG struct {
 	roots []Vertex
 
 	// status computed while building this dag.
-	statuses []Status
+	statuses        []Status
+	gatewayStatuses []GatewayStatus
 }
 
 // Visit calls fn on each root of this DAG.
@@ -48,8 +51,46 @@ func (d *DAG) Statuses() []Status {
 	return d.statuses
 }
 
+// IngressRouteStatuses returns the Statuses of this DAG aggregated
+// into a single IngressRouteStatus per IngressRoute, in the order
+// each IngressRoute was first visited.
+func (d *DAG) IngressRouteStatuses() []IngressRouteStatus {
+	var statuses []IngressRouteStatus
+	index := make(map[meta]int)
+	for _, st := range d.statuses {
+		m := meta{name: st.Object.Name, namespace: st.Object.Namespace}
+		i, ok := index[m]
+		if !ok {
+			i = len(statuses)
+			index[m] = i
+			statuses = append(statuses, IngressRouteStatus{Object: st.Object})
+		}
+		statuses[i].add(st)
+	}
+	return statuses
+}
+
+// GatewayStatuses returns a slice of GatewayStatus objects
+// associated with the computation of this DAG.
+func (d *DAG) GatewayStatuses() []GatewayStatus {
+	return d.gatewayStatuses
+}
+
 type Route struct {
-	Prefix   string
+	Prefix string
+
+	// Path, if set, matches the request path exactly instead of Prefix.
+	Path string
+
+	// Regex, if set, matches the request path with a regular expression instead of Prefix.
+	Regex string
+
+	// HeaderConditions are request headers a request must match.
+	HeaderConditions []HeaderCondition
+
+	// QueryParameterConditions are query parameters a request must match.
+	QueryParameterConditions []QueryParameterCondition
+
 	object   interface{} // one of Ingress or IngressRoute
 	services map[servicemeta]*Service
 
@@ -81,6 +122,91 @@ type Route struct {
 
 	// Indicates that during forwarding, the matched prefix (or path) should be swapped with this value
 	PrefixRewrite string
+
+	// HostRewrite, if set, replaces the Host header of requests
+	// forwarded to the services of this route.
+	HostRewrite string
+
+	// RequestHeadersPolicy and ResponseHeadersPolicy manage the
+	// headers of requests to, and responses from, this route.
+	RequestHeadersPolicy  *HeadersPolicy
+	ResponseHeadersPolicy *HeadersPolicy
+}
+
+// HeadersPolicy holds the headers to set and remove during forwarding.
+type HeadersPolicy struct {
+	// Set adds these headers, replacing any existing values.
+	Set []HeaderValue
+
+	// Remove removes these headers.
+	Remove []string
+}
+
+// HeaderValue is a header name and value.
+type HeaderValue struct {
+	Name  string
+	Value string
+}
+
+// CORSPolicy holds the origins, methods and headers of the cross
+// origin requests allowed to a virtual host.
+type CORSPolicy struct {
+	AllowOrigin      []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+
+	// MaxAge is how long the response to a preflight request
+	// can be cached. Zero uses Envoy's default.
+	MaxAge time.Duration
+}
+
+// Header match types of a HeaderCondition.
+const (
+	HeaderMatchTypePresent  = "present"
+	HeaderMatchTypeExact    = "exact"
+	HeaderMatchTypeContains = "contains"
+)
+
+// HeaderCondition matches a request header.
+type HeaderCondition struct {
+	Name string
+
+	// MatchType is one of HeaderMatchTypePresent, HeaderMatchTypeExact
+	// or HeaderMatchTypeContains.
+	MatchType string
+
+	// Value is ignored for HeaderMatchTypePresent.
+	Value string
+}
+
+// QueryParameterCondition matches a query parameter of a request.
+// If Value is empty, the parameter only has to be present.
+type QueryParameterCondition struct {
+	Name  string
+	Value string
+}
+
+// key returns the identity of the route's match. Routes with equal keys
+// match the same requests. The key of a route without conditions is its prefix.
+func (r *Route) key() string {
+	var key strings.Builder
+	switch {
+	case r.Path != "":
+		key.WriteString("path:" + r.Path)
+	case r.Regex != "":
+		key.WriteString("regex:" + r.Regex)
+	default:
+		key.WriteString(r.Prefix)
+	}
+	for _, h := range r.HeaderConditions {
+		fmt.Fprintf(&key, ",header:%s:%s:%s", h.Name, h.MatchType, h.Value)
+	}
+	for _, q := range r.QueryParameterConditions {
+		fmt.Fprintf(&key, ",query:%s:%s", q.Name, q.Value)
+	}
+	return key.String()
 }
 
 func (r *Route) addService(s *Service) {
@@ -105,13 +231,16 @@ type VirtualHost struct {
 
 	Host   string
 	routes map[string]*Route
+
+	// CORSPolicy, if set, allows cross origin requests to this host.
+	CORSPolicy *CORSPolicy
 }
 
 func (v *VirtualHost) addRoute(route *Route) {
 	if v.routes == nil {
 		v.routes = make(map[string]*Route)
 	}
-	v.routes[route.Prefix] = route
+	v.routes[route.key()] = route
 }
 
 func (v *VirtualHost) Visit(f func(Vertex)) {
@@ -127,6 +256,15 @@ type SecureVirtualHost struct {
 	// TLS minimum protocol version. Defaults to auth.TlsParameters_TLS_AUTO
 	MinProtoVersion auth.TlsParameters_TlsProtocol
 
+	// TCPProxy, if set, proxies connections to this host at the
+	// TCP level rather than routing HTTP requests. If the host has
+	// no secret, TLS is passed through to the proxied services.
+	TCPProxy *TCPProxy
+
+	// AuthorizationServer, if set, checks requests to this host
+	// with an external authorization service.
+	AuthorizationServer *AuthorizationServer
+
 	secret *Secret
 }
 
@@ -139,7 +277,49 @@ func (s *SecureVirtualHost) Data() map[string][]byte {
 
 func (s *SecureVirtualHost) Visit(f func(Vertex)) {
 	s.VirtualHost.Visit(f)
-	f(s.secret)
+	if s.TCPProxy != nil {
+		f(s.TCPProxy)
+	}
+	if s.AuthorizationServer != nil {
+		f(s.AuthorizationServer)
+	}
+	if s.secret != nil {
+		f(s.secret)
+	}
+}
+
+// AuthorizationServer represents an external authorization service.
+type AuthorizationServer struct {
+	Service *Service
+
+	// FailOpen allows requests if the service fails to respond.
+	FailOpen bool
+
+	// ResponseTimeout is the timeout of a check request.
+	// A timeout of zero implies "use envoy's default"
+	ResponseTimeout time.Duration
+}
+
+func (a *AuthorizationServer) Visit(f func(Vertex)) {
+	f(a.Service)
+}
+
+// TCPProxy represents a TCP proxy to a set of services.
+type TCPProxy struct {
+	services map[servicemeta]*Service
+}
+
+func (t *TCPProxy) addService(s *Service) {
+	if t.services == nil {
+		t.services = make(map[servicemeta]*Service)
+	}
+	t.services[s.toMeta()] = s
+}
+
+func (t *TCPProxy) Visit(f func(Vertex)) {
+	for _, s := range t.services {
+		f(s)
+	}
 }
 
 type Visitable interface {
@@ -181,6 +361,34 @@ type Service struct {
 	// MaxRetries is the maximum number of parallel retries that
 	// Envoy will allow to the upstream cluster.
 	MaxRetries int
+
+	// RequestHeadersPolicy and ResponseHeadersPolicy manage the
+	// headers of requests to, and responses from, this service.
+	RequestHeadersPolicy  *HeadersPolicy
+	ResponseHeadersPolicy *HeadersPolicy
+
+	// UpstreamValidation, if set, verifies the certificate
+	// presented by this service.
+	UpstreamValidation *UpstreamValidation
+
+	// ClientCertificate, if set, is presented to this service
+	// if it uses TLS.
+	ClientCertificate *Secret
+}
+
+// CACertificateKey is the key of the CA certificate in the
+// secret referenced by an upstream validation.
+const CACertificateKey = "ca.crt"
+
+// UpstreamValidation holds the CA certificate and subject name
+// used to verify the certificate of an upstream service.
+type UpstreamValidation struct {
+	// CACertificate holds the CA certificate under the ca.crt key.
+	CACertificate *Secret
+
+	// SubjectName is the subject alternative name the
+	// certificate of the service must present.
+	SubjectName string
 }
 
 func (s *Service) Name() string       { return s.Object.Name }
@@ -194,6 +402,8 @@ type servicemeta struct {
 	weight      int
 	strategy    string
 	healthcheck string // %#v of *ingressroutev1.HealthCheck
+	headers     string // %#v of the request and response *HeadersPolicy
+	validation  string // namespace/name of the CA secret and subject name
 }
 
 func (s *Service) toMeta() servicemeta {
@@ -204,6 +414,8 @@ func (s *Service) toMeta() servicemeta {
 		weight:      s.Weight,
 		strategy:    s.LoadBalancerStrategy,
 		healthcheck: healthcheckToString(s.HealthCheck),
+		headers:     headersToString(s.RequestHeadersPolicy, s.ResponseHeadersPolicy),
+		validation:  validationToString(s.UpstreamValidation),
 	}
 }
 
@@ -0,0 +1,647 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	gatewayv1 "github.com/heptio/contour/apis/gateway/v1alpha1"
+)
+
+// Reasons of the conditions of Gateway API objects.
+const (
+	ReasonAdmitted              = "Admitted"
+	ReasonReady                 = "Ready"
+	ReasonListenersNotReady     = "ListenersNotReady"
+	ReasonPortUnavailable       = "PortUnavailable"
+	ReasonUnsupportedProtocol   = "UnsupportedProtocol"
+	ReasonInvalidHostname       = "InvalidHostname"
+	ReasonInvalidCertificateRef = "InvalidCertificateRef"
+	ReasonInvalidRoutes         = "InvalidRoutes"
+	ReasonInvalid               = "Invalid"
+	ReasonNoMatchingHostname    = "NoMatchingHostname"
+)
+
+// GatewayStatus contains the status computed for a Gateway API object.
+type GatewayStatus struct {
+	// Object is one of *gatewayv1.GatewayClass, *gatewayv1.Gateway,
+	// *gatewayv1.HTTPRoute or *gatewayv1.TLSRoute.
+	Object interface{}
+
+	// Conditions of a GatewayClass or Gateway.
+	Conditions []gatewayv1.Condition
+
+	// Listeners of a Gateway.
+	Listeners []gatewayv1.ListenerStatus
+
+	// Gateways which bind an HTTPRoute or TLSRoute.
+	Gateways []gatewayv1.RouteGatewayStatus
+}
+
+// routeBindings records the admission of routes by the Gateways which bind them.
+type routeBindings map[interface{}]map[gatewayv1.GatewayReference]gatewayv1.Condition
+
+// bind records the admission of route by gw. A route admitted by any
+// listener of gw is admitted, otherwise the first rejection is kept.
+func (rb routeBindings) bind(route interface{}, generation int64, gw *gatewayv1.Gateway, reason string, err error) {
+	ref := gatewayv1.GatewayReference{Name: gw.Name, Namespace: gw.Namespace}
+	if rb[route] == nil {
+		rb[route] = make(map[gatewayv1.GatewayReference]gatewayv1.Condition)
+	}
+	cur, ok := rb[route][ref]
+	switch {
+	case err == nil:
+		rb[route][ref] = gatewayCondition(gatewayv1.ConditionAdmitted, true, ReasonAdmitted, "route is admitted", generation)
+	case !ok:
+		rb[route][ref] = gatewayCondition(gatewayv1.ConditionAdmitted, false, reason, err.Error(), generation)
+	default:
+		rb[route][ref] = cur
+	}
+}
+
+// statuses returns the statuses of route for each Gateway which binds it.
+func (rb routeBindings) statuses(route interface{}) []gatewayv1.RouteGatewayStatus {
+	var statuses []gatewayv1.RouteGatewayStatus
+	for ref, cond := range rb[route] {
+		statuses = append(statuses, gatewayv1.RouteGatewayStatus{
+			GatewayRef: ref,
+			Conditions: []gatewayv1.Condition{cond},
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		a, b := statuses[i].GatewayRef, statuses[j].GatewayRef
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return statuses
+}
+
+// gatewayCondition returns a Gateway API condition. The LastTransitionTime
+// is left to the writer of the status.
+func gatewayCondition(typ string, ok bool, reason, message string, generation int64) gatewayv1.Condition {
+	status := v1.ConditionFalse
+	if ok {
+		status = v1.ConditionTrue
+	}
+	return gatewayv1.Condition{
+		Type:               typ,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	}
+}
+
+// setGatewayStatus assigns a status to a Gateway API object.
+func (b *builder) setGatewayStatus(st GatewayStatus) {
+	b.gatewayStatuses = append(b.gatewayStatuses, st)
+}
+
+// processGateways adds the listeners of the Gateways of the GatewayClasses
+// managed by Contour to the DAG, and the routes they bind.
+func (b *builder) processGateways() {
+	if b.source.GatewayController == "" {
+		return
+	}
+
+	var names []string
+	for name := range b.source.gatewayclasses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	admitted := make(map[string]bool)
+	for _, name := range names {
+		gc := b.source.gatewayclasses[name]
+		if gc.Spec.Controller != b.source.GatewayController {
+			continue
+		}
+		admitted[name] = true
+		b.setGatewayStatus(GatewayStatus{
+			Object:     gc,
+			Conditions: []gatewayv1.Condition{gatewayCondition(gatewayv1.ConditionAdmitted, true, ReasonAdmitted, "GatewayClass is admitted by Contour", gc.Generation)},
+		})
+	}
+
+	bindings := make(routeBindings)
+	for _, m := range sortedMetas(b.source.gateways) {
+		gw := b.source.gateways[m]
+		if admitted[gw.Spec.GatewayClassName] {
+			b.processGateway(gw, bindings)
+		}
+	}
+
+	for _, m := range sortedMetas(b.source.httproutes) {
+		route := b.source.httproutes[m]
+		if _, ok := bindings[route]; ok {
+			b.setGatewayStatus(GatewayStatus{Object: route, Gateways: bindings.statuses(route)})
+		}
+	}
+	for _, m := range sortedMetas(b.source.tlsroutes) {
+		route := b.source.tlsroutes[m]
+		if _, ok := bindings[route]; ok {
+			b.setGatewayStatus(GatewayStatus{Object: route, Gateways: bindings.statuses(route)})
+		}
+	}
+}
+
+// sortedMetas returns the keys of m, a map keyed by meta, sorted by namespace and name.
+func sortedMetas(m interface{}) []meta {
+	var metas []meta
+	switch m := m.(type) {
+	case map[meta]*gatewayv1.Gateway:
+		for k := range m {
+			metas = append(metas, k)
+		}
+	case map[meta]*gatewayv1.HTTPRoute:
+		for k := range m {
+			metas = append(metas, k)
+		}
+	case map[meta]*gatewayv1.TLSRoute:
+		for k := range m {
+			metas = append(metas, k)
+		}
+	}
+	sort.Slice(metas, func(i, j int) bool {
+		if metas[i].namespace != metas[j].namespace {
+			return metas[i].namespace < metas[j].namespace
+		}
+		return metas[i].name < metas[j].name
+	})
+	return metas
+}
+
+// processGateway adds the listeners of gw to the DAG.
+func (b *builder) processGateway(gw *gatewayv1.Gateway, bindings routeBindings) {
+	var listeners []gatewayv1.ListenerStatus
+	notReady := 0
+	for _, l := range gw.Spec.Listeners {
+		cond := gatewayCondition(gatewayv1.ConditionReady, true, ReasonReady, "listener is ready", gw.Generation)
+		if reason, err := b.processListener(gw, l, bindings); err != nil {
+			cond = gatewayCondition(gatewayv1.ConditionReady, false, reason, err.Error(), gw.Generation)
+			notReady++
+		}
+		listeners = append(listeners, gatewayv1.ListenerStatus{
+			Port:       l.Port,
+			Protocol:   l.Protocol,
+			Hostname:   l.Hostname,
+			Conditions: []gatewayv1.Condition{cond},
+		})
+	}
+
+	cond := gatewayCondition(gatewayv1.ConditionReady, true, ReasonReady, "Gateway is ready", gw.Generation)
+	if notReady > 0 {
+		cond = gatewayCondition(gatewayv1.ConditionReady, false, ReasonListenersNotReady, fmt.Sprintf("%d of %d listeners are not ready", notReady, len(listeners)), gw.Generation)
+	}
+	b.setGatewayStatus(GatewayStatus{
+		Object:     gw,
+		Conditions: []gatewayv1.Condition{cond},
+		Listeners:  listeners,
+	})
+}
+
+// processListener adds the listener l of gw, and the routes it binds,
+// to the DAG. If l cannot be added the reason and error are returned.
+func (b *builder) processListener(gw *gatewayv1.Gateway, l gatewayv1.Listener, bindings routeBindings) (string, error) {
+	var hostname string
+	if l.Hostname != nil {
+		hostname = string(*l.Hostname)
+	}
+
+	switch l.Protocol {
+	case gatewayv1.HTTPProtocolType:
+		if l.Port != 80 {
+			return ReasonPortUnavailable, fmt.Errorf("HTTP listeners must use port 80")
+		}
+		if err := validateRouteKind(l.Routes, "HTTPRoute"); err != nil {
+			return ReasonInvalidRoutes, err
+		}
+		return b.bindHTTPRoutes(gw, l, hostname, nil, bindings)
+	case gatewayv1.HTTPSProtocolType:
+		if l.Port != 443 {
+			return ReasonPortUnavailable, fmt.Errorf("HTTPS listeners must use port 443")
+		}
+		if err := validateRouteKind(l.Routes, "HTTPRoute"); err != nil {
+			return ReasonInvalidRoutes, err
+		}
+		if err := validateSecureHostname(hostname); err != nil {
+			return ReasonInvalidHostname, err
+		}
+		if l.TLS != nil && l.TLS.Mode != nil && *l.TLS.Mode != gatewayv1.TLSModeTerminate {
+			return ReasonInvalidCertificateRef, fmt.Errorf("HTTPS listeners must terminate TLS")
+		}
+		sec, err := b.certificate(gw, l.TLS)
+		if err != nil {
+			return ReasonInvalidCertificateRef, err
+		}
+		return b.bindHTTPRoutes(gw, l, hostname, sec, bindings)
+	case gatewayv1.TLSProtocolType:
+		if l.Port != 443 {
+			return ReasonPortUnavailable, fmt.Errorf("TLS listeners must use port 443")
+		}
+		if err := validateRouteKind(l.Routes, "TLSRoute"); err != nil {
+			return ReasonInvalidRoutes, err
+		}
+		if l.TLS != nil && l.TLS.Mode != nil && *l.TLS.Mode == gatewayv1.TLSModePassthrough {
+			if l.TLS.CertificateRef != nil {
+				return ReasonInvalidCertificateRef, fmt.Errorf("TLS passthrough listeners cannot specify a certificateRef")
+			}
+			return b.bindTLSRoutes(gw, l, hostname, nil, bindings)
+		}
+		if err := validateSecureHostname(hostname); err != nil {
+			return ReasonInvalidHostname, err
+		}
+		sec, err := b.certificate(gw, l.TLS)
+		if err != nil {
+			return ReasonInvalidCertificateRef, err
+		}
+		return b.bindTLSRoutes(gw, l, hostname, sec, bindings)
+	default:
+		return ReasonUnsupportedProtocol, fmt.Errorf("protocol %q is not supported", l.Protocol)
+	}
+}
+
+// validateRouteKind checks that the routes of a listener select routes of kind.
+func validateRouteKind(sel gatewayv1.RouteBindingSelector, kind string) error {
+	if sel.Group != "" && sel.Group != gatewayv1.GroupName {
+		return fmt.Errorf("routes group %q is not supported", sel.Group)
+	}
+	if sel.Kind != "" && sel.Kind != kind {
+		return fmt.Errorf("routes kind %q is not supported, expected %q", sel.Kind, kind)
+	}
+	return nil
+}
+
+// validateSecureHostname checks that hostname names a single TLS virtual host.
+func validateSecureHostname(hostname string) error {
+	switch {
+	case hostname == "":
+		return fmt.Errorf("listeners which terminate TLS must specify a hostname")
+	case strings.HasPrefix(hostname, "*"):
+		return fmt.Errorf("listeners which terminate TLS cannot specify a wildcard hostname")
+	}
+	return nil
+}
+
+// certificate returns the Secret referenced by the tls configuration of a listener of gw.
+func (b *builder) certificate(gw *gatewayv1.Gateway, tls *gatewayv1.GatewayTLSConfig) (*Secret, error) {
+	if tls == nil || tls.CertificateRef == nil {
+		return nil, fmt.Errorf("listeners which terminate TLS must specify tls.certificateRef")
+	}
+	ref := tls.CertificateRef
+	if (ref.Group != "" && ref.Group != "core") || ref.Kind != "Secret" {
+		return nil, fmt.Errorf("certificateRef must refer to a Secret")
+	}
+	sec := b.lookupSecret(meta{name: ref.Name, namespace: gw.Namespace})
+	if sec == nil {
+		return nil, fmt.Errorf("secret %q not found", ref.Name)
+	}
+	return sec, nil
+}
+
+// bindHTTPRoutes adds the HTTPRoutes selected by the listener l of gw to the DAG.
+// If sec is not nil the routes are added to the TLS virtual host of hostname.
+func (b *builder) bindHTTPRoutes(gw *gatewayv1.Gateway, l gatewayv1.Listener, hostname string, sec *Secret, bindings routeBindings) (string, error) {
+	sel, err := b.routeSelector(gw, l.Routes)
+	if err != nil {
+		return ReasonInvalidRoutes, err
+	}
+	if sec != nil {
+		svhost := b.lookupSecureVirtualHost(hostname, 443)
+		svhost.secret = sec
+		svhost.MinProtoVersion = auth.TlsParameters_TLSv1_1
+	}
+
+	for _, m := range sortedMetas(b.source.httproutes) {
+		route := b.source.httproutes[m]
+		if !sel(route.ObjectMeta) || !routeAllows(route.Spec.Gateways, route.Namespace, gw) {
+			continue
+		}
+		hosts := routeHostnames(hostname, route.Spec.Hostnames)
+		if len(hosts) == 0 {
+			bindings.bind(route, route.Generation, gw, ReasonNoMatchingHostname, fmt.Errorf("no hostnames match the listeners of the Gateway"))
+			continue
+		}
+		routes, err := b.httpRoutes(route)
+		if err != nil {
+			bindings.bind(route, route.Generation, gw, ReasonInvalid, err)
+			continue
+		}
+		for _, r := range routes {
+			if sec != nil {
+				b.lookupSecureVirtualHost(hostname, 443).addRoute(r)
+				continue
+			}
+			for _, host := range hosts {
+				b.lookupVirtualHost(host, 80).addRoute(r)
+			}
+		}
+		bindings.bind(route, route.Generation, gw, "", nil)
+	}
+	return "", nil
+}
+
+// bindTLSRoutes adds the TLSRoutes selected by the listener l of gw to the DAG.
+// If sec is nil TLS is passed through to the services of the routes.
+func (b *builder) bindTLSRoutes(gw *gatewayv1.Gateway, l gatewayv1.Listener, hostname string, sec *Secret, bindings routeBindings) (string, error) {
+	sel, err := b.routeSelector(gw, l.Routes)
+	if err != nil {
+		return ReasonInvalidRoutes, err
+	}
+
+	for _, m := range sortedMetas(b.source.tlsroutes) {
+		route := b.source.tlsroutes[m]
+		if !sel(route.ObjectMeta) || !routeAllows(route.Spec.Gateways, route.Namespace, gw) {
+			continue
+		}
+		proxies, err := b.tlsProxies(route, hostname)
+		if err != nil {
+			bindings.bind(route, route.Generation, gw, ReasonInvalid, err)
+			continue
+		}
+		if len(proxies) == 0 {
+			bindings.bind(route, route.Generation, gw, ReasonNoMatchingHostname, fmt.Errorf("no snis match the listeners of the Gateway"))
+			continue
+		}
+		if err := b.checkSNIs(proxies, sec); err != nil {
+			bindings.bind(route, route.Generation, gw, ReasonInvalid, err)
+			continue
+		}
+		for sni, proxy := range proxies {
+			svhost := b.lookupSecureVirtualHost(sni, 443)
+			svhost.secret = sec
+			if sec != nil {
+				svhost.MinProtoVersion = auth.TlsParameters_TLSv1_1
+			}
+			svhost.TCPProxy = proxy
+		}
+		bindings.bind(route, route.Generation, gw, "", nil)
+	}
+	return "", nil
+}
+
+// checkSNIs checks that the snis of proxies are not bound already.
+func (b *builder) checkSNIs(proxies map[string]*TCPProxy, sec *Secret) error {
+	var snis []string
+	for sni := range proxies {
+		snis = append(snis, sni)
+	}
+	sort.Strings(snis)
+	for _, sni := range snis {
+		svhost, ok := b.svhosts[hostport{host: sni, port: 443}]
+		if !ok {
+			continue
+		}
+		if svhost.TCPProxy != nil || len(svhost.routes) > 0 || (sec == nil && svhost.secret != nil) {
+			return fmt.Errorf("sni %q is already bound", sni)
+		}
+	}
+	return nil
+}
+
+// routeSelector returns a function which reports whether a route is
+// selected by the listener routes sel of gw.
+func (b *builder) routeSelector(gw *gatewayv1.Gateway, sel gatewayv1.RouteBindingSelector) (func(metav1.ObjectMeta) bool, error) {
+	routes := labels.Everything()
+	if sel.Selector != nil {
+		var err error
+		routes, err = metav1.LabelSelectorAsSelector(sel.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid routes selector: %v", err)
+		}
+	}
+
+	var namespaces func(string) bool
+	switch sel.Namespaces.From {
+	case "", gatewayv1.RouteSelectSame:
+		namespaces = func(ns string) bool { return ns == gw.Namespace }
+	case gatewayv1.RouteSelectAll:
+		namespaces = func(string) bool { return true }
+	case gatewayv1.RouteSelectSelector:
+		if sel.Namespaces.Selector == nil {
+			return nil, fmt.Errorf("routes namespaces must specify a selector")
+		}
+		nsSelector, err := metav1.LabelSelectorAsSelector(sel.Namespaces.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid routes namespaces selector: %v", err)
+		}
+		namespaces = func(name string) bool {
+			ns, ok := b.source.namespaces[name]
+			return ok && nsSelector.Matches(labels.Set(ns.Labels))
+		}
+	default:
+		return nil, fmt.Errorf("routes namespaces from %q is not supported", sel.Namespaces.From)
+	}
+
+	return func(m metav1.ObjectMeta) bool {
+		return namespaces(m.Namespace) && routes.Matches(labels.Set(m.Labels))
+	}, nil
+}
+
+// routeAllows reports whether a route in namespace allows gw to bind it.
+func routeAllows(gateways gatewayv1.RouteGateways, namespace string, gw *gatewayv1.Gateway) bool {
+	switch gateways.Allow {
+	case "", gatewayv1.GatewayAllowSameNamespace:
+		return gw.Namespace == namespace
+	case gatewayv1.GatewayAllowAll:
+		return true
+	case gatewayv1.GatewayAllowFromList:
+		for _, ref := range gateways.GatewayRefs {
+			if ref.Name == gw.Name && ref.Namespace == gw.Namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// routeHostnames returns the hostnames of a route bound by a listener of
+// hostname. A hostname is matched by an equal hostname or by a wildcard
+// hostname of the same domain, the more specific of the two is returned.
+func routeHostnames(hostname string, hostnames []gatewayv1.Hostname) []string {
+	if len(hostnames) == 0 {
+		if hostname == "" {
+			return []string{"*"}
+		}
+		return []string{hostname}
+	}
+
+	var hosts []string
+	for _, h := range hostnames {
+		h := string(h)
+		switch {
+		case hostname == "" || hostname == h || matchesWildcard(hostname, h):
+			hosts = append(hosts, h)
+		case matchesWildcard(h, hostname):
+			hosts = append(hosts, hostname)
+		}
+	}
+	return hosts
+}
+
+// matchesWildcard reports whether host is matched by the wildcard hostname wildcard.
+func matchesWildcard(wildcard, host string) bool {
+	return strings.HasPrefix(wildcard, "*.") && strings.HasSuffix(host, wildcard[1:])
+}
+
+// forwardTo returns the services of namespace referenced by forwardTo.
+func (b *builder) forwardTo(namespace string, forwardTo []gatewayv1.RouteForwardTo) ([]*Service, error) {
+	if len(forwardTo) == 0 {
+		return nil, fmt.Errorf("rules must forward to at least one service")
+	}
+	var services []*Service
+	for _, f := range forwardTo {
+		if isBlank(f.ServiceName) {
+			return nil, fmt.Errorf("forwardTo must specify a serviceName")
+		}
+		if f.Port < 1 || f.Port > 65535 {
+			return nil, fmt.Errorf("service %q: port must be in the range 1-65535", f.ServiceName)
+		}
+		weight := 1
+		if f.Weight != nil {
+			if *f.Weight < 0 {
+				return nil, fmt.Errorf("service %q: weight must be greater than or equal to zero", f.ServiceName)
+			}
+			weight = int(*f.Weight)
+		}
+		m := meta{name: f.ServiceName, namespace: namespace}
+		if svc := b.lookupService(m, intstr.FromInt(int(f.Port)), weight, "", nil, nil, nil, nil); svc != nil {
+			services = append(services, svc)
+		}
+	}
+	return services, nil
+}
+
+// httpRoutes returns the dag.Routes of the rules of route.
+func (b *builder) httpRoutes(route *gatewayv1.HTTPRoute) ([]*Route, error) {
+	var routes []*Route
+	for _, rule := range route.Spec.Rules {
+		services, err := b.forwardTo(route.Namespace, rule.ForwardTo)
+		if err != nil {
+			return nil, err
+		}
+		matches := rule.Matches
+		if len(matches) == 0 {
+			matches = []gatewayv1.HTTPRouteMatch{{}}
+		}
+		for _, match := range matches {
+			r, err := httpRouteMatch(match)
+			if err != nil {
+				return nil, err
+			}
+			r.object = route
+			for _, svc := range services {
+				r.addService(svc)
+			}
+			routes = append(routes, r)
+		}
+	}
+	return routes, nil
+}
+
+// httpRouteMatch returns a dag.Route which matches the requests matched by match.
+func httpRouteMatch(match gatewayv1.HTTPRouteMatch) (*Route, error) {
+	var r Route
+	value := match.Path.Value
+	switch match.Path.Type {
+	case "", gatewayv1.PathMatchPrefix:
+		if value == "" {
+			value = "/"
+		}
+		if !strings.HasPrefix(value, "/") {
+			return nil, fmt.Errorf("path prefix %q must start with /", value)
+		}
+		r.Prefix = value
+	case gatewayv1.PathMatchExact:
+		if !strings.HasPrefix(value, "/") {
+			return nil, fmt.Errorf("exact path %q must start with /", value)
+		}
+		r.Path = value
+	case gatewayv1.PathMatchRegularExpression:
+		if _, err := regexp.Compile(value); err != nil {
+			return nil, fmt.Errorf("path regular expression %q is invalid: %v", value, err)
+		}
+		r.Regex = value
+	default:
+		return nil, fmt.Errorf("path match type %q is not supported", match.Path.Type)
+	}
+
+	if h := match.Headers; h != nil {
+		if h.Type != "" && h.Type != gatewayv1.HeaderMatchExact {
+			return nil, fmt.Errorf("header match type %q is not supported", h.Type)
+		}
+		var names []string
+		for name := range h.Values {
+			if isBlank(name) {
+				return nil, fmt.Errorf("header match must specify a header name")
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			r.HeaderConditions = append(r.HeaderConditions, HeaderCondition{
+				Name:      name,
+				MatchType: HeaderMatchTypeExact,
+				Value:     h.Values[name],
+			})
+		}
+	}
+	return &r, nil
+}
+
+// tlsProxies returns the TCPProxies of the rules of route keyed by SNI.
+// Only the SNIs matched by a listener of hostname are returned.
+func (b *builder) tlsProxies(route *gatewayv1.TLSRoute, hostname string) (map[string]*TCPProxy, error) {
+	proxies := make(map[string]*TCPProxy)
+	for _, rule := range route.Spec.Rules {
+		services, err := b.forwardTo(route.Namespace, rule.ForwardTo)
+		if err != nil {
+			return nil, err
+		}
+		var snis []gatewayv1.Hostname
+		for _, match := range rule.Matches {
+			snis = append(snis, match.SNIs...)
+		}
+		if len(snis) == 0 && (hostname == "" || strings.HasPrefix(hostname, "*")) {
+			return nil, fmt.Errorf("rules must specify snis unless the listener specifies a hostname")
+		}
+		for _, sni := range snis {
+			if strings.HasPrefix(string(sni), "*") {
+				return nil, fmt.Errorf("sni %q cannot be a wildcard", sni)
+			}
+		}
+		for _, sni := range routeHostnames(hostname, snis) {
+			if _, ok := proxies[sni]; ok {
+				return nil, fmt.Errorf("sni %q is matched by more than one rule", sni)
+			}
+			var proxy TCPProxy
+			for _, svc := range services {
+				proxy.addService(svc)
+			}
+			proxies[sni] = &proxy
+		}
+	}
+	return proxies, nil
+}
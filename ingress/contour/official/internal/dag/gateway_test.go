@@ -0,0 +1,498 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	"github.com/google/go-cmp/cmp"
+	gatewayv1 "github.com/heptio/contour/apis/gateway/v1alpha1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDAGGateway(t *testing.T) {
+	const controller = "contour.heptio.com/gateway-controller"
+
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	sec1 := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Data: secretdata("certificate", "key"),
+	}
+
+	gc1 := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "contour",
+		},
+		Spec: gatewayv1.GatewayClassSpec{
+			Controller: controller,
+		},
+	}
+
+	// gc2 is managed by another controller
+	gc2 := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "other",
+		},
+		Spec: gatewayv1.GatewayClassSpec{
+			Controller: "example.com/gateway-controller",
+		},
+	}
+
+	hostname := func(h string) *gatewayv1.Hostname {
+		hn := gatewayv1.Hostname(h)
+		return &hn
+	}
+
+	gateway := func(class string, listeners ...gatewayv1.Listener) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: class,
+				Listeners:        listeners,
+			},
+		}
+	}
+
+	httpListener := gatewayv1.Listener{
+		Port:     80,
+		Protocol: gatewayv1.HTTPProtocolType,
+	}
+	httpsListener := gatewayv1.Listener{
+		Hostname: hostname("example.com"),
+		Port:     443,
+		Protocol: gatewayv1.HTTPSProtocolType,
+		TLS: &gatewayv1.GatewayTLSConfig{
+			CertificateRef: &gatewayv1.LocalObjectReference{
+				Kind: "Secret",
+				Name: "secret",
+			},
+		},
+	}
+	passthrough := gatewayv1.TLSModePassthrough
+	tlsListener := gatewayv1.Listener{
+		Port:     443,
+		Protocol: gatewayv1.TLSProtocolType,
+		TLS: &gatewayv1.GatewayTLSConfig{
+			Mode: &passthrough,
+		},
+	}
+	tcpListener := gatewayv1.Listener{
+		Port:     5432,
+		Protocol: gatewayv1.TCPProtocolType,
+	}
+
+	gw1 := gateway("contour", httpListener)
+	gw2 := gateway("contour", httpsListener)
+	gw3 := gateway("contour", tlsListener)
+	gw4 := gateway("contour", httpListener, tcpListener)
+	gw5 := gateway("other", httpListener)
+
+	forwardTo := []gatewayv1.RouteForwardTo{{
+		ServiceName: "kuard",
+		Port:        8080,
+	}}
+
+	httproute := func(namespace string, spec gatewayv1.HTTPRouteSpec) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: namespace,
+			},
+			Spec: spec,
+		}
+	}
+
+	// hr1 matches a prefix, an exact path and headers
+	hr1 := httproute("default", gatewayv1.HTTPRouteSpec{
+		Hostnames: []gatewayv1.Hostname{"example.com"},
+		Rules: []gatewayv1.HTTPRouteRule{{
+			ForwardTo: forwardTo,
+		}, {
+			Matches: []gatewayv1.HTTPRouteMatch{{
+				Path: gatewayv1.HTTPPathMatch{
+					Type:  gatewayv1.PathMatchExact,
+					Value: "/healthz",
+				},
+				Headers: &gatewayv1.HTTPHeaderMatch{
+					Values: map[string]string{
+						"x-version": "2",
+					},
+				},
+			}},
+			ForwardTo: forwardTo,
+		}},
+	})
+
+	// hr2 lives in another namespace and does not allow gw1 to bind it
+	hr2 := httproute("other", gatewayv1.HTTPRouteSpec{
+		Rules: []gatewayv1.HTTPRouteRule{{
+			ForwardTo: forwardTo,
+		}},
+	})
+
+	// hr3 does not match the hostname of the https listener
+	hr3 := httproute("default", gatewayv1.HTTPRouteSpec{
+		Hostnames: []gatewayv1.Hostname{"example.org"},
+		Rules: []gatewayv1.HTTPRouteRule{{
+			ForwardTo: forwardTo,
+		}},
+	})
+
+	// hr4 has an invalid path prefix
+	hr4 := httproute("default", gatewayv1.HTTPRouteSpec{
+		Rules: []gatewayv1.HTTPRouteRule{{
+			Matches: []gatewayv1.HTTPRouteMatch{{
+				Path: gatewayv1.HTTPPathMatch{
+					Value: "api",
+				},
+			}},
+			ForwardTo: forwardTo,
+		}},
+	})
+
+	tr1 := &gatewayv1.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: gatewayv1.TLSRouteSpec{
+			Rules: []gatewayv1.TLSRouteRule{{
+				Matches: []gatewayv1.TLSRouteMatch{{
+					SNIs: []gatewayv1.Hostname{"example.com"},
+				}},
+				ForwardTo: forwardTo,
+			}},
+		},
+	}
+
+	kuard := servicemap(&Service{
+		Object:      s1,
+		ServicePort: &s1.Spec.Ports[0],
+		Weight:      1,
+	})
+
+	admitted := func(obj metav1.Object) []gatewayv1.Condition {
+		return []gatewayv1.Condition{
+			gatewayCondition(gatewayv1.ConditionAdmitted, true, ReasonAdmitted, "GatewayClass is admitted by Contour", obj.GetGeneration()),
+		}
+	}
+	ready := func(l gatewayv1.Listener) gatewayv1.ListenerStatus {
+		return gatewayv1.ListenerStatus{
+			Port:       l.Port,
+			Protocol:   l.Protocol,
+			Hostname:   l.Hostname,
+			Conditions: []gatewayv1.Condition{gatewayCondition(gatewayv1.ConditionReady, true, ReasonReady, "listener is ready", 0)},
+		}
+	}
+	gatewayReady := []gatewayv1.Condition{gatewayCondition(gatewayv1.ConditionReady, true, ReasonReady, "Gateway is ready", 0)}
+	bound := func(cond gatewayv1.Condition) []gatewayv1.RouteGatewayStatus {
+		return []gatewayv1.RouteGatewayStatus{{
+			GatewayRef: gatewayv1.GatewayReference{Name: "gateway", Namespace: "default"},
+			Conditions: []gatewayv1.Condition{cond},
+		}}
+	}
+	routeAdmitted := gatewayCondition(gatewayv1.ConditionAdmitted, true, ReasonAdmitted, "route is admitted", 0)
+
+	tests := map[string]struct {
+		controller string
+		objs       []interface{}
+		want       []Vertex
+		wantStatus []GatewayStatus
+	}{
+		"gateway api disabled": {
+			objs: []interface{}{
+				s1, gc1, gw1, hr1,
+			},
+			want: []Vertex{},
+		},
+		"gatewayclass of another controller": {
+			controller: controller,
+			objs: []interface{}{
+				s1, gc2, gw5, hr1,
+			},
+			want: []Vertex{},
+		},
+		"http listener": {
+			controller: controller,
+			objs: []interface{}{
+				s1, gc1, gw1, hr1, hr2,
+			},
+			want: []Vertex{
+				&VirtualHost{
+					Host: "example.com",
+					Port: 80,
+					routes: routemap(
+						route("/", hr1, kuard),
+						&Route{
+							Path:             "/healthz",
+							HeaderConditions: []HeaderCondition{{Name: "x-version", MatchType: HeaderMatchTypeExact, Value: "2"}},
+							object:           hr1,
+							services:         kuard,
+						},
+					),
+				},
+			},
+			wantStatus: []GatewayStatus{
+				{Object: gc1, Conditions: admitted(gc1)},
+				{Object: gw1, Conditions: gatewayReady, Listeners: []gatewayv1.ListenerStatus{ready(httpListener)}},
+				{Object: hr1, Gateways: bound(routeAdmitted)},
+			},
+		},
+		"https listener": {
+			controller: controller,
+			objs: []interface{}{
+				s1, sec1, gc1, gw2, hr1,
+			},
+			want: []Vertex{
+				&SecureVirtualHost{
+					VirtualHost: VirtualHost{
+						Host: "example.com",
+						Port: 443,
+						routes: routemap(
+							route("/", hr1, kuard),
+							&Route{
+								Path:             "/healthz",
+								HeaderConditions: []HeaderCondition{{Name: "x-version", MatchType: HeaderMatchTypeExact, Value: "2"}},
+								object:           hr1,
+								services:         kuard,
+							},
+						),
+					},
+					MinProtoVersion: auth.TlsParameters_TLSv1_1,
+					secret: &Secret{
+						object: sec1,
+					},
+				},
+			},
+			wantStatus: []GatewayStatus{
+				{Object: gc1, Conditions: admitted(gc1)},
+				{Object: gw2, Conditions: gatewayReady, Listeners: []gatewayv1.ListenerStatus{ready(httpsListener)}},
+				{Object: hr1, Gateways: bound(routeAdmitted)},
+			},
+		},
+		"https listener without secret": {
+			controller: controller,
+			objs: []interface{}{
+				s1, gc1, gw2, hr1,
+			},
+			want: []Vertex{},
+			wantStatus: []GatewayStatus{
+				{Object: gc1, Conditions: admitted(gc1)},
+				{
+					Object:     gw2,
+					Conditions: []gatewayv1.Condition{gatewayCondition(gatewayv1.ConditionReady, false, ReasonListenersNotReady, "1 of 1 listeners are not ready", 0)},
+					Listeners: []gatewayv1.ListenerStatus{{
+						Port:       443,
+						Protocol:   gatewayv1.HTTPSProtocolType,
+						Hostname:   httpsListener.Hostname,
+						Conditions: []gatewayv1.Condition{gatewayCondition(gatewayv1.ConditionReady, false, ReasonInvalidCertificateRef, `secret "secret" not found`, 0)},
+					}},
+				},
+			},
+		},
+		"https listener with route of another hostname": {
+			controller: controller,
+			objs: []interface{}{
+				s1, sec1, gc1, gw2, hr3,
+			},
+			want: []Vertex{
+				&SecureVirtualHost{
+					VirtualHost: VirtualHost{
+						Host: "example.com",
+						Port: 443,
+					},
+					MinProtoVersion: auth.TlsParameters_TLSv1_1,
+					secret: &Secret{
+						object: sec1,
+					},
+				},
+			},
+			wantStatus: []GatewayStatus{
+				{Object: gc1, Conditions: admitted(gc1)},
+				{Object: gw2, Conditions: gatewayReady, Listeners: []gatewayv1.ListenerStatus{ready(httpsListener)}},
+				{Object: hr3, Gateways: bound(gatewayCondition(gatewayv1.ConditionAdmitted, false, ReasonNoMatchingHostname, "no hostnames match the listeners of the Gateway", 0))},
+			},
+		},
+		"invalid httproute": {
+			controller: controller,
+			objs: []interface{}{
+				s1, gc1, gw1, hr4,
+			},
+			want: []Vertex{},
+			wantStatus: []GatewayStatus{
+				{Object: gc1, Conditions: admitted(gc1)},
+				{Object: gw1, Conditions: gatewayReady, Listeners: []gatewayv1.ListenerStatus{ready(httpListener)}},
+				{Object: hr4, Gateways: bound(gatewayCondition(gatewayv1.ConditionAdmitted, false, ReasonInvalid, `path prefix "api" must start with /`, 0))},
+			},
+		},
+		"tls passthrough listener": {
+			controller: controller,
+			objs: []interface{}{
+				s1, gc1, gw3, tr1,
+			},
+			want: []Vertex{
+				&SecureVirtualHost{
+					VirtualHost: VirtualHost{
+						Host: "example.com",
+						Port: 443,
+					},
+					TCPProxy: &TCPProxy{
+						services: kuard,
+					},
+				},
+			},
+			wantStatus: []GatewayStatus{
+				{Object: gc1, Conditions: admitted(gc1)},
+				{Object: gw3, Conditions: gatewayReady, Listeners: []gatewayv1.ListenerStatus{ready(tlsListener)}},
+				{Object: tr1, Gateways: bound(routeAdmitted)},
+			},
+		},
+		"unsupported listener protocol": {
+			controller: controller,
+			objs: []interface{}{
+				s1, gc1, gw4, hr2,
+			},
+			want: []Vertex{},
+			wantStatus: []GatewayStatus{
+				{Object: gc1, Conditions: admitted(gc1)},
+				{
+					Object:     gw4,
+					Conditions: []gatewayv1.Condition{gatewayCondition(gatewayv1.ConditionReady, false, ReasonListenersNotReady, "1 of 2 listeners are not ready", 0)},
+					Listeners: []gatewayv1.ListenerStatus{
+						ready(httpListener),
+						{
+							Port:       5432,
+							Protocol:   gatewayv1.TCPProtocolType,
+							Conditions: []gatewayv1.Condition{gatewayCondition(gatewayv1.ConditionReady, false, ReasonUnsupportedProtocol, `protocol "TCP" is not supported`, 0)},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := Builder{
+				KubernetesCache: KubernetesCache{
+					GatewayController: tc.controller,
+				},
+			}
+			for _, o := range tc.objs {
+				b.Insert(o)
+			}
+			dag := b.Build()
+
+			got := make(map[hostport]Vertex)
+			dag.Visit(func(v Vertex) {
+				switch v := v.(type) {
+				case *VirtualHost:
+					got[hostport{host: v.Host, port: v.Port}] = v
+				case *SecureVirtualHost:
+					got[hostport{host: v.Host, port: v.Port}] = v
+				}
+			})
+
+			want := make(map[hostport]Vertex)
+			for _, v := range tc.want {
+				switch v := v.(type) {
+				case *VirtualHost:
+					want[hostport{host: v.Host, port: v.Port}] = v
+				case *SecureVirtualHost:
+					want[hostport{host: v.Host, port: v.Port}] = v
+				}
+			}
+
+			opts := []cmp.Option{
+				cmp.AllowUnexported(VirtualHost{}, SecureVirtualHost{}, Route{}, Secret{}, TCPProxy{}),
+			}
+			if diff := cmp.Diff(want, got, opts...); diff != "" {
+				t.Fatal(diff)
+			}
+			if diff := cmp.Diff(tc.wantStatus, dag.GatewayStatuses()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestRouteHostnames(t *testing.T) {
+	tests := map[string]struct {
+		hostname  string
+		hostnames []gatewayv1.Hostname
+		want      []string
+	}{
+		"no hostnames": {
+			want: []string{"*"},
+		},
+		"listener hostname": {
+			hostname: "example.com",
+			want:     []string{"example.com"},
+		},
+		"route hostnames": {
+			hostnames: []gatewayv1.Hostname{"example.com", "example.org"},
+			want:      []string{"example.com", "example.org"},
+		},
+		"matching hostname": {
+			hostname:  "example.com",
+			hostnames: []gatewayv1.Hostname{"example.com", "example.org"},
+			want:      []string{"example.com"},
+		},
+		"wildcard listener hostname": {
+			hostname:  "*.example.com",
+			hostnames: []gatewayv1.Hostname{"www.example.com", "example.com"},
+			want:      []string{"www.example.com"},
+		},
+		"wildcard route hostname": {
+			hostname:  "www.example.com",
+			hostnames: []gatewayv1.Hostname{"*.example.com"},
+			want:      []string{"www.example.com"},
+		},
+		"no matching hostname": {
+			hostname:  "example.com",
+			hostnames: []gatewayv1.Hostname{"example.org"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := routeHostnames(tc.hostname, tc.hostnames)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
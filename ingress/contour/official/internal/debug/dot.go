@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"io"
 
+	ingressroutev1 "github.com/heptio/contour/apis/contour/v1beta1"
 	"github.com/heptio/contour/internal/dag"
 )
 
@@ -50,11 +51,23 @@ func (c *ctx) writeVertex(v dag.Vertex) {
 		fmt.Fprintf(c.w, `"%p" [shape=record, label="{http://%s:%d}"]`+"\n", v, v.Host, v.Port)
 	case *dag.SecureVirtualHost:
 		fmt.Fprintf(c.w, `"%p" [shape=record, label="{https://%s:%d}"]`+"\n", v, v.Host, v.Port)
+	case *dag.AuthorizationServer:
+		fmt.Fprintf(c.w, `"%p" [shape=record, label="{authorization}"]`+"\n", v)
+	case *dag.TCPProxy:
+		fmt.Fprintf(c.w, `"%p" [shape=record, label="{tcpproxy}"]`+"\n", v)
 	case *dag.Route:
 		fmt.Fprintf(c.w, `"%p" [shape=record, label="{prefix|%s}"]`+"\n", v, v.Prefix)
 	}
 }
 
+func (c *ctx) writeOrphan(ir *ingressroutev1.IngressRoute) {
+	if c.nodes[ir] {
+		return
+	}
+	c.nodes[ir] = true
+	fmt.Fprintf(c.w, `"%p" [shape=record, style=dashed, label="{orphaned ingressroute|%s/%s}"]`+"\n", ir, ir.Namespace, ir.Name)
+}
+
 func (c *ctx) writeEdge(parent, child dag.Vertex) {
 	if c.edges[pair{parent, child}] {
 		return
@@ -85,7 +98,16 @@ func (dw *dotWriter) writeDot(w io.Writer) {
 		})
 	}
 
-	dw.Builder.Build().Visit(visit)
+	d := dw.Builder.Build()
+	d.Visit(visit)
+
+	// orphaned ingressroutes are not reachable from any root so
+	// draw them as free standing nodes.
+	for _, st := range d.IngressRouteStatuses() {
+		if st.Status == dag.StatusOrphaned {
+			ctx.writeOrphan(st.Object)
+		}
+	}
 
 	fmt.Fprintln(w, "}")
 }
@@ -16,6 +16,8 @@ package e2e
 import (
 	"bytes"
 	"context"
+	"net"
+	"strconv"
 	"testing"
 
 	ingressroutev1 "github.com/heptio/contour/apis/contour/v1beta1"
@@ -25,7 +27,11 @@ import (
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/listener"
 	accesslog "github.com/envoyproxy/go-control-plane/envoy/config/filter/accesslog/v2"
+	ext_authz "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/ext_authz/v2alpha"
 	envoy_config_v2_http_conn_mgr "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	envoy_service_auth_v2alpha "github.com/envoyproxy/go-control-plane/envoy/service/auth/v2alpha"
+	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type"
+	"github.com/gogo/googleapis/google/rpc"
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
@@ -413,6 +419,268 @@ func TestIngressRouteTLSListener(t *testing.T) {
 	}, streamLDS(t, cc))
 }
 
+func TestIngressRouteAuthorizationListener(t *testing.T) {
+	rh, cc, done := setup(t)
+	defer done()
+
+	rh.OnAdd(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			v1.TLSCertKey:       []byte("certificate"),
+			v1.TLSPrivateKeyKey: []byte("key"),
+		},
+	})
+
+	// auth implements the envoy external authorization gRPC API
+	rh.OnAdd(serviceWithAnnotations("default", "auth", map[string]string{
+		"contour.heptio.com/upstream-protocol.h2c": "9000",
+	}, v1.ServicePort{
+		Protocol:   "TCP",
+		Port:       9000,
+		TargetPort: intstr.FromInt(9000),
+	}))
+
+	rh.OnAdd(&ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "kuard.example.com",
+				TLS: &ingressroutev1.TLS{
+					SecretName: "secret",
+				},
+				Authorization: &ingressroutev1.AuthorizationServer{
+					Name:            "auth",
+					Port:            9000,
+					FailOpen:        true,
+					ResponseTimeout: "1s",
+				},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "backend",
+					Port: 80,
+				}},
+			}},
+		},
+	})
+
+	authz := &envoy_config_v2_http_conn_mgr.HttpFilter{
+		Name: "envoy.ext_authz",
+		Config: messageToStruct(&ext_authz.ExtAuthz{
+			Services: &ext_authz.ExtAuthz_GrpcService{
+				GrpcService: &core.GrpcService{
+					TargetSpecifier: &core.GrpcService_EnvoyGrpc_{
+						EnvoyGrpc: &core.GrpcService_EnvoyGrpc{
+							ClusterName: "default/auth/9000/da39a3ee5e",
+						},
+					},
+					Timeout: &types.Duration{Seconds: 1},
+				},
+			},
+			FailureModeAllow: true,
+		}),
+	}
+
+	assertEqual(t, &v2.DiscoveryResponse{
+		VersionInfo: "0",
+		Resources: []types.Any{
+			any(t, &v2.Listener{
+				Name:    "ingress_http",
+				Address: socketaddress("0.0.0.0", 8080),
+				FilterChains: []listener.FilterChain{
+					filterchain(false, httpfilter("ingress_http")),
+				},
+			}),
+			any(t, &v2.Listener{
+				Name:    "ingress_https",
+				Address: socketaddress("0.0.0.0", 8443),
+				FilterChains: []listener.FilterChain{
+					filterchaintls([]string{"kuard.example.com"}, "certificate", "key", false, httpfilter("ingress_https", authz)),
+				},
+				ListenerFilters: []listener.ListenerFilter{
+					envoy.TLSInspector(),
+				},
+			}),
+		},
+		TypeUrl: listenerType,
+		Nonce:   "0",
+	}, streamLDS(t, cc))
+}
+
+// authorizationServer is a stub envoy external authorization service, which
+// allows requests with the "authorization: allow" header and denies all others.
+type authorizationServer struct{}
+
+func (authorizationServer) Check(ctx context.Context, req *envoy_service_auth_v2alpha.CheckRequest) (*envoy_service_auth_v2alpha.CheckResponse, error) {
+	if req.GetAttributes().GetRequest().GetHttp().GetHeaders()["authorization"] == "allow" {
+		return &envoy_service_auth_v2alpha.CheckResponse{
+			Status: &rpc.Status{Code: int32(rpc.OK)},
+		}, nil
+	}
+	return &envoy_service_auth_v2alpha.CheckResponse{
+		Status: &rpc.Status{Code: int32(rpc.PERMISSION_DENIED)},
+		HttpResponse: &envoy_service_auth_v2alpha.CheckResponse_DeniedResponse{
+			DeniedResponse: &envoy_service_auth_v2alpha.DeniedHttpResponse{
+				Status: &envoy_type.HttpStatus{Code: envoy_type.StatusCode_Forbidden},
+			},
+		},
+	}, nil
+}
+
+// TestIngressRouteAuthorizationCheck follows the ext_authz filter of the
+// https listener through CDS and EDS to a stub authorization service and
+// checks that it allows and denies requests as envoy would call it.
+func TestIngressRouteAuthorizationCheck(t *testing.T) {
+	rh, cc, done := setup(t)
+	defer done()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	check(t, err)
+	srv := grpc.NewServer()
+	envoy_service_auth_v2alpha.RegisterAuthorizationServer(srv, authorizationServer{})
+	go srv.Serve(l)
+	defer srv.Stop()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	rh.OnAdd(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			v1.TLSCertKey:       []byte("certificate"),
+			v1.TLSPrivateKeyKey: []byte("key"),
+		},
+	})
+
+	rh.OnAdd(serviceWithAnnotations("default", "auth", map[string]string{
+		"contour.heptio.com/upstream-protocol.h2c": strconv.Itoa(port),
+	}, v1.ServicePort{
+		Protocol:   "TCP",
+		Port:       int32(port),
+		TargetPort: intstr.FromInt(port),
+	}))
+
+	rh.OnAdd(endpoints("default", "auth", v1.EndpointSubset{
+		Addresses: addresses("127.0.0.1"),
+		Ports: []v1.EndpointPort{{
+			Port: int32(port),
+		}},
+	}))
+
+	rh.OnAdd(&ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "kuard.example.com",
+				TLS: &ingressroutev1.TLS{
+					SecretName: "secret",
+				},
+				Authorization: &ingressroutev1.AuthorizationServer{
+					Name: "auth",
+					Port: port,
+				},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "backend",
+					Port: 80,
+				}},
+			}},
+		},
+	})
+
+	// find the ext_authz filter of the https listener.
+	var authz *ext_authz.ExtAuthz
+	for _, r := range streamLDS(t, cc, "ingress_https").Resources {
+		var l v2.Listener
+		check(t, types.UnmarshalAny(&r, &l))
+		var hcm envoy_config_v2_http_conn_mgr.HttpConnectionManager
+		structToMessage(t, l.FilterChains[0].Filters[0].Config, &hcm)
+		for _, f := range hcm.HttpFilters {
+			if f.Name == "envoy.ext_authz" {
+				authz = new(ext_authz.ExtAuthz)
+				structToMessage(t, f.Config, authz)
+			}
+		}
+	}
+	if authz == nil {
+		t.Fatal("ext_authz filter not found")
+	}
+
+	// resolve the authorization cluster to its endpoints.
+	clusterName := authz.GetGrpcService().GetEnvoyGrpc().GetClusterName()
+	var serviceName string
+	for _, r := range streamCDS(t, cc).Resources {
+		var c v2.Cluster
+		check(t, types.UnmarshalAny(&r, &c))
+		if c.Name != clusterName {
+			continue
+		}
+		if c.Http2ProtocolOptions == nil {
+			t.Fatalf("cluster %q: expected http2 protocol options for gRPC", c.Name)
+		}
+		serviceName = c.EdsClusterConfig.ServiceName
+	}
+	if serviceName == "" {
+		t.Fatalf("cluster %q not found", clusterName)
+	}
+	var cla v2.ClusterLoadAssignment
+	resp := streamEDS(t, cc, serviceName)
+	if len(resp.Resources) != 1 {
+		t.Fatalf("expected one ClusterLoadAssignment for %q, got %d", serviceName, len(resp.Resources))
+	}
+	check(t, types.UnmarshalAny(&resp.Resources[0], &cla))
+	sa := cla.Endpoints[0].LbEndpoints[0].Endpoint.Address.GetSocketAddress()
+
+	conn, err := grpc.Dial(net.JoinHostPort(sa.Address, strconv.Itoa(int(sa.GetPortValue()))), grpc.WithInsecure())
+	check(t, err)
+	defer conn.Close()
+	client := envoy_service_auth_v2alpha.NewAuthorizationClient(conn)
+
+	checkRequest := func(headers map[string]string) *envoy_service_auth_v2alpha.CheckResponse {
+		t.Helper()
+		resp, err := client.Check(context.TODO(), &envoy_service_auth_v2alpha.CheckRequest{
+			Attributes: &envoy_service_auth_v2alpha.AttributeContext{
+				Request: &envoy_service_auth_v2alpha.AttributeContext_Request{
+					Http: &envoy_service_auth_v2alpha.AttributeContext_HttpRequest{
+						Method:  "GET",
+						Host:    "kuard.example.com",
+						Path:    "/",
+						Headers: headers,
+					},
+				},
+			},
+		})
+		check(t, err)
+		return resp
+	}
+
+	allowed := checkRequest(map[string]string{"authorization": "allow"})
+	if got := rpc.Code(allowed.Status.Code); got != rpc.OK {
+		t.Errorf("allowed request: expected %v, got %v", rpc.OK, got)
+	}
+
+	denied := checkRequest(map[string]string{"authorization": "deny"})
+	if got := rpc.Code(denied.Status.Code); got != rpc.PERMISSION_DENIED {
+		t.Errorf("denied request: expected %v, got %v", rpc.PERMISSION_DENIED, got)
+	}
+	if got := denied.GetDeniedResponse().GetStatus().GetCode(); got != envoy_type.StatusCode_Forbidden {
+		t.Errorf("denied request: expected http status %v, got %v", envoy_type.StatusCode_Forbidden, got)
+	}
+}
+
 func TestLDSFilter(t *testing.T) {
 	rh, cc, done := setup(t)
 	defer done()
@@ -1060,7 +1328,13 @@ func filterchaintls(domains []string, cert, key string, useproxy bool, filters .
 	return fc
 }
 
-func httpfilter(routename string) listener.Filter {
+func httpfilter(routename string, filters ...*envoy_config_v2_http_conn_mgr.HttpFilter) listener.Filter {
+	httpFilters := []*envoy_config_v2_http_conn_mgr.HttpFilter{
+		{Name: "envoy.gzip"},
+		{Name: "envoy.grpc_web"},
+	}
+	httpFilters = append(httpFilters, filters...)
+	httpFilters = append(httpFilters, &envoy_config_v2_http_conn_mgr.HttpFilter{Name: "envoy.router"})
 	return listener.Filter{
 		Name: "envoy.http_connection_manager",
 		Config: messageToStruct(&envoy_config_v2_http_conn_mgr.HttpConnectionManager{
@@ -1089,15 +1363,20 @@ func httpfilter(routename string) listener.Filter {
 				Config: messageToStruct(fileAccessLog("/dev/stdout")),
 			}},
 			UseRemoteAddress: bv(true),
-			HttpFilters: []*envoy_config_v2_http_conn_mgr.HttpFilter{
-				{Name: "envoy.gzip"},
-				{Name: "envoy.grpc_web"},
-				{Name: "envoy.router"},
-			},
+			HttpFilters:      httpFilters,
 		}),
 	}
 }
 
+// structToMessage decodes a Struct into a protobuf Message. Unknown fields
+// are rejected, so the Struct must be valid for the pinned envoy API.
+func structToMessage(t *testing.T, pbs *types.Struct, msg proto.Message) {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	check(t, (&jsonpb.Marshaler{OrigName: true}).Marshal(buf, pbs))
+	check(t, jsonpb.Unmarshal(buf, msg))
+}
+
 // messageToStruct encodes a protobuf Message into a Struct.
 // Hilariously, it uses JSON as the intermediary.
 // author:glen@turbinelabs.io
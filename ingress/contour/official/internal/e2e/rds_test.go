@@ -2175,6 +2175,64 @@ func TestRouteRetryAnnotations(t *testing.T) {
 	}}, nil)
 }
 
+func TestCookieSessionAffinityIngressRoute(t *testing.T) {
+	rh, cc, done := setup(t)
+	defer done()
+
+	rh.OnAdd(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backend",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       80,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	})
+
+	rh.OnAdd(&ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{Fqdn: "sticky.hello.world"},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name:     "backend",
+					Port:     80,
+					Strategy: "Cookie",
+				}},
+			}},
+		},
+	})
+
+	var rc v2.RouteConfiguration
+	check(t, types.UnmarshalAny(&streamRDS(t, cc, "ingress_http").Resources[0], &rc))
+	if len(rc.VirtualHosts) != 1 || len(rc.VirtualHosts[0].Routes) != 1 {
+		t.Fatalf("expected one virtual host with one route, got: %v", rc.VirtualHosts)
+	}
+	hp := rc.VirtualHosts[0].Routes[0].GetRoute().GetHashPolicy()
+	if len(hp) != 1 {
+		t.Fatalf("expected one hash policy, got: %v", hp)
+	}
+
+	// envoy only generates the cookie if its ttl is not zero.
+	ttl := 24 * time.Hour
+	want := &route.RouteAction_HashPolicy_Cookie{
+		Name: "X-Contour-Session-Affinity",
+		Ttl:  &ttl,
+		Path: "/",
+	}
+	if got := hp[0].GetCookie(); !want.Equal(got) {
+		t.Fatalf("expected cookie: %v, got: %v", want, got)
+	}
+}
+
 func assertRDS(t *testing.T, cc *grpc.ClientConn, ingress_http, ingress_https []route.VirtualHost) {
 	t.Helper()
 	assertEqual(t, &v2.DiscoveryResponse{
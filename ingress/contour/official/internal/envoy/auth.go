@@ -13,13 +13,45 @@
 
 package envoy
 
-import "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+import (
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	"github.com/heptio/contour/internal/dag"
+	"k8s.io/api/core/v1"
+)
 
-// UpstreamTLSContext creates an ALPN h2 enabled TLS Context.
-func UpstreamTLSContext() *auth.UpstreamTlsContext {
-	return &auth.UpstreamTlsContext{
+// UpstreamTLSContext creates a TLS Context negotiating alpnProtocols.
+// If uv is not nil the certificate of the upstream is validated, and if
+// clientCert is not nil it is presented to the upstream. Both are inlined
+// into the context, as Envoy 1.7 cannot fetch upstream secrets with SDS.
+func UpstreamTLSContext(uv *dag.UpstreamValidation, clientCert *dag.Secret, alpnProtocols ...string) *auth.UpstreamTlsContext {
+	context := &auth.UpstreamTlsContext{
 		CommonTlsContext: &auth.CommonTlsContext{
-			AlpnProtocols: []string{"h2"},
+			AlpnProtocols: alpnProtocols,
+		},
+	}
+	if uv != nil {
+		context.Sni = uv.SubjectName
+		context.CommonTlsContext.ValidationContextType = &auth.CommonTlsContext_ValidationContext{
+			ValidationContext: &auth.CertificateValidationContext{
+				TrustedCa:            inlinebytes(uv.CACertificate.Data()[dag.CACertificateKey]),
+				VerifySubjectAltName: []string{uv.SubjectName},
+			},
+		}
+	}
+	if clientCert != nil {
+		context.CommonTlsContext.TlsCertificates = []*auth.TlsCertificate{{
+			CertificateChain: inlinebytes(clientCert.Data()[v1.TLSCertKey]),
+			PrivateKey:       inlinebytes(clientCert.Data()[v1.TLSPrivateKeyKey]),
+		}}
+	}
+	return context
+}
+
+func inlinebytes(b []byte) *core.DataSource {
+	return &core.DataSource{
+		Specifier: &core.DataSource_InlineBytes{
+			InlineBytes: b,
 		},
 	}
 }
@@ -52,8 +52,10 @@ func Cluster(service *dag.Service) *v2.Cluster {
 		}
 	}
 	switch service.Protocol {
+	case "tls":
+		cluster.TlsContext = UpstreamTLSContext(service.UpstreamValidation, service.ClientCertificate)
 	case "h2":
-		cluster.TlsContext = UpstreamTLSContext()
+		cluster.TlsContext = UpstreamTLSContext(service.UpstreamValidation, service.ClientCertificate, "h2")
 		fallthrough
 	case "h2c":
 		cluster.Http2ProtocolOptions = &core.Http2ProtocolOptions{}
@@ -80,7 +82,7 @@ func lbPolicy(strategy string) v2.Cluster_LbPolicy {
 	switch strategy {
 	case "WeightedLeastRequest":
 		return v2.Cluster_LEAST_REQUEST
-	case "RingHash":
+	case "RingHash", "Cookie":
 		return v2.Cluster_RING_HASH
 	case "Maglev":
 		return v2.Cluster_MAGLEV
@@ -118,6 +120,9 @@ func Clustername(service *dag.Service) string {
 		}
 		buf += hc.Path
 	}
+	if uv := service.UpstreamValidation; uv != nil {
+		buf += uv.CACertificate.Namespace() + "/" + uv.CACertificate.Name() + uv.SubjectName
+	}
 
 	hash := sha1.Sum([]byte(buf))
 	ns := service.Namespace()
@@ -101,10 +101,29 @@ func TestCluster(t *testing.T) {
 				ConnectTimeout:       250 * time.Millisecond,
 				LbPolicy:             v2.Cluster_ROUND_ROBIN,
 				Http2ProtocolOptions: &core.Http2ProtocolOptions{},
-				TlsContext:           UpstreamTLSContext(),
+				TlsContext:           UpstreamTLSContext(nil, nil, "h2"),
 				CommonLbConfig:       clusterCommonLBConfig(),
 			},
 		},
+		"tls upstream": {
+			service: &dag.Service{
+				Object:      s1,
+				ServicePort: &s1.Spec.Ports[0],
+				Protocol:    "tls",
+			},
+			want: &v2.Cluster{
+				Name: "default/kuard/443/da39a3ee5e",
+				Type: v2.Cluster_EDS,
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				ConnectTimeout: 250 * time.Millisecond,
+				LbPolicy:       v2.Cluster_ROUND_ROBIN,
+				TlsContext:     UpstreamTLSContext(nil, nil),
+				CommonLbConfig: clusterCommonLBConfig(),
+			},
+		},
 		"contour.heptio.com/max-connections": {
 			service: &dag.Service{
 				Object:         s1,
@@ -274,6 +293,7 @@ func TestLBPolicy(t *testing.T) {
 	tests := map[string]v2.Cluster_LbPolicy{
 		"WeightedLeastRequest": v2.Cluster_LEAST_REQUEST,
 		"RingHash":             v2.Cluster_RING_HASH,
+		"Cookie":               v2.Cluster_RING_HASH,
 		"Maglev":               v2.Cluster_MAGLEV,
 		"Random":               v2.Cluster_RANDOM,
 		"":                     v2.Cluster_ROUND_ROBIN,
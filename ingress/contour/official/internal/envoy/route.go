@@ -14,7 +14,11 @@
 package envoy
 
 import (
+	"regexp"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
@@ -22,12 +26,62 @@ import (
 	"github.com/heptio/contour/internal/dag"
 )
 
+// RouteMatch returns a route.RouteMatch for the path, header and
+// query parameter conditions of the supplied route.
+func RouteMatch(r *dag.Route) route.RouteMatch {
+	var rm route.RouteMatch
+	switch {
+	case r.Path != "":
+		rm.PathSpecifier = &route.RouteMatch_Path{
+			Path: r.Path,
+		}
+	case r.Regex != "":
+		rm.PathSpecifier = &route.RouteMatch_Regex{
+			Regex: r.Regex,
+		}
+	default:
+		rm.PathSpecifier = &route.RouteMatch_Prefix{
+			Prefix: r.Prefix,
+		}
+	}
+	for _, hc := range r.HeaderConditions {
+		hm := &route.HeaderMatcher{
+			Name: hc.Name,
+		}
+		switch hc.MatchType {
+		case dag.HeaderMatchTypeExact:
+			hm.HeaderMatchSpecifier = &route.HeaderMatcher_ExactMatch{
+				ExactMatch: hc.Value,
+			}
+		case dag.HeaderMatchTypeContains:
+			hm.HeaderMatchSpecifier = &route.HeaderMatcher_RegexMatch{
+				RegexMatch: ".*" + regexp.QuoteMeta(hc.Value) + ".*",
+			}
+		default:
+			hm.HeaderMatchSpecifier = &route.HeaderMatcher_PresentMatch{
+				PresentMatch: true,
+			}
+		}
+		rm.Headers = append(rm.Headers, hm)
+	}
+	for _, qc := range r.QueryParameterConditions {
+		rm.QueryParameters = append(rm.QueryParameters, &route.QueryParameterMatcher{
+			Name:  qc.Name,
+			Value: qc.Value,
+		})
+	}
+	return rm
+}
+
 // RouteRoute returns a route.Route_Route for the services supplied.
 // If len(services) is greater than one, the route's action will be a
 // weighted cluster.
+// If the only service manages headers, the route's action will also
+// be a weighted cluster, as only weighted clusters carry per service
+// headers.
 func RouteRoute(services []*dag.Service) route.Route_Route {
-	switch len(services) {
-	case 1:
+	switch {
+	case len(services) == 1 && !managesHeaders(services[0]):
 		return RouteCluster(services[0])
 	default:
 		return route.Route_Route{
@@ -40,6 +94,56 @@ func RouteRoute(services []*dag.Service) route.Route_Route {
 	}
 }
 
+// SessionAffinityCookie is the name of the cookie Envoy generates to
+// route the requests of a client to the same endpoint of services
+// using the Cookie load balancing strategy.
+const SessionAffinityCookie = "X-Contour-Session-Affinity"
+
+// SessionAffinityCookieTTL is the lifetime of the session affinity cookie.
+// Envoy only generates the cookie if its TTL is not zero.
+const SessionAffinityCookieTTL = 24 * time.Hour
+
+// HashPolicy returns the hash policy of a route to services. If any
+// service uses the Cookie strategy, requests are hashed on a session
+// cookie generated by Envoy.
+func HashPolicy(services []*dag.Service) []*route.RouteAction_HashPolicy {
+	for _, svc := range services {
+		if svc.LoadBalancerStrategy != "Cookie" {
+			continue
+		}
+		ttl := SessionAffinityCookieTTL
+		return []*route.RouteAction_HashPolicy{{
+			PolicySpecifier: &route.RouteAction_HashPolicy_Cookie_{
+				Cookie: &route.RouteAction_HashPolicy_Cookie{
+					Name: SessionAffinityCookie,
+					Ttl:  &ttl,
+					Path: "/",
+				},
+			},
+		}}
+	}
+	return nil
+}
+
+// CORSPolicy returns the route.CorsPolicy of a virtual host.
+// CORSPolicy returns nil if policy is nil.
+func CORSPolicy(policy *dag.CORSPolicy) *route.CorsPolicy {
+	if policy == nil {
+		return nil
+	}
+	cp := &route.CorsPolicy{
+		AllowOrigin:      policy.AllowOrigin,
+		AllowMethods:     strings.Join(policy.AllowMethods, ","),
+		AllowHeaders:     strings.Join(policy.AllowHeaders, ","),
+		ExposeHeaders:    strings.Join(policy.ExposeHeaders, ","),
+		AllowCredentials: bv(policy.AllowCredentials),
+	}
+	if policy.MaxAge > 0 {
+		cp.MaxAge = strconv.Itoa(int(policy.MaxAge.Seconds()))
+	}
+	return cp
+}
+
 // RouteCluster returns a route.Route_Route for a single service.
 func RouteCluster(service *dag.Service) route.Route_Route {
 	return route.Route_Route{
@@ -63,9 +167,12 @@ func WeightedClusters(services []*dag.Service) *route.WeightedCluster {
 		wc.Clusters = append(wc.Clusters, &route.WeightedCluster_ClusterWeight{
 			Name:   Clustername(svc),
 			Weight: u32(svc.Weight),
-			RequestHeadersToAdd: headers(
+			RequestHeadersToAdd: append(headers(
 				appendHeader("x-request-start", "t=%START_TIME(%s.%3f)%"),
-			),
+			), HeadersToAdd(svc.RequestHeadersPolicy)...),
+			RequestHeadersToRemove:  HeadersToRemove(svc.RequestHeadersPolicy),
+			ResponseHeadersToAdd:    HeadersToAdd(svc.ResponseHeadersPolicy),
+			ResponseHeadersToRemove: HeadersToRemove(svc.ResponseHeadersPolicy),
 		})
 	}
 	// Check if no weights were defined, if not default to even distribution
@@ -93,6 +200,32 @@ func (c clusterWeightByName) Less(i, j int) bool {
 
 }
 
+// HeadersToAdd returns the headers set by policy, replacing
+// any existing values. If policy is nil, nil is returned.
+func HeadersToAdd(policy *dag.HeadersPolicy) []*core.HeaderValueOption {
+	if policy == nil {
+		return nil
+	}
+	var hvo []*core.HeaderValueOption
+	for _, h := range policy.Set {
+		hvo = append(hvo, setHeader(h.Name, h.Value))
+	}
+	return hvo
+}
+
+// HeadersToRemove returns the headers removed by policy.
+// If policy is nil, nil is returned.
+func HeadersToRemove(policy *dag.HeadersPolicy) []string {
+	if policy == nil {
+		return nil
+	}
+	return policy.Remove
+}
+
+func managesHeaders(service *dag.Service) bool {
+	return service.RequestHeadersPolicy != nil || service.ResponseHeadersPolicy != nil
+}
+
 func headers(first *core.HeaderValueOption, rest ...*core.HeaderValueOption) []*core.HeaderValueOption {
 	return append([]*core.HeaderValueOption{first}, rest...)
 }
@@ -107,5 +240,15 @@ func appendHeader(key, value string) *core.HeaderValueOption {
 	}
 }
 
+func setHeader(key, value string) *core.HeaderValueOption {
+	return &core.HeaderValueOption{
+		Header: &core.HeaderValue{
+			Key:   key,
+			Value: value,
+		},
+		Append: bv(false),
+	}
+}
+
 func u32(val int) *types.UInt32Value { return &types.UInt32Value{Value: uint32(val)} }
 func bv(val bool) *types.BoolValue   { return &types.BoolValue{Value: val} }
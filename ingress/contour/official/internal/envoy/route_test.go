@@ -15,14 +15,115 @@ package envoy
 
 import (
 	"testing"
+	"time"
 
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	"github.com/gogo/protobuf/types"
 	"github.com/google/go-cmp/cmp"
 	"github.com/heptio/contour/internal/dag"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+func TestRouteMatch(t *testing.T) {
+	tests := map[string]struct {
+		route *dag.Route
+		want  route.RouteMatch
+	}{
+		"prefix": {
+			route: &dag.Route{
+				Prefix: "/foo",
+			},
+			want: route.RouteMatch{
+				PathSpecifier: &route.RouteMatch_Prefix{
+					Prefix: "/foo",
+				},
+			},
+		},
+		"exact path": {
+			route: &dag.Route{
+				Prefix: "/foo",
+				Path:   "/foo/bar",
+			},
+			want: route.RouteMatch{
+				PathSpecifier: &route.RouteMatch_Path{
+					Path: "/foo/bar",
+				},
+			},
+		},
+		"regex": {
+			route: &dag.Route{
+				Prefix: "/foo",
+				Regex:  "/foo/[0-9]+",
+			},
+			want: route.RouteMatch{
+				PathSpecifier: &route.RouteMatch_Regex{
+					Regex: "/foo/[0-9]+",
+				},
+			},
+		},
+		"header and query parameter conditions": {
+			route: &dag.Route{
+				Prefix: "/",
+				HeaderConditions: []dag.HeaderCondition{{
+					Name:      "x-present",
+					MatchType: dag.HeaderMatchTypePresent,
+				}, {
+					Name:      "x-exact",
+					MatchType: dag.HeaderMatchTypeExact,
+					Value:     "abc",
+				}, {
+					Name:      "user-agent",
+					MatchType: dag.HeaderMatchTypeContains,
+					Value:     "Firefox/6.0",
+				}},
+				QueryParameterConditions: []dag.QueryParameterCondition{{
+					Name: "debug",
+				}, {
+					Name:  "page",
+					Value: "1",
+				}},
+			},
+			want: route.RouteMatch{
+				PathSpecifier: &route.RouteMatch_Prefix{
+					Prefix: "/",
+				},
+				Headers: []*route.HeaderMatcher{{
+					Name: "x-present",
+					HeaderMatchSpecifier: &route.HeaderMatcher_PresentMatch{
+						PresentMatch: true,
+					},
+				}, {
+					Name: "x-exact",
+					HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{
+						ExactMatch: "abc",
+					},
+				}, {
+					Name: "user-agent",
+					HeaderMatchSpecifier: &route.HeaderMatcher_RegexMatch{
+						RegexMatch: `.*Firefox/6\.0.*`,
+					},
+				}},
+				QueryParameters: []*route.QueryParameterMatcher{{
+					Name: "debug",
+				}, {
+					Name:  "page",
+					Value: "1",
+				}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := RouteMatch(tc.route)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
 func TestWeightedClusters(t *testing.T) {
 	tests := map[string]struct {
 		services []*dag.Service
@@ -151,6 +252,39 @@ func TestWeightedClusters(t *testing.T) {
 				TotalWeight: u32(100),
 			},
 		},
+		"service with headers policies": {
+			services: []*dag.Service{{
+				Object: &v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+				},
+				ServicePort: &v1.ServicePort{
+					Port: 8080,
+				},
+				RequestHeadersPolicy: &dag.HeadersPolicy{
+					Set:    []dag.HeaderValue{{Name: "x-forwarded-prefix", Value: "/kuard"}},
+					Remove: []string{"x-debug"},
+				},
+				ResponseHeadersPolicy: &dag.HeadersPolicy{
+					Remove: []string{"x-powered-by"},
+				},
+			}},
+			want: &route.WeightedCluster{
+				Clusters: []*route.WeightedCluster_ClusterWeight{{
+					Name:   "default/kuard/8080/da39a3ee5e",
+					Weight: u32(1),
+					RequestHeadersToAdd: headers(
+						appendHeader("x-request-start", "t=%START_TIME(%s.%3f)%"),
+						setHeader("x-forwarded-prefix", "/kuard"),
+					),
+					RequestHeadersToRemove:  []string{"x-debug"},
+					ResponseHeadersToRemove: []string{"x-powered-by"},
+				}},
+				TotalWeight: u32(1),
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -162,3 +296,158 @@ func TestWeightedClusters(t *testing.T) {
 		})
 	}
 }
+
+func TestRouteRoute(t *testing.T) {
+	kuard := &dag.Service{
+		Object: &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+		},
+		ServicePort: &v1.ServicePort{
+			Port: 8080,
+		},
+	}
+	headers := *kuard
+	headers.ResponseHeadersPolicy = &dag.HeadersPolicy{
+		Remove: []string{"server"},
+	}
+
+	tests := map[string]struct {
+		services []*dag.Service
+		want     route.Route_Route
+	}{
+		"single service": {
+			services: []*dag.Service{kuard},
+			want:     RouteCluster(kuard),
+		},
+		"single service with headers policy": {
+			services: []*dag.Service{&headers},
+			want: route.Route_Route{
+				Route: &route.RouteAction{
+					ClusterSpecifier: &route.RouteAction_WeightedClusters{
+						WeightedClusters: WeightedClusters([]*dag.Service{&headers}),
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := RouteRoute(tc.services)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestHashPolicy(t *testing.T) {
+	service := func(strategy string) *dag.Service {
+		return &dag.Service{
+			Object: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kuard",
+					Namespace: "default",
+				},
+			},
+			ServicePort: &v1.ServicePort{
+				Port: 8080,
+			},
+			LoadBalancerStrategy: strategy,
+		}
+	}
+	ttl := 24 * time.Hour
+	cookie := []*route.RouteAction_HashPolicy{{
+		PolicySpecifier: &route.RouteAction_HashPolicy_Cookie_{
+			Cookie: &route.RouteAction_HashPolicy_Cookie{
+				Name: "X-Contour-Session-Affinity",
+				Ttl:  &ttl,
+				Path: "/",
+			},
+		},
+	}}
+
+	tests := map[string]struct {
+		services []*dag.Service
+		want     []*route.RouteAction_HashPolicy
+	}{
+		"round robin": {
+			services: []*dag.Service{service("")},
+			want:     nil,
+		},
+		"ring hash": {
+			services: []*dag.Service{service("RingHash")},
+			want:     nil,
+		},
+		"cookie": {
+			services: []*dag.Service{service("Cookie")},
+			want:     cookie,
+		},
+		"cookie and round robin": {
+			services: []*dag.Service{service(""), service("Cookie")},
+			want:     cookie,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := HashPolicy(tc.services)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestCORSPolicy(t *testing.T) {
+	tests := map[string]struct {
+		policy *dag.CORSPolicy
+		want   *route.CorsPolicy
+	}{
+		"nil": {
+			policy: nil,
+			want:   nil,
+		},
+		"origins and methods": {
+			policy: &dag.CORSPolicy{
+				AllowOrigin:  []string{"*"},
+				AllowMethods: []string{"GET", "POST"},
+			},
+			want: &route.CorsPolicy{
+				AllowOrigin:      []string{"*"},
+				AllowMethods:     "GET,POST",
+				AllowCredentials: &types.BoolValue{Value: false},
+			},
+		},
+		"full policy": {
+			policy: &dag.CORSPolicy{
+				AllowOrigin:      []string{"https://example.com", "https://www.example.com"},
+				AllowMethods:     []string{"GET"},
+				AllowHeaders:     []string{"authorization", "cache-control"},
+				ExposeHeaders:    []string{"x-request-id"},
+				AllowCredentials: true,
+				MaxAge:           10 * time.Minute,
+			},
+			want: &route.CorsPolicy{
+				AllowOrigin:      []string{"https://example.com", "https://www.example.com"},
+				AllowMethods:     "GET",
+				AllowHeaders:     "authorization,cache-control",
+				ExposeHeaders:    "x-request-id",
+				AllowCredentials: &types.BoolValue{Value: true},
+				MaxAge:           "600",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := CORSPolicy(tc.policy)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"encoding/json"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	gatewayv1 "github.com/heptio/contour/apis/gateway/v1alpha1"
+	clientset "github.com/heptio/contour/apis/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// GatewayStatus allows for updating the status subresource of Gateway API objects.
+type GatewayStatus struct {
+	Client clientset.Interface
+}
+
+// SetGatewayClassStatus sets the conditions of a GatewayClass.
+func (gs *GatewayStatus) SetGatewayClassStatus(conditions []gatewayv1.Condition, existing *gatewayv1.GatewayClass) error {
+	now := metav1.Now()
+	status := gatewayv1.GatewayClassStatus{
+		Conditions: mergeConditions(conditions, existing.Status.Conditions, now),
+	}
+	if reflect.DeepEqual(status, existing.Status) {
+		return nil
+	}
+	updated := existing.DeepCopy()
+	updated.Status = status
+	patch, err := statusPatch(existing, updated)
+	if err != nil {
+		return err
+	}
+	_, err = gs.Client.GatewayV1alpha1().GatewayClasses().Patch(existing.GetName(), types.MergePatchType, patch, "status")
+	return err
+}
+
+// SetGatewayStatus sets the conditions of a Gateway and its listeners.
+func (gs *GatewayStatus) SetGatewayStatus(conditions []gatewayv1.Condition, listeners []gatewayv1.ListenerStatus, existing *gatewayv1.Gateway) error {
+	now := metav1.Now()
+	status := gatewayv1.GatewayStatus{
+		Conditions: mergeConditions(conditions, existing.Status.Conditions, now),
+	}
+	for _, l := range listeners {
+		var prev []gatewayv1.Condition
+		for _, e := range existing.Status.Listeners {
+			if e.Port == l.Port && e.Protocol == l.Protocol && reflect.DeepEqual(e.Hostname, l.Hostname) {
+				prev = e.Conditions
+				break
+			}
+		}
+		l.Conditions = mergeConditions(l.Conditions, prev, now)
+		status.Listeners = append(status.Listeners, l)
+	}
+	if reflect.DeepEqual(status, existing.Status) {
+		return nil
+	}
+	updated := existing.DeepCopy()
+	updated.Status = status
+	patch, err := statusPatch(existing, updated)
+	if err != nil {
+		return err
+	}
+	_, err = gs.Client.GatewayV1alpha1().Gateways(existing.GetNamespace()).Patch(existing.GetName(), types.MergePatchType, patch, "status")
+	return err
+}
+
+// SetHTTPRouteStatus sets the conditions of an HTTPRoute for each Gateway which binds it.
+func (gs *GatewayStatus) SetHTTPRouteStatus(gateways []gatewayv1.RouteGatewayStatus, existing *gatewayv1.HTTPRoute) error {
+	status := routeStatus(gateways, existing.Status, metav1.Now())
+	if reflect.DeepEqual(status, existing.Status) {
+		return nil
+	}
+	updated := existing.DeepCopy()
+	updated.Status = status
+	patch, err := statusPatch(existing, updated)
+	if err != nil {
+		return err
+	}
+	_, err = gs.Client.GatewayV1alpha1().HTTPRoutes(existing.GetNamespace()).Patch(existing.GetName(), types.MergePatchType, patch, "status")
+	return err
+}
+
+// SetTLSRouteStatus sets the conditions of a TLSRoute for each Gateway which binds it.
+func (gs *GatewayStatus) SetTLSRouteStatus(gateways []gatewayv1.RouteGatewayStatus, existing *gatewayv1.TLSRoute) error {
+	status := routeStatus(gateways, existing.Status, metav1.Now())
+	if reflect.DeepEqual(status, existing.Status) {
+		return nil
+	}
+	updated := existing.DeepCopy()
+	updated.Status = status
+	patch, err := statusPatch(existing, updated)
+	if err != nil {
+		return err
+	}
+	_, err = gs.Client.GatewayV1alpha1().TLSRoutes(existing.GetNamespace()).Patch(existing.GetName(), types.MergePatchType, patch, "status")
+	return err
+}
+
+// routeStatus returns the status of a route bound by gateways.
+func routeStatus(gateways []gatewayv1.RouteGatewayStatus, existing gatewayv1.RouteStatus, now metav1.Time) gatewayv1.RouteStatus {
+	var status gatewayv1.RouteStatus
+	for _, g := range gateways {
+		var prev []gatewayv1.Condition
+		for _, e := range existing.Gateways {
+			if e.GatewayRef == g.GatewayRef {
+				prev = e.Conditions
+				break
+			}
+		}
+		g.Conditions = mergeConditions(g.Conditions, prev, now)
+		status.Gateways = append(status.Gateways, g)
+	}
+	return status
+}
+
+// mergeConditions returns conditions with the LastTransitionTime of the
+// existing condition of the same type if its status is unchanged, or now.
+func mergeConditions(conditions, existing []gatewayv1.Condition, now metav1.Time) []gatewayv1.Condition {
+	var merged []gatewayv1.Condition
+	for _, c := range conditions {
+		c.LastTransitionTime = now
+		for _, e := range existing {
+			if e.Type == c.Type && e.Status == c.Status {
+				c.LastTransitionTime = e.LastTransitionTime
+				break
+			}
+		}
+		merged = append(merged, c)
+	}
+	return merged
+}
+
+// statusPatch returns the merge patch from existing to updated.
+func statusPatch(existing, updated metav1.Object) ([]byte, error) {
+	existingBytes, err := json.Marshal(existing)
+	if err != nil {
+		return nil, err
+	}
+	// as with IngressRoutes the patch must carry the resource version
+	// of the existing object.
+	updated.SetResourceVersion(existing.GetResourceVersion())
+	updatedBytes, err := json.Marshal(updated)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.CreateMergePatch(existingBytes, updatedBytes)
+}
@@ -0,0 +1,112 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	gatewayv1 "github.com/heptio/contour/apis/gateway/v1alpha1"
+	"github.com/heptio/contour/apis/generated/clientset/versioned/fake"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestSetGatewayClassStatus(t *testing.T) {
+	then := metav1.NewTime(time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC))
+	admitted := gatewayv1.Condition{
+		Type:    gatewayv1.ConditionAdmitted,
+		Status:  v1.ConditionTrue,
+		Reason:  "Admitted",
+		Message: "GatewayClass is admitted by Contour",
+	}
+	withTime := func(c gatewayv1.Condition, t metav1.Time) gatewayv1.Condition {
+		c.LastTransitionTime = t
+		return c
+	}
+
+	tests := map[string]struct {
+		conditions    []gatewayv1.Condition
+		existing      []gatewayv1.Condition
+		expectedPatch string
+		expectedVerbs []string
+	}{
+		"new condition": {
+			conditions:    []gatewayv1.Condition{admitted},
+			expectedPatch: `{"status":{"conditions":[{"lastTransitionTime":`,
+			expectedVerbs: []string{"patch"},
+		},
+		"unchanged condition": {
+			conditions:    []gatewayv1.Condition{admitted},
+			existing:      []gatewayv1.Condition{withTime(admitted, then)},
+			expectedVerbs: []string{},
+		},
+		"changed message keeps transition time": {
+			conditions: []gatewayv1.Condition{admitted},
+			existing: []gatewayv1.Condition{withTime(gatewayv1.Condition{
+				Type:    gatewayv1.ConditionAdmitted,
+				Status:  v1.ConditionTrue,
+				Reason:  "Admitted",
+				Message: "admitted",
+			}, then)},
+			expectedPatch: `{"status":{"conditions":[{"lastTransitionTime":"2018-10-01T00:00:00Z","message":"GatewayClass is admitted by Contour",`,
+			expectedVerbs: []string{"patch"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			existing := &gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "contour",
+				},
+				Status: gatewayv1.GatewayClassStatus{
+					Conditions: tc.existing,
+				},
+			}
+			var gotPatchBytes []byte
+			client := fake.NewSimpleClientset(existing)
+			client.PrependReactor("patch", "gatewayclasses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				switch patchAction := action.(type) {
+				default:
+					return true, nil, fmt.Errorf("got unexpected action of type: %T", action)
+				case k8stesting.PatchActionImpl:
+					if patchAction.GetSubresource() != "status" {
+						return true, nil, fmt.Errorf("expected patch of status subresource, got %q", patchAction.GetSubresource())
+					}
+					gotPatchBytes = patchAction.GetPatch()
+					return true, existing, nil
+				}
+			})
+			gs := GatewayStatus{
+				Client: client,
+			}
+			if err := gs.SetGatewayClassStatus(tc.conditions, existing); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(client.Actions()) != len(tc.expectedVerbs) {
+				t.Fatalf("Expected verbs mismatch: want: %d, got: %d", len(tc.expectedVerbs), len(client.Actions()))
+			}
+
+			if !strings.HasPrefix(string(gotPatchBytes), tc.expectedPatch) {
+				t.Fatalf("expected patch: %s, got: %s", tc.expectedPatch, string(gotPatchBytes))
+			}
+		})
+	}
+}
@@ -15,10 +15,12 @@ package k8s
 
 import (
 	"encoding/json"
+	"reflect"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	ingressroutev1 "github.com/heptio/contour/apis/contour/v1beta1"
 	clientset "github.com/heptio/contour/apis/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -28,17 +30,37 @@ type IngressRouteStatus struct {
 }
 
 // SetStatus sets the IngressRoute status field to an Valid or Invalid status
-func (irs *IngressRouteStatus) SetStatus(status, desc string, existing *ingressroutev1.IngressRoute) error {
-	// Check if update needed by comparing status & desc
-	if existing.CurrentStatus != status || existing.Description != desc {
-		updated := existing.DeepCopy()
-		updated.Status = ingressroutev1.Status{
-			CurrentStatus: status,
-			Description:   desc,
+// along with the conditions which led to it.
+func (irs *IngressRouteStatus) SetStatus(status, desc string, conditions []ingressroutev1.Condition, existing *ingressroutev1.IngressRoute) error {
+	updated := ingressroutev1.Status{
+		CurrentStatus: status,
+		Description:   desc,
+		Conditions:    mergeIngressRouteConditions(conditions, existing.Status.Conditions, metav1.Now()),
+	}
+	// Check if update needed by comparing status, desc & conditions
+	if reflect.DeepEqual(updated, existing.Status) {
+		return nil
+	}
+	ir := existing.DeepCopy()
+	ir.Status = updated
+	return irs.setStatus(existing, ir)
+}
+
+// mergeIngressRouteConditions stamps each of conditions with now, unless an
+// identical condition already exists, in which case its transition time is kept.
+func mergeIngressRouteConditions(conditions, existing []ingressroutev1.Condition, now metav1.Time) []ingressroutev1.Condition {
+	var merged []ingressroutev1.Condition
+	for _, c := range conditions {
+		c.LastTransitionTime = now
+		for _, e := range existing {
+			if e.Status == c.Status && e.Reason == c.Reason && e.Message == c.Message && e.VirtualHost == c.VirtualHost {
+				c.LastTransitionTime = e.LastTransitionTime
+				break
+			}
 		}
-		return irs.setStatus(existing, updated)
+		merged = append(merged, c)
 	}
-	return nil
+	return merged
 }
 
 func (irs *IngressRouteStatus) setStatus(existing, updated *ingressroutev1.IngressRoute) error {
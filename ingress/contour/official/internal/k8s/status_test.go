@@ -28,6 +28,7 @@ func TestSetStatus(t *testing.T) {
 	tests := map[string]struct {
 		msg           string
 		desc          string
+		conditions    []ingressroutev1beta1.Condition
 		existing      *ingressroutev1beta1.IngressRoute
 		expectedPatch string
 		expectedVerbs []string
@@ -80,6 +81,70 @@ func TestSetStatus(t *testing.T) {
 			expectedPatch: `{"status":{"currentStatus":"valid","description":"this is a valid IR"}}`,
 			expectedVerbs: []string{"patch"},
 		},
+		"no update with conditions": {
+			msg:  "valid",
+			desc: "this is a valid IR",
+			conditions: []ingressroutev1beta1.Condition{{
+				Status:      "valid",
+				Reason:      "Valid",
+				Message:     "this is a valid IR",
+				VirtualHost: "example.com",
+			}},
+			existing: &ingressroutev1beta1.IngressRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Status: ingressroutev1beta1.Status{
+					CurrentStatus: "valid",
+					Description:   "this is a valid IR",
+					Conditions: []ingressroutev1beta1.Condition{{
+						Status:             "valid",
+						Reason:             "Valid",
+						Message:            "this is a valid IR",
+						VirtualHost:        "example.com",
+						LastTransitionTime: metav1.Unix(1000, 0),
+					}},
+				},
+			},
+			expectedPatch: ``,
+			expectedVerbs: []string{},
+		},
+		"drop resolved condition": {
+			msg:  "valid",
+			desc: "this is a valid IR",
+			conditions: []ingressroutev1beta1.Condition{{
+				Status:      "valid",
+				Reason:      "Valid",
+				Message:     "this is a valid IR",
+				VirtualHost: "example.com",
+			}},
+			existing: &ingressroutev1beta1.IngressRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Status: ingressroutev1beta1.Status{
+					CurrentStatus: "invalid",
+					Description:   "route creates a delegation cycle",
+					Conditions: []ingressroutev1beta1.Condition{{
+						Status:             "valid",
+						Reason:             "Valid",
+						Message:            "this is a valid IR",
+						VirtualHost:        "example.com",
+						LastTransitionTime: metav1.Unix(1000, 0),
+					}, {
+						Status:             "invalid",
+						Reason:             "DelegationCycle",
+						Message:            "route creates a delegation cycle",
+						VirtualHost:        "example.org",
+						LastTransitionTime: metav1.Unix(1000, 0),
+					}},
+				},
+			},
+			expectedPatch: `{"status":{"conditions":[{"lastTransitionTime":"1970-01-01T00:16:40Z","message":"this is a valid IR","reason":"Valid","status":"valid","virtualhost":"example.com"}],"currentStatus":"valid","description":"this is a valid IR"}}`,
+			expectedVerbs: []string{"patch"},
+		},
 	}
 
 	for name, tc := range tests {
@@ -98,7 +163,7 @@ func TestSetStatus(t *testing.T) {
 			irs := IngressRouteStatus{
 				Client: client,
 			}
-			if err := irs.SetStatus(tc.msg, tc.desc, tc.existing); err != nil {
+			if err := irs.SetStatus(tc.msg, tc.desc, tc.conditions, tc.existing); err != nil {
 				t.Fatal(err)
 			}
 
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	ingressroutev1 "github.com/heptio/contour/apis/contour/v1beta1"
+	gatewayv1 "github.com/heptio/contour/apis/gateway/v1alpha1"
 	clientset "github.com/heptio/contour/apis/generated/clientset/versioned"
 	"github.com/heptio/workgroup"
 	"github.com/sirupsen/logrus"
@@ -55,6 +56,31 @@ func WatchIngressRoutes(g *workgroup.Group, client *clientset.Clientset, log log
 	watch(g, client.ContourV1beta1().RESTClient(), log, ingressroutev1.ResourcePlural, new(ingressroutev1.IngressRoute), rs...)
 }
 
+// WatchNamespaces creates a SharedInformer for v1.Namespaces and registers it with g.
+func WatchNamespaces(g *workgroup.Group, client *kubernetes.Clientset, log logrus.FieldLogger, rs ...cache.ResourceEventHandler) {
+	watch(g, client.CoreV1().RESTClient(), log, "namespaces", new(v1.Namespace), rs...)
+}
+
+// WatchGatewayClasses creates a SharedInformer for networking.x-k8s.io/v1alpha1.GatewayClasses and registers it with g.
+func WatchGatewayClasses(g *workgroup.Group, client *clientset.Clientset, log logrus.FieldLogger, rs ...cache.ResourceEventHandler) {
+	watch(g, client.GatewayV1alpha1().RESTClient(), log, "gatewayclasses", new(gatewayv1.GatewayClass), rs...)
+}
+
+// WatchGateways creates a SharedInformer for networking.x-k8s.io/v1alpha1.Gateways and registers it with g.
+func WatchGateways(g *workgroup.Group, client *clientset.Clientset, log logrus.FieldLogger, rs ...cache.ResourceEventHandler) {
+	watch(g, client.GatewayV1alpha1().RESTClient(), log, "gateways", new(gatewayv1.Gateway), rs...)
+}
+
+// WatchHTTPRoutes creates a SharedInformer for networking.x-k8s.io/v1alpha1.HTTPRoutes and registers it with g.
+func WatchHTTPRoutes(g *workgroup.Group, client *clientset.Clientset, log logrus.FieldLogger, rs ...cache.ResourceEventHandler) {
+	watch(g, client.GatewayV1alpha1().RESTClient(), log, "httproutes", new(gatewayv1.HTTPRoute), rs...)
+}
+
+// WatchTLSRoutes creates a SharedInformer for networking.x-k8s.io/v1alpha1.TLSRoutes and registers it with g.
+func WatchTLSRoutes(g *workgroup.Group, client *clientset.Clientset, log logrus.FieldLogger, rs ...cache.ResourceEventHandler) {
+	watch(g, client.GatewayV1alpha1().RESTClient(), log, "tlsroutes", new(gatewayv1.TLSRoute), rs...)
+}
+
 func watch(g *workgroup.Group, c cache.Getter, log logrus.FieldLogger, resource string, objType runtime.Object, rs ...cache.ResourceEventHandler) {
 	lw := cache.NewListWatchFromClient(c, resource, v1.NamespaceAll, fields.Everything())
 	sw := cache.NewSharedInformer(lw, objType, time.Duration(0)) // resync timer disabled
@@ -29,6 +29,7 @@ type Metrics struct {
 	ingressRouteInvalidGauge    *prometheus.GaugeVec
 	ingressRouteValidGauge      *prometheus.GaugeVec
 	ingressRouteOrphanedGauge   *prometheus.GaugeVec
+	ingressRouteConditionGauge  *prometheus.GaugeVec
 	ingressRouteDAGRebuildGauge *prometheus.GaugeVec
 
 	CacheHandlerOnUpdateSummary prometheus.Summary
@@ -45,6 +46,10 @@ type IngressRouteMetric struct {
 	Invalid  map[Meta]int
 	Orphaned map[Meta]int
 	Root     map[Meta]int
+
+	// Conditions counts IngressRoutes by their aggregate status
+	// and the reasons which contributed to it.
+	Conditions map[ConditionMeta]int
 }
 
 // Meta holds the vhost and namespace of a metric object
@@ -52,12 +57,18 @@ type Meta struct {
 	VHost, Namespace string
 }
 
+// ConditionMeta holds the namespace, status and reason of a condition metric
+type ConditionMeta struct {
+	Namespace, Status, Reason string
+}
+
 const (
 	IngressRouteTotalGauge      = "contour_ingressroute_total"
 	IngressRouteRootTotalGauge  = "contour_ingressroute_root_total"
 	IngressRouteInvalidGauge    = "contour_ingressroute_invalid_total"
 	IngressRouteValidGauge      = "contour_ingressroute_valid_total"
 	IngressRouteOrphanedGauge   = "contour_ingressroute_orphaned_total"
+	IngressRouteConditionGauge  = "contour_ingressroute_condition_total"
 	IngressRouteDAGRebuildGauge = "contour_ingressroute_dagrebuild_timestamp"
 
 	cacheHandlerOnUpdateSummary = "contour_cachehandler_onupdate_duration_seconds"
@@ -104,6 +115,13 @@ func NewMetrics(registry *prometheus.Registry) *Metrics {
 			},
 			[]string{"namespace"},
 		),
+		ingressRouteConditionGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: IngressRouteConditionGauge,
+				Help: "Total number of IngressRoutes by status and reason",
+			},
+			[]string{"namespace", "status", "reason"},
+		),
 		ingressRouteDAGRebuildGauge: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: IngressRouteDAGRebuildGauge,
@@ -136,6 +154,7 @@ func (m *Metrics) register(registry *prometheus.Registry) {
 		m.ingressRouteInvalidGauge,
 		m.ingressRouteValidGauge,
 		m.ingressRouteOrphanedGauge,
+		m.ingressRouteConditionGauge,
 		m.ingressRouteDAGRebuildGauge,
 		m.CacheHandlerOnUpdateSummary,
 		m.ResourceEventHandlerSummary,
@@ -180,6 +199,12 @@ func (m *Metrics) SetIngressRouteMetric(metrics IngressRouteMetric) {
 			delete(m.metricCache.Root, meta)
 		}
 	}
+	for meta, value := range metrics.Conditions {
+		m.ingressRouteConditionGauge.WithLabelValues(meta.Namespace, meta.Status, meta.Reason).Set(float64(value))
+		if _, ok := m.metricCache.Conditions[meta]; ok {
+			delete(m.metricCache.Conditions, meta)
+		}
+	}
 
 	// All metrics processed, now remove what's left as they are not needed
 	for meta := range m.metricCache.Total {
@@ -197,14 +222,18 @@ func (m *Metrics) SetIngressRouteMetric(metrics IngressRouteMetric) {
 	for meta := range m.metricCache.Root {
 		m.ingressRouteRootTotalGauge.DeleteLabelValues(meta.Namespace)
 	}
+	for meta := range m.metricCache.Conditions {
+		m.ingressRouteConditionGauge.DeleteLabelValues(meta.Namespace, meta.Status, meta.Reason)
+	}
 
 	// copier.Copy(&m.metricCache, metrics)
 	m.metricCache = &IngressRouteMetric{
-		Total:    metrics.Total,
-		Invalid:  metrics.Invalid,
-		Valid:    metrics.Valid,
-		Orphaned: metrics.Orphaned,
-		Root:     metrics.Root,
+		Total:      metrics.Total,
+		Invalid:    metrics.Invalid,
+		Valid:      metrics.Valid,
+		Orphaned:   metrics.Orphaned,
+		Root:       metrics.Root,
+		Conditions: metrics.Conditions,
 	}
 }
 
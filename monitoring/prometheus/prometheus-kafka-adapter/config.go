@@ -15,22 +15,23 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
 
-	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/sirupsen/logrus"
 )
 
 var (
-	kafkaBrokerList   = "kafka:9092"
-	kafkaTopic        = "metrics"
-	basicauth         = false
-	basicauthUsername = ""
-	basicauthPassword = ""
-	kafkaPartition    = kafka.TopicPartition{
-		Topic:     &kafkaTopic,
-		Partition: kafka.PartitionAny,
-	}
+	kafkaBrokerList        = "kafka:9092"
+	kafkaTopic             = "metrics"
+	basicauth              = false
+	basicauthUsername      = ""
+	basicauthPassword      = ""
 	kafkaCompression       = "none"
 	kafkaBatchNumMessages  = "10000"
 	kafkaSslClientCertFile = ""
@@ -38,7 +39,31 @@ var (
 	kafkaSslClientKeyPass  = ""
 	kafkaSslCACertFile     = ""
 	kafkaSslValidation     = true
-	serializer             Serializer
+)
+
+var (
+	kafkaTopicTemplate        *template.Template
+	kafkaPartitionLabels      []string
+	serializationFormat       = "json"
+	topicSerializationFormats = map[string]string{}
+	schemaRegistryURL         = ""
+	schemaRegistryUsername    = ""
+	schemaRegistryPassword    = ""
+	schemaRegistry            *SchemaRegistryClient
+	serializers               = map[string]Serializer{}
+	serializersMu             sync.Mutex
+)
+
+var (
+	mode                     = "producer"
+	kafkaConsumerTopics      []string
+	kafkaConsumerGroupID     = "prometheus-kafka-adapter"
+	remoteWriteURLs          []string
+	remoteWriteBatchSize     = 1000
+	remoteWriteBatchTimeout  = 5 * time.Second
+	remoteWriteTimeout       = 30 * time.Second
+	remoteWriteMaxRetries    = 5
+	remoteWriteRetryInterval = 30 * time.Second
 )
 
 func init() {
@@ -55,11 +80,16 @@ func init() {
 
 	if value := os.Getenv("KAFKA_TOPIC"); value != "" {
 		kafkaTopic = value
+	}
 
-		kafkaPartition = kafka.TopicPartition{
-			Topic:     &kafkaTopic,
-			Partition: kafka.PartitionAny,
-		}
+	var err error
+	kafkaTopicTemplate, err = parseTopicTemplate(kafkaTopic)
+	if err != nil {
+		logrus.WithError(err).Fatalln("couldn't parse kafka topic template")
+	}
+
+	if value := os.Getenv("KAFKA_PARTITION_LABELS"); value != "" {
+		kafkaPartitionLabels = parseList(value)
 	}
 
 	if value := os.Getenv("BASIC_AUTH_USERNAME"); value != "" {
@@ -95,10 +125,78 @@ func init() {
 		kafkaSslCACertFile = value
 	}
 
-	var err error
-	serializer, err = parseSerializationFormat(os.Getenv("SERIALIZATION_FORMAT"))
-	if err != nil {
-		logrus.WithError(err).Fatalln("couldn't create a metrics serializer")
+	if value := os.Getenv("MODE"); value != "" {
+		mode = value
+	}
+
+	if value := os.Getenv("KAFKA_CONSUMER_TOPICS"); value != "" {
+		kafkaConsumerTopics = parseList(value)
+	} else if !isTopicTemplate(kafkaTopic) {
+		kafkaConsumerTopics = []string{kafkaTopic}
+	}
+
+	if value := os.Getenv("KAFKA_CONSUMER_GROUP_ID"); value != "" {
+		kafkaConsumerGroupID = value
+	}
+
+	if value := os.Getenv("REMOTE_WRITE_URLS"); value != "" {
+		remoteWriteURLs = parseList(value)
+	}
+
+	if value := os.Getenv("REMOTE_WRITE_BATCH_SIZE"); value != "" {
+		remoteWriteBatchSize = parseInt("REMOTE_WRITE_BATCH_SIZE", value)
+	}
+
+	if value := os.Getenv("REMOTE_WRITE_BATCH_TIMEOUT"); value != "" {
+		remoteWriteBatchTimeout = parseDuration("REMOTE_WRITE_BATCH_TIMEOUT", value)
+	}
+
+	if value := os.Getenv("REMOTE_WRITE_TIMEOUT"); value != "" {
+		remoteWriteTimeout = parseDuration("REMOTE_WRITE_TIMEOUT", value)
+	}
+
+	if value := os.Getenv("REMOTE_WRITE_MAX_RETRIES"); value != "" {
+		remoteWriteMaxRetries = parseInt("REMOTE_WRITE_MAX_RETRIES", value)
+	}
+
+	if value := os.Getenv("REMOTE_WRITE_RETRY_INTERVAL"); value != "" {
+		remoteWriteRetryInterval = parseDuration("REMOTE_WRITE_RETRY_INTERVAL", value)
+	}
+
+	if value := os.Getenv("SERIALIZATION_FORMAT"); value != "" {
+		serializationFormat = value
+	}
+
+	if value := os.Getenv("TOPIC_SERIALIZATION_FORMATS"); value != "" {
+		topicSerializationFormats = parseTopicSerializationFormats(value)
+	}
+
+	if value := os.Getenv("SCHEMA_REGISTRY_URL"); value != "" {
+		schemaRegistryURL = strings.TrimSuffix(value, "/")
+	}
+
+	if value := os.Getenv("SCHEMA_REGISTRY_USERNAME"); value != "" {
+		schemaRegistryUsername = value
+	}
+
+	if value := os.Getenv("SCHEMA_REGISTRY_PASSWORD"); value != "" {
+		schemaRegistryPassword = value
+	}
+
+	if schemaRegistryURL != "" {
+		schemaRegistry = NewSchemaRegistryClient(schemaRegistryURL, schemaRegistryUsername, schemaRegistryPassword)
+	}
+
+	if !isTopicTemplate(kafkaTopic) {
+		if _, err := serializerForTopic(kafkaTopic); err != nil {
+			logrus.WithError(err).Fatalln("couldn't create a metrics serializer")
+		}
+	}
+
+	for topic := range topicSerializationFormats {
+		if _, err := serializerForTopic(topic); err != nil {
+			logrus.WithError(err).WithField("topic", topic).Fatalln("couldn't create a metrics serializer")
+		}
 	}
 }
 
@@ -113,12 +211,99 @@ func parseLogLevel(value string) logrus.Level {
 	return level
 }
 
-func parseSerializationFormat(value string) (Serializer, error) {
+// parseList parses a comma separated list, ignoring empty items.
+func parseList(value string) []string {
+	items := []string{}
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func parseInt(name, value string) int {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		logrus.WithError(err).WithField(name, value).Fatalln("invalid integer in env var")
+	}
+
+	return i
+}
+
+func parseDuration(name, value string) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		logrus.WithError(err).WithField(name, value).Fatalln("invalid duration in env var")
+	}
+
+	return d
+}
+
+// parseTopicSerializationFormats parses a comma separated list of topic=format pairs.
+func parseTopicSerializationFormats(value string) map[string]string {
+	formats := map[string]string{}
+
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			logrus.WithField("topic-serialization-format-value", pair).Warningln("invalid topic serialization format, ignoring")
+			continue
+		}
+
+		formats[parts[0]] = parts[1]
+	}
+
+	return formats
+}
+
+// maxSerializers limits the number of cached serializers, topics rendered from label values
+// must not grow the cache without bound.
+const maxSerializers = 1000
+
+// serializerForTopic returns the serializer configured for the given topic,
+// serializers are created once per topic and reused. The cache is reset when it's full.
+func serializerForTopic(topic string) (Serializer, error) {
+	serializersMu.Lock()
+	defer serializersMu.Unlock()
+
+	if s, ok := serializers[topic]; ok {
+		return s, nil
+	}
+
+	if len(serializers) >= maxSerializers {
+		serializers = map[string]Serializer{}
+	}
+
+	format, ok := topicSerializationFormats[topic]
+	if !ok {
+		format = serializationFormat
+	}
+
+	s, err := parseSerializationFormat(format, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	serializers[topic] = s
+	return s, nil
+}
+
+func parseSerializationFormat(value string, topic string) (Serializer, error) {
 	switch value {
 	case "json":
 		return NewJSONSerializer()
 	case "avro-json":
 		return NewAvroJSONSerializer("schemas/metric.avsc")
+	case "avro":
+		if schemaRegistry == nil {
+			return nil, fmt.Errorf("avro serialization format requires SCHEMA_REGISTRY_URL")
+		}
+		return NewAvroSerializer("schemas/metric.avsc", schemaRegistry, topic+"-value")
+	case "protobuf":
+		return NewProtobufSerializer()
 	default:
 		logrus.WithField("serialization-format-value", value).Warningln("invalid serialization format, using json")
 		return NewJSONSerializer()
@@ -0,0 +1,266 @@
+// Copyright 2018 Telefónica
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/sirupsen/logrus"
+)
+
+// retryPollInterval is how long the consumer is polled at once while a batch is retried.
+const retryPollInterval = 100 * time.Millisecond
+
+// messageConsumer is the part of kafka.Consumer used by the remoteWriteForwarder
+type messageConsumer interface {
+	ReadMessage(timeout time.Duration) (*kafka.Message, error)
+	CommitOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error)
+	Poll(timeoutMs int) kafka.Event
+	Assignment() ([]kafka.TopicPartition, error)
+	Pause(partitions []kafka.TopicPartition) error
+	Resume(partitions []kafka.TopicPartition) error
+	Seek(partition kafka.TopicPartition, timeoutMs int) error
+}
+
+// remoteWriteForwarder reads serialized samples from kafka, batches them into write requests
+// and sends them to remote write endpoints. Offsets are committed only after a batch was written
+// to every endpoint, so kafka keeps the samples while the endpoints are unavailable.
+type remoteWriteForwarder struct {
+	consumer      messageConsumer
+	writers       []*RemoteWriteClient
+	batchSize     int
+	batchTimeout  time.Duration
+	retryInterval time.Duration
+
+	batch         prompb.WriteRequest
+	batchMessages int
+	batchSamples  int
+	batchStart    time.Time
+	offsets       map[string]kafka.TopicPartition
+	paused        []kafka.TopicPartition
+}
+
+func newRemoteWriteForwarder(consumer messageConsumer, writers []*RemoteWriteClient) *remoteWriteForwarder {
+	return &remoteWriteForwarder{
+		consumer:      consumer,
+		writers:       writers,
+		batchSize:     remoteWriteBatchSize,
+		batchTimeout:  remoteWriteBatchTimeout,
+		retryInterval: remoteWriteRetryInterval,
+		offsets:       map[string]kafka.TopicPartition{},
+	}
+}
+
+// Run consumes messages until stop is closed. Samples of an unfinished batch are not committed
+// and are consumed again after restart.
+func (f *remoteWriteForwarder) Run(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		msg, err := f.consumer.ReadMessage(f.batchTimeout)
+		if err != nil {
+			if kerr, ok := err.(kafka.Error); !ok || kerr.Code() != kafka.ErrTimedOut {
+				logrus.WithError(err).Error("couldn't read message from kafka")
+			}
+		} else {
+			f.add(msg)
+		}
+
+		if f.batchSamples >= f.batchSize || (f.batchMessages > 0 && time.Since(f.batchStart) >= f.batchTimeout) {
+			f.flush(stop)
+		}
+	}
+}
+
+// add deserializes the message into the current batch. Messages, which cannot be deserialized,
+// are skipped, but their offsets are committed with the batch.
+func (f *remoteWriteForwarder) add(msg *kafka.Message) {
+	tp := msg.TopicPartition
+	topic := *tp.Topic
+
+	f.offsets[fmt.Sprintf("%s/%d", topic, tp.Partition)] = kafka.TopicPartition{
+		Topic:     tp.Topic,
+		Partition: tp.Partition,
+		Offset:    tp.Offset + 1,
+	}
+
+	if f.batchMessages == 0 {
+		f.batchStart = time.Now()
+	}
+	f.batchMessages++
+
+	kafkaMessagesConsumedTotal.WithLabelValues(topic).Inc()
+
+	ts, err := deserializeMessage(topic, msg.Value)
+	if err != nil {
+		kafkaConsumeErrorsTotal.WithLabelValues(topic).Inc()
+		logrus.WithError(err).WithField("topic", topic).Error("couldn't deserialize message")
+		return
+	}
+
+	f.batch.Timeseries = append(f.batch.Timeseries, ts)
+	f.batchSamples += len(ts.Samples)
+}
+
+// flush writes the current batch to every endpoint and commits its offsets.
+// Endpoints are retried until the batch is written or stop is closed, non recoverable
+// errors drop the batch for the failed endpoint only. Partitions are paused while retrying.
+func (f *remoteWriteForwarder) flush(stop <-chan struct{}) {
+	pending := f.writers
+
+	for len(pending) > 0 {
+		var failed []*RemoteWriteClient
+
+		for _, w := range pending {
+			err := w.Write(&f.batch)
+			if err == nil {
+				remoteWriteSamplesTotal.WithLabelValues(w.url).Add(float64(f.batchSamples))
+				continue
+			}
+
+			if _, ok := err.(recoverableError); !ok {
+				remoteWriteDroppedSamplesTotal.WithLabelValues(w.url).Add(float64(f.batchSamples))
+				logrus.WithError(err).WithFields(logrus.Fields{
+					"url":     w.url,
+					"samples": f.batchSamples,
+				}).Error("remote write endpoint rejected samples, dropping them")
+				continue
+			}
+
+			logrus.WithError(err).WithField("url", w.url).Error("couldn't write samples, will retry")
+			failed = append(failed, w)
+		}
+
+		pending = failed
+		if len(pending) == 0 {
+			break
+		}
+
+		if f.paused == nil {
+			f.pause()
+		}
+
+		if !f.waitRetry(stop) {
+			return
+		}
+	}
+
+	f.resume()
+
+	if len(f.offsets) > 0 {
+		offsets := make([]kafka.TopicPartition, 0, len(f.offsets))
+		for _, tp := range f.offsets {
+			offsets = append(offsets, tp)
+		}
+
+		if _, err := f.consumer.CommitOffsets(offsets); err != nil {
+			logrus.WithError(err).Error("couldn't commit kafka offsets")
+		}
+	}
+
+	f.batch = prompb.WriteRequest{}
+	f.batchMessages = 0
+	f.batchSamples = 0
+	f.offsets = map[string]kafka.TopicPartition{}
+}
+
+// pause stops fetching messages from the assigned partitions, so the consumer can be polled
+// while a batch is retried without consuming more messages.
+func (f *remoteWriteForwarder) pause() {
+	assigned, err := f.consumer.Assignment()
+	if err != nil {
+		logrus.WithError(err).Error("couldn't get assigned kafka partitions")
+		return
+	}
+
+	if err := f.consumer.Pause(assigned); err != nil {
+		logrus.WithError(err).Error("couldn't pause kafka partitions")
+		return
+	}
+
+	f.paused = assigned
+}
+
+// resume continues fetching messages from partitions paused while retrying.
+func (f *remoteWriteForwarder) resume() {
+	if f.paused == nil {
+		return
+	}
+
+	if err := f.consumer.Resume(f.paused); err != nil {
+		logrus.WithError(err).Error("couldn't resume kafka partitions")
+	}
+
+	f.paused = nil
+}
+
+// waitRetry polls the consumer until the retry interval elapses, so it isn't removed from the
+// consumer group after max.poll.interval.ms during long outages. It returns false if stop was closed.
+func (f *remoteWriteForwarder) waitRetry(stop <-chan struct{}) bool {
+	deadline := time.Now().Add(f.retryInterval)
+
+	for {
+		select {
+		case <-stop:
+			return false
+		default:
+		}
+
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return true
+		}
+		if timeout > retryPollInterval {
+			timeout = retryPollInterval
+		}
+
+		switch e := f.consumer.Poll(int(timeout / time.Millisecond)).(type) {
+		case *kafka.Message:
+			// partitions assigned by a rebalance aren't paused yet, the message is read again after resuming
+			tp := e.TopicPartition
+			if err := f.consumer.Pause([]kafka.TopicPartition{tp}); err != nil {
+				logrus.WithError(err).Error("couldn't pause kafka partition")
+			}
+			if err := f.consumer.Seek(tp, 0); err != nil {
+				logrus.WithError(err).Error("couldn't seek kafka partition")
+			}
+			f.paused = append(f.paused, tp)
+		case kafka.Error:
+			logrus.WithError(e).Error("kafka consumer error")
+		}
+	}
+}
+
+// deserializeMessage decodes a message with the serialization format of its topic.
+func deserializeMessage(topic string, data []byte) (*prompb.TimeSeries, error) {
+	serializer, err := serializerForTopic(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	deserializer, ok := serializer.(Deserializer)
+	if !ok {
+		return nil, fmt.Errorf("serialization format of topic %s can't be deserialized", topic)
+	}
+
+	return deserializer.Unmarshal(data)
+}
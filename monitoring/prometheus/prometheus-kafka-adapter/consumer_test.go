@@ -0,0 +1,194 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConsumer struct {
+	committed [][]kafka.TopicPartition
+	assigned  []kafka.TopicPartition
+	events    []kafka.Event
+	polls     int
+	paused    []kafka.TopicPartition
+	resumed   []kafka.TopicPartition
+	seeked    []kafka.TopicPartition
+}
+
+func (c *fakeConsumer) ReadMessage(timeout time.Duration) (*kafka.Message, error) {
+	return nil, nil
+}
+
+func (c *fakeConsumer) CommitOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+	c.committed = append(c.committed, offsets)
+	return offsets, nil
+}
+
+func (c *fakeConsumer) Poll(timeoutMs int) kafka.Event {
+	c.polls++
+	if len(c.events) > 0 {
+		event := c.events[0]
+		c.events = c.events[1:]
+		return event
+	}
+
+	time.Sleep(time.Duration(timeoutMs) * time.Millisecond)
+	return nil
+}
+
+func (c *fakeConsumer) Assignment() ([]kafka.TopicPartition, error) {
+	return c.assigned, nil
+}
+
+func (c *fakeConsumer) Pause(partitions []kafka.TopicPartition) error {
+	c.paused = append(c.paused, partitions...)
+	return nil
+}
+
+func (c *fakeConsumer) Resume(partitions []kafka.TopicPartition) error {
+	c.resumed = append(c.resumed, partitions...)
+	return nil
+}
+
+func (c *fakeConsumer) Seek(partition kafka.TopicPartition, timeoutMs int) error {
+	c.seeked = append(c.seeked, partition)
+	return nil
+}
+
+func NewConsumedMessages(t *testing.T, topic string) []*kafka.Message {
+	serializer, err := serializerForTopic(topic)
+	assert.Nil(t, err)
+
+	data, err := Serialize(serializer, NewWriteRequest())
+	assert.Nil(t, err)
+
+	messages := []*kafka.Message{}
+	for i, value := range data {
+		messages = append(messages, &kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: kafka.Offset(10 + i)},
+			Value:          value,
+		})
+	}
+
+	return messages
+}
+
+func TestRemoteWriteForwarderFlush(t *testing.T) {
+	server, requests := NewRemoteWriteStub(t)
+	defer server.Close()
+
+	consumer := &fakeConsumer{}
+	forwarder := newRemoteWriteForwarder(consumer, []*RemoteWriteClient{NewRemoteWriteClient(server.URL, 0, 0)})
+
+	for _, msg := range NewConsumedMessages(t, "metrics") {
+		forwarder.add(msg)
+	}
+	forwarder.add(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &kafkaTopic, Partition: 1, Offset: 3},
+		Value:          []byte("not a metric"),
+	})
+	assert.Equal(t, 2, forwarder.batchSamples)
+
+	forwarder.flush(make(chan struct{}))
+
+	assert.Len(t, *requests, 1)
+	assert.Len(t, (*requests)[0].Timeseries, 2)
+	assert.Equal(t, int64(10000), (*requests)[0].Timeseries[1].Samples[0].Timestamp)
+
+	assert.Len(t, consumer.committed, 1)
+	assert.ElementsMatch(t, []kafka.TopicPartition{
+		{Topic: &kafkaTopic, Partition: 0, Offset: 12},
+		{Topic: &kafkaTopic, Partition: 1, Offset: 4},
+	}, consumer.committed[0])
+	assert.Equal(t, 0, forwarder.batchSamples)
+}
+
+func TestRemoteWriteForwarderRetriesFailedEndpoints(t *testing.T) {
+	healthy, healthyRequests := NewRemoteWriteStub(t)
+	defer healthy.Close()
+	flaky, flakyRequests := NewRemoteWriteStub(t, http.StatusServiceUnavailable)
+	defer flaky.Close()
+
+	consumer := &fakeConsumer{}
+	forwarder := newRemoteWriteForwarder(consumer, []*RemoteWriteClient{
+		NewRemoteWriteClient(healthy.URL, 0, 0),
+		NewRemoteWriteClient(flaky.URL, 0, 0),
+	})
+	forwarder.retryInterval = 0
+
+	for _, msg := range NewConsumedMessages(t, "metrics") {
+		forwarder.add(msg)
+	}
+	forwarder.flush(make(chan struct{}))
+
+	assert.Len(t, *healthyRequests, 1, "succeeded endpoints must not be retried")
+	assert.Len(t, *flakyRequests, 2)
+	assert.Len(t, consumer.committed, 1)
+}
+
+func TestRemoteWriteForwarderStopWithoutCommit(t *testing.T) {
+	server, _ := NewRemoteWriteStub(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	consumer := &fakeConsumer{}
+	forwarder := newRemoteWriteForwarder(consumer, []*RemoteWriteClient{NewRemoteWriteClient(server.URL, 0, 0)})
+	forwarder.retryInterval = time.Hour
+
+	for _, msg := range NewConsumedMessages(t, "metrics") {
+		forwarder.add(msg)
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+	forwarder.flush(stop)
+
+	assert.Len(t, consumer.committed, 0, "offsets must not be committed before samples were written")
+}
+
+func TestRemoteWriteForwarderPollsWhileRetrying(t *testing.T) {
+	server, requests := NewRemoteWriteStub(t, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	rebalanced := kafka.TopicPartition{Topic: &kafkaTopic, Partition: 2, Offset: 7}
+	consumer := &fakeConsumer{
+		assigned: []kafka.TopicPartition{{Topic: &kafkaTopic, Partition: 0}},
+		events:   []kafka.Event{&kafka.Message{TopicPartition: rebalanced}},
+	}
+	forwarder := newRemoteWriteForwarder(consumer, []*RemoteWriteClient{NewRemoteWriteClient(server.URL, 0, 0)})
+	forwarder.retryInterval = 3 * retryPollInterval
+
+	for _, msg := range NewConsumedMessages(t, "metrics") {
+		forwarder.add(msg)
+	}
+	forwarder.flush(make(chan struct{}))
+
+	assert.Len(t, *requests, 2)
+	assert.True(t, consumer.polls > 1, "consumer must be polled while retrying")
+	assert.Equal(t, []kafka.TopicPartition{consumer.assigned[0], rebalanced}, consumer.paused)
+	assert.Equal(t, []kafka.TopicPartition{rebalanced}, consumer.seeked, "messages polled while retrying must be read again")
+	assert.Equal(t, consumer.paused, consumer.resumed)
+	assert.Len(t, consumer.committed, 1)
+}
+
+func TestRemoteWriteForwarderCountsDroppedSamples(t *testing.T) {
+	server, _ := NewRemoteWriteStub(t, http.StatusBadRequest)
+	defer server.Close()
+
+	consumer := &fakeConsumer{}
+	forwarder := newRemoteWriteForwarder(consumer, []*RemoteWriteClient{NewRemoteWriteClient(server.URL, 0, 0)})
+
+	for _, msg := range NewConsumedMessages(t, "metrics") {
+		forwarder.add(msg)
+	}
+	forwarder.flush(make(chan struct{}))
+
+	var m dto.Metric
+	assert.Nil(t, remoteWriteDroppedSamplesTotal.WithLabelValues(server.URL).Write(&m))
+	assert.Equal(t, float64(2), m.GetCounter().GetValue())
+	assert.Len(t, consumer.committed, 1, "rejected samples must not be retried")
+}
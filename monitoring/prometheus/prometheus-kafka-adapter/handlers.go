@@ -28,7 +28,7 @@ import (
 	"github.com/prometheus/prometheus/prompb"
 )
 
-func receiveHandler(producer *kafka.Producer, serializer Serializer) func(c *gin.Context) {
+func receiveHandler(producer *kafka.Producer) func(c *gin.Context) {
 	return func(c *gin.Context) {
 
 		httpRequestsTotal.Add(float64(1))
@@ -54,25 +54,49 @@ func receiveHandler(producer *kafka.Producer, serializer Serializer) func(c *gin
 			return
 		}
 
-		metrics, err := processWriteRequest(&req)
+		messages, err := processWriteRequest(&req)
 		if err != nil {
 			c.AbortWithStatus(http.StatusInternalServerError)
 			logrus.WithError(err).Error("couldn't process write request")
 			return
 		}
 
-		for _, metric := range metrics {
-			err := producer.Produce(&kafka.Message{
-				TopicPartition: kafkaPartition,
-				Value:          metric,
-			}, nil)
+		for _, message := range messages {
+			topic := *message.TopicPartition.Topic
 
+			err := producer.Produce(message, nil)
 			if err != nil {
+				kafkaProduceErrorsTotal.WithLabelValues(topic).Inc()
 				c.AbortWithStatus(http.StatusInternalServerError)
-				logrus.WithError(err).Error("couldn't produce message in kafka")
+				logrus.WithError(err).WithField("topic", topic).Error("couldn't produce message in kafka")
 				return
 			}
 		}
 
 	}
 }
+
+// deliveryReportHandler counts messages by their delivery reports, so only messages acknowledged
+// by kafka are counted as produced.
+func deliveryReportHandler(events <-chan kafka.Event) {
+	for event := range events {
+		switch e := event.(type) {
+		case *kafka.Message:
+			topic := ""
+			if e.TopicPartition.Topic != nil {
+				topic = *e.TopicPartition.Topic
+			}
+
+			if err := e.TopicPartition.Error; err != nil {
+				kafkaProduceErrorsTotal.WithLabelValues(topic).Inc()
+				logrus.WithError(err).WithField("topic", topic).Error("couldn't deliver message to kafka")
+				continue
+			}
+
+			kafkaMessagesProducedTotal.WithLabelValues(topic).Inc()
+			kafkaBytesProducedTotal.WithLabelValues(topic).Add(float64(len(e.Value)))
+		case kafka.Error:
+			logrus.WithError(e).Error("kafka producer error")
+		}
+	}
+}
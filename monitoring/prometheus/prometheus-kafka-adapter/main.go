@@ -26,44 +26,99 @@ import (
 )
 
 func main() {
-	log.Info("creating kafka producer")
+	r := gin.New()
 
-	kafkaConfig := kafka.ConfigMap{
-		"bootstrap.servers":        kafkaBrokerList,
-		"compression.codec":        kafkaCompression,
-		"batch.num.messages":       kafkaBatchNumMessages,
-		"go.batch.producer":        true,                   // Enable batch producer (for increased performance).
-		"go.delivery.reports":      false,                  // per-message delivery reports to the Events() channel
-		"ssl.ca.location":          kafkaSslCACertFile,     // CA certificate file for verifying the broker's certificate.
-		"ssl.certificate.location": kafkaSslClientCertFile, // Client's certificate
-		"ssl.key.location":         kafkaSslClientKeyFile,  // Client's key
-		"ssl.key.password":         kafkaSslClientKeyPass,  // Key password, if any.
-	}
+	r.Use(ginrus.Ginrus(logrus.StandardLogger(), time.RFC3339, true), gin.Recovery())
 
-	if kafkaSslClientCertFile != "" && kafkaSslClientKeyFile != "" && kafkaSslCACertFile != "" {
-		kafkaConfig["security.protocol"] = "ssl"
+	r.GET("/metrics", gin.WrapH(prometheus.UninstrumentedHandler()))
+
+	switch mode {
+	case "consumer":
+		startConsumer()
+	case "producer":
+		startProducer(r)
+	default:
+		logrus.WithField("mode", mode).Fatal("invalid mode, can be producer or consumer")
 	}
 
+	r.Run()
+}
+
+func startProducer(r *gin.Engine) {
+	log.Info("creating kafka producer")
+
+	kafkaConfig := newKafkaConfig()
+	kafkaConfig["compression.codec"] = kafkaCompression
+	kafkaConfig["batch.num.messages"] = kafkaBatchNumMessages
+	kafkaConfig["go.batch.producer"] = true   // Enable batch producer (for increased performance).
+	kafkaConfig["go.delivery.reports"] = true // per-message delivery reports to the Events() channel
+
 	producer, err := kafka.NewProducer(&kafkaConfig)
 
 	if err != nil {
 		logrus.WithError(err).Fatal("couldn't create kafka producer")
 	}
 
-	r := gin.New()
-
-	r.Use(ginrus.Ginrus(logrus.StandardLogger(), time.RFC3339, true), gin.Recovery())
-
-	r.GET("/metrics", gin.WrapH(prometheus.UninstrumentedHandler()))
+	go deliveryReportHandler(producer.Events())
 
 	if basicauth {
 		authorized := r.Group("/", gin.BasicAuth(gin.Accounts{
 			basicauthUsername: basicauthPassword,
 		}))
-		authorized.POST("/receive", receiveHandler(producer, serializer))
+		authorized.POST("/receive", receiveHandler(producer))
 	} else {
-		r.POST("/receive", receiveHandler(producer, serializer))
+		r.POST("/receive", receiveHandler(producer))
 	}
+}
 
-	r.Run()
+// startConsumer starts forwarding of consumed samples to remote write endpoints,
+// offsets are committed by the forwarder after samples were written.
+func startConsumer() {
+	log.Info("creating kafka consumer")
+
+	if len(kafkaConsumerTopics) == 0 {
+		logrus.Fatal("consumer mode requires KAFKA_CONSUMER_TOPICS")
+	}
+
+	if len(remoteWriteURLs) == 0 {
+		logrus.Fatal("consumer mode requires REMOTE_WRITE_URLS")
+	}
+
+	kafkaConfig := newKafkaConfig()
+	kafkaConfig["group.id"] = kafkaConsumerGroupID
+	kafkaConfig["enable.auto.commit"] = false
+	kafkaConfig["auto.offset.reset"] = "earliest"
+
+	consumer, err := kafka.NewConsumer(&kafkaConfig)
+
+	if err != nil {
+		logrus.WithError(err).Fatal("couldn't create kafka consumer")
+	}
+
+	if err := consumer.SubscribeTopics(kafkaConsumerTopics, nil); err != nil {
+		logrus.WithError(err).Fatal("couldn't subscribe to kafka topics")
+	}
+
+	writers := make([]*RemoteWriteClient, 0, len(remoteWriteURLs))
+	for _, url := range remoteWriteURLs {
+		writers = append(writers, NewRemoteWriteClient(url, remoteWriteTimeout, remoteWriteMaxRetries))
+	}
+
+	go newRemoteWriteForwarder(consumer, writers).Run(make(chan struct{}))
+}
+
+func newKafkaConfig() kafka.ConfigMap {
+	kafkaConfig := kafka.ConfigMap{
+		"bootstrap.servers":        kafkaBrokerList,
+		"ssl.ca.location":          kafkaSslCACertFile,     // CA certificate file for verifying the broker's certificate.
+		"ssl.certificate.location": kafkaSslClientCertFile, // Client's certificate
+		"ssl.key.location":         kafkaSslClientKeyFile,  // Client's key
+		"ssl.key.password":         kafkaSslClientKeyPass,  // Key password, if any.
+	}
+
+	if kafkaSslClientCertFile != "" && kafkaSslClientKeyFile != "" && kafkaSslCACertFile != "" {
+		kafkaConfig["security.protocol"] = "ssl"
+	}
+
+	return kafkaConfig
 }
@@ -27,9 +27,57 @@ var (
 			Name: "http_requests_total",
 			Help: "Count of all http requests",
 		})
+	kafkaMessagesProducedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_messages_produced_total",
+			Help: "Count of messages delivered to Kafka per topic",
+		}, []string{"topic"})
+	kafkaBytesProducedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_bytes_produced_total",
+			Help: "Size of message values delivered to Kafka per topic",
+		}, []string{"topic"})
+	kafkaProduceErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_produce_errors_total",
+			Help: "Count of errors producing messages to Kafka per topic",
+		}, []string{"topic"})
+	kafkaMessagesConsumedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_messages_consumed_total",
+			Help: "Count of messages consumed from Kafka per topic",
+		}, []string{"topic"})
+	kafkaConsumeErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_consume_errors_total",
+			Help: "Count of consumed messages, which couldn't be deserialized, per topic",
+		}, []string{"topic"})
+	remoteWriteSamplesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "remote_write_samples_total",
+			Help: "Count of samples written to remote write endpoints",
+		}, []string{"url"})
+	remoteWriteDroppedSamplesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "remote_write_dropped_samples_total",
+			Help: "Count of samples rejected by remote write endpoints",
+		}, []string{"url"})
+	remoteWriteRetriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "remote_write_retries_total",
+			Help: "Count of retried remote write requests",
+		}, []string{"url"})
 )
 
 func init() {
 	prometheus.MustRegister(queueSize)
 	prometheus.MustRegister(httpRequestsTotal)
+	prometheus.MustRegister(kafkaMessagesProducedTotal)
+	prometheus.MustRegister(kafkaBytesProducedTotal)
+	prometheus.MustRegister(kafkaProduceErrorsTotal)
+	prometheus.MustRegister(kafkaMessagesConsumedTotal)
+	prometheus.MustRegister(kafkaConsumeErrorsTotal)
+	prometheus.MustRegister(remoteWriteSamplesTotal)
+	prometheus.MustRegister(remoteWriteDroppedSamplesTotal)
+	prometheus.MustRegister(remoteWriteRetriesTotal)
 }
@@ -15,11 +15,48 @@
 package main
 
 import (
+	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/sirupsen/logrus"
 )
 
-func processWriteRequest(req *prompb.WriteRequest) ([][]byte, error) {
+// processWriteRequest serializes every series of the request with the serializer of its topic,
+// routing series by the topic template and setting partition keys from series labels.
+func processWriteRequest(req *prompb.WriteRequest) ([]*kafka.Message, error) {
 	logrus.WithField("var", req).Debugln()
-	return Serialize(serializer, req)
+
+	messages := []*kafka.Message{}
+
+	for _, ts := range req.Timeseries {
+		labels := seriesLabels(ts)
+
+		topic, err := topicForSeries(kafkaTopicTemplate, labels)
+		if err != nil {
+			return nil, err
+		}
+
+		serializer, err := serializerForTopic(topic)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := Serialize(serializer, &prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{ts}})
+		if err != nil {
+			return nil, err
+		}
+
+		key := partitionKey(kafkaPartitionLabels, labels)
+		for _, value := range data {
+			messages = append(messages, &kafka.Message{
+				TopicPartition: kafka.TopicPartition{
+					Topic:     &topic,
+					Partition: kafka.PartitionAny,
+				},
+				Key:   key,
+				Value: value,
+			})
+		}
+	}
+
+	return messages, nil
 }
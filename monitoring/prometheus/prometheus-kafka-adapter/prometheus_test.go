@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessWriteRequestRouting(t *testing.T) {
+	defaultTemplate, defaultLabels := kafkaTopicTemplate, kafkaPartitionLabels
+	defer func() {
+		kafkaTopicTemplate, kafkaPartitionLabels = defaultTemplate, defaultLabels
+	}()
+
+	var err error
+	kafkaTopicTemplate, err = parseTopicTemplate(`metrics_{{ index . "tenant" | default "shared" }}`)
+	assert.Nil(t, err)
+	kafkaPartitionLabels = []string{"__name__", "tenant"}
+
+	writeRequest := NewWriteRequest()
+	writeRequest.Timeseries = append(writeRequest.Timeseries, &prompb.TimeSeries{
+		Labels: []*prompb.Label{
+			&prompb.Label{Name: "__name__", Value: "bar"},
+			&prompb.Label{Name: "tenant", Value: "acme"},
+		},
+		Samples: []*prompb.Sample{
+			&prompb.Sample{Timestamp: 0, Value: 1},
+		},
+	})
+
+	messages, err := processWriteRequest(writeRequest)
+	assert.Nil(t, err)
+	assert.Len(t, messages, 3)
+
+	expected := []struct {
+		topic string
+		key   string
+	}{
+		{"metrics_shared", "foo,"},
+		{"metrics_shared", "foo,"},
+		{"metrics_acme", "bar,acme"},
+	}
+
+	for i, message := range messages {
+		assert.Equal(t, expected[i].topic, *message.TopicPartition.Topic)
+		assert.Equal(t, expected[i].key, string(message.Key))
+	}
+}
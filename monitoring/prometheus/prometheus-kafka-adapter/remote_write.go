@@ -0,0 +1,118 @@
+// Copyright 2018 Telefónica
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/sirupsen/logrus"
+)
+
+// recoverableError is returned for failures worth retrying, like network errors or 5xx responses
+type recoverableError struct {
+	error
+}
+
+// RemoteWriteClient sends write requests to a Prometheus remote write endpoint
+type RemoteWriteClient struct {
+	url        string
+	httpClient *http.Client
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// NewRemoteWriteClient builds a new instance of the RemoteWriteClient
+func NewRemoteWriteClient(url string, timeout time.Duration, maxRetries int) *RemoteWriteClient {
+	return &RemoteWriteClient{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+		maxRetries: maxRetries,
+		minBackoff: 100 * time.Millisecond,
+		maxBackoff: 10 * time.Second,
+	}
+}
+
+// Write sends the request, retrying recoverable errors with exponential backoff.
+// Non recoverable errors, like 4xx responses, are returned immediately.
+func (c *RemoteWriteClient) Write(req *prompb.WriteRequest) error {
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	compressed := snappy.Encode(nil, data)
+	backoff := c.minBackoff
+
+	for try := 0; ; try++ {
+		err = c.send(compressed)
+		if err == nil {
+			return nil
+		}
+
+		if _, ok := err.(recoverableError); !ok || try >= c.maxRetries {
+			return err
+		}
+
+		logrus.WithError(err).WithField("url", c.url).Warningln("couldn't send remote write request, retrying")
+		remoteWriteRetriesTotal.WithLabelValues(c.url).Inc()
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+func (c *RemoteWriteClient) send(compressed []byte) error {
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(compressed))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return recoverableError{err}
+	}
+	defer func() {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("server returned HTTP status %s: %s", resp.Status, bytes.TrimSpace(body))
+
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		return recoverableError{err}
+	}
+
+	return err
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+)
+
+// NewRemoteWriteStub starts a remote write endpoint, which responds with the given status codes in turn
+// and records the received requests.
+func NewRemoteWriteStub(t *testing.T, codes ...int) (*httptest.Server, *[]prompb.WriteRequest) {
+	requests := []prompb.WriteRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+
+		compressed, err := ioutil.ReadAll(r.Body)
+		assert.Nil(t, err)
+		data, err := snappy.Decode(nil, compressed)
+		assert.Nil(t, err)
+
+		var req prompb.WriteRequest
+		assert.Nil(t, proto.Unmarshal(data, &req))
+		requests = append(requests, req)
+
+		code := http.StatusNoContent
+		if len(codes) > 0 {
+			code, codes = codes[0], codes[1:]
+		}
+		w.WriteHeader(code)
+	}))
+
+	return server, &requests
+}
+
+func TestRemoteWriteClientWrite(t *testing.T) {
+	server, requests := NewRemoteWriteStub(t)
+	defer server.Close()
+
+	client := NewRemoteWriteClient(server.URL, 0, 0)
+	assert.Nil(t, client.Write(NewWriteRequest()))
+	assert.Len(t, *requests, 1)
+	assert.Equal(t, *NewWriteRequest(), (*requests)[0])
+}
+
+func TestRemoteWriteClientRetries(t *testing.T) {
+	server, requests := NewRemoteWriteStub(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	defer server.Close()
+
+	client := NewRemoteWriteClient(server.URL, 0, 2)
+	client.minBackoff = 0
+	assert.Nil(t, client.Write(NewWriteRequest()))
+	assert.Len(t, *requests, 3)
+}
+
+func TestRemoteWriteClientRetriesExhausted(t *testing.T) {
+	server, requests := NewRemoteWriteStub(t, http.StatusInternalServerError, http.StatusInternalServerError)
+	defer server.Close()
+
+	client := NewRemoteWriteClient(server.URL, 0, 1)
+	client.minBackoff = 0
+	err := client.Write(NewWriteRequest())
+	assert.IsType(t, recoverableError{}, err)
+	assert.Len(t, *requests, 2)
+}
+
+func TestRemoteWriteClientNonRecoverable(t *testing.T) {
+	server, requests := NewRemoteWriteStub(t, http.StatusBadRequest)
+	defer server.Close()
+
+	client := NewRemoteWriteClient(server.URL, 0, 3)
+	err := client.Write(NewWriteRequest())
+	assert.NotNil(t, err)
+	_, recoverable := err.(recoverableError)
+	assert.False(t, recoverable)
+	assert.Len(t, *requests, 1)
+}
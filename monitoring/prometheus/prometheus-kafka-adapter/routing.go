@@ -0,0 +1,109 @@
+// Copyright 2018 Telefónica
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// maxTopicLength is the longest topic name accepted by kafka.
+const maxTopicLength = 249
+
+// invalidTopicCharacters matches characters, which are not allowed in kafka topic names.
+var invalidTopicCharacters = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+var topicTemplateFuncs = template.FuncMap{
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"replace": func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+	// prefix returns the part of s before the first separator, e.g. "node" for "node_cpu_seconds_total".
+	"prefix": func(sep, s string) string { return strings.SplitN(s, sep, 2)[0] },
+	// default returns def if s is empty.
+	"default": func(def, s string) string {
+		if s == "" {
+			return def
+		}
+		return s
+	},
+}
+
+// parseTopicTemplate parses a kafka topic, which may be a text/template rendered against series labels,
+// e.g. `metrics_{{ index . "tenant" | default "shared" }}`.
+func parseTopicTemplate(topic string) (*template.Template, error) {
+	return template.New("topic").Funcs(topicTemplateFuncs).Option("missingkey=zero").Parse(topic)
+}
+
+// isTopicTemplate checks if a kafka topic depends on series labels.
+func isTopicTemplate(topic string) bool {
+	return strings.Contains(topic, "{{")
+}
+
+func seriesLabels(ts *prompb.TimeSeries) map[string]string {
+	labels := make(map[string]string, len(ts.Labels))
+
+	for _, l := range ts.Labels {
+		labels[l.Name] = l.Value
+	}
+
+	return labels
+}
+
+// topicForSeries renders the kafka topic for the given series labels.
+// Characters not allowed in topic names are replaced with underscores and long topics are cut
+// to the kafka limit, as label values may contain anything.
+func topicForSeries(tmpl *template.Template, labels map[string]string) (string, error) {
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, labels); err != nil {
+		return "", err
+	}
+
+	topic := strings.TrimSpace(buf.String())
+	if topic == "" {
+		return "", fmt.Errorf("topic template rendered an empty topic for labels %v", labels)
+	}
+
+	topic = invalidTopicCharacters.ReplaceAllString(topic, "_")
+	if len(topic) > maxTopicLength {
+		topic = topic[:maxTopicLength]
+	}
+
+	if topic == "." || topic == ".." {
+		return "", fmt.Errorf("topic template rendered an invalid topic %q for labels %v", topic, labels)
+	}
+
+	return topic, nil
+}
+
+// partitionKey builds a kafka message key from the values of the given labels,
+// so every sample of a series lands on the same partition. It returns nil if no labels are configured.
+func partitionKey(labelNames []string, labels map[string]string) []byte {
+	if len(labelNames) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(labelNames))
+	for i, name := range labelNames {
+		values[i] = labels[name]
+	}
+
+	return []byte(strings.Join(values, ","))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicForSeries(t *testing.T) {
+	tests := []struct {
+		topic    string
+		labels   map[string]string
+		expected string
+	}{
+		{"metrics", map[string]string{"__name__": "up"}, "metrics"},
+		{`metrics_{{ index . "tenant" }}`, map[string]string{"__name__": "up", "tenant": "acme"}, "metrics_acme"},
+		{`metrics_{{ index . "tenant" | default "shared" }}`, map[string]string{"__name__": "up"}, "metrics_shared"},
+		{`{{ index . "__name__" | prefix "_" }}_metrics`, map[string]string{"__name__": "node_cpu_seconds_total"}, "node_metrics"},
+		{`metrics_{{ index . "tenant" | lower | replace "." "_" }}`, map[string]string{"tenant": "Acme.Corp"}, "metrics_acme_corp"},
+		{`metrics_{{ index . "instance" }}`, map[string]string{"instance": "host:9100/path"}, "metrics_host_9100_path"},
+		{`metrics_{{ index . "tenant" }}`, map[string]string{"tenant": strings.Repeat("a", 300)}, "metrics_" + strings.Repeat("a", maxTopicLength-len("metrics_"))},
+	}
+
+	for _, test := range tests {
+		tmpl, err := parseTopicTemplate(test.topic)
+		assert.Nil(t, err)
+
+		topic, err := topicForSeries(tmpl, test.labels)
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, topic)
+	}
+}
+
+func TestTopicForSeriesEmpty(t *testing.T) {
+	tmpl, err := parseTopicTemplate(`{{ index . "tenant" }}`)
+	assert.Nil(t, err)
+
+	_, err = topicForSeries(tmpl, map[string]string{"__name__": "up"})
+	assert.NotNil(t, err)
+
+	_, err = topicForSeries(tmpl, map[string]string{"tenant": ".."})
+	assert.NotNil(t, err)
+}
+
+func TestPartitionKey(t *testing.T) {
+	labels := map[string]string{"__name__": "up", "instance": "host:9100", "job": "node"}
+
+	assert.Nil(t, partitionKey(nil, labels))
+	assert.Equal(t, []byte("up,host:9100"), partitionKey([]string{"__name__", "instance"}, labels))
+	assert.Equal(t, []byte("up,"), partitionKey([]string{"__name__", "tenant"}, labels))
+}
@@ -0,0 +1,103 @@
+// Copyright 2018 Telefónica
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+
+// SchemaRegistryClient is a minimal client of a Confluent-compatible schema registry
+type SchemaRegistryClient struct {
+	url        string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu  sync.Mutex
+	ids map[string]int
+}
+
+// NewSchemaRegistryClient builds a new instance of the SchemaRegistryClient
+func NewSchemaRegistryClient(registryURL, username, password string) *SchemaRegistryClient {
+	return &SchemaRegistryClient{
+		url:        registryURL,
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		ids:        make(map[string]int),
+	}
+}
+
+type schemaRegistryRequest struct {
+	Schema string `json:"schema"`
+}
+
+type schemaRegistryResponse struct {
+	ID        int    `json:"id"`
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// RegisterSchema registers the schema under the given subject and returns its id.
+// Registering an already known schema is idempotent, ids are cached per subject.
+func (c *SchemaRegistryClient) RegisterSchema(subject, schema string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id, ok := c.ids[subject]; ok {
+		return id, nil
+	}
+
+	body, err := json.Marshal(schemaRegistryRequest{Schema: schema})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/subjects/%s/versions", c.url, url.PathEscape(subject)), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", schemaRegistryContentType)
+	req.Header.Set("Accept", schemaRegistryContentType)
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var result schemaRegistryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("couldn't decode schema registry response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("schema registry returned %d for subject %s: %s", resp.StatusCode, subject, result.Message)
+	}
+
+	c.ids[subject] = result.ID
+	return result.ID, nil
+}
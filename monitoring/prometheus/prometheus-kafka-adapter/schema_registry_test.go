@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewSchemaRegistryStub(t *testing.T, id int) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/subjects/metrics-value/versions", r.URL.Path)
+		assert.Equal(t, schemaRegistryContentType, r.Header.Get("Content-Type"))
+
+		var req schemaRegistryRequest
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.NotEmpty(t, req.Schema)
+
+		w.Header().Set("Content-Type", schemaRegistryContentType)
+		json.NewEncoder(w).Encode(schemaRegistryResponse{ID: id})
+	}))
+
+	return server, &requests
+}
+
+func TestSchemaRegistryRegisterSchema(t *testing.T) {
+	server, requests := NewSchemaRegistryStub(t, 42)
+	defer server.Close()
+
+	registry := NewSchemaRegistryClient(server.URL, "", "")
+
+	id, err := registry.RegisterSchema("metrics-value", `"string"`)
+	assert.Nil(t, err)
+	assert.Equal(t, 42, id)
+
+	id, err = registry.RegisterSchema("metrics-value", `"string"`)
+	assert.Nil(t, err)
+	assert.Equal(t, 42, id)
+	assert.Equal(t, 1, *requests, "schema ids must be cached per subject")
+}
+
+func TestSchemaRegistryRegisterSchemaError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(schemaRegistryResponse{ErrorCode: 409, Message: "incompatible schema"})
+	}))
+	defer server.Close()
+
+	registry := NewSchemaRegistryClient(server.URL, "", "")
+
+	_, err := registry.RegisterSchema("metrics-value", `"string"`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "incompatible schema")
+}
@@ -15,11 +15,15 @@
 package main
 
 import (
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"sort"
 	"strconv"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/sirupsen/logrus"
@@ -32,11 +36,31 @@ type Serializer interface {
 	Marshal(metric map[string]interface{}) ([]byte, error)
 }
 
+// TimeSeriesSerializer represents a metrics serializer that writes a whole time series at once
+type TimeSeriesSerializer interface {
+	MarshalTimeSeries(ts *prompb.TimeSeries) ([]byte, error)
+}
+
+// Deserializer represents an abstract metrics deserializer, reading the messages written by a Serializer
+type Deserializer interface {
+	Unmarshal(data []byte) (*prompb.TimeSeries, error)
+}
+
 // Serialize generates the JSON representation for a given Prometheus metric.
 func Serialize(s Serializer, req *prompb.WriteRequest) ([][]byte, error) {
 	result := [][]byte{}
 
 	for _, ts := range req.Timeseries {
+		if tss, ok := s.(TimeSeriesSerializer); ok {
+			data, err := tss.MarshalTimeSeries(ts)
+			if err != nil {
+				logrus.WithError(err).Errorln("couldn't marshal timeseries")
+			}
+
+			result = append(result, data)
+			continue
+		}
+
 		labels := make(map[string]string, len(ts.Labels))
 
 		for _, l := range ts.Labels {
@@ -65,6 +89,50 @@ func Serialize(s Serializer, req *prompb.WriteRequest) ([][]byte, error) {
 	return result, nil
 }
 
+// timeSeriesFromMetric builds a single sample time series from the metric representation used by Serialize.
+func timeSeriesFromMetric(metric map[string]interface{}) (*prompb.TimeSeries, error) {
+	timestamp, ok := metric["timestamp"].(string)
+	if !ok {
+		return nil, fmt.Errorf("metric has no timestamp")
+	}
+
+	epoch, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := metric["value"].(string)
+	if !ok {
+		return nil, fmt.Errorf("metric has no value")
+	}
+
+	sample, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, ok := metric["labels"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("metric has no labels")
+	}
+
+	ts := &prompb.TimeSeries{
+		Samples: []*prompb.Sample{
+			&prompb.Sample{Timestamp: epoch.UnixNano() / 1e6, Value: sample},
+		},
+	}
+
+	for name, value := range labels {
+		ts.Labels = append(ts.Labels, &prompb.Label{Name: name, Value: fmt.Sprint(value)})
+	}
+
+	sort.Slice(ts.Labels, func(i, j int) bool {
+		return ts.Labels[i].Name < ts.Labels[j].Name
+	})
+
+	return ts, nil
+}
+
 // JSONSerializer represents a metrics serializer that writes JSON
 type JSONSerializer struct {
 }
@@ -73,6 +141,15 @@ func (s *JSONSerializer) Marshal(metric map[string]interface{}) ([]byte, error)
 	return json.Marshal(metric)
 }
 
+func (s *JSONSerializer) Unmarshal(data []byte) (*prompb.TimeSeries, error) {
+	var metric map[string]interface{}
+	if err := json.Unmarshal(data, &metric); err != nil {
+		return nil, err
+	}
+
+	return timeSeriesFromMetric(metric)
+}
+
 func NewJSONSerializer() (*JSONSerializer, error) {
 	return &JSONSerializer{}, nil
 }
@@ -86,6 +163,20 @@ func (s *AvroJSONSerializer) Marshal(metric map[string]interface{}) ([]byte, err
 	return s.codec.TextualFromNative(nil, metric)
 }
 
+func (s *AvroJSONSerializer) Unmarshal(data []byte) (*prompb.TimeSeries, error) {
+	native, _, err := s.codec.NativeFromTextual(data)
+	if err != nil {
+		return nil, err
+	}
+
+	metric, ok := native.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected avro record %T", native)
+	}
+
+	return timeSeriesFromMetric(metric)
+}
+
 // NewAvroJSONSerializer builds a new instance of the AvroJSONSerializer
 func NewAvroJSONSerializer(schemaPath string) (*AvroJSONSerializer, error) {
 	schema, err := ioutil.ReadFile(schemaPath)
@@ -104,3 +195,88 @@ func NewAvroJSONSerializer(schemaPath string) (*AvroJSONSerializer, error) {
 		codec: codec,
 	}, nil
 }
+
+// AvroSerializer represents a metrics serializer that writes binary Avro
+// in the Confluent wire format: a zero magic byte, the 4-byte schema id and the Avro payload.
+type AvroSerializer struct {
+	codec    *goavro.Codec
+	schemaID int
+}
+
+func (s *AvroSerializer) Marshal(metric map[string]interface{}) ([]byte, error) {
+	buf := make([]byte, 5, 64)
+	binary.BigEndian.PutUint32(buf[1:], uint32(s.schemaID))
+
+	return s.codec.BinaryFromNative(buf, metric)
+}
+
+// Unmarshal reads a message in the Confluent wire format, the payload is decoded with the local schema.
+func (s *AvroSerializer) Unmarshal(data []byte) (*prompb.TimeSeries, error) {
+	if len(data) < 5 || data[0] != 0 {
+		return nil, fmt.Errorf("message is not in the confluent wire format")
+	}
+
+	native, _, err := s.codec.NativeFromBinary(data[5:])
+	if err != nil {
+		return nil, err
+	}
+
+	metric, ok := native.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected avro record %T", native)
+	}
+
+	return timeSeriesFromMetric(metric)
+}
+
+// NewAvroSerializer builds a new instance of the AvroSerializer,
+// registering the schema in the schema registry under the given subject.
+func NewAvroSerializer(schemaPath string, registry *SchemaRegistryClient, subject string) (*AvroSerializer, error) {
+	schema, err := ioutil.ReadFile(schemaPath)
+	if err != nil {
+		logrus.WithError(err).Errorln("couldn't read avro schema")
+		return nil, err
+	}
+
+	codec, err := goavro.NewCodec(string(schema))
+	if err != nil {
+		logrus.WithError(err).Errorln("couldn't create avro codec")
+		return nil, err
+	}
+
+	schemaID, err := registry.RegisterSchema(subject, codec.Schema())
+	if err != nil {
+		logrus.WithError(err).WithField("subject", subject).Errorln("couldn't register avro schema")
+		return nil, err
+	}
+
+	return &AvroSerializer{
+		codec:    codec,
+		schemaID: schemaID,
+	}, nil
+}
+
+// ProtobufSerializer represents a metrics serializer that writes each time series as prompb.TimeSeries
+type ProtobufSerializer struct {
+}
+
+func (s *ProtobufSerializer) Marshal(metric map[string]interface{}) ([]byte, error) {
+	return nil, fmt.Errorf("protobuf serializer marshals whole time series only")
+}
+
+func (s *ProtobufSerializer) MarshalTimeSeries(ts *prompb.TimeSeries) ([]byte, error) {
+	return proto.Marshal(ts)
+}
+
+func (s *ProtobufSerializer) Unmarshal(data []byte) (*prompb.TimeSeries, error) {
+	var ts prompb.TimeSeries
+	if err := proto.Unmarshal(data, &ts); err != nil {
+		return nil, err
+	}
+
+	return &ts, nil
+}
+
+func NewProtobufSerializer() (*ProtobufSerializer, error) {
+	return &ProtobufSerializer{}, nil
+}
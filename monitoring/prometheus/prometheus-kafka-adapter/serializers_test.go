@@ -1,9 +1,11 @@
 package main
 
 import (
+	"encoding/binary"
 	"math"
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
 )
@@ -85,6 +87,82 @@ func TestSerializeToAvro(t *testing.T) {
 	}
 }
 
+func TestSerializeToAvroBinary(t *testing.T) {
+	server, _ := NewSchemaRegistryStub(t, 7)
+	defer server.Close()
+
+	serializer, err := NewAvroSerializer("schemas/metric.avsc", NewSchemaRegistryClient(server.URL, "", ""), "metrics-value")
+	assert.Nil(t, err)
+
+	writeRequest := NewWriteRequest()
+	output, err := Serialize(serializer, writeRequest)
+	assert.Len(t, output, 2)
+	assert.Nil(t, err)
+
+	expectedSamples := []map[string]interface{}{
+		{"value": "456", "timestamp": "1970-01-01T00:00:00Z", "name": "foo", "labels": map[string]interface{}{"__name__": "foo", "labelfoo": "label-bar"}},
+		{"value": "+Inf", "timestamp": "1970-01-01T00:00:10Z", "name": "foo", "labels": map[string]interface{}{"__name__": "foo", "labelfoo": "label-bar"}},
+	}
+
+	for i, metric := range output {
+		assert.Equal(t, byte(0), metric[0], "wrong magic byte found")
+		assert.Equal(t, uint32(7), binary.BigEndian.Uint32(metric[1:5]), "wrong schema id found")
+
+		native, _, err := serializer.codec.NativeFromBinary(metric[5:])
+		assert.Nil(t, err)
+		assert.Equal(t, expectedSamples[i], native)
+	}
+}
+
+func TestSerializeToProtobuf(t *testing.T) {
+	serializer, err := NewProtobufSerializer()
+	assert.Nil(t, err)
+
+	writeRequest := NewWriteRequest()
+	output, err := Serialize(serializer, writeRequest)
+	assert.Len(t, output, 1)
+	assert.Nil(t, err)
+
+	var ts prompb.TimeSeries
+	assert.Nil(t, proto.Unmarshal(output[0], &ts))
+	assert.Equal(t, *writeRequest.Timeseries[0], ts)
+}
+
+func TestDeserialize(t *testing.T) {
+	server, _ := NewSchemaRegistryStub(t, 7)
+	defer server.Close()
+
+	jsonSerializer, err := NewJSONSerializer()
+	assert.Nil(t, err)
+	avroJSONSerializer, err := NewAvroJSONSerializer("schemas/metric.avsc")
+	assert.Nil(t, err)
+	avroSerializer, err := NewAvroSerializer("schemas/metric.avsc", NewSchemaRegistryClient(server.URL, "", ""), "metrics-value")
+	assert.Nil(t, err)
+
+	writeRequest := NewWriteRequest()
+	for _, serializer := range []Serializer{jsonSerializer, avroJSONSerializer, avroSerializer} {
+		output, err := Serialize(serializer, writeRequest)
+		assert.Nil(t, err)
+
+		for i, metric := range output {
+			ts, err := serializer.(Deserializer).Unmarshal(metric)
+			assert.Nil(t, err)
+			assert.Equal(t, writeRequest.Timeseries[0].Labels, ts.Labels)
+			assert.Equal(t, []*prompb.Sample{writeRequest.Timeseries[0].Samples[i]}, ts.Samples)
+		}
+	}
+
+	protobufSerializer, err := NewProtobufSerializer()
+	assert.Nil(t, err)
+
+	output, err := Serialize(protobufSerializer, writeRequest)
+	assert.Nil(t, err)
+
+	ts, err := protobufSerializer.Unmarshal(output[0])
+	assert.Nil(t, err)
+	assert.Equal(t, writeRequest.Timeseries[0], ts)
+}
+
 func BenchmarkSerializeToAvroJSON(b *testing.B) {
 	serializer, _ := NewAvroJSONSerializer("schemas/metric.avsc")
 	writeRequest := NewWriteRequest()
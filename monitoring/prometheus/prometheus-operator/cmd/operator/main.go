@@ -26,7 +26,9 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/coreos/prometheus-operator/pkg/admission"
 	alertmanagercontroller "github.com/coreos/prometheus-operator/pkg/alertmanager"
 	"github.com/coreos/prometheus-operator/pkg/api"
 	monitoring "github.com/coreos/prometheus-operator/pkg/apis/monitoring"
@@ -93,6 +95,8 @@ func (n namespaces) asSlice() []string {
 
 var (
 	cfg                prometheuscontroller.Config
+	webTLSCertFile     string
+	webTLSKeyFile      string
 	availableLogLevels = []string{
 		logLevelAll,
 		logLevelDebug,
@@ -136,6 +140,8 @@ func init() {
 	flagset.StringVar(&cfg.LocalHost, "localhost", "localhost", "EXPERIMENTAL (could be removed in future releases) - Host used to communicate between local services on a pod. Fixes issues where localhost resolves incorrectly.")
 	flagset.StringVar(&cfg.LogLevel, "log-level", logLevelInfo, fmt.Sprintf("Log level to use. Possible values: %s", strings.Join(availableLogLevels, ", ")))
 	flagset.StringVar(&cfg.LogFormat, "log-format", logFormatLogfmt, fmt.Sprintf("Log format to use. Possible values: %s", strings.Join(availableLogFormats, ", ")))
+	flagset.StringVar(&webTLSCertFile, "web-tls-cert-file", "", "Path to the TLS certificate served on the web port. Required for the PrometheusRule admission webhooks, which the Kubernetes API server only calls over HTTPS.")
+	flagset.StringVar(&webTLSKeyFile, "web-tls-key-file", "", "Path to the TLS private key matching --web-tls-cert-file.")
 	flagset.BoolVar(&cfg.ManageCRDs, "manage-crds", true, "Manage all CRDs with the Prometheus Operator.")
 	flagset.Parse(os.Args[1:])
 	cfg.Namespaces = ns.asSlice()
@@ -166,6 +172,11 @@ func Main() int {
 	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
 	logger = log.With(logger, "caller", log.DefaultCaller)
 
+	if (webTLSCertFile == "") != (webTLSKeyFile == "") {
+		fmt.Fprint(os.Stderr, "--web-tls-cert-file and --web-tls-key-file must be set together")
+		return 1
+	}
+
 	logger.Log("msg", fmt.Sprintf("Starting Prometheus Operator version '%v'.", version.Version))
 
 	po, err := prometheuscontroller.New(cfg, log.With(logger, "component", "prometheusoperator"))
@@ -187,7 +198,10 @@ func Main() int {
 		return 1
 	}
 
+	admit := admission.New(log.With(logger, "component", "admissionwebhook"))
+
 	web.Register(mux)
+	admit.Register(mux)
 	l, err := net.Listen("tcp", ":8080")
 	if err != nil {
 		fmt.Fprint(os.Stderr, "listening port 8080 failed", err)
@@ -227,6 +241,8 @@ func Main() int {
 		triggerByCounter.MustCurryWith(alertmanagerLabels),
 	)
 
+	admit.RegisterMetrics(r)
+
 	mux.Handle("/metrics", promhttp.HandlerFor(r, promhttp.HandlerOpts{}))
 	mux.Handle("/debug/pprof/", http.HandlerFunc(pprof.Index))
 	mux.Handle("/debug/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
@@ -241,7 +257,18 @@ func Main() int {
 	wg.Go(func() error { return ao.Run(ctx.Done()) })
 
 	srv := &http.Server{Handler: mux}
-	go srv.Serve(l)
+	wg.Go(func() error {
+		var err error
+		if webTLSCertFile != "" {
+			err = srv.ServeTLS(l, webTLSCertFile, webTLSKeyFile)
+		} else {
+			err = srv.Serve(l)
+		}
+		if err != http.ErrServerClosed {
+			return fmt.Errorf("serving web port 8080 failed: %v", err)
+		}
+		return nil
+	})
 
 	term := make(chan os.Signal)
 	signal.Notify(term, os.Interrupt, syscall.SIGTERM)
@@ -252,6 +279,12 @@ func Main() int {
 	case <-ctx.Done():
 	}
 
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		level.Warn(logger).Log("msg", "shutting down web server failed", "err", err)
+	}
+
 	cancel()
 	if err := wg.Wait(); err != nil {
 		logger.Log("msg", "Unhandled error received. Exiting...", "err", err)
@@ -73,6 +73,10 @@ func main() {
 			Kind:     cfg.Kind,
 			SpecName: cfg.SpecDefinitionName},
 		cfg.Group, cfg.Labels.LabelsMap, cfg.EnableValidation)
+	switch cfg.Kind {
+	case monitoringv1.PrometheusesKind, monitoringv1.AlertmanagersKind:
+		k8sutil.EnableStatusSubresource(crd)
+	}
 
 	err := crdutils.MarshallCrd(crd, cfg.OutputFormat)
 	if err != nil {
@@ -52,6 +52,8 @@ local k = import 'ksonnet/ksonnet.beta.3/k.libsonnet';
                                'prometheuses',
                                'prometheuses/finalizers',
                                'alertmanagers/finalizers',
+                               'prometheuses/status',
+                               'alertmanagers/status',
                                'servicemonitors',
                                'prometheusrules',
                              ]) +
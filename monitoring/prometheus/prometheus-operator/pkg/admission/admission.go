@@ -0,0 +1,316 @@
+// Copyright 2019 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	prometheusRuleValidatePath = "/admission-prometheusrules/validate"
+	prometheusRuleMutatePath   = "/admission-prometheusrules/mutate"
+)
+
+// Admission serves validating and mutating admission webhooks for
+// PrometheusRule objects, so that rule files Prometheus would refuse to load
+// never make it into the rule ConfigMaps.
+type Admission struct {
+	validationErrorsCounter prometheus.Counter
+	validationTriggered     prometheus.Counter
+	mutationTriggered       prometheus.Counter
+	logger                  log.Logger
+}
+
+// New returns a new Admission with its metrics initialized.
+func New(logger log.Logger) *Admission {
+	return &Admission{
+		validationErrorsCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_operator_rule_validation_errors_total",
+			Help: "Number of PrometheusRule objects rejected by the validating admission webhook",
+		}),
+		validationTriggered: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_operator_rule_validation_triggered_total",
+			Help: "Number of times a PrometheusRule object was validated by the admission webhook",
+		}),
+		mutationTriggered: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_operator_rule_mutation_triggered_total",
+			Help: "Number of times a PrometheusRule object was normalized by the mutating admission webhook",
+		}),
+		logger: logger,
+	}
+}
+
+// RegisterMetrics registers the admission webhook metrics with the given
+// registerer.
+func (a *Admission) RegisterMetrics(r prometheus.Registerer) {
+	r.MustRegister(
+		a.validationErrorsCounter,
+		a.validationTriggered,
+		a.mutationTriggered,
+	)
+}
+
+// Register adds the admission webhook handlers to the given mux.
+func (a *Admission) Register(mux *http.ServeMux) {
+	mux.HandleFunc(prometheusRuleValidatePath, a.servePrometheusRulesValidate)
+	mux.HandleFunc(prometheusRuleMutatePath, a.servePrometheusRulesMutate)
+}
+
+type admitFunc func(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse
+
+func (a *Admission) servePrometheusRulesValidate(w http.ResponseWriter, r *http.Request) {
+	a.serveAdmission(w, r, a.validatePrometheusRules)
+}
+
+func (a *Admission) servePrometheusRulesMutate(w http.ResponseWriter, r *http.Request) {
+	a.serveAdmission(w, r, a.mutatePrometheusRules)
+}
+
+func (a *Admission) serveAdmission(w http.ResponseWriter, r *http.Request, admit admitFunc) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+		http.Error(w, fmt.Sprintf("unsupported content type %q, expected application/json", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		level.Warn(a.logger).Log("msg", "failed to read admission request body", "err", err)
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	review := v1beta1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil {
+		level.Warn(a.logger).Log("msg", "failed to decode admission review", "err", err)
+		http.Error(w, "failed to decode admission review", http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "admission review contains no request", http.StatusBadRequest)
+		return
+	}
+
+	response := admit(review)
+	response.UID = review.Request.UID
+	review.Response = response
+	review.Request = nil
+
+	resp, err := json.Marshal(review)
+	if err != nil {
+		level.Error(a.logger).Log("msg", "failed to encode admission response", "err", err)
+		http.Error(w, "failed to encode admission response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(resp); err != nil {
+		level.Error(a.logger).Log("msg", "failed to write admission response", "err", err)
+	}
+}
+
+func (a *Admission) validatePrometheusRules(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
+	a.validationTriggered.Inc()
+
+	rule := &monitoringv1.PrometheusRule{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, rule); err != nil {
+		a.validationErrorsCounter.Inc()
+		return toAdmissionResponseFailure(errors.Wrap(err, "failed to decode PrometheusRule").Error())
+	}
+
+	if errs := ValidateRule(rule.Spec); len(errs) != 0 {
+		a.validationErrorsCounter.Inc()
+		msgs := make([]string, 0, len(errs))
+		for _, err := range errs {
+			msgs = append(msgs, err.Error())
+		}
+		level.Debug(a.logger).Log(
+			"msg", "invalid PrometheusRule rejected",
+			"namespace", ar.Request.Namespace,
+			"name", rule.Name,
+			"err", strings.Join(msgs, "; "),
+		)
+		return toAdmissionResponseFailure("invalid PrometheusRule: " + strings.Join(msgs, "; "))
+	}
+
+	return &v1beta1.AdmissionResponse{Allowed: true}
+}
+
+func (a *Admission) mutatePrometheusRules(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
+	a.mutationTriggered.Inc()
+
+	patch, err := normalizeRuleGroups(ar.Request.Object.Raw)
+	if err != nil {
+		return toAdmissionResponseFailure(err.Error())
+	}
+
+	response := &v1beta1.AdmissionResponse{Allowed: true}
+	if patch != nil {
+		patchType := v1beta1.PatchTypeJSONPatch
+		response.Patch = patch
+		response.PatchType = &patchType
+	}
+	return response
+}
+
+// ValidateRule checks that the given rule spec renders into a rule file that
+// Prometheus accepts. This covers PromQL syntax, duplicate group names,
+// durations and label and annotation templates.
+func ValidateRule(spec monitoringv1.PrometheusRuleSpec) []error {
+	content, err := yaml.Marshal(spec)
+	if err != nil {
+		return []error{errors.Wrap(err, "failed to marshal rule spec")}
+	}
+
+	_, errs := rulefmt.Parse(content)
+	return errs
+}
+
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// normalizeRuleGroups decodes the raw PrometheusRule and returns a JSON patch
+// replacing its groups with a normalized form, or nil if nothing changed.
+// Label and annotation values are converted to strings, durations are
+// rewritten in their canonical form and expressions are trimmed.
+func normalizeRuleGroups(raw []byte) ([]byte, error) {
+	var obj struct {
+		Spec struct {
+			Groups []map[string]interface{} `json:"groups"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, errors.Wrap(err, "failed to decode PrometheusRule")
+	}
+
+	changed := false
+	for _, group := range obj.Spec.Groups {
+		if normalizeDuration(group, "interval") {
+			changed = true
+		}
+
+		rules, ok := group["rules"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, r := range rules {
+			rule, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if normalizeDuration(rule, "for") {
+				changed = true
+			}
+			if expr, ok := rule["expr"].(string); ok && expr != strings.TrimSpace(expr) {
+				rule["expr"] = strings.TrimSpace(expr)
+				changed = true
+			}
+			for _, key := range []string{"labels", "annotations"} {
+				if normalizeStringMap(rule, key) {
+					changed = true
+				}
+			}
+		}
+	}
+
+	if !changed {
+		return nil, nil
+	}
+
+	return json.Marshal([]jsonPatchOperation{{
+		Op:    "replace",
+		Path:  "/spec/groups",
+		Value: obj.Spec.Groups,
+	}})
+}
+
+func normalizeDuration(obj map[string]interface{}, key string) bool {
+	s, ok := obj[key].(string)
+	if !ok {
+		return false
+	}
+	d, err := model.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		// Leave invalid durations untouched, the validating webhook
+		// rejects them with a proper error message.
+		return false
+	}
+	if d.String() == s {
+		return false
+	}
+	obj[key] = d.String()
+	return true
+}
+
+func normalizeStringMap(obj map[string]interface{}, key string) bool {
+	m, ok := obj[key].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	changed := false
+	for k, v := range m {
+		switch value := v.(type) {
+		case string:
+		case float64:
+			m[k] = strconv.FormatFloat(value, 'f', -1, 64)
+			changed = true
+		case bool:
+			m[k] = strconv.FormatBool(value)
+			changed = true
+		case nil:
+			m[k] = ""
+			changed = true
+		default:
+			m[k] = fmt.Sprintf("%v", value)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func toAdmissionResponseFailure(message string) *v1beta1.AdmissionResponse {
+	return &v1beta1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Code:    http.StatusBadRequest,
+			Status:  metav1.StatusFailure,
+			Message: message,
+		},
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2019 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"k8s.io/api/admission/v1beta1"
+)
+
+func TestValidatePrometheusRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		allowed bool
+		message string
+	}{
+		{
+			name: "ValidRule",
+			rule: `{
+				"groups": [{
+					"name": "test",
+					"rules": [{"alert": "Up", "expr": "up == 0", "for": "5m"}]
+				}]
+			}`,
+			allowed: true,
+		},
+		{
+			name: "InvalidExpression",
+			rule: `{
+				"groups": [{
+					"name": "test",
+					"rules": [{"alert": "Up", "expr": "sum(up"}]
+				}]
+			}`,
+			message: "could not parse expression",
+		},
+		{
+			name: "DuplicateGroupName",
+			rule: `{
+				"groups": [
+					{"name": "test", "rules": [{"record": "a", "expr": "up"}]},
+					{"name": "test", "rules": [{"record": "b", "expr": "up"}]}
+				]
+			}`,
+			message: "repeated in the same file",
+		},
+		{
+			name: "InvalidDuration",
+			rule: `{
+				"groups": [{
+					"name": "test",
+					"rules": [{"alert": "Up", "expr": "up == 0", "for": "5 minutes"}]
+				}]
+			}`,
+			message: "not a valid duration string",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := sendAdmissionReview(t, prometheusRuleValidatePath, tc.rule)
+			if resp.Allowed != tc.allowed {
+				t.Fatalf("expected allowed to be %v but got %v: %v", tc.allowed, resp.Allowed, resp.Result)
+			}
+			if tc.message != "" && !strings.Contains(resp.Result.Message, tc.message) {
+				t.Fatalf("expected message to contain %q but got %q", tc.message, resp.Result.Message)
+			}
+			if resp.UID != "test-uid" {
+				t.Fatalf("expected response UID to match request UID but got %q", resp.UID)
+			}
+		})
+	}
+}
+
+func TestMutatePrometheusRules(t *testing.T) {
+	t.Run("ShouldNormalizeRule", func(t *testing.T) {
+		resp := sendAdmissionReview(t, prometheusRuleMutatePath, `{
+			"groups": [{
+				"name": "test",
+				"interval": "60s",
+				"rules": [{
+					"alert": "Up",
+					"expr": "  up == 0\n",
+					"for": "120s",
+					"labels": {"severity": 3, "page": true}
+				}]
+			}]
+		}`)
+		if !resp.Allowed {
+			t.Fatalf("expected mutation to be allowed: %v", resp.Result)
+		}
+		if resp.PatchType == nil || *resp.PatchType != v1beta1.PatchTypeJSONPatch {
+			t.Fatalf("expected JSON patch type but got %v", resp.PatchType)
+		}
+
+		var patch []struct {
+			Op    string `json:"op"`
+			Path  string `json:"path"`
+			Value []struct {
+				Interval string `json:"interval"`
+				Rules    []struct {
+					Expr   string            `json:"expr"`
+					For    string            `json:"for"`
+					Labels map[string]string `json:"labels"`
+				} `json:"rules"`
+			} `json:"value"`
+		}
+		if err := json.Unmarshal(resp.Patch, &patch); err != nil {
+			t.Fatalf("expected patch to be a valid JSON patch: %v", err)
+		}
+		if len(patch) != 1 || patch[0].Path != "/spec/groups" {
+			t.Fatalf("expected a single patch of /spec/groups but got %s", resp.Patch)
+		}
+
+		group := patch[0].Value[0]
+		if group.Interval != "1m" {
+			t.Errorf("expected interval 1m but got %q", group.Interval)
+		}
+		rule := group.Rules[0]
+		if rule.Expr != "up == 0" {
+			t.Errorf("expected trimmed expression but got %q", rule.Expr)
+		}
+		if rule.For != "2m" {
+			t.Errorf("expected for 2m but got %q", rule.For)
+		}
+		if rule.Labels["severity"] != "3" || rule.Labels["page"] != "true" {
+			t.Errorf("expected labels to be converted to strings but got %v", rule.Labels)
+		}
+	})
+
+	t.Run("ShouldNotPatchNormalizedRule", func(t *testing.T) {
+		resp := sendAdmissionReview(t, prometheusRuleMutatePath, `{
+			"groups": [{
+				"name": "test",
+				"rules": [{"alert": "Up", "expr": "up == 0", "for": "5m", "labels": {"severity": "page"}}]
+			}]
+		}`)
+		if !resp.Allowed {
+			t.Fatalf("expected mutation to be allowed: %v", resp.Result)
+		}
+		if resp.Patch != nil {
+			t.Fatalf("expected no patch but got %s", resp.Patch)
+		}
+	})
+}
+
+func TestServeAdmissionRejectsInvalidRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	New(log.NewNopLogger()).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, prometheusRuleValidatePath, nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status %v but got %v", http.StatusMethodNotAllowed, w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, prometheusRuleValidatePath, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "text/plain")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected status %v but got %v", http.StatusUnsupportedMediaType, w.Code)
+	}
+}
+
+func sendAdmissionReview(t *testing.T, path, spec string) *v1beta1.AdmissionResponse {
+	mux := http.NewServeMux()
+	New(log.NewNopLogger()).Register(mux)
+
+	review := []byte(`{
+		"kind": "AdmissionReview",
+		"apiVersion": "admission.k8s.io/v1beta1",
+		"request": {
+			"uid": "test-uid",
+			"kind": {"group": "monitoring.coreos.com", "version": "v1", "kind": "PrometheusRule"},
+			"resource": {"group": "monitoring.coreos.com", "version": "v1", "resource": "prometheusrules"},
+			"namespace": "default",
+			"operation": "CREATE",
+			"object": {
+				"apiVersion": "monitoring.coreos.com/v1",
+				"kind": "PrometheusRule",
+				"metadata": {"name": "test", "namespace": "default"},
+				"spec": ` + spec + `
+			}
+		}
+	}`)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(review))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 but got %v: %v", w.Code, w.Body.String())
+	}
+
+	resp := v1beta1.AdmissionReview{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode admission review response: %v", err)
+	}
+	if resp.Response == nil {
+		t.Fatal("expected admission review to contain a response")
+	}
+	return resp.Response
+}
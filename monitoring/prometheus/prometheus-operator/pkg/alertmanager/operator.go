@@ -34,6 +34,7 @@ import (
 	"k8s.io/api/core/v1"
 	extensionsobj "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -455,15 +456,25 @@ func (c *Operator) sync(key string) error {
 
 	level.Info(c.logger).Log("msg", "sync alertmanager", "key", key)
 
+	err = c.reconcile(key, am)
+	if statusErr := c.updateStatus(am, err); statusErr != nil {
+		level.Warn(c.logger).Log("msg", "updating Alertmanager status failed", "key", key, "err", statusErr)
+	}
+	return err
+}
+
+// reconcile brings the objects managed for the given Alertmanager in line
+// with its spec.
+func (c *Operator) reconcile(key string, am *monitoringv1.Alertmanager) error {
 	// Create governing service if it doesn't exist.
 	svcClient := c.kclient.Core().Services(am.Namespace)
-	if err = k8sutil.CreateOrUpdateService(svcClient, makeStatefulSetService(am, c.config)); err != nil {
+	if err := k8sutil.CreateOrUpdateService(svcClient, makeStatefulSetService(am, c.config)); err != nil {
 		return errors.Wrap(err, "synchronizing governing service failed")
 	}
 
 	ssetClient := c.kclient.AppsV1beta2().StatefulSets(am.Namespace)
 	// Ensure we have a StatefulSet running Alertmanager deployed.
-	obj, exists, err = c.ssetInf.GetIndexer().GetByKey(alertmanagerKeyToStatefulSetKey(key))
+	obj, exists, err := c.ssetInf.GetIndexer().GetByKey(alertmanagerKeyToStatefulSetKey(key))
 	if err != nil {
 		return errors.Wrap(err, "retrieving statefulset failed")
 	}
@@ -503,6 +514,52 @@ func (c *Operator) sync(key string) error {
 	return nil
 }
 
+// updateStatus computes the replica counts and conditions of the given
+// Alertmanager and writes them back, unless nothing changed.
+func (c *Operator) updateStatus(am *monitoringv1.Alertmanager, reconcileErr error) error {
+	var conditions []monitoringv1.StatusCondition
+	if am.Status != nil {
+		conditions = append(conditions, am.Status.Conditions...)
+	}
+
+	current, _, err := AlertmanagerStatus(c.kclient, am)
+	if err != nil {
+		conditions = k8sutil.SetStatusCondition(conditions, monitoringv1.StatusCondition{
+			Type:    monitoringv1.Available,
+			Status:  v1.ConditionUnknown,
+			Reason:  "StatusUnknown",
+			Message: err.Error(),
+		})
+		current = &monitoringv1.AlertmanagerStatus{Paused: am.Spec.Paused}
+	} else {
+		desired := int32(1)
+		if am.Spec.Replicas != nil {
+			desired = *am.Spec.Replicas
+		}
+		conditions = k8sutil.SetStatusCondition(conditions, k8sutil.AvailableCondition(desired, current.AvailableReplicas))
+	}
+	conditions = k8sutil.SetStatusCondition(conditions, k8sutil.ReconciledCondition(reconcileErr))
+
+	current.ObservedGeneration = am.Generation
+	current.Conditions = conditions
+
+	if am.Status != nil && statusEqual(*am.Status, *current) {
+		return nil
+	}
+
+	am.Status = current
+	_, err = c.mclient.MonitoringV1().Alertmanagers(am.Namespace).UpdateStatus(am)
+	return err
+}
+
+// statusEqual reports whether both statuses are the same, regardless of the
+// order of their conditions.
+func statusEqual(a, b monitoringv1.AlertmanagerStatus) bool {
+	a.Conditions = k8sutil.SortedStatusConditions(a.Conditions)
+	b.Conditions = k8sutil.SortedStatusConditions(b.Conditions)
+	return apiequality.Semantic.DeepEqual(a, b)
+}
+
 func ListOptions(name string) metav1.ListOptions {
 	return metav1.ListOptions{
 		LabelSelector: fields.SelectorFromSet(fields.Set(map[string]string{
@@ -611,7 +668,7 @@ func (c *Operator) destroyAlertmanager(key string) error {
 
 func (c *Operator) createCRDs() error {
 	crds := []*extensionsobj.CustomResourceDefinition{
-		k8sutil.NewCustomResourceDefinition(c.config.CrdKinds.Alertmanager, c.config.CrdGroup, c.config.Labels.LabelsMap, c.config.EnableValidation),
+		k8sutil.EnableStatusSubresource(k8sutil.NewCustomResourceDefinition(c.config.CrdKinds.Alertmanager, c.config.CrdGroup, c.config.Labels.LabelsMap, c.config.EnableValidation)),
 	}
 
 	crdClient := c.crdclient.ApiextensionsV1beta1().CustomResourceDefinitions()
@@ -107,10 +107,21 @@ func (api *API) prometheusStatus(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	p.Status, _, err = prometheus.PrometheusStatus(api.kclient, p)
+	status, _, err := prometheus.PrometheusStatus(api.kclient, p)
 	if err != nil {
 		api.logger.Log("error", err)
 	}
+	if status != nil {
+		// Refresh the replica counts but keep the conditions and selected
+		// resources written by the operator.
+		if p.Status != nil {
+			status.ObservedGeneration = p.Status.ObservedGeneration
+			status.Conditions = p.Status.Conditions
+			status.ServiceMonitors = p.Status.ServiceMonitors
+			status.Rules = p.Status.Rules
+		}
+		p.Status = status
+	}
 
 	b, err := json.Marshal(p)
 	if err != nil {
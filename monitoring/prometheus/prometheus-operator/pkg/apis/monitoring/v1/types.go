@@ -53,9 +53,8 @@ type Prometheus struct {
 	// Specification of the desired behavior of the Prometheus cluster. More info:
 	// https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#spec-and-status
 	Spec PrometheusSpec `json:"spec"`
-	// Most recent observed status of the Prometheus cluster. Read-only. Not
-	// included when requesting from the apiserver, only from the Prometheus
-	// Operator API itself. More info:
+	// Most recent observed status of the Prometheus cluster. Read-only.
+	// Written by the Prometheus Operator after each reconciliation. More info:
 	// https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#spec-and-status
 	Status *PrometheusStatus `json:"status,omitempty"`
 }
@@ -246,9 +245,8 @@ type PrometheusSpec struct {
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
-// PrometheusStatus is the most recent observed status of the Prometheus cluster. Read-only. Not
-// included when requesting from the apiserver, only from the Prometheus
-// Operator API itself. More info:
+// PrometheusStatus is the most recent observed status of the Prometheus cluster. Read-only.
+// Written by the Prometheus Operator after each reconciliation. More info:
 // https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#spec-and-status
 // +k8s:openapi-gen=true
 type PrometheusStatus struct {
@@ -266,6 +264,58 @@ type PrometheusStatus struct {
 	AvailableReplicas int32 `json:"availableReplicas"`
 	// Total number of unavailable pods targeted by this Prometheus deployment.
 	UnavailableReplicas int32 `json:"unavailableReplicas"`
+	// The generation of the Prometheus object the status was computed for.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The latest available observations of the Prometheus deployment's state.
+	Conditions []StatusCondition `json:"conditions,omitempty"`
+	// ServiceMonitors selected by the ServiceMonitorSelector and whether
+	// they made it into the generated configuration.
+	ServiceMonitors []SelectedResource `json:"serviceMonitors,omitempty"`
+	// PrometheusRules selected by the RuleSelector and whether they made it
+	// into the rule files.
+	Rules []SelectedResource `json:"rules,omitempty"`
+}
+
+// StatusConditionType is the type of a StatusCondition.
+type StatusConditionType string
+
+const (
+	// Available indicates whether all pods of the deployment are running and
+	// ready.
+	Available StatusConditionType = "Available"
+	// Reconciled indicates whether the operator managed to bring the managed
+	// objects in line with the latest spec.
+	Reconciled StatusConditionType = "Reconciled"
+)
+
+// StatusCondition describes the state of a Prometheus or Alertmanager
+// deployment at a certain point.
+// +k8s:openapi-gen=true
+type StatusCondition struct {
+	// Type of the condition.
+	Type StatusConditionType `json:"type"`
+	// Status of the condition, one of True, False or Unknown.
+	Status v1.ConditionStatus `json:"status"`
+	// The last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// A machine readable, CamelCase reason for the last transition.
+	Reason string `json:"reason,omitempty"`
+	// A human readable message with details about the last transition.
+	Message string `json:"message,omitempty"`
+}
+
+// SelectedResource references an object selected for a Prometheus deployment
+// and whether it was accepted by the operator.
+// +k8s:openapi-gen=true
+type SelectedResource struct {
+	// Namespace of the selected object.
+	Namespace string `json:"namespace"`
+	// Name of the selected object.
+	Name string `json:"name"`
+	// Whether the object is part of the generated configuration.
+	Accepted bool `json:"accepted"`
+	// Why the object was rejected. Empty if it was accepted.
+	Reason string `json:"reason,omitempty"`
 }
 
 // AlertingSpec defines parameters for alerting configuration of Prometheus servers.
@@ -671,9 +721,8 @@ type Alertmanager struct {
 	// Specification of the desired behavior of the Alertmanager cluster. More info:
 	// https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#spec-and-status
 	Spec AlertmanagerSpec `json:"spec"`
-	// Most recent observed status of the Alertmanager cluster. Read-only. Not
-	// included when requesting from the apiserver, only from the Prometheus
-	// Operator API itself. More info:
+	// Most recent observed status of the Alertmanager cluster. Read-only.
+	// Written by the Prometheus Operator after each reconciliation. More info:
 	// https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#spec-and-status
 	Status *AlertmanagerStatus `json:"status,omitempty"`
 }
@@ -776,9 +825,8 @@ type AlertmanagerList struct {
 	Items []Alertmanager `json:"items"`
 }
 
-// AlertmanagerStatus is the most recent observed status of the Alertmanager cluster. Read-only. Not
-// included when requesting from the apiserver, only from the Prometheus
-// Operator API itself. More info:
+// AlertmanagerStatus is the most recent observed status of the Alertmanager cluster. Read-only.
+// Written by the Prometheus Operator after each reconciliation. More info:
 // https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#spec-and-status
 // +k8s:openapi-gen=true
 type AlertmanagerStatus struct {
@@ -796,6 +844,10 @@ type AlertmanagerStatus struct {
 	AvailableReplicas int32 `json:"availableReplicas"`
 	// Total number of unavailable pods targeted by this Alertmanager cluster.
 	UnavailableReplicas int32 `json:"unavailableReplicas"`
+	// The generation of the Alertmanager object the status was computed for.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The latest available observations of the Alertmanager cluster's state.
+	Conditions []StatusCondition `json:"conditions,omitempty"`
 }
 
 // NamespaceSelector is a selector for selecting either all namespaces or a
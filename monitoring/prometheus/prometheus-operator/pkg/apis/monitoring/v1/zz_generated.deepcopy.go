@@ -82,7 +82,7 @@ func (in *Alertmanager) DeepCopyInto(out *Alertmanager) {
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(AlertmanagerStatus)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -235,6 +235,13 @@ func (in *AlertmanagerSpec) DeepCopy() *AlertmanagerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertmanagerStatus) DeepCopyInto(out *AlertmanagerStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]StatusCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -405,7 +412,7 @@ func (in *Prometheus) DeepCopyInto(out *Prometheus) {
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(PrometheusStatus)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -683,6 +690,23 @@ func (in *PrometheusSpec) DeepCopy() *PrometheusSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusStatus) DeepCopyInto(out *PrometheusStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]StatusCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceMonitors != nil {
+		in, out := &in.ServiceMonitors, &out.ServiceMonitors
+		*out = make([]SelectedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]SelectedResource, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -922,6 +946,22 @@ func (in *RulesAlert) DeepCopy() *RulesAlert {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelectedResource) DeepCopyInto(out *SelectedResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectedResource.
+func (in *SelectedResource) DeepCopy() *SelectedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(SelectedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceMonitor) DeepCopyInto(out *ServiceMonitor) {
 	*out = *in
@@ -1005,6 +1045,23 @@ func (in *ServiceMonitorSpec) DeepCopy() *ServiceMonitorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusCondition) DeepCopyInto(out *StatusCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusCondition.
+func (in *StatusCondition) DeepCopy() *StatusCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
 	*out = *in
@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -194,6 +195,108 @@ func NewCustomResourceDefinition(crdKind monitoringv1.CrdKind, group string, lab
 	})
 }
 
+// EnableStatusSubresource enables the status subresource on the given custom
+// resource definition, so that status updates by the operator neither bump
+// the generation nor overwrite concurrent spec changes.
+func EnableStatusSubresource(crd *extensionsobj.CustomResourceDefinition) *extensionsobj.CustomResourceDefinition {
+	crd.Spec.Subresources = &extensionsobj.CustomResourceSubresources{
+		Status: &extensionsobj.CustomResourceSubresourceStatus{},
+	}
+	return crd
+}
+
+// SetStatusCondition adds or replaces the condition of the same type in the
+// given list. The last transition time is only updated when the status of
+// the condition changed.
+func SetStatusCondition(conditions []monitoringv1.StatusCondition, c monitoringv1.StatusCondition) []monitoringv1.StatusCondition {
+	for i := range conditions {
+		if conditions[i].Type != c.Type {
+			continue
+		}
+		if conditions[i].Status == c.Status {
+			c.LastTransitionTime = conditions[i].LastTransitionTime
+		} else if c.LastTransitionTime.IsZero() {
+			c.LastTransitionTime = metav1.Now()
+		}
+		conditions[i] = c
+		return conditions
+	}
+
+	if c.LastTransitionTime.IsZero() {
+		c.LastTransitionTime = metav1.Now()
+	}
+	return append(conditions, c)
+}
+
+// SortedStatusConditions returns a copy of the given conditions sorted by
+// type, or nil if there are none, so that statuses only differing in the
+// order of their conditions compare equal.
+func SortedStatusConditions(conditions []monitoringv1.StatusCondition) []monitoringv1.StatusCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	sorted := append([]monitoringv1.StatusCondition(nil), conditions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Type < sorted[j].Type
+	})
+	return sorted
+}
+
+// SortedSelectedResources returns a copy of the given resources sorted by
+// namespace and name, or nil if there are none. Empty lists are omitted from
+// the serialized status and read back as nil.
+func SortedSelectedResources(resources []monitoringv1.SelectedResource) []monitoringv1.SelectedResource {
+	if len(resources) == 0 {
+		return nil
+	}
+	sorted := append([]monitoringv1.SelectedResource(nil), resources...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// AvailableCondition returns the Available condition for a deployment with the
+// given number of desired and ready replicas.
+func AvailableCondition(desired, available int32) monitoringv1.StatusCondition {
+	c := monitoringv1.StatusCondition{
+		Type:    monitoringv1.Available,
+		Status:  v1.ConditionTrue,
+		Reason:  "AllPodsReady",
+		Message: fmt.Sprintf("%d/%d pods are ready", available, desired),
+	}
+	switch {
+	case available == 0 && desired > 0:
+		c.Status = v1.ConditionFalse
+		c.Reason = "NoPodReady"
+	case available < desired:
+		c.Status = v1.ConditionFalse
+		c.Reason = "SomePodsNotReady"
+	}
+	return c
+}
+
+// ReconciledCondition returns the Reconciled condition for the outcome of a
+// reconciliation.
+func ReconciledCondition(err error) monitoringv1.StatusCondition {
+	if err != nil {
+		return monitoringv1.StatusCondition{
+			Type:    monitoringv1.Reconciled,
+			Status:  v1.ConditionFalse,
+			Reason:  "ReconciliationFailed",
+			Message: err.Error(),
+		}
+	}
+	return monitoringv1.StatusCondition{
+		Type:   monitoringv1.Reconciled,
+		Status: v1.ConditionTrue,
+		Reason: "ReconciliationSucceeded",
+	}
+}
+
 // SanitizeVolumeName ensures that the given volume name is a valid DNS-1123 label
 // accepted by Kubernetes.
 func SanitizeVolumeName(name string) string {
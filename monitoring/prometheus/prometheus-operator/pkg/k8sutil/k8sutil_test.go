@@ -15,9 +15,14 @@
 package k8sutil
 
 import (
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
 
@@ -59,3 +64,55 @@ func Test_SanitizeVolumeName(t *testing.T) {
 		}
 	}
 }
+
+func TestSetStatusCondition(t *testing.T) {
+	then := metav1.NewTime(time.Now().Add(-time.Hour))
+	conditions := []monitoringv1.StatusCondition{
+		{Type: monitoringv1.Available, Status: v1.ConditionTrue, LastTransitionTime: then},
+		{Type: monitoringv1.Reconciled, Status: v1.ConditionTrue, LastTransitionTime: then},
+	}
+
+	conditions = SetStatusCondition(conditions, AvailableCondition(2, 2))
+	if len(conditions) != 2 {
+		t.Fatalf("expected 2 conditions but got %d", len(conditions))
+	}
+	if !conditions[0].LastTransitionTime.Equal(&then) {
+		t.Errorf("expected last transition time to be kept when the status did not change")
+	}
+	if conditions[0].Reason != "AllPodsReady" {
+		t.Errorf("expected reason to be updated but got %q", conditions[0].Reason)
+	}
+
+	conditions = SetStatusCondition(conditions, ReconciledCondition(errors.New("boom")))
+	if conditions[1].Status != v1.ConditionFalse || conditions[1].Message != "boom" {
+		t.Errorf("expected failed Reconciled condition but got %+v", conditions[1])
+	}
+	if conditions[1].LastTransitionTime.Equal(&then) {
+		t.Errorf("expected last transition time to be updated when the status changed")
+	}
+
+	conditions = SetStatusCondition(nil, ReconciledCondition(nil))
+	if len(conditions) != 1 || conditions[0].LastTransitionTime.IsZero() {
+		t.Errorf("expected new condition with a last transition time but got %+v", conditions)
+	}
+}
+
+func TestAvailableCondition(t *testing.T) {
+	cases := []struct {
+		desired, available int32
+		status             v1.ConditionStatus
+		reason             string
+	}{
+		{desired: 2, available: 2, status: v1.ConditionTrue, reason: "AllPodsReady"},
+		{desired: 2, available: 1, status: v1.ConditionFalse, reason: "SomePodsNotReady"},
+		{desired: 2, available: 0, status: v1.ConditionFalse, reason: "NoPodReady"},
+		{desired: 0, available: 0, status: v1.ConditionTrue, reason: "AllPodsReady"},
+	}
+
+	for i, c := range cases {
+		cond := AvailableCondition(c.desired, c.available)
+		if cond.Status != c.status || cond.Reason != c.reason {
+			t.Errorf("expected test case %d to be %v/%v but got %v/%v", i, c.status, c.reason, cond.Status, cond.Reason)
+		}
+	}
+}
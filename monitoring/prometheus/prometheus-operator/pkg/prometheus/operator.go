@@ -36,6 +36,7 @@ import (
 	"k8s.io/api/core/v1"
 	extensionsobj "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -969,16 +970,35 @@ func (c *Operator) sync(key string) error {
 
 	level.Info(c.logger).Log("msg", "sync prometheus", "key", key)
 
-	ruleConfigMapNames, err := c.createOrUpdateRuleConfigMaps(p)
+	status := &monitoringv1.PrometheusStatus{}
+	err = c.reconcile(key, p, status)
+	if statusErr := c.updateStatus(p, status, err); statusErr != nil {
+		level.Warn(c.logger).Log("msg", "updating Prometheus status failed", "key", key, "err", statusErr)
+	}
+	return err
+}
+
+// reconcile brings the objects managed for the given Prometheus in line with
+// its spec. The ServiceMonitors and PrometheusRules selected on the way are
+// recorded in status.
+func (c *Operator) reconcile(key string, p *monitoringv1.Prometheus, status *monitoringv1.PrometheusStatus) error {
+	ruleConfigMapNames, rules, err := c.createOrUpdateRuleConfigMaps(p)
 	if err != nil {
 		return err
 	}
+	status.Rules = rules
 
 	// If no service monitor selectors are configured, the user wants to
 	// manage configuration themselves.
 	if p.Spec.ServiceMonitorSelector != nil {
+		smons, err := c.selectServiceMonitors(p)
+		if err != nil {
+			return errors.Wrap(err, "selecting ServiceMonitors failed")
+		}
+		status.ServiceMonitors = selectedServiceMonitors(smons)
+
 		// We just always regenerate the configuration to be safe.
-		if err := c.createOrUpdateConfigurationSecret(p, ruleConfigMapNames); err != nil {
+		if err := c.createOrUpdateConfigurationSecret(p, smons, ruleConfigMapNames); err != nil {
 			return errors.Wrap(err, "creating config failed")
 		}
 	}
@@ -1007,7 +1027,7 @@ func (c *Operator) sync(key string) error {
 
 	ssetClient := c.kclient.AppsV1beta2().StatefulSets(p.Namespace)
 	// Ensure we have a StatefulSet running Prometheus deployed.
-	obj, exists, err = c.ssetInf.GetIndexer().GetByKey(prometheusKeyToStatefulSetKey(key))
+	obj, exists, err := c.ssetInf.GetIndexer().GetByKey(prometheusKeyToStatefulSetKey(key))
 	if err != nil {
 		return errors.Wrap(err, "retrieving statefulset failed")
 	}
@@ -1058,6 +1078,82 @@ func (c *Operator) sync(key string) error {
 	return nil
 }
 
+// updateStatus computes the replica counts and conditions of the given
+// Prometheus and writes them back together with the selected resources in
+// status, unless nothing changed.
+func (c *Operator) updateStatus(p *monitoringv1.Prometheus, status *monitoringv1.PrometheusStatus, reconcileErr error) error {
+	var conditions []monitoringv1.StatusCondition
+	if p.Status != nil {
+		conditions = append(conditions, p.Status.Conditions...)
+	}
+
+	current, _, err := PrometheusStatus(c.kclient, p)
+	if err != nil {
+		conditions = k8sutil.SetStatusCondition(conditions, monitoringv1.StatusCondition{
+			Type:    monitoringv1.Available,
+			Status:  v1.ConditionUnknown,
+			Reason:  "StatusUnknown",
+			Message: err.Error(),
+		})
+		current = &monitoringv1.PrometheusStatus{Paused: p.Spec.Paused}
+	} else {
+		desired := int32(1)
+		if p.Spec.Replicas != nil {
+			desired = *p.Spec.Replicas
+		}
+		conditions = k8sutil.SetStatusCondition(conditions, k8sutil.AvailableCondition(desired, current.AvailableReplicas))
+	}
+	conditions = k8sutil.SetStatusCondition(conditions, k8sutil.ReconciledCondition(reconcileErr))
+
+	current.ObservedGeneration = p.Generation
+	current.Conditions = conditions
+	current.ServiceMonitors = status.ServiceMonitors
+	current.Rules = status.Rules
+	if reconcileErr != nil && p.Status != nil {
+		// The selection is incomplete if the reconciliation failed early,
+		// keep what was reported before in that case.
+		if current.ServiceMonitors == nil {
+			current.ServiceMonitors = p.Status.ServiceMonitors
+		}
+		if current.Rules == nil {
+			current.Rules = p.Status.Rules
+		}
+	}
+
+	if p.Status != nil && statusEqual(*p.Status, *current) {
+		return nil
+	}
+
+	p.Status = current
+	_, err = c.mclient.MonitoringV1().Prometheuses(p.Namespace).UpdateStatus(p)
+	return err
+}
+
+// statusEqual reports whether both statuses are the same, regardless of the
+// order of their conditions and selected resources.
+func statusEqual(a, b monitoringv1.PrometheusStatus) bool {
+	for _, s := range []*monitoringv1.PrometheusStatus{&a, &b} {
+		s.Conditions = k8sutil.SortedStatusConditions(s.Conditions)
+		s.ServiceMonitors = k8sutil.SortedSelectedResources(s.ServiceMonitors)
+		s.Rules = k8sutil.SortedSelectedResources(s.Rules)
+	}
+	return apiequality.Semantic.DeepEqual(a, b)
+}
+
+// selectedServiceMonitors turns the selected ServiceMonitors into a sorted list
+// for the Prometheus status.
+func selectedServiceMonitors(smons map[string]*monitoringv1.ServiceMonitor) []monitoringv1.SelectedResource {
+	var selected []monitoringv1.SelectedResource
+	for _, smon := range smons {
+		selected = append(selected, monitoringv1.SelectedResource{
+			Namespace: smon.Namespace,
+			Name:      smon.Name,
+			Accepted:  true,
+		})
+	}
+	return k8sutil.SortedSelectedResources(selected)
+}
+
 func createSSetInputHash(p monitoringv1.Prometheus, c Config, ruleConfigMapNames []string) (string, error) {
 	hash, err := hashstructure.Hash(struct {
 		P monitoringv1.Prometheus
@@ -1292,12 +1388,7 @@ func (c *Operator) loadBasicAuthSecrets(
 
 }
 
-func (c *Operator) createOrUpdateConfigurationSecret(p *monitoringv1.Prometheus, ruleConfigMapNames []string) error {
-	smons, err := c.selectServiceMonitors(p)
-	if err != nil {
-		return errors.Wrap(err, "selecting ServiceMonitors failed")
-	}
-
+func (c *Operator) createOrUpdateConfigurationSecret(p *monitoringv1.Prometheus, smons map[string]*monitoringv1.ServiceMonitor, ruleConfigMapNames []string) error {
 	sClient := c.kclient.CoreV1().Secrets(p.Namespace)
 	SecretsInPromNS, err := sClient.List(metav1.ListOptions{})
 	if err != nil {
@@ -1434,7 +1525,7 @@ func (c *Operator) listMatchingNamespaces(selector labels.Selector) ([]string, e
 
 func (c *Operator) createCRDs() error {
 	crds := []*extensionsobj.CustomResourceDefinition{
-		k8sutil.NewCustomResourceDefinition(c.config.CrdKinds.Prometheus, c.config.CrdGroup, c.config.Labels.LabelsMap, c.config.EnableValidation),
+		k8sutil.EnableStatusSubresource(k8sutil.NewCustomResourceDefinition(c.config.CrdKinds.Prometheus, c.config.CrdGroup, c.config.Labels.LabelsMap, c.config.EnableValidation)),
 		k8sutil.NewCustomResourceDefinition(c.config.CrdKinds.ServiceMonitor, c.config.CrdGroup, c.config.Labels.LabelsMap, c.config.EnableValidation),
 		k8sutil.NewCustomResourceDefinition(c.config.CrdKinds.PrometheusRule, c.config.CrdGroup, c.config.Labels.LabelsMap, c.config.EnableValidation),
 	}
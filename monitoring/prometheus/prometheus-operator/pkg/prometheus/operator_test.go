@@ -137,3 +137,44 @@ func TestGetNodeAddresses(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusEqual(t *testing.T) {
+	a := monitoringv1.SelectedResource{Namespace: "default", Name: "a", Accepted: true}
+	b := monitoringv1.SelectedResource{Namespace: "default", Name: "b", Accepted: true}
+	available := monitoringv1.StatusCondition{Type: monitoringv1.Available, Status: v1.ConditionTrue}
+	reconciled := monitoringv1.StatusCondition{Type: monitoringv1.Reconciled, Status: v1.ConditionTrue}
+
+	cases := []struct {
+		name  string
+		s1    monitoringv1.PrometheusStatus
+		s2    monitoringv1.PrometheusStatus
+		equal bool
+	}{
+		{
+			name:  "nil and empty selections",
+			s1:    monitoringv1.PrometheusStatus{},
+			s2:    monitoringv1.PrometheusStatus{ServiceMonitors: []monitoringv1.SelectedResource{}, Rules: []monitoringv1.SelectedResource{}},
+			equal: true,
+		},
+		{
+			name:  "different order",
+			s1:    monitoringv1.PrometheusStatus{Conditions: []monitoringv1.StatusCondition{available, reconciled}, Rules: []monitoringv1.SelectedResource{a, b}},
+			s2:    monitoringv1.PrometheusStatus{Conditions: []monitoringv1.StatusCondition{reconciled, available}, Rules: []monitoringv1.SelectedResource{b, a}},
+			equal: true,
+		},
+		{
+			name:  "different selections",
+			s1:    monitoringv1.PrometheusStatus{ServiceMonitors: []monitoringv1.SelectedResource{a}},
+			s2:    monitoringv1.PrometheusStatus{ServiceMonitors: []monitoringv1.SelectedResource{a, b}},
+			equal: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if equal := statusEqual(c.s1, c.s2); equal != c.equal {
+				t.Fatalf("expected statusEqual to return %v, got %v", c.equal, equal)
+			}
+		})
+	}
+}
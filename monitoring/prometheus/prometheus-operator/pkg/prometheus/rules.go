@@ -21,7 +21,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/coreos/prometheus-operator/pkg/admission"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/coreos/prometheus-operator/pkg/k8sutil"
 
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,22 +40,25 @@ import (
 // large buffer.
 var maxConfigMapDataSize = int(float64(v1.MaxSecretSize) * 0.5)
 
-func (c *Operator) createOrUpdateRuleConfigMaps(p *monitoringv1.Prometheus) ([]string, error) {
+// createOrUpdateRuleConfigMaps writes the selected PrometheusRules into the
+// rule file ConfigMaps of the given Prometheus. It returns the names of the
+// ConfigMaps and which PrometheusRules were accepted or rejected.
+func (c *Operator) createOrUpdateRuleConfigMaps(p *monitoringv1.Prometheus) ([]string, []monitoringv1.SelectedResource, error) {
 	cClient := c.kclient.CoreV1().ConfigMaps(p.Namespace)
 
 	namespaces, err := c.selectRuleNamespaces(p)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	newRules, err := c.selectRules(p, namespaces)
+	newRules, selected, err := c.selectRules(p, namespaces)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	currentConfigMapList, err := cClient.List(prometheusRulesConfigMapSelector(p.Name))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	currentConfigMaps := currentConfigMapList.Items
 
@@ -75,12 +80,12 @@ func (c *Operator) createOrUpdateRuleConfigMaps(p *monitoringv1.Prometheus) ([]s
 		for _, cm := range currentConfigMaps {
 			currentConfigMapNames = append(currentConfigMapNames, cm.Name)
 		}
-		return currentConfigMapNames, nil
+		return currentConfigMapNames, selected, nil
 	}
 
 	newConfigMaps, err := makeRulesConfigMaps(p, newRules)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to make rules ConfigMaps")
+		return nil, nil, errors.Wrap(err, "failed to make rules ConfigMaps")
 	}
 
 	newConfigMapNames := []string{}
@@ -97,10 +102,10 @@ func (c *Operator) createOrUpdateRuleConfigMaps(p *monitoringv1.Prometheus) ([]s
 		for _, cm := range newConfigMaps {
 			_, err = cClient.Create(&cm)
 			if err != nil {
-				return nil, errors.Wrapf(err, "failed to create ConfigMap '%v'", cm.Name)
+				return nil, nil, errors.Wrapf(err, "failed to create ConfigMap '%v'", cm.Name)
 			}
 		}
-		return newConfigMapNames, nil
+		return newConfigMapNames, selected, nil
 	}
 
 	// Simply deleting old ConfigMaps and creating new ones for now. Could be
@@ -108,7 +113,7 @@ func (c *Operator) createOrUpdateRuleConfigMaps(p *monitoringv1.Prometheus) ([]s
 	for _, cm := range currentConfigMaps {
 		err := cClient.Delete(cm.Name, &metav1.DeleteOptions{})
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to delete current ConfigMap '%v'", cm.Name)
+			return nil, nil, errors.Wrapf(err, "failed to delete current ConfigMap '%v'", cm.Name)
 		}
 	}
 
@@ -120,11 +125,11 @@ func (c *Operator) createOrUpdateRuleConfigMaps(p *monitoringv1.Prometheus) ([]s
 	for _, cm := range newConfigMaps {
 		_, err = cClient.Create(&cm)
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to create new ConfigMap '%v'", cm.Name)
+			return nil, nil, errors.Wrapf(err, "failed to create new ConfigMap '%v'", cm.Name)
 		}
 	}
 
-	return newConfigMapNames, nil
+	return newConfigMapNames, selected, nil
 }
 
 func prometheusRulesConfigMapSelector(prometheusName string) metav1.ListOptions {
@@ -159,30 +164,54 @@ func (c *Operator) selectRuleNamespaces(p *monitoringv1.Prometheus) ([]string, e
 	return namespaces, nil
 }
 
-func (c *Operator) selectRules(p *monitoringv1.Prometheus, namespaces []string) (map[string]string, error) {
+func (c *Operator) selectRules(p *monitoringv1.Prometheus, namespaces []string) (map[string]string, []monitoringv1.SelectedResource, error) {
 	rules := map[string]string{}
+	var selected []monitoringv1.SelectedResource
 
 	ruleSelector, err := metav1.LabelSelectorAsSelector(p.Spec.RuleSelector)
 	if err != nil {
-		return rules, errors.Wrap(err, "convert rule label selector to selector")
+		return rules, nil, errors.Wrap(err, "convert rule label selector to selector")
 	}
 
 	for _, ns := range namespaces {
 		var marshalErr error
 		err := cache.ListAllByNamespace(c.ruleInf.GetIndexer(), ns, ruleSelector, func(obj interface{}) {
 			rule := obj.(*monitoringv1.PrometheusRule)
+			// Invalid rule files make Prometheus fail to reload and keep
+			// serving stale rules, hence skip them instead of breaking all
+			// rules of this Prometheus.
+			if errs := admission.ValidateRule(rule.Spec); len(errs) != 0 {
+				level.Warn(c.logger).Log(
+					"msg", "skipping invalid PrometheusRule",
+					"rule", fmt.Sprintf("%v/%v", rule.Namespace, rule.Name),
+					"err", errs[0],
+					"namespace", p.Namespace,
+					"prometheus", p.Name,
+				)
+				selected = append(selected, monitoringv1.SelectedResource{
+					Namespace: rule.Namespace,
+					Name:      rule.Name,
+					Reason:    errs[0].Error(),
+				})
+				return
+			}
 			content, err := yaml.Marshal(rule.Spec)
 			if err != nil {
 				marshalErr = err
 				return
 			}
 			rules[fmt.Sprintf("%v-%v.yaml", rule.Namespace, rule.Name)] = string(content)
+			selected = append(selected, monitoringv1.SelectedResource{
+				Namespace: rule.Namespace,
+				Name:      rule.Name,
+				Accepted:  true,
+			})
 		})
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if marshalErr != nil {
-			return nil, marshalErr
+			return nil, nil, marshalErr
 		}
 	}
 
@@ -198,7 +227,7 @@ func (c *Operator) selectRules(p *monitoringv1.Prometheus, namespaces []string)
 		"prometheus", p.Name,
 	)
 
-	return rules, nil
+	return rules, k8sutil.SortedSelectedResources(selected), nil
 }
 
 // makeRulesConfigMaps takes a Prometheus configuration and rule files and
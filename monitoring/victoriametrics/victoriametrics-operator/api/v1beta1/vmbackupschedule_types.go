@@ -0,0 +1,232 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	BackupTargetVMSingle  = "VMSingle"
+	BackupTargetVMCluster = "VMCluster"
+
+	BackupTypeFull        = "full"
+	BackupTypeIncremental = "incremental"
+
+	BackupPhaseRunning   = "Running"
+	BackupPhaseSucceeded = "Succeeded"
+	BackupPhaseFailed    = "Failed"
+
+	// incremental backups are uploaded into this path of destination
+	latestBackupPath = "latest"
+	fsBackupScheme   = "fs://"
+)
+
+// BackupTarget defines VMSingle or VMCluster, which data is backed up or restored
+type BackupTarget struct {
+	// Kind of target
+	// +kubebuilder:validation:Enum=VMSingle;VMCluster
+	Kind string `json:"kind"`
+	// Name of target at the same namespace
+	Name string `json:"name"`
+}
+
+// BackupStorage defines remote storage for backups
+type BackupStorage struct {
+	// Destination for backups: s3://bucket/path, gs://bucket/path or fs:///path for local filesystem
+	Destination string `json:"destination"`
+	// DestinationClaimName - PersistentVolumeClaim in the same namespace, it's mounted at fs:// Destination path.
+	// Its useful for local backups and tests.
+	// +optional
+	DestinationClaimName string `json:"destinationClaimName,omitempty"`
+	// Custom S3 endpoint for use with S3-compatible storages (e.g. MinIO). S3 is used if not set
+	// +optional
+	CustomS3Endpoint *string `json:"customS3Endpoint,omitempty"`
+	// CredentialsSecret is secret in the same namespace for access to remote storage
+	// The secret is mounted into /etc/vm/creds.
+	// +optional
+	CredentialsSecret *v1.SecretKeySelector `json:"credentialsSecret,omitempty"`
+}
+
+// IsLocal checks if backups are stored at filesystem
+func (bs BackupStorage) IsLocal() bool {
+	return strings.HasPrefix(bs.Destination, fsBackupScheme)
+}
+
+// LocalPath returns filesystem path of fs:// Destination
+func (bs BackupStorage) LocalPath() string {
+	return strings.TrimPrefix(bs.Destination, fsBackupScheme)
+}
+
+// SnapshotCreateURL returns url for snapshot creation at VictoriaMetrics, listening on given host:port address
+func SnapshotCreateURL(addr string, extraArgs map[string]string) string {
+	return fmt.Sprintf("http://%s%s", addr, buildPathWithPrefixFlag(extraArgs, snapshotCreate))
+}
+
+// SnapshotDeleteURL returns url for snapshot deletion at VictoriaMetrics, listening on given host:port address
+func SnapshotDeleteURL(addr string, extraArgs map[string]string) string {
+	return fmt.Sprintf("http://%s%s", addr, buildPathWithPrefixFlag(extraArgs, snapshotDelete))
+}
+
+// VMBackupScheduleSpec defines the desired state of VMBackupSchedule
+// +k8s:openapi-gen=true
+type VMBackupScheduleSpec struct {
+	// Target defines VMSingle or VMCluster, which data is backed up.
+	// Its data must be stored at persistent volume.
+	Target BackupTarget `json:"target"`
+	// Storage for backups
+	Storage BackupStorage `json:"storage"`
+	// FullSchedule in cron format, each full backup is uploaded into separate path of destination
+	// +optional
+	FullSchedule string `json:"fullSchedule,omitempty"`
+	// IncrementalSchedule in cron format, incremental backups upload only new data
+	// into the same path of destination
+	// +optional
+	IncrementalSchedule string `json:"incrementalSchedule,omitempty"`
+	// KeepLastFull defines count of full backups to keep, older backups are deleted.
+	// All backups are kept if not set.
+	// +optional
+	KeepLastFull *int32 `json:"keepLastFull,omitempty"`
+	// Suspend disables creation of new backups
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Defines number of concurrent workers. Higher concurrency may reduce backup duration (default 10)
+	// +optional
+	Concurrency *int32 `json:"concurrency,omitempty"`
+	// Image - docker image settings for vmbackup
+	// +optional
+	Image Image `json:"image,omitempty"`
+	// Resources container resource request and limits, https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	// extra args for vmbackup like maxBytesPerSecond
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+}
+
+// VMBackupScheduleStatus defines the observed state of VMBackupSchedule
+type VMBackupScheduleStatus struct {
+	// LastFullScheduleTime - the last time, when full backup was scheduled
+	// +optional
+	LastFullScheduleTime *metav1.Time `json:"lastFullScheduleTime,omitempty"`
+	// LastIncrementalScheduleTime - the last time, when incremental backup was scheduled
+	// +optional
+	LastIncrementalScheduleTime *metav1.Time `json:"lastIncrementalScheduleTime,omitempty"`
+	// Backups - created backups, sorted by start time
+	// +optional
+	Backups []BackupRecord `json:"backups,omitempty"`
+	// Reason of the last failure
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// BackupRecord defines backup, made by VMBackupSchedule
+type BackupRecord struct {
+	// Name of backup, its used as name of backup jobs
+	Name string `json:"name"`
+	// Type of backup - full or incremental
+	Type string `json:"type"`
+	// Destination of backup, for VMCluster each vmstorage node is backed up into own sub-path
+	Destination string `json:"destination"`
+	// Phase - Running, Succeeded or Failed
+	Phase string `json:"phase"`
+	// StartTime of backup
+	StartTime metav1.Time `json:"startTime"`
+	// CompletionTime of backup
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// VMBackupSchedule is the Schema for the vmbackupschedules API
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=vmbackupschedules,scope=Namespaced
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.target.name"
+// +kubebuilder:printcolumn:name="Full Schedule",type="string",JSONPath=".spec.fullSchedule"
+// +kubebuilder:printcolumn:name="Incremental Schedule",type="string",JSONPath=".spec.incrementalSchedule"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type VMBackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VMBackupScheduleSpec   `json:"spec"`
+	Status VMBackupScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// VMBackupScheduleList contains a list of VMBackupSchedule
+type VMBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VMBackupSchedule `json:"items"`
+}
+
+func (cr *VMBackupSchedule) AsOwner() []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		{
+			APIVersion:         cr.APIVersion,
+			Kind:               cr.Kind,
+			Name:               cr.Name,
+			UID:                cr.UID,
+			Controller:         pointer.BoolPtr(true),
+			BlockOwnerDeletion: pointer.BoolPtr(true),
+		},
+	}
+}
+
+// PrefixedName returns name prefix for backup jobs
+func (cr VMBackupSchedule) PrefixedName() string {
+	return fmt.Sprintf("vmbackup-%s", cr.Name)
+}
+
+// SelectorLabels returns labels for backup jobs
+func (cr VMBackupSchedule) SelectorLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":      "vmbackup",
+		"app.kubernetes.io/instance":  cr.Name,
+		"app.kubernetes.io/component": "monitoring",
+		"managed-by":                  "vm-operator",
+	}
+}
+
+// BackupDestination returns destination path for backup of given type,
+// each full backup has own path, incremental backups share the same one.
+func (cr VMBackupSchedule) BackupDestination(backupType, backupName string) string {
+	if backupType == BackupTypeIncremental {
+		return fmt.Sprintf("%s/%s", strings.TrimSuffix(cr.Spec.Storage.Destination, "/"), latestBackupPath)
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(cr.Spec.Storage.Destination, "/"), backupName)
+}
+
+// LatestSucceededBackup returns the last succeeded backup
+func (cr VMBackupSchedule) LatestSucceededBackup() *BackupRecord {
+	for i := len(cr.Status.Backups) - 1; i >= 0; i-- {
+		if cr.Status.Backups[i].Phase == BackupPhaseSucceeded {
+			return &cr.Status.Backups[i]
+		}
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&VMBackupSchedule{}, &VMBackupScheduleList{})
+}
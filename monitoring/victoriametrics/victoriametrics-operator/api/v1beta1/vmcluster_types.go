@@ -20,6 +20,16 @@ const (
 	SelectRollingUpdateFailed = "failed to perform rolling update on vmSelect"
 	SelectCreationFailed      = "failed to create vmSelect statefulset"
 	InsertCreationFailed      = "failed to create vmInsert deployment"
+
+	// StorageScaleDownRefuse - vmstorage scale down isn't performed
+	StorageScaleDownRefuse = "Refuse"
+	// StorageScaleDownDrain - vmstorage nodes are removed from vminsert first,
+	// and from vmselect after retention period passes
+	StorageScaleDownDrain = "Drain"
+
+	StorageScaleDownPhaseRefused     = "Refused"
+	StorageScaleDownPhaseDraining    = "Draining"
+	StorageScaleDownPhaseScalingDown = "ScalingDown"
 )
 
 // VMClusterSpec defines the desired state of VMCluster
@@ -87,6 +97,26 @@ type VMClusterStatus struct {
 	LastSync        string `json:"lastSync,omitempty"`
 	ClusterStatus   string `json:"clusterStatus"`
 	Reason          string `json:"reason,omitempty"`
+	// StorageScaleDown reports progress of vmstorage scale down
+	// +optional
+	StorageScaleDown *VMStorageScaleDownStatus `json:"storageScaleDown,omitempty"`
+}
+
+// VMStorageScaleDownStatus defines the observed state of vmstorage scale down
+type VMStorageScaleDownStatus struct {
+	// Phase of scale down - Refused, Draining or ScalingDown
+	Phase string `json:"phase"`
+	// FromReplicas - count of vmstorage nodes before scale down
+	FromReplicas int32 `json:"fromReplicas"`
+	// TargetReplicas - desired count of vmstorage nodes
+	TargetReplicas int32 `json:"targetReplicas"`
+	// DrainStartedAt - time, when removed vmstorage nodes were excluded from vminsert
+	// +optional
+	DrainStartedAt *metav1.Time `json:"drainStartedAt,omitempty"`
+	// DrainFinishAt - time, when retention period for data at removed vmstorage nodes passes
+	// and nodes can be excluded from vmselect
+	// +optional
+	DrainFinishAt *metav1.Time `json:"drainFinishAt,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -438,6 +468,14 @@ type VMStorage struct {
 	// +optional
 	SchedulerName string `json:"schedulerName,omitempty"`
 
+	// ScaleDownStrategy defines, how decreasing of ReplicaCount is handled.
+	// Refuse - default, vmstorage isn't scaled down, it's reported at status.
+	// Drain - removed vmstorage nodes are excluded from vminsert at first,
+	// they are kept at vmselect until retention period passes and only then statefulset is scaled down.
+	// +optional
+	// +kubebuilder:validation:Enum=Refuse;Drain
+	ScaleDownStrategy string `json:"scaleDownStrategy,omitempty"`
+
 	//Port for health check connetions
 	Port string `json:"port,omitempty"`
 
@@ -0,0 +1,120 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	RestorePhaseRestoring = "Restoring"
+	RestorePhaseSucceeded = "Succeeded"
+	RestorePhaseFailed    = "Failed"
+
+	// RestoreAnnotation is set at pod template of restored target,
+	// its value is uid of VMRestore.
+	RestoreAnnotation = "operator.victoriametrics.com/restore"
+)
+
+// VMRestoreSpec defines the desired state of VMRestore
+// +k8s:openapi-gen=true
+type VMRestoreSpec struct {
+	// Target defines VMSingle or VMCluster, which data is restored.
+	// Its pods are restarted and data is restored by vmrestore init container before start.
+	Target BackupTarget `json:"target"`
+	// BackupScheduleName - VMBackupSchedule at the same namespace, its storage is used for restore.
+	// The latest succeeded backup is restored if Source isn't set.
+	// +optional
+	BackupScheduleName string `json:"backupScheduleName,omitempty"`
+	// Storage with backups, required if BackupScheduleName isn't set
+	// +optional
+	Storage *BackupStorage `json:"storage,omitempty"`
+	// Source - path of backup at storage, like s3://bucket/path/20201019120000.
+	// For VMCluster each vmstorage node is restored from own sub-path.
+	// +optional
+	Source string `json:"source,omitempty"`
+	// Defines number of concurrent workers. Higher concurrency may reduce restore duration (default 10)
+	// +optional
+	Concurrency *int32 `json:"concurrency,omitempty"`
+	// Image - docker image settings for vmrestore
+	// +optional
+	Image Image `json:"image,omitempty"`
+	// Resources container resource request and limits, https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	// extra args for vmrestore like maxBytesPerSecond
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+}
+
+// VMRestoreStatus defines the observed state of VMRestore
+type VMRestoreStatus struct {
+	// Phase - Restoring, Succeeded or Failed
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// Source - path of restored backup
+	// +optional
+	Source string `json:"source,omitempty"`
+	// StartTime of restore
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime of restore
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Reason of failure
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// VMRestore is the Schema for the vmrestores API
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=vmrestores,scope=Namespaced
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.target.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".status.source"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type VMRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VMRestoreSpec   `json:"spec"`
+	Status VMRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// VMRestoreList contains a list of VMRestore
+type VMRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VMRestore `json:"items"`
+}
+
+// IsActive checks if restore is in progress
+func (cr VMRestore) IsActive() bool {
+	return cr.Status.Phase == RestorePhaseRestoring
+}
+
+// IsFinished checks if restore succeeded or failed
+func (cr VMRestore) IsFinished() bool {
+	return cr.Status.Phase == RestorePhaseSucceeded || cr.Status.Phase == RestorePhaseFailed
+}
+
+func init() {
+	SchemeBuilder.Register(&VMRestore{}, &VMRestoreList{})
+}
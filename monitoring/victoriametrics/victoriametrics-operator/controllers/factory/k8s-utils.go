@@ -3,6 +3,7 @@ package factory
 import (
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -19,6 +20,18 @@ func SanitizeVolumeName(name string) string {
 	return strings.Trim(name, "-")
 }
 
+// truncateName shortens name to maxLength, the cut off part is replaced with hash of the full name,
+// so different long names remain unique after truncation.
+func truncateName(name string, maxLength int) string {
+	if len(name) <= maxLength {
+		return name
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	suffix := fmt.Sprintf("-%08x", h.Sum32())
+	return strings.TrimRight(name[:maxLength-len(suffix)], "-.") + suffix
+}
+
 // MergePatchContainers adds patches to base using a strategic merge patch and iterating by container name, failing on the first error
 func MergePatchContainers(base, patches []v1.Container) ([]v1.Container, error) {
 	var out []v1.Container
@@ -38,6 +38,14 @@ func testGetScheme() *runtime.Scheme {
 		&victoriametricsv1beta1.VMProbe{},
 		&victoriametricsv1beta1.VMProbeList{},
 	)
+	s.AddKnownTypes(victoriametricsv1beta1.GroupVersion,
+		&victoriametricsv1beta1.VMCluster{},
+		&victoriametricsv1beta1.VMClusterList{},
+		&victoriametricsv1beta1.VMBackupSchedule{},
+		&victoriametricsv1beta1.VMBackupScheduleList{},
+		&victoriametricsv1beta1.VMRestore{},
+		&victoriametricsv1beta1.VMRestoreList{},
+	)
 	return s
 }
 
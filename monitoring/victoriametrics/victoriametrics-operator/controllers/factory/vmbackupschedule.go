@@ -0,0 +1,613 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/operator/api/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	backupRecordLabel           = "operator.victoriametrics.com/backup"
+	backupCleanupLabel          = "operator.victoriametrics.com/backup-cleanup"
+	backupCredsVolumeName       = "backup-creds"
+	backupDestinationVolumeName = "backup-destination"
+	backupDataVolumeName        = "vm-data"
+	backupTimeFormat            = "20060102150405"
+	backupJobBackoffLimit       = int32(3)
+	// incremental backups share the same destination, so only a few last records are kept at status
+	maxIncrementalBackupRecords = 5
+	// backup jobs may be missing at cache right after creation
+	backupJobsLookupTimeout = time.Minute
+	// due backup is postponed until running backup finishes
+	backupRetryInterval = time.Minute
+)
+
+// backupNode describes pod of backup target, which data is stored at persistent volume.
+type backupNode struct {
+	podName   string
+	claimName string
+	// host:port of VictoriaMetrics http api
+	addr      string
+	extraArgs map[string]string
+	// backup job is scheduled to the node of target pod, since persistent volume may be local
+	affinityLabels map[string]string
+}
+
+// backupTarget describes VMSingle or VMCluster, which data is backed up or restored.
+type backupTarget struct {
+	nodes       []backupNode
+	podSelector map[string]string
+	dataVolume  string
+	dataPath    string
+	// each node of cluster has own sub-path at backup destination
+	isCluster bool
+}
+
+// getBackupTarget builds backupTarget for VMSingle or VMCluster, its data must be stored at persistent volumes.
+func getBackupTarget(ctx context.Context, rclient client.Client, target v1beta1.BackupTarget, namespace string, c *config.BaseOperatorConf) (*backupTarget, error) {
+	switch target.Kind {
+	case v1beta1.BackupTargetVMSingle:
+		vmSingle := &v1beta1.VMSingle{}
+		if err := rclient.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: namespace}, vmSingle); err != nil {
+			return nil, fmt.Errorf("cannot get vmsingle %s: %w", target.Name, err)
+		}
+		if vmSingle.Spec.Storage == nil {
+			return nil, fmt.Errorf("vmsingle %s has no persistent storage", target.Name)
+		}
+		port := vmSingle.Spec.Port
+		if port == "" {
+			port = c.VMSingleDefault.Port
+		}
+		return &backupTarget{
+			nodes: []backupNode{{
+				claimName:      vmSingle.PrefixedName(),
+				addr:           fmt.Sprintf("%s.%s.svc.%s:%s", vmSingle.PrefixedName(), namespace, c.ClusterDomainName, port),
+				extraArgs:      vmSingle.Spec.ExtraArgs,
+				affinityLabels: vmSingle.SelectorLabels(),
+			}},
+			podSelector: vmSingle.SelectorLabels(),
+			dataVolume:  vmDataVolumeName,
+			dataPath:    vmSingleDataDir,
+		}, nil
+	case v1beta1.BackupTargetVMCluster:
+		cluster := &v1beta1.VMCluster{}
+		if err := rclient.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: namespace}, cluster); err != nil {
+			return nil, fmt.Errorf("cannot get vmcluster %s: %w", target.Name, err)
+		}
+		vmStorage := cluster.Spec.VMStorage
+		if vmStorage == nil || vmStorage.ReplicaCount == nil {
+			return nil, fmt.Errorf("vmcluster %s has no vmstorage", target.Name)
+		}
+		if vmStorage.Storage == nil || vmStorage.Storage.EmptyDir != nil {
+			return nil, fmt.Errorf("vmstorage of vmcluster %s has no persistent storage", target.Name)
+		}
+		claimTemplateName := vmStorage.Storage.VolumeClaimTemplate.Name
+		if claimTemplateName == "" {
+			claimTemplateName = vmStorage.GetStorageVolumeName()
+		}
+		port := vmStorage.Port
+		if port == "" {
+			port = c.VMClusterDefault.VMStorageDefault.Port
+		}
+		dataPath := vmStorage.StorageDataPath
+		if dataPath == "" {
+			dataPath = vmStorageDefaultDBPath
+		}
+		stsName := vmStorage.GetNameWithPrefix(cluster.Name)
+		bt := &backupTarget{
+			podSelector: cluster.VMStorageSelectorLabels(),
+			dataVolume:  vmStorage.GetStorageVolumeName(),
+			dataPath:    dataPath,
+			isCluster:   true,
+		}
+		for i := int32(0); i < vmStorageReplicas(cluster); i++ {
+			podName := fmt.Sprintf("%s-%d", stsName, i)
+			bt.nodes = append(bt.nodes, backupNode{
+				podName:        podName,
+				claimName:      fmt.Sprintf("%s-%s", claimTemplateName, podName),
+				addr:           fmt.Sprintf("%s.%s.%s.svc.%s:%s", podName, stsName, namespace, c.ClusterDomainName, port),
+				extraArgs:      vmStorage.ExtraArgs,
+				affinityLabels: map[string]string{"statefulset.kubernetes.io/pod-name": podName},
+			})
+		}
+		return bt, nil
+	default:
+		return nil, fmt.Errorf("unsupported backup target kind: %q", target.Kind)
+	}
+}
+
+// backupStorageVolumes returns volumes and mounts, which are needed for access to backup storage.
+func backupStorageVolumes(storage v1beta1.BackupStorage) ([]corev1.Volume, []corev1.VolumeMount) {
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	if storage.CredentialsSecret != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: backupCredsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: storage.CredentialsSecret.Name,
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      backupCredsVolumeName,
+			MountPath: vmBackuperCreds,
+			ReadOnly:  true,
+		})
+	}
+	if storage.IsLocal() && storage.DestinationClaimName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: backupDestinationVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: storage.DestinationClaimName,
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      backupDestinationVolumeName,
+			MountPath: storage.LocalPath(),
+		})
+	}
+	return volumes, mounts
+}
+
+// backupStorageArgs returns vmbackup and vmrestore flags for access to backup storage.
+func backupStorageArgs(storage v1beta1.BackupStorage, concurrency *int32) []string {
+	var args []string
+	if storage.CredentialsSecret != nil {
+		args = append(args, fmt.Sprintf("-credsFilePath=%s/%s", vmBackuperCreds, storage.CredentialsSecret.Key))
+	}
+	if storage.CustomS3Endpoint != nil {
+		args = append(args, fmt.Sprintf("-customS3Endpoint=%s", *storage.CustomS3Endpoint))
+	}
+	if concurrency != nil {
+		args = append(args, fmt.Sprintf("-concurrency=%d", *concurrency))
+	}
+	return args
+}
+
+// genVMBackupJobs generates jobs, which upload data of backup target into backup destination.
+// VMSingle is backed up by single job, VMCluster by a job per vmstorage node.
+func genVMBackupJobs(cr *v1beta1.VMBackupSchedule, record v1beta1.BackupRecord, target *backupTarget, c *config.BaseOperatorConf) []*batchv1.Job {
+	image := cr.Spec.Image
+	if image.Repository == "" {
+		image.Repository = c.VMUtilsDefault.BackupImage
+	}
+	if image.Tag == "" {
+		image.Tag = c.VMUtilsDefault.Version
+	}
+	if image.PullPolicy == "" {
+		image.PullPolicy = corev1.PullIfNotPresent
+	}
+	storageVolumes, storageMounts := backupStorageVolumes(cr.Spec.Storage)
+
+	jobs := make([]*batchv1.Job, 0, len(target.nodes))
+	for i, node := range target.nodes {
+		dst := record.Destination
+		if target.isCluster {
+			dst = fmt.Sprintf("%s/%s", record.Destination, node.podName)
+		}
+		args := []string{
+			fmt.Sprintf("-storageDataPath=%s", target.dataPath),
+			fmt.Sprintf("-dst=%s", dst),
+			fmt.Sprintf("-snapshot.createURL=%s", v1beta1.SnapshotCreateURL(node.addr, node.extraArgs)),
+			fmt.Sprintf("-snapshot.deleteURL=%s", v1beta1.SnapshotDeleteURL(node.addr, node.extraArgs)),
+		}
+		args = append(args, backupStorageArgs(cr.Spec.Storage, cr.Spec.Concurrency)...)
+		for arg, value := range cr.Spec.ExtraArgs {
+			args = append(args, fmt.Sprintf("-%s=%s", arg, value))
+		}
+
+		volumes := []corev1.Volume{
+			{
+				Name: backupDataVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: node.claimName,
+					},
+				},
+			},
+		}
+		volumes = append(volumes, storageVolumes...)
+		mounts := []corev1.VolumeMount{
+			{
+				Name:      backupDataVolumeName,
+				MountPath: target.dataPath,
+				ReadOnly:  true,
+			},
+		}
+		mounts = append(mounts, storageMounts...)
+
+		labels := cr.SelectorLabels()
+		labels[backupRecordLabel] = record.Name
+		jobs = append(jobs, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            backupJobName(record, target, i),
+				Namespace:       cr.Namespace,
+				Labels:          c.Labels.Merge(labels),
+				OwnerReferences: cr.AsOwner(),
+			},
+			Spec: batchv1.JobSpec{
+				BackoffLimit: pointer.Int32Ptr(backupJobBackoffLimit),
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: labels,
+					},
+					Spec: corev1.PodSpec{
+						RestartPolicy: corev1.RestartPolicyOnFailure,
+						Volumes:       volumes,
+						Affinity: &corev1.Affinity{
+							PodAffinity: &corev1.PodAffinity{
+								RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+									{
+										LabelSelector: &metav1.LabelSelector{MatchLabels: node.affinityLabels},
+										TopologyKey:   "kubernetes.io/hostname",
+									},
+								},
+							},
+						},
+						Containers: []corev1.Container{
+							{
+								Name:                     "vmbackup",
+								Image:                    fmt.Sprintf("%s:%s", image.Repository, image.Tag),
+								ImagePullPolicy:          image.PullPolicy,
+								Args:                     args,
+								VolumeMounts:             mounts,
+								Resources:                cr.Spec.Resources,
+								TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	return jobs
+}
+
+// genVMBackupCleanupJob generates job, which removes full backup from its destination.
+// Local backups are removed with rm and S3 backups with aws-cli.
+func genVMBackupCleanupJob(cr *v1beta1.VMBackupSchedule, record v1beta1.BackupRecord, c *config.BaseOperatorConf) (*batchv1.Job, error) {
+	storage := cr.Spec.Storage
+	volumes, mounts := backupStorageVolumes(storage)
+	var container corev1.Container
+	switch {
+	case storage.IsLocal():
+		if storage.DestinationClaimName == "" {
+			return nil, fmt.Errorf("destinationClaimName is required for cleanup of local backup %s", record.Destination)
+		}
+		container = corev1.Container{
+			Name:    "cleanup",
+			Image:   c.VMUtilsDefault.FSCleanupImage,
+			Command: []string{"rm", "-rf", strings.TrimPrefix(record.Destination, "fs://")},
+		}
+	case strings.HasPrefix(record.Destination, "s3://"):
+		args := []string{"s3", "rm", "--recursive", strings.TrimSuffix(record.Destination, "/") + "/"}
+		if storage.CustomS3Endpoint != nil {
+			args = append(args, "--endpoint-url", *storage.CustomS3Endpoint)
+		}
+		container = corev1.Container{
+			Name:  "cleanup",
+			Image: c.VMUtilsDefault.S3CleanupImage,
+			Args:  args,
+		}
+		if storage.CredentialsSecret != nil {
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name:  "AWS_SHARED_CREDENTIALS_FILE",
+				Value: path.Join(vmBackuperCreds, storage.CredentialsSecret.Key),
+			})
+		}
+	default:
+		return nil, fmt.Errorf("cleanup of backup %s isn't supported, only fs:// and s3:// destinations can be cleaned up", record.Destination)
+	}
+	container.ImagePullPolicy = corev1.PullIfNotPresent
+	container.VolumeMounts = mounts
+	container.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+
+	labels := cr.SelectorLabels()
+	labels[backupCleanupLabel] = record.Name
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            truncateName(fmt.Sprintf("%s-cleanup", record.Name), validation.DNS1123LabelMaxLength),
+			Namespace:       cr.Namespace,
+			Labels:          c.Labels.Merge(labels),
+			OwnerReferences: cr.AsOwner(),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32Ptr(backupJobBackoffLimit),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyOnFailure,
+					Volumes:       volumes,
+					Containers:    []corev1.Container{container},
+				},
+			},
+		},
+	}, nil
+}
+
+// ReconcileVMBackupSchedule tracks running backups, starts new backups according to cron schedules
+// and removes backups, which exceed retention. It returns duration until the next scheduled backup,
+// zero duration means that there are no scheduled backups.
+func ReconcileVMBackupSchedule(ctx context.Context, cr *v1beta1.VMBackupSchedule, rclient client.Client, c *config.BaseOperatorConf, now time.Time) (time.Duration, error) {
+	if err := updateBackupRecords(ctx, cr, rclient, now); err != nil {
+		return 0, err
+	}
+	next, err := scheduleBackups(ctx, cr, rclient, c, now)
+	if err != nil {
+		return 0, err
+	}
+	if err := pruneBackups(ctx, cr, rclient, c); err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// updateBackupRecords updates phase of running backups with state of their jobs.
+func updateBackupRecords(ctx context.Context, cr *v1beta1.VMBackupSchedule, rclient client.Client, now time.Time) error {
+	for i := range cr.Status.Backups {
+		record := &cr.Status.Backups[i]
+		if record.Phase != v1beta1.BackupPhaseRunning {
+			continue
+		}
+		jobs := &batchv1.JobList{}
+		if err := rclient.List(ctx, jobs, client.InNamespace(cr.Namespace), client.MatchingLabels{backupRecordLabel: record.Name}); err != nil {
+			return fmt.Errorf("cannot list jobs of backup %s: %w", record.Name, err)
+		}
+		if len(jobs.Items) == 0 {
+			if now.Sub(record.StartTime.Time) > backupJobsLookupTimeout {
+				failBackupRecord(cr, record, "backup jobs were not found", now)
+			}
+			continue
+		}
+		completed := 0
+		for _, job := range jobs.Items {
+			if cond := jobCondition(job, batchv1.JobFailed); cond != nil {
+				failBackupRecord(cr, record, fmt.Sprintf("job %s failed: %s", job.Name, cond.Message), now)
+				break
+			}
+			if jobCondition(job, batchv1.JobComplete) != nil {
+				completed++
+			}
+		}
+		if record.Phase == v1beta1.BackupPhaseRunning && completed == len(jobs.Items) {
+			completionTime := metav1.NewTime(now)
+			record.Phase = v1beta1.BackupPhaseSucceeded
+			record.CompletionTime = &completionTime
+		}
+	}
+	return nil
+}
+
+func failBackupRecord(cr *v1beta1.VMBackupSchedule, record *v1beta1.BackupRecord, reason string, now time.Time) {
+	completionTime := metav1.NewTime(now)
+	record.Phase = v1beta1.BackupPhaseFailed
+	record.CompletionTime = &completionTime
+	cr.Status.Reason = fmt.Sprintf("backup %s failed: %s", record.Name, reason)
+}
+
+// jobCondition returns condition of given type, if its status is true.
+func jobCondition(job batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		cond := job.Status.Conditions[i]
+		if cond.Type == conditionType && cond.Status == corev1.ConditionTrue {
+			return &cond
+		}
+	}
+	return nil
+}
+
+// scheduleBackups starts backup, if its schedule is due.
+// Full backup has priority and replaces incremental one, scheduled at the same time.
+func scheduleBackups(ctx context.Context, cr *v1beta1.VMBackupSchedule, rclient client.Client, c *config.BaseOperatorConf, now time.Time) (time.Duration, error) {
+	if cr.Spec.Suspend {
+		return 0, nil
+	}
+	fullNext, err := nextBackupTime(cr.Spec.FullSchedule, cr.Status.LastFullScheduleTime, cr.CreationTimestamp)
+	if err != nil {
+		return 0, err
+	}
+	incrementalNext, err := nextBackupTime(cr.Spec.IncrementalSchedule, cr.Status.LastIncrementalScheduleTime, cr.CreationTimestamp)
+	if err != nil {
+		return 0, err
+	}
+	fullDue := fullNext != nil && !now.Before(*fullNext)
+	incrementalDue := incrementalNext != nil && !now.Before(*incrementalNext)
+	if fullDue || incrementalDue {
+		for _, record := range cr.Status.Backups {
+			if record.Phase == v1beta1.BackupPhaseRunning {
+				log.Info("backup is still running, postpone scheduled backup", "vmbackupschedule", cr.Name, "backup", record.Name)
+				return backupRetryInterval, nil
+			}
+		}
+		scheduleTime := metav1.NewTime(now)
+		backupType := v1beta1.BackupTypeIncremental
+		if fullDue {
+			backupType = v1beta1.BackupTypeFull
+			cr.Status.LastFullScheduleTime = &scheduleTime
+		}
+		if incrementalDue {
+			cr.Status.LastIncrementalScheduleTime = &scheduleTime
+		}
+		startBackup(ctx, cr, rclient, c, backupType, now)
+
+		if fullNext, err = nextBackupTime(cr.Spec.FullSchedule, cr.Status.LastFullScheduleTime, cr.CreationTimestamp); err != nil {
+			return 0, err
+		}
+		if incrementalNext, err = nextBackupTime(cr.Spec.IncrementalSchedule, cr.Status.LastIncrementalScheduleTime, cr.CreationTimestamp); err != nil {
+			return 0, err
+		}
+	}
+
+	var next time.Duration
+	for _, t := range []*time.Time{fullNext, incrementalNext} {
+		if t == nil {
+			continue
+		}
+		if d := t.Sub(now); next == 0 || d < next {
+			next = d
+		}
+	}
+	return next, nil
+}
+
+// nextBackupTime returns time of the next backup for given cron schedule or nil, if schedule isn't set.
+func nextBackupTime(schedule string, lastScheduleTime *metav1.Time, creationTime metav1.Time) (*time.Time, error) {
+	if schedule == "" {
+		return nil, nil
+	}
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse backup schedule: %q: %w", schedule, err)
+	}
+	from := creationTime.Time
+	if lastScheduleTime != nil {
+		from = lastScheduleTime.Time
+	}
+	next := sched.Next(from)
+	return &next, nil
+}
+
+// startBackup creates backup jobs and adds backup record into status.
+// Backup is marked as failed, if its jobs cannot be created, it's retried at the next schedule.
+func startBackup(ctx context.Context, cr *v1beta1.VMBackupSchedule, rclient client.Client, c *config.BaseOperatorConf, backupType string, now time.Time) {
+	backupTime := now.UTC().Format(backupTimeFormat)
+	record := v1beta1.BackupRecord{
+		Name:        truncateName(fmt.Sprintf("%s-%s", cr.PrefixedName(), backupTime), validation.DNS1123LabelMaxLength),
+		Type:        backupType,
+		Destination: cr.BackupDestination(backupType, backupTime),
+		Phase:       v1beta1.BackupPhaseRunning,
+		StartTime:   metav1.NewTime(now),
+	}
+	l := log.WithValues("vmbackupschedule", cr.Name, "backup", record.Name, "type", backupType)
+	err := createBackupJobs(ctx, cr, record, rclient, c)
+	if err != nil {
+		l.Error(err, "cannot start backup")
+		failBackupRecord(cr, &record, err.Error(), now)
+	} else {
+		l.Info("backup was started")
+	}
+	cr.Status.Backups = append(cr.Status.Backups, record)
+}
+
+func createBackupJobs(ctx context.Context, cr *v1beta1.VMBackupSchedule, record v1beta1.BackupRecord, rclient client.Client, c *config.BaseOperatorConf) error {
+	target, err := getBackupTarget(ctx, rclient, cr.Spec.Target, cr.Namespace, c)
+	if err != nil {
+		return err
+	}
+	for _, job := range genVMBackupJobs(cr, record, target, c) {
+		if err := rclient.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("cannot create backup job %s: %w", job.Name, err)
+		}
+	}
+	return nil
+}
+
+// backupRecordsToRemove returns names of backup records, which exceed retention.
+// Full backups over KeepLastFull are removed with failed full backups, which have newer succeeded backup.
+// Incremental backups share the same destination, only their records are removed.
+func backupRecordsToRemove(cr *v1beta1.VMBackupSchedule) map[string]bool {
+	toRemove := make(map[string]bool)
+	var keptFull, keptIncremental int
+	var hasNewerSucceeded bool
+	for i := len(cr.Status.Backups) - 1; i >= 0; i-- {
+		record := cr.Status.Backups[i]
+		if record.Phase == v1beta1.BackupPhaseRunning {
+			continue
+		}
+		if record.Type == v1beta1.BackupTypeIncremental {
+			if keptIncremental < maxIncrementalBackupRecords {
+				keptIncremental++
+			} else {
+				toRemove[record.Name] = true
+			}
+			continue
+		}
+		succeeded := record.Phase == v1beta1.BackupPhaseSucceeded
+		switch {
+		case cr.Spec.KeepLastFull == nil:
+		case succeeded && keptFull < int(*cr.Spec.KeepLastFull):
+			keptFull++
+		case succeeded || hasNewerSucceeded:
+			toRemove[record.Name] = true
+		}
+		if succeeded {
+			hasNewerSucceeded = true
+		}
+	}
+	return toRemove
+}
+
+// pruneBackups removes backups, which exceed retention, and finished cleanup jobs.
+func pruneBackups(ctx context.Context, cr *v1beta1.VMBackupSchedule, rclient client.Client, c *config.BaseOperatorConf) error {
+	cleanupJobs := &batchv1.JobList{}
+	if err := rclient.List(ctx, cleanupJobs, client.InNamespace(cr.Namespace), client.MatchingLabels(cr.SelectorLabels()), client.HasLabels{backupCleanupLabel}); err != nil {
+		return fmt.Errorf("cannot list backup cleanup jobs: %w", err)
+	}
+	for i := range cleanupJobs.Items {
+		job := &cleanupJobs.Items[i]
+		if jobCondition(*job, batchv1.JobComplete) == nil {
+			continue
+		}
+		if err := rclient.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("cannot delete backup cleanup job %s: %w", job.Name, err)
+		}
+	}
+
+	toRemove := backupRecordsToRemove(cr)
+	if len(toRemove) == 0 {
+		return nil
+	}
+	kept := cr.Status.Backups[:0]
+	for _, record := range cr.Status.Backups {
+		if !toRemove[record.Name] {
+			kept = append(kept, record)
+			continue
+		}
+		if record.Type == v1beta1.BackupTypeFull {
+			job, err := genVMBackupCleanupJob(cr, record, c)
+			if err != nil {
+				cr.Status.Reason = fmt.Sprintf("cannot remove backup %s: %s", record.Name, err)
+				kept = append(kept, record)
+				continue
+			}
+			if err := rclient.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+				return fmt.Errorf("cannot create backup cleanup job %s: %w", job.Name, err)
+			}
+			log.Info("removing backup, which exceeds retention", "vmbackupschedule", cr.Name, "backup", record.Name)
+		}
+		if err := rclient.DeleteAllOf(ctx, &batchv1.Job{}, client.InNamespace(cr.Namespace), client.MatchingLabels{backupRecordLabel: record.Name}, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+			return fmt.Errorf("cannot delete jobs of backup %s: %w", record.Name, err)
+		}
+	}
+	cr.Status.Backups = kept
+	return nil
+}
+
+// backupJobName returns name of backup job for node of backup target with given index.
+// Job name is used as label value for its pods, so it's limited to 63 characters.
+func backupJobName(record v1beta1.BackupRecord, target *backupTarget, idx int) string {
+	if target.isCluster {
+		return truncateName(record.Name+"-"+strconv.Itoa(idx), validation.DNS1123LabelMaxLength)
+	}
+	return record.Name
+}
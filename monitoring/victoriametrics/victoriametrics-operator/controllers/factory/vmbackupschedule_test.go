@@ -0,0 +1,291 @@
+package factory
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/operator/api/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileVMBackupSchedule(t *testing.T) {
+	created := time.Date(2020, 10, 19, 11, 30, 0, 0, time.UTC)
+	now := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	vmSingle := &v1beta1.VMSingle{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "single"},
+		Spec:       v1beta1.VMSingleSpec{Storage: &corev1.PersistentVolumeClaimSpec{}},
+	}
+	vmCluster := &v1beta1.VMCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster"},
+		Spec: v1beta1.VMClusterSpec{
+			VMStorage: &v1beta1.VMStorage{
+				ReplicaCount: pointer.Int32Ptr(2),
+				Storage:      &v1beta1.StorageSpec{},
+			},
+		},
+	}
+	newSchedule := func(kind, name string, status v1beta1.VMBackupScheduleStatus) *v1beta1.VMBackupSchedule {
+		return &v1beta1.VMBackupSchedule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "backup", CreationTimestamp: metav1.NewTime(created)},
+			Spec: v1beta1.VMBackupScheduleSpec{
+				Target:              v1beta1.BackupTarget{Kind: kind, Name: name},
+				Storage:             v1beta1.BackupStorage{Destination: "fs:///backups", DestinationClaimName: "backups"},
+				FullSchedule:        "0 * * * *",
+				IncrementalSchedule: "*/15 * * * *",
+				KeepLastFull:        pointer.Int32Ptr(1),
+			},
+			Status: status,
+		}
+	}
+	lastFullSchedule := metav1.NewTime(now)
+	lastIncrementalSchedule := metav1.NewTime(now.Add(-30 * time.Minute))
+	tests := []struct {
+		name              string
+		cr                *v1beta1.VMBackupSchedule
+		predefinedObjects []runtime.Object
+		wantNext          time.Duration
+		wantJobs          []string
+		wantBackups       []string
+		wantPhases        []string
+		wantErr           bool
+	}{
+		{
+			name:        "full backup of vmsingle",
+			cr:          newSchedule(v1beta1.BackupTargetVMSingle, "single", v1beta1.VMBackupScheduleStatus{}),
+			wantNext:    15 * time.Minute,
+			wantJobs:    []string{"vmbackup-backup-20201019120000"},
+			wantBackups: []string{"fs:///backups/20201019120000"},
+			wantPhases:  []string{v1beta1.BackupPhaseRunning},
+		},
+		{
+			name: "incremental backup of vmcluster",
+			cr: newSchedule(v1beta1.BackupTargetVMCluster, "cluster", v1beta1.VMBackupScheduleStatus{
+				LastFullScheduleTime:        &lastFullSchedule,
+				LastIncrementalScheduleTime: &lastIncrementalSchedule,
+			}),
+			predefinedObjects: []runtime.Object{vmCluster},
+			wantNext:          15 * time.Minute,
+			wantJobs:          []string{"vmbackup-backup-20201019120000-0", "vmbackup-backup-20201019120000-1"},
+			wantBackups:       []string{"fs:///backups/latest"},
+			wantPhases:        []string{v1beta1.BackupPhaseRunning},
+		},
+		{
+			name: "postpone backup while previous one is running",
+			cr: newSchedule(v1beta1.BackupTargetVMSingle, "single", v1beta1.VMBackupScheduleStatus{
+				Backups: []v1beta1.BackupRecord{
+					{Name: "vmbackup-backup-1", Type: v1beta1.BackupTypeFull, Destination: "fs:///backups/1", Phase: v1beta1.BackupPhaseRunning, StartTime: metav1.NewTime(now.Add(-time.Minute))},
+				},
+			}),
+			predefinedObjects: []runtime.Object{&batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vmbackup-backup-1", Labels: map[string]string{backupRecordLabel: "vmbackup-backup-1"}},
+			}},
+			wantNext:    backupRetryInterval,
+			wantJobs:    []string{"vmbackup-backup-1"},
+			wantBackups: []string{"fs:///backups/1"},
+			wantPhases:  []string{v1beta1.BackupPhaseRunning},
+		},
+		{
+			name: "finish backup and remove old one",
+			cr: newSchedule(v1beta1.BackupTargetVMSingle, "single", v1beta1.VMBackupScheduleStatus{
+				LastFullScheduleTime:        &lastFullSchedule,
+				LastIncrementalScheduleTime: &lastFullSchedule,
+				Backups: []v1beta1.BackupRecord{
+					{Name: "vmbackup-backup-0", Type: v1beta1.BackupTypeFull, Destination: "fs:///backups/0", Phase: v1beta1.BackupPhaseSucceeded, StartTime: metav1.NewTime(now.Add(-2 * time.Hour))},
+					{Name: "vmbackup-backup-1", Type: v1beta1.BackupTypeFull, Destination: "fs:///backups/1", Phase: v1beta1.BackupPhaseRunning, StartTime: metav1.NewTime(now.Add(-time.Hour))},
+				},
+			}),
+			predefinedObjects: []runtime.Object{
+				&batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vmbackup-backup-0", Labels: map[string]string{backupRecordLabel: "vmbackup-backup-0"}},
+				},
+				&batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vmbackup-backup-1", Labels: map[string]string{backupRecordLabel: "vmbackup-backup-1"}},
+					Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+						{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+					}},
+				},
+			},
+			wantNext:    15 * time.Minute,
+			wantJobs:    []string{"vmbackup-backup-0-cleanup", "vmbackup-backup-1"},
+			wantBackups: []string{"fs:///backups/1"},
+			wantPhases:  []string{v1beta1.BackupPhaseSucceeded},
+		},
+		{
+			name: "fail backup with failed job",
+			cr: newSchedule(v1beta1.BackupTargetVMSingle, "single", v1beta1.VMBackupScheduleStatus{
+				LastFullScheduleTime:        &lastFullSchedule,
+				LastIncrementalScheduleTime: &lastIncrementalSchedule,
+				Backups: []v1beta1.BackupRecord{
+					{Name: "vmbackup-backup-1", Type: v1beta1.BackupTypeFull, Destination: "fs:///backups/1", Phase: v1beta1.BackupPhaseRunning, StartTime: metav1.NewTime(now.Add(-time.Hour))},
+				},
+			}),
+			predefinedObjects: []runtime.Object{&batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vmbackup-backup-1", Labels: map[string]string{backupRecordLabel: "vmbackup-backup-1"}},
+				Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+				}},
+			}},
+			wantNext:    15 * time.Minute,
+			wantJobs:    []string{"vmbackup-backup-1", "vmbackup-backup-20201019120000"},
+			wantBackups: []string{"fs:///backups/1", "fs:///backups/latest"},
+			wantPhases:  []string{v1beta1.BackupPhaseFailed, v1beta1.BackupPhaseRunning},
+		},
+		{
+			name:        "fail backup of missing target",
+			cr:          newSchedule(v1beta1.BackupTargetVMCluster, "missing", v1beta1.VMBackupScheduleStatus{}),
+			wantNext:    15 * time.Minute,
+			wantBackups: []string{"fs:///backups/20201019120000"},
+			wantPhases:  []string{v1beta1.BackupPhaseFailed},
+		},
+		{
+			name: "invalid schedule",
+			cr: func() *v1beta1.VMBackupSchedule {
+				cr := newSchedule(v1beta1.BackupTargetVMSingle, "single", v1beta1.VMBackupScheduleStatus{})
+				cr.Spec.FullSchedule = "every hour"
+				return cr
+			}(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := append([]runtime.Object{vmSingle}, tt.predefinedObjects...)
+			fclient := fake.NewFakeClientWithScheme(testGetScheme(), obj...)
+			next, err := ReconcileVMBackupSchedule(context.TODO(), tt.cr, fclient, config.MustGetBaseConfig(), now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReconcileVMBackupSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if next != tt.wantNext {
+				t.Errorf("ReconcileVMBackupSchedule() next = %v, want %v", next, tt.wantNext)
+			}
+			var gotBackups, gotPhases []string
+			for _, record := range tt.cr.Status.Backups {
+				gotBackups = append(gotBackups, record.Destination)
+				gotPhases = append(gotPhases, record.Phase)
+			}
+			if !reflect.DeepEqual(gotBackups, tt.wantBackups) {
+				t.Errorf("ReconcileVMBackupSchedule() backups = %v, want %v", gotBackups, tt.wantBackups)
+			}
+			if !reflect.DeepEqual(gotPhases, tt.wantPhases) {
+				t.Errorf("ReconcileVMBackupSchedule() phases = %v, want %v", gotPhases, tt.wantPhases)
+			}
+			jobs := &batchv1.JobList{}
+			if err := fclient.List(context.TODO(), jobs, client.InNamespace("default")); err != nil {
+				t.Fatalf("cannot list jobs: %v", err)
+			}
+			var gotJobs []string
+			for _, job := range jobs.Items {
+				gotJobs = append(gotJobs, job.Name)
+			}
+			sort.Strings(gotJobs)
+			if !reflect.DeepEqual(gotJobs, tt.wantJobs) {
+				t.Errorf("ReconcileVMBackupSchedule() jobs = %v, want %v", gotJobs, tt.wantJobs)
+			}
+		})
+	}
+}
+
+func Test_genVMBackupCleanupJob(t *testing.T) {
+	tests := []struct {
+		name        string
+		storage     v1beta1.BackupStorage
+		wantCommand []string
+		wantArgs    []string
+		wantErr     bool
+	}{
+		{
+			name:        "local backup",
+			storage:     v1beta1.BackupStorage{Destination: "fs:///backups", DestinationClaimName: "backups"},
+			wantCommand: []string{"rm", "-rf", "/backups/1"},
+		},
+		{
+			name: "s3 backup",
+			storage: v1beta1.BackupStorage{
+				Destination:      "s3://bucket/backups",
+				CustomS3Endpoint: pointer.StringPtr("http://minio:9000"),
+			},
+			wantArgs: []string{"s3", "rm", "--recursive", "s3://bucket/backups/1/", "--endpoint-url", "http://minio:9000"},
+		},
+		{
+			name:    "local backup without claim",
+			storage: v1beta1.BackupStorage{Destination: "fs:///backups"},
+			wantErr: true,
+		},
+		{
+			name:    "gcs backup",
+			storage: v1beta1.BackupStorage{Destination: "gs://bucket/backups"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &v1beta1.VMBackupSchedule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "backup"},
+				Spec:       v1beta1.VMBackupScheduleSpec{Storage: tt.storage},
+			}
+			record := v1beta1.BackupRecord{Name: "vmbackup-backup-1", Destination: tt.storage.Destination + "/1"}
+			job, err := genVMBackupCleanupJob(cr, record, config.MustGetBaseConfig())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("genVMBackupCleanupJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			container := job.Spec.Template.Spec.Containers[0]
+			if !reflect.DeepEqual(container.Command, tt.wantCommand) {
+				t.Errorf("genVMBackupCleanupJob() command = %v, want %v", container.Command, tt.wantCommand)
+			}
+			if !reflect.DeepEqual(container.Args, tt.wantArgs) {
+				t.Errorf("genVMBackupCleanupJob() args = %v, want %v", container.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func Test_backupNamesLength(t *testing.T) {
+	cr := &v1beta1.VMBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: strings.Repeat("long-backup-schedule-name", 3)},
+		Spec:       v1beta1.VMBackupScheduleSpec{Storage: v1beta1.BackupStorage{Destination: "fs:///backups", DestinationClaimName: "backups"}},
+	}
+	now := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	startBackup(context.TODO(), cr, fake.NewFakeClientWithScheme(testGetScheme()), config.MustGetBaseConfig(), v1beta1.BackupTypeFull, now)
+	record := cr.Status.Backups[0]
+	cleanupJob, err := genVMBackupCleanupJob(cr, record, config.MustGetBaseConfig())
+	if err != nil {
+		t.Fatalf("genVMBackupCleanupJob() error = %v", err)
+	}
+	names := []string{
+		record.Name,
+		cleanupJob.Name,
+		backupJobName(record, &backupTarget{isCluster: true}, 0),
+		backupJobName(record, &backupTarget{isCluster: true}, 1),
+	}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if len(name) > validation.DNS1123LabelMaxLength {
+			t.Errorf("name %q exceeds %d characters", name, validation.DNS1123LabelMaxLength)
+		}
+		if seen[name] {
+			t.Errorf("name %q isn't unique", name)
+		}
+		seen[name] = true
+	}
+	if !strings.HasPrefix(record.Name, cr.PrefixedName()[:40]) {
+		t.Errorf("truncated name %q must keep prefix of the original name", record.Name)
+	}
+}
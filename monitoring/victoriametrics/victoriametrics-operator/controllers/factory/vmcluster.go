@@ -17,7 +17,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/utils/pointer"
 	"path"
+	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -38,8 +40,10 @@ func CreateOrUpdateVMCluster(ctx context.Context, cr *v1beta1.VMCluster, rclient
 	var expanding, reconciled bool
 	status := v1beta1.ClusterStatusFailed
 	var reason string
+	prevScaleDown := cr.Status.StorageScaleDown.DeepCopy()
 	defer func() {
-		if cr.Status.ClusterStatus == v1beta1.ClusterStatusOperational {
+		scaleDownChanged := !reflect.DeepEqual(prevScaleDown, cr.Status.StorageScaleDown)
+		if cr.Status.ClusterStatus == v1beta1.ClusterStatusOperational && !scaleDownChanged {
 			log.Info("no need for resync")
 			return
 		}
@@ -57,7 +61,15 @@ func CreateOrUpdateVMCluster(ctx context.Context, cr *v1beta1.VMCluster, rclient
 			log.Error(err, "cannot update cluster status")
 		}
 	}()
+	if cr.Spec.VMStorage == nil {
+		cr.Status.StorageScaleDown = nil
+	}
 	if cr.Spec.VMStorage != nil {
+		err := reconcileVMStorageScaleDown(ctx, cr, rclient)
+		if err != nil {
+			reason = "failed to check vmStorage scale down"
+			return status, err
+		}
 		vmStorageSts, err := createOrUpdateVMStorage(ctx, cr, rclient, c)
 		if err != nil {
 			reason = v1beta1.StorageCreationFailed
@@ -81,7 +93,7 @@ func CreateOrUpdateVMCluster(ctx context.Context, cr *v1beta1.VMCluster, rclient
 			}
 		}
 		//wait for expand
-		expanding, err = waitForExpanding(ctx, rclient, cr.Namespace, cr.VMStorageSelectorLabels(), *cr.Spec.VMStorage.ReplicaCount)
+		expanding, err = waitForExpanding(ctx, rclient, cr.Namespace, cr.VMStorageSelectorLabels(), vmStorageReplicas(cr))
 		if err != nil {
 			reason = "failed to check for vmStorage expanding"
 			return status, err
@@ -165,6 +177,7 @@ func CreateOrUpdateVMCluster(ctx context.Context, cr *v1beta1.VMCluster, rclient
 	}
 	reconciled = true
 	status = v1beta1.ClusterStatusOperational
+	reason = vmStorageScaleDownReason(cr)
 	log.Info("created or updated vmCluster ")
 	return status, nil
 
@@ -329,6 +342,10 @@ func createOrUpdateVMStorage(ctx context.Context, cr *v1beta1.VMCluster, rclient
 	if err != nil {
 		return nil, err
 	}
+	target := v1beta1.BackupTarget{Kind: v1beta1.BackupTargetVMCluster, Name: cr.Name}
+	if err := applyVMRestore(ctx, rclient, &newSts.Spec.Template, target, cr.Namespace, c); err != nil {
+		return nil, fmt.Errorf("cannot apply vmrestore to vmstorage sts: %w", err)
+	}
 	currentSts := &appsv1.StatefulSet{}
 	err = rclient.Get(ctx, types.NamespacedName{Name: newSts.Name, Namespace: newSts.Namespace}, currentSts)
 	if err != nil {
@@ -350,6 +367,10 @@ func createOrUpdateVMStorage(ctx context.Context, cr *v1beta1.VMCluster, rclient
 	}
 
 	for annotation, value := range currentSts.Spec.Template.Annotations {
+		// restore annotation is managed by applyVMRestore
+		if annotation == v1beta1.RestoreAnnotation {
+			continue
+		}
 		newSts.Spec.Template.Annotations[annotation] = value
 	}
 
@@ -545,7 +566,8 @@ func makePodSpecForVMSelect(cr *v1beta1.VMCluster, c *config.BaseOperatorConf) (
 			cr.Spec.VMStorage.VMSelectPort = c.VMClusterDefault.VMStorageDefault.VMSelectPort
 		}
 		storageArg := "-storageNode="
-		vmstorageCount := *cr.Spec.VMStorage.ReplicaCount
+		// nodes under drain must be kept until retention period passes
+		vmstorageCount := vmStorageReplicas(cr)
 		for i := int32(0); i < vmstorageCount; i++ {
 			storageArg += cr.Spec.VMStorage.BuildPodFQDNName(cr.Spec.VMStorage.GetNameWithPrefix(cr.Name), i, cr.Namespace, cr.Spec.VMStorage.VMSelectPort, c.ClusterDomainName)
 		}
@@ -816,7 +838,8 @@ func makePodSpecForVMInsert(cr *v1beta1.VMCluster, c *config.BaseOperatorConf) (
 			cr.Spec.VMStorage.VMInsertPort = c.VMClusterDefault.VMStorageDefault.VMInsertPort
 		}
 		storageArg := "-storageNode="
-		storageCount := *cr.Spec.VMStorage.ReplicaCount
+		// nodes under drain must not receive new data
+		storageCount := vmStorageInsertReplicas(cr)
 		for i := int32(0); i < storageCount; i++ {
 			storageArg += cr.Spec.VMStorage.BuildPodFQDNName(cr.Spec.VMStorage.GetNameWithPrefix(cr.Name), i, cr.Namespace, cr.Spec.VMStorage.VMInsertPort, c.ClusterDomainName)
 		}
@@ -1067,7 +1090,7 @@ func GenVMStorageSpec(cr *v1beta1.VMCluster, c *config.BaseOperatorConf) (*appsv
 			OwnerReferences: cr.AsOwner(),
 		},
 		Spec: appsv1.StatefulSetSpec{
-			Replicas: cr.Spec.VMStorage.ReplicaCount,
+			Replicas: pointer.Int32Ptr(vmStorageReplicas(cr)),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: cr.VMStorageSelectorLabels(),
 			},
@@ -1347,6 +1370,121 @@ func genVMStorageService(cr *v1beta1.VMCluster, c *config.BaseOperatorConf) *cor
 	}
 }
 
+// reconcileVMStorageScaleDown checks if vmstorage ReplicaCount was decreased and tracks scale down at status.
+// Statefulset removes nodes with the highest ordinals, but their data is still needed for queries.
+// So scale down is refused by default, with Drain strategy removed nodes are excluded from vminsert at first
+// and kept at vmselect and statefulset until retention period passes.
+func reconcileVMStorageScaleDown(ctx context.Context, cr *v1beta1.VMCluster, rclient client.Client) error {
+	currentSts := &appsv1.StatefulSet{}
+	err := rclient.Get(ctx, types.NamespacedName{Name: cr.Spec.VMStorage.GetNameWithPrefix(cr.Name), Namespace: cr.Namespace}, currentSts)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			cr.Status.StorageScaleDown = nil
+			return nil
+		}
+		return fmt.Errorf("cannot get vmstorage sts: %w", err)
+	}
+	desiredReplicas := *cr.Spec.VMStorage.ReplicaCount
+	currentReplicas := int32(1)
+	if currentSts.Spec.Replicas != nil {
+		currentReplicas = *currentSts.Spec.Replicas
+	}
+	l := log.WithValues("controller", "vmstorage.scaledown", "cluster", cr.Name, "currentReplicas", currentReplicas, "desiredReplicas", desiredReplicas)
+	scaleDown := cr.Status.StorageScaleDown
+	if desiredReplicas >= currentReplicas {
+		if scaleDown != nil {
+			l.Info("vmstorage scale down was finished or canceled")
+		}
+		cr.Status.StorageScaleDown = nil
+		return nil
+	}
+
+	if cr.Spec.VMStorage.ScaleDownStrategy != v1beta1.StorageScaleDownDrain {
+		l.Info("vmstorage scale down was refused, it's allowed only with Drain scaleDownStrategy")
+		cr.Status.StorageScaleDown = &v1beta1.VMStorageScaleDownStatus{
+			Phase:          v1beta1.StorageScaleDownPhaseRefused,
+			FromReplicas:   currentReplicas,
+			TargetReplicas: desiredReplicas,
+		}
+		return nil
+	}
+
+	// drain can be continued, if all removed nodes were already excluded from vminsert
+	if scaleDown != nil && scaleDown.Phase != v1beta1.StorageScaleDownPhaseRefused && scaleDown.DrainFinishAt != nil &&
+		scaleDown.FromReplicas == currentReplicas && desiredReplicas >= scaleDown.TargetReplicas {
+		scaleDown.TargetReplicas = desiredReplicas
+		if scaleDown.Phase == v1beta1.StorageScaleDownPhaseDraining && !time.Now().Before(scaleDown.DrainFinishAt.Time) {
+			l.Info("retention period for drained vmstorage nodes passed, scaling down")
+			scaleDown.Phase = v1beta1.StorageScaleDownPhaseScalingDown
+		}
+		return nil
+	}
+
+	retention, err := retentionPeriodDuration(cr.Spec.RetentionPeriod)
+	if err != nil {
+		return err
+	}
+	drainStartedAt := metav1.Now()
+	drainFinishAt := metav1.NewTime(drainStartedAt.Add(retention))
+	cr.Status.StorageScaleDown = &v1beta1.VMStorageScaleDownStatus{
+		Phase:          v1beta1.StorageScaleDownPhaseDraining,
+		FromReplicas:   currentReplicas,
+		TargetReplicas: desiredReplicas,
+		DrainStartedAt: &drainStartedAt,
+		DrainFinishAt:  &drainFinishAt,
+	}
+	l.Info("started drain of vmstorage nodes, they were excluded from vminsert", "drainFinishAt", drainFinishAt.String())
+	return nil
+}
+
+// retentionPeriodDuration converts retention period in months into duration,
+// month is counted as 31 days, it covers the longest month.
+func retentionPeriodDuration(retentionPeriod string) (time.Duration, error) {
+	months, err := strconv.Atoi(retentionPeriod)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse retentionPeriod: %q, it must be count of months: %w", retentionPeriod, err)
+	}
+	return time.Duration(months) * 31 * 24 * time.Hour, nil
+}
+
+// vmStorageReplicas returns count of vmstorage nodes, which are kept at statefulset and vmselect.
+func vmStorageReplicas(cr *v1beta1.VMCluster) int32 {
+	scaleDown := cr.Status.StorageScaleDown
+	if scaleDown != nil && scaleDown.Phase != v1beta1.StorageScaleDownPhaseScalingDown {
+		return scaleDown.FromReplicas
+	}
+	return *cr.Spec.VMStorage.ReplicaCount
+}
+
+// vmStorageInsertReplicas returns count of vmstorage nodes, which accept new data from vminsert.
+func vmStorageInsertReplicas(cr *v1beta1.VMCluster) int32 {
+	scaleDown := cr.Status.StorageScaleDown
+	if scaleDown != nil && scaleDown.Phase == v1beta1.StorageScaleDownPhaseRefused {
+		return scaleDown.FromReplicas
+	}
+	return *cr.Spec.VMStorage.ReplicaCount
+}
+
+// vmStorageScaleDownReason describes vmstorage scale down progress for cluster status.
+func vmStorageScaleDownReason(cr *v1beta1.VMCluster) string {
+	scaleDown := cr.Status.StorageScaleDown
+	if scaleDown == nil {
+		return ""
+	}
+	switch scaleDown.Phase {
+	case v1beta1.StorageScaleDownPhaseRefused:
+		return fmt.Sprintf("vmStorage scale down from %d to %d replicas was refused, set scaleDownStrategy: %s to allow it",
+			scaleDown.FromReplicas, scaleDown.TargetReplicas, v1beta1.StorageScaleDownDrain)
+	case v1beta1.StorageScaleDownPhaseDraining:
+		if scaleDown.DrainFinishAt == nil {
+			break
+		}
+		return fmt.Sprintf("vmStorage nodes are draining for scale down from %d to %d replicas until %s",
+			scaleDown.FromReplicas, scaleDown.TargetReplicas, scaleDown.DrainFinishAt.String())
+	}
+	return ""
+}
+
 func waitForExpanding(ctx context.Context, kclient client.Client, namespace string, lbs map[string]string, desiredCount int32) (bool, error) {
 	log.Info("check pods availability")
 	podList := &corev1.PodList{}
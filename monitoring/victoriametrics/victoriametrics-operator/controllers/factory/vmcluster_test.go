@@ -589,3 +589,154 @@ func TestCreateOrUpdateVMCluster(t *testing.T) {
 		})
 	}
 }
+
+func Test_reconcileVMStorageScaleDown(t *testing.T) {
+	newCluster := func(replicas int32, strategy string, scaleDown *v1beta1.VMStorageScaleDownStatus) *v1beta1.VMCluster {
+		return &v1beta1.VMCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster-1"},
+			Spec: v1beta1.VMClusterSpec{
+				RetentionPeriod: "1",
+				VMStorage:       &v1beta1.VMStorage{ReplicaCount: pointer.Int32Ptr(replicas), ScaleDownStrategy: strategy},
+			},
+			Status: v1beta1.VMClusterStatus{StorageScaleDown: scaleDown},
+		}
+	}
+	storageSts := func(replicas int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vmstorage-cluster-1"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32Ptr(replicas)},
+		}
+	}
+	past := metav1.NewTime(time.Now().Add(-time.Hour))
+	future := metav1.NewTime(time.Now().Add(time.Hour))
+	tests := []struct {
+		name                 string
+		cr                   *v1beta1.VMCluster
+		predefinedObjects    []runtime.Object
+		wantPhase            string
+		wantStorageReplicas  int32
+		wantInsertReplicas   int32
+		wantDrainRestartedAt bool
+	}{
+		{
+			name:                "new cluster",
+			cr:                  newCluster(3, "", nil),
+			wantStorageReplicas: 3,
+			wantInsertReplicas:  3,
+		},
+		{
+			name:                "scale up",
+			cr:                  newCluster(3, "", nil),
+			predefinedObjects:   []runtime.Object{storageSts(2)},
+			wantStorageReplicas: 3,
+			wantInsertReplicas:  3,
+		},
+		{
+			name:                "refuse scale down by default",
+			cr:                  newCluster(2, "", nil),
+			predefinedObjects:   []runtime.Object{storageSts(3)},
+			wantPhase:           v1beta1.StorageScaleDownPhaseRefused,
+			wantStorageReplicas: 3,
+			wantInsertReplicas:  3,
+		},
+		{
+			name:                 "start drain",
+			cr:                   newCluster(2, v1beta1.StorageScaleDownDrain, nil),
+			predefinedObjects:    []runtime.Object{storageSts(3)},
+			wantPhase:            v1beta1.StorageScaleDownPhaseDraining,
+			wantStorageReplicas:  3,
+			wantInsertReplicas:   2,
+			wantDrainRestartedAt: true,
+		},
+		{
+			name: "start drain after refused scale down",
+			cr: newCluster(2, v1beta1.StorageScaleDownDrain, &v1beta1.VMStorageScaleDownStatus{
+				Phase: v1beta1.StorageScaleDownPhaseRefused, FromReplicas: 3, TargetReplicas: 2,
+			}),
+			predefinedObjects:    []runtime.Object{storageSts(3)},
+			wantPhase:            v1beta1.StorageScaleDownPhaseDraining,
+			wantStorageReplicas:  3,
+			wantInsertReplicas:   2,
+			wantDrainRestartedAt: true,
+		},
+		{
+			name: "continue drain",
+			cr: newCluster(2, v1beta1.StorageScaleDownDrain, &v1beta1.VMStorageScaleDownStatus{
+				Phase: v1beta1.StorageScaleDownPhaseDraining, FromReplicas: 3, TargetReplicas: 2, DrainStartedAt: &past, DrainFinishAt: &future,
+			}),
+			predefinedObjects:   []runtime.Object{storageSts(3)},
+			wantPhase:           v1beta1.StorageScaleDownPhaseDraining,
+			wantStorageReplicas: 3,
+			wantInsertReplicas:  2,
+		},
+		{
+			name: "restart drain for more removed nodes",
+			cr: newCluster(1, v1beta1.StorageScaleDownDrain, &v1beta1.VMStorageScaleDownStatus{
+				Phase: v1beta1.StorageScaleDownPhaseDraining, FromReplicas: 3, TargetReplicas: 2, DrainStartedAt: &past, DrainFinishAt: &future,
+			}),
+			predefinedObjects:    []runtime.Object{storageSts(3)},
+			wantPhase:            v1beta1.StorageScaleDownPhaseDraining,
+			wantStorageReplicas:  3,
+			wantInsertReplicas:   1,
+			wantDrainRestartedAt: true,
+		},
+		{
+			name: "scale down after retention period",
+			cr: newCluster(2, v1beta1.StorageScaleDownDrain, &v1beta1.VMStorageScaleDownStatus{
+				Phase: v1beta1.StorageScaleDownPhaseDraining, FromReplicas: 3, TargetReplicas: 2, DrainStartedAt: &past, DrainFinishAt: &past,
+			}),
+			predefinedObjects:   []runtime.Object{storageSts(3)},
+			wantPhase:           v1beta1.StorageScaleDownPhaseScalingDown,
+			wantStorageReplicas: 2,
+			wantInsertReplicas:  2,
+		},
+		{
+			name: "scale down finished",
+			cr: newCluster(2, v1beta1.StorageScaleDownDrain, &v1beta1.VMStorageScaleDownStatus{
+				Phase: v1beta1.StorageScaleDownPhaseScalingDown, FromReplicas: 3, TargetReplicas: 2, DrainStartedAt: &past, DrainFinishAt: &past,
+			}),
+			predefinedObjects:   []runtime.Object{storageSts(2)},
+			wantStorageReplicas: 2,
+			wantInsertReplicas:  2,
+		},
+		{
+			name: "scale down canceled",
+			cr: newCluster(3, v1beta1.StorageScaleDownDrain, &v1beta1.VMStorageScaleDownStatus{
+				Phase: v1beta1.StorageScaleDownPhaseDraining, FromReplicas: 3, TargetReplicas: 2, DrainStartedAt: &past, DrainFinishAt: &future,
+			}),
+			predefinedObjects:   []runtime.Object{storageSts(3)},
+			wantStorageReplicas: 3,
+			wantInsertReplicas:  3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fclient := fake.NewFakeClientWithScheme(testGetScheme(), tt.predefinedObjects...)
+			if err := reconcileVMStorageScaleDown(context.TODO(), tt.cr, fclient); err != nil {
+				t.Fatalf("reconcileVMStorageScaleDown() unexpected error: %v", err)
+			}
+			scaleDown := tt.cr.Status.StorageScaleDown
+			var gotPhase string
+			if scaleDown != nil {
+				gotPhase = scaleDown.Phase
+			}
+			if gotPhase != tt.wantPhase {
+				t.Errorf("reconcileVMStorageScaleDown() phase = %q, want %q", gotPhase, tt.wantPhase)
+			}
+			if got := vmStorageReplicas(tt.cr); got != tt.wantStorageReplicas {
+				t.Errorf("vmStorageReplicas() = %d, want %d", got, tt.wantStorageReplicas)
+			}
+			if got := vmStorageInsertReplicas(tt.cr); got != tt.wantInsertReplicas {
+				t.Errorf("vmStorageInsertReplicas() = %d, want %d", got, tt.wantInsertReplicas)
+			}
+			if tt.wantDrainRestartedAt {
+				if scaleDown.DrainStartedAt == nil || scaleDown.DrainStartedAt.Before(&past) || scaleDown.DrainStartedAt.Equal(&past) {
+					t.Errorf("expected drain to be started again, got: %v", scaleDown.DrainStartedAt)
+				}
+				if scaleDown.DrainFinishAt.Sub(scaleDown.DrainStartedAt.Time) != 31*24*time.Hour {
+					t.Errorf("expected drain to last for retention period, got: %v", scaleDown.DrainFinishAt.Sub(scaleDown.DrainStartedAt.Time))
+				}
+			}
+		})
+	}
+}
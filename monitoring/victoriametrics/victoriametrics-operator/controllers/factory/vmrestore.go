@@ -0,0 +1,213 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VictoriaMetrics/operator/api/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	vmRestoreContainerName = "vmrestore"
+	// restore is failed, if vmrestore init container was restarted this number of times
+	vmRestoreMaxRestarts = 3
+)
+
+// resolveVMRestoreSource returns storage and path of backup for the given restore.
+// Storage of VMBackupSchedule is used, if restore has no own storage.
+func resolveVMRestoreSource(ctx context.Context, rclient client.Client, cr *v1beta1.VMRestore) (*v1beta1.BackupStorage, string, error) {
+	if cr.Spec.Storage != nil {
+		if cr.Spec.Source == "" {
+			return nil, "", fmt.Errorf("source must be set for restore from storage")
+		}
+		return cr.Spec.Storage, cr.Spec.Source, nil
+	}
+	if cr.Spec.BackupScheduleName == "" {
+		return nil, "", fmt.Errorf("storage or backupScheduleName must be set")
+	}
+	schedule := &v1beta1.VMBackupSchedule{}
+	if err := rclient.Get(ctx, types.NamespacedName{Name: cr.Spec.BackupScheduleName, Namespace: cr.Namespace}, schedule); err != nil {
+		return nil, "", fmt.Errorf("cannot get vmbackupschedule %s: %w", cr.Spec.BackupScheduleName, err)
+	}
+	source := cr.Spec.Source
+	if source == "" {
+		latest := schedule.LatestSucceededBackup()
+		if latest == nil {
+			return nil, "", fmt.Errorf("vmbackupschedule %s has no succeeded backups", schedule.Name)
+		}
+		source = latest.Destination
+	}
+	return &schedule.Spec.Storage, source, nil
+}
+
+// getActiveVMRestore returns restore, which is in progress for the given target.
+func getActiveVMRestore(ctx context.Context, rclient client.Client, target v1beta1.BackupTarget, namespace string) (*v1beta1.VMRestore, error) {
+	restores := &v1beta1.VMRestoreList{}
+	if err := rclient.List(ctx, restores, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("cannot list vmrestores: %w", err)
+	}
+	for i := range restores.Items {
+		restore := &restores.Items[i]
+		if restore.Spec.Target == target && restore.IsActive() {
+			return restore, nil
+		}
+	}
+	return nil, nil
+}
+
+// StartVMRestore resolves backup source of the given restore and marks it as active,
+// restore fails if its target cannot be restored or another restore of the same target is in progress.
+func StartVMRestore(ctx context.Context, cr *v1beta1.VMRestore, rclient client.Client, c *config.BaseOperatorConf, now time.Time) error {
+	_, source, err := resolveVMRestoreSource(ctx, rclient, cr)
+	if err != nil {
+		failVMRestore(cr, err.Error(), now)
+		return nil
+	}
+	if _, err := getBackupTarget(ctx, rclient, cr.Spec.Target, cr.Namespace, c); err != nil {
+		failVMRestore(cr, err.Error(), now)
+		return nil
+	}
+	active, err := getActiveVMRestore(ctx, rclient, cr.Spec.Target, cr.Namespace)
+	if err != nil {
+		return err
+	}
+	if active != nil && active.Name != cr.Name {
+		failVMRestore(cr, fmt.Sprintf("restore %s of the same target is in progress", active.Name), now)
+		return nil
+	}
+	startTime := metav1.NewTime(now)
+	cr.Status.Phase = v1beta1.RestorePhaseRestoring
+	cr.Status.Source = source
+	cr.Status.StartTime = &startTime
+	cr.Status.Reason = ""
+	return nil
+}
+
+// CheckVMRestoreProgress marks restore as succeeded, when all pods of target were restarted with vmrestore
+// init container and became ready. Restore fails, if vmrestore init container cannot finish.
+func CheckVMRestoreProgress(ctx context.Context, cr *v1beta1.VMRestore, rclient client.Client, c *config.BaseOperatorConf, now time.Time) error {
+	target, err := getBackupTarget(ctx, rclient, cr.Spec.Target, cr.Namespace, c)
+	if err != nil {
+		failVMRestore(cr, err.Error(), now)
+		return nil
+	}
+	pods := &corev1.PodList{}
+	if err := rclient.List(ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels(target.podSelector)); err != nil {
+		return fmt.Errorf("cannot list pods of restore target: %w", err)
+	}
+	var restored int
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Annotations[v1beta1.RestoreAnnotation] != string(cr.UID) {
+			continue
+		}
+		for _, status := range pod.Status.InitContainerStatuses {
+			terminated := status.LastTerminationState.Terminated
+			if status.Name == vmRestoreContainerName && status.RestartCount >= vmRestoreMaxRestarts && terminated != nil && terminated.ExitCode != 0 {
+				failVMRestore(cr, fmt.Sprintf("vmrestore failed at pod %s: %s", pod.Name, terminated.Message), now)
+				return nil
+			}
+		}
+		if PodIsReady(pod) {
+			restored++
+		}
+	}
+	if restored >= len(target.nodes) {
+		completionTime := metav1.NewTime(now)
+		cr.Status.Phase = v1beta1.RestorePhaseSucceeded
+		cr.Status.CompletionTime = &completionTime
+	}
+	return nil
+}
+
+func failVMRestore(cr *v1beta1.VMRestore, reason string, now time.Time) {
+	completionTime := metav1.NewTime(now)
+	cr.Status.Phase = v1beta1.RestorePhaseFailed
+	cr.Status.CompletionTime = &completionTime
+	cr.Status.Reason = reason
+}
+
+// applyVMRestore adds vmrestore init container into pod template of restore target, if restore is in progress.
+// Pods are restarted after template change and data is restored before start of VictoriaMetrics.
+func applyVMRestore(ctx context.Context, rclient client.Client, template *corev1.PodTemplateSpec, target v1beta1.BackupTarget, namespace string, c *config.BaseOperatorConf) error {
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string)
+	}
+	delete(template.Annotations, v1beta1.RestoreAnnotation)
+	restore, err := getActiveVMRestore(ctx, rclient, target, namespace)
+	if err != nil || restore == nil {
+		return err
+	}
+	storage, _, err := resolveVMRestoreSource(ctx, rclient, restore)
+	if err != nil {
+		return fmt.Errorf("cannot resolve source of vmrestore %s: %w", restore.Name, err)
+	}
+	bt, err := getBackupTarget(ctx, rclient, target, namespace, c)
+	if err != nil {
+		return err
+	}
+	container, volumes := genVMRestoreInitContainer(restore, *storage, bt, c)
+	template.Spec.InitContainers = append([]corev1.Container{container}, template.Spec.InitContainers...)
+	template.Spec.Volumes = append(template.Spec.Volumes, volumes...)
+	template.Annotations[v1beta1.RestoreAnnotation] = string(restore.UID)
+	return nil
+}
+
+// genVMRestoreInitContainer generates vmrestore init container and its volumes.
+// Each vmstorage node of cluster is restored from own sub-path of backup.
+func genVMRestoreInitContainer(cr *v1beta1.VMRestore, storage v1beta1.BackupStorage, target *backupTarget, c *config.BaseOperatorConf) (corev1.Container, []corev1.Volume) {
+	image := cr.Spec.Image
+	if image.Repository == "" {
+		image.Repository = c.VMUtilsDefault.RestoreImage
+	}
+	if image.Tag == "" {
+		image.Tag = c.VMUtilsDefault.Version
+	}
+	if image.PullPolicy == "" {
+		image.PullPolicy = corev1.PullIfNotPresent
+	}
+
+	var envs []corev1.EnvVar
+	src := cr.Status.Source
+	if target.isCluster {
+		envs = append(envs, corev1.EnvVar{
+			Name: "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+			},
+		})
+		src = fmt.Sprintf("%s/$(POD_NAME)", src)
+	}
+	args := []string{
+		fmt.Sprintf("-storageDataPath=%s", target.dataPath),
+		fmt.Sprintf("-src=%s", src),
+	}
+	args = append(args, backupStorageArgs(storage, cr.Spec.Concurrency)...)
+	for arg, value := range cr.Spec.ExtraArgs {
+		args = append(args, fmt.Sprintf("-%s=%s", arg, value))
+	}
+
+	volumes, mounts := backupStorageVolumes(storage)
+	mounts = append([]corev1.VolumeMount{
+		{
+			Name:      target.dataVolume,
+			MountPath: target.dataPath,
+		},
+	}, mounts...)
+
+	return corev1.Container{
+		Name:                     vmRestoreContainerName,
+		Image:                    fmt.Sprintf("%s:%s", image.Repository, image.Tag),
+		ImagePullPolicy:          image.PullPolicy,
+		Args:                     args,
+		Env:                      envs,
+		VolumeMounts:             mounts,
+		Resources:                cr.Spec.Resources,
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}, volumes
+}
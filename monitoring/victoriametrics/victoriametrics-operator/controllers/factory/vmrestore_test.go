@@ -0,0 +1,259 @@
+package factory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/operator/api/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStartVMRestore(t *testing.T) {
+	now := time.Now()
+	vmSingle := &v1beta1.VMSingle{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "single"},
+		Spec:       v1beta1.VMSingleSpec{Storage: &corev1.PersistentVolumeClaimSpec{}},
+	}
+	schedule := &v1beta1.VMBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "backup"},
+		Spec: v1beta1.VMBackupScheduleSpec{
+			Storage: v1beta1.BackupStorage{Destination: "s3://bucket/backups"},
+		},
+		Status: v1beta1.VMBackupScheduleStatus{
+			Backups: []v1beta1.BackupRecord{
+				{Name: "vmbackup-backup-1", Destination: "s3://bucket/backups/1", Phase: v1beta1.BackupPhaseSucceeded},
+				{Name: "vmbackup-backup-2", Destination: "s3://bucket/backups/2", Phase: v1beta1.BackupPhaseFailed},
+			},
+		},
+	}
+	newRestore := func(name string, spec v1beta1.VMRestoreSpec, phase string) *v1beta1.VMRestore {
+		spec.Target = v1beta1.BackupTarget{Kind: v1beta1.BackupTargetVMSingle, Name: "single"}
+		return &v1beta1.VMRestore{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       spec,
+			Status:     v1beta1.VMRestoreStatus{Phase: phase},
+		}
+	}
+	tests := []struct {
+		name              string
+		cr                *v1beta1.VMRestore
+		predefinedObjects []runtime.Object
+		wantPhase         string
+		wantSource        string
+	}{
+		{
+			name:       "restore latest succeeded backup",
+			cr:         newRestore("restore", v1beta1.VMRestoreSpec{BackupScheduleName: "backup"}, ""),
+			wantPhase:  v1beta1.RestorePhaseRestoring,
+			wantSource: "s3://bucket/backups/1",
+		},
+		{
+			name: "restore from storage",
+			cr: newRestore("restore", v1beta1.VMRestoreSpec{
+				Storage: &v1beta1.BackupStorage{Destination: "fs:///backups"},
+				Source:  "fs:///backups/latest",
+			}, ""),
+			wantPhase:  v1beta1.RestorePhaseRestoring,
+			wantSource: "fs:///backups/latest",
+		},
+		{
+			name:      "fail without source",
+			cr:        newRestore("restore", v1beta1.VMRestoreSpec{Storage: &v1beta1.BackupStorage{Destination: "fs:///backups"}}, ""),
+			wantPhase: v1beta1.RestorePhaseFailed,
+		},
+		{
+			name:              "fail with restore in progress",
+			cr:                newRestore("restore", v1beta1.VMRestoreSpec{BackupScheduleName: "backup"}, ""),
+			predefinedObjects: []runtime.Object{newRestore("restore-0", v1beta1.VMRestoreSpec{BackupScheduleName: "backup"}, v1beta1.RestorePhaseRestoring)},
+			wantPhase:         v1beta1.RestorePhaseFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := append([]runtime.Object{vmSingle, schedule}, tt.predefinedObjects...)
+			fclient := fake.NewFakeClientWithScheme(testGetScheme(), obj...)
+			if err := StartVMRestore(context.TODO(), tt.cr, fclient, config.MustGetBaseConfig(), now); err != nil {
+				t.Fatalf("StartVMRestore() error = %v", err)
+			}
+			if tt.cr.Status.Phase != tt.wantPhase {
+				t.Errorf("StartVMRestore() phase = %v, want %v, reason: %s", tt.cr.Status.Phase, tt.wantPhase, tt.cr.Status.Reason)
+			}
+			if tt.cr.Status.Source != tt.wantSource {
+				t.Errorf("StartVMRestore() source = %v, want %v", tt.cr.Status.Source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestCheckVMRestoreProgress(t *testing.T) {
+	cluster := &v1beta1.VMCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster"},
+		Spec: v1beta1.VMClusterSpec{
+			VMStorage: &v1beta1.VMStorage{
+				ReplicaCount: pointer.Int32Ptr(2),
+				Storage:      &v1beta1.StorageSpec{},
+			},
+		},
+	}
+	restore := &v1beta1.VMRestore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "restore", UID: "restore-uid"},
+		Spec: v1beta1.VMRestoreSpec{
+			Target: v1beta1.BackupTarget{Kind: v1beta1.BackupTargetVMCluster, Name: "cluster"},
+		},
+		Status: v1beta1.VMRestoreStatus{Phase: v1beta1.RestorePhaseRestoring},
+	}
+	newPod := func(name, restoreUID string, ready bool, initStatus *corev1.ContainerStatus) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				Labels:      cluster.VMStorageSelectorLabels(),
+				Annotations: map[string]string{v1beta1.RestoreAnnotation: restoreUID},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		}
+		if ready {
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		if initStatus != nil {
+			pod.Status.InitContainerStatuses = []corev1.ContainerStatus{*initStatus}
+		}
+		return pod
+	}
+	tests := []struct {
+		name              string
+		predefinedObjects []runtime.Object
+		wantPhase         string
+	}{
+		{
+			name: "all pods restored",
+			predefinedObjects: []runtime.Object{
+				newPod("vmstorage-cluster-0", "restore-uid", true, nil),
+				newPod("vmstorage-cluster-1", "restore-uid", true, nil),
+			},
+			wantPhase: v1beta1.RestorePhaseSucceeded,
+		},
+		{
+			name: "pod is not restarted yet",
+			predefinedObjects: []runtime.Object{
+				newPod("vmstorage-cluster-0", "restore-uid", true, nil),
+				newPod("vmstorage-cluster-1", "", true, nil),
+			},
+			wantPhase: v1beta1.RestorePhaseRestoring,
+		},
+		{
+			name: "vmrestore init container fails",
+			predefinedObjects: []runtime.Object{
+				newPod("vmstorage-cluster-0", "restore-uid", true, nil),
+				newPod("vmstorage-cluster-1", "restore-uid", false, &corev1.ContainerStatus{
+					Name:         vmRestoreContainerName,
+					RestartCount: vmRestoreMaxRestarts,
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "cannot find backup"},
+					},
+				}),
+			},
+			wantPhase: v1beta1.RestorePhaseFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := restore.DeepCopy()
+			obj := append([]runtime.Object{cluster}, tt.predefinedObjects...)
+			fclient := fake.NewFakeClientWithScheme(testGetScheme(), obj...)
+			if err := CheckVMRestoreProgress(context.TODO(), cr, fclient, config.MustGetBaseConfig(), time.Now()); err != nil {
+				t.Fatalf("CheckVMRestoreProgress() error = %v", err)
+			}
+			if cr.Status.Phase != tt.wantPhase {
+				t.Errorf("CheckVMRestoreProgress() phase = %v, want %v, reason: %s", cr.Status.Phase, tt.wantPhase, cr.Status.Reason)
+			}
+		})
+	}
+}
+
+func Test_applyVMRestore(t *testing.T) {
+	cluster := &v1beta1.VMCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster"},
+		Spec: v1beta1.VMClusterSpec{
+			VMStorage: &v1beta1.VMStorage{
+				ReplicaCount: pointer.Int32Ptr(2),
+				Storage:      &v1beta1.StorageSpec{},
+			},
+		},
+	}
+	target := v1beta1.BackupTarget{Kind: v1beta1.BackupTargetVMCluster, Name: "cluster"}
+	restore := &v1beta1.VMRestore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "restore", UID: "restore-uid"},
+		Spec: v1beta1.VMRestoreSpec{
+			Target: target,
+			Storage: &v1beta1.BackupStorage{
+				Destination:       "s3://bucket/backups",
+				CredentialsSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "s3"}, Key: "creds"},
+			},
+			Source: "s3://bucket/backups/1",
+		},
+		Status: v1beta1.VMRestoreStatus{Phase: v1beta1.RestorePhaseRestoring, Source: "s3://bucket/backups/1"},
+	}
+	finished := restore.DeepCopy()
+	finished.Status.Phase = v1beta1.RestorePhaseSucceeded
+
+	tests := []struct {
+		name              string
+		predefinedObjects []runtime.Object
+		wantInitContainer bool
+	}{
+		{
+			name:              "restore in progress",
+			predefinedObjects: []runtime.Object{restore},
+			wantInitContainer: true,
+		},
+		{
+			name:              "restore finished",
+			predefinedObjects: []runtime.Object{finished},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := append([]runtime.Object{cluster}, tt.predefinedObjects...)
+			fclient := fake.NewFakeClientWithScheme(testGetScheme(), obj...)
+			template := &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1beta1.RestoreAnnotation: "old-uid"}},
+			}
+			if err := applyVMRestore(context.TODO(), fclient, template, target, "default", config.MustGetBaseConfig()); err != nil {
+				t.Fatalf("applyVMRestore() error = %v", err)
+			}
+			if !tt.wantInitContainer {
+				if len(template.Spec.InitContainers) != 0 || template.Annotations[v1beta1.RestoreAnnotation] != "" {
+					t.Fatalf("applyVMRestore() unexpected restore at pod template: %v", template)
+				}
+				return
+			}
+			if template.Annotations[v1beta1.RestoreAnnotation] != "restore-uid" {
+				t.Errorf("applyVMRestore() annotation = %q, want restore-uid", template.Annotations[v1beta1.RestoreAnnotation])
+			}
+			if len(template.Spec.InitContainers) != 1 {
+				t.Fatalf("applyVMRestore() init containers = %d, want 1", len(template.Spec.InitContainers))
+			}
+			args := template.Spec.InitContainers[0].Args
+			wantArgs := []string{
+				"-storageDataPath=vmstorage-data",
+				"-src=s3://bucket/backups/1/$(POD_NAME)",
+				"-credsFilePath=/etc/vm/creds/creds",
+			}
+			if !reflect.DeepEqual(args, wantArgs) {
+				t.Errorf("applyVMRestore() args = %v, want %v", args, wantArgs)
+			}
+			if len(template.Spec.Volumes) != 1 || template.Spec.Volumes[0].Name != backupCredsVolumeName {
+				t.Errorf("applyVMRestore() volumes = %v, want creds volume", template.Spec.Volumes)
+			}
+		})
+	}
+}
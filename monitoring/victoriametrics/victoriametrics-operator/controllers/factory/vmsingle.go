@@ -83,6 +83,10 @@ func CreateOrUpdateVMSingle(ctx context.Context, cr *victoriametricsv1beta1.VMSi
 	if err != nil {
 		return nil, fmt.Errorf("cannot generate new deploy for vmsingle: %w", err)
 	}
+	target := victoriametricsv1beta1.BackupTarget{Kind: victoriametricsv1beta1.BackupTargetVMSingle, Name: cr.Name}
+	if err := applyVMRestore(ctx, rclient, &newDeploy.Spec.Template, target, cr.Namespace, c); err != nil {
+		return nil, fmt.Errorf("cannot apply vmrestore to vmsingle deploy: %w", err)
+	}
 
 	l = l.WithValues("single.deploy.name", newDeploy.Name, "single.deploy.namespace", newDeploy.Namespace)
 
@@ -107,6 +111,10 @@ func CreateOrUpdateVMSingle(ctx context.Context, cr *victoriametricsv1beta1.VMSi
 	}
 
 	for annotation, value := range currentDeploy.Spec.Template.Annotations {
+		// restore annotation is managed by applyVMRestore
+		if annotation == victoriametricsv1beta1.RestoreAnnotation {
+			continue
+		}
 		newDeploy.Spec.Template.Annotations[annotation] = value
 	}
 
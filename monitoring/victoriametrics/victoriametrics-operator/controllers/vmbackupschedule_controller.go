@@ -0,0 +1,88 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/VictoriaMetrics/operator/controllers/factory"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	victoriametricsv1beta1 "github.com/VictoriaMetrics/operator/api/v1beta1"
+)
+
+// VMBackupScheduleReconciler reconciles a VMBackupSchedule object
+type VMBackupScheduleReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	BaseConf *config.BaseOperatorConf
+}
+
+// Reconcile - starts backups by schedule, tracks their jobs and removes old backups
+// +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmbackupschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmbackupschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=*
+func (r *VMBackupScheduleReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("vmbackupschedule", req.NamespacedName)
+	reqLogger.Info("Reconciling vmbackupschedule")
+
+	ctx := context.Background()
+	instance := &victoriametricsv1beta1.VMBackupSchedule{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	prevStatus := instance.Status.DeepCopy()
+	next, err := factory.ReconcileVMBackupSchedule(ctx, instance, r, r.BaseConf, time.Now())
+	if err != nil {
+		reqLogger.Error(err, "cannot reconcile vmbackupschedule")
+		instance.Status.Reason = err.Error()
+	}
+	if !equality.Semantic.DeepEqual(prevStatus, &instance.Status) {
+		if updateErr := r.Status().Update(ctx, instance); updateErr != nil {
+			reqLogger.Error(updateErr, "cannot update vmbackupschedule status")
+			return ctrl.Result{}, updateErr
+		}
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	reqLogger.Info("vmbackupschedule reconciled", "nextBackupAfter", next.String())
+	return ctrl.Result{RequeueAfter: next}, nil
+}
+
+// SetupWithManager general setup method
+func (r *VMBackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&victoriametricsv1beta1.VMBackupSchedule{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
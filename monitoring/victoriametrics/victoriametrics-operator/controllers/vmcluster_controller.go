@@ -13,6 +13,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"time"
 )
 
@@ -60,6 +61,13 @@ func (r *VMClusterReconciler) Reconcile(request ctrl.Request) (ctrl.Result, erro
 		}, nil
 	}
 
+	if scaleDown := cluster.Status.StorageScaleDown; scaleDown != nil && scaleDown.Phase == victoriametricsv1beta1.StorageScaleDownPhaseDraining && scaleDown.DrainFinishAt != nil {
+		reqLogger.Info("vmstorage nodes are draining, requeue request after drain finish", "drainFinishAt", scaleDown.DrainFinishAt.String())
+		return reconcile.Result{
+			RequeueAfter: time.Until(scaleDown.DrainFinishAt.Time),
+		}, nil
+	}
+
 	reqLogger.Info("cluster was reconciled")
 
 	return reconcile.Result{}, nil
@@ -71,5 +79,6 @@ func (r *VMClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&victoriametricsv1beta1.VMCluster{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.StatefulSet{}).
+		Watches(&source.Kind{Type: &victoriametricsv1beta1.VMRestore{}}, restoreTargetRequests(victoriametricsv1beta1.BackupTargetVMCluster)).
 		Complete(r)
 }
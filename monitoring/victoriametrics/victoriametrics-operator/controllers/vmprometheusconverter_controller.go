@@ -9,7 +9,9 @@ import (
 	v1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/coreos/prometheus-operator/pkg/client/versioned"
 	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -19,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
@@ -46,6 +49,14 @@ const (
 	IgnoreConversionLabel = "operator.victoriametrics.com/ignore-prometheus-updates"
 	// IgnoreConversion - disables updates from prometheus api
 	IgnoreConversion = "enabled"
+
+	// PrometheusSourceUIDAnnotation contains uid of prometheus api object, which VMObject was converted from.
+	// It's set by operator and used for VMObject deletion, when prometheus object was deleted
+	// annotations:
+	//  operator.victoriametrics.com/prometheus-source-uid: 0e2d5a8e-7b7c-4ba4-8b0a-d1a27d0e9c4b
+	PrometheusSourceUIDAnnotation = "operator.victoriametrics.com/prometheus-source-uid"
+	// ConversionFailedReason - event reason for failed conversion of prometheus object
+	ConversionFailedReason = "ConversionFailed"
 )
 
 // ConverterController - watches for prometheus objects
@@ -57,13 +68,17 @@ type ConverterController struct {
 	podInf     cache.SharedInformer
 	serviceInf cache.SharedInformer
 	probeInf   cache.SharedIndexInformer
+	recorder   record.EventRecorder
+	baseConf   *config.BaseOperatorConf
 }
 
 // NewConverterController builder for vmprometheusconverter service
-func NewConverterController(promCl versioned.Interface, vclient client.Client) *ConverterController {
+func NewConverterController(promCl versioned.Interface, vclient client.Client, recorder record.EventRecorder, baseConf *config.BaseOperatorConf) *ConverterController {
 	c := &ConverterController{
 		promClient: promCl,
 		vclient:    vclient,
+		recorder:   recorder,
+		baseConf:   baseConf,
 	}
 	c.ruleInf = cache.NewSharedIndexInformer(
 		&cache.ListWatch{
@@ -81,6 +96,7 @@ func NewConverterController(promCl versioned.Interface, vclient client.Client) *
 	c.ruleInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.CreatePrometheusRule,
 		UpdateFunc: c.UpdatePrometheusRule,
+		DeleteFunc: c.DeletePrometheusRule,
 	})
 	c.podInf = cache.NewSharedIndexInformer(
 		&cache.ListWatch{
@@ -98,6 +114,7 @@ func NewConverterController(promCl versioned.Interface, vclient client.Client) *
 	c.podInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.CreatePodMonitor,
 		UpdateFunc: c.UpdatePodMonitor,
+		DeleteFunc: c.DeletePodMonitor,
 	})
 	c.serviceInf = cache.NewSharedIndexInformer(
 		&cache.ListWatch{
@@ -115,6 +132,7 @@ func NewConverterController(promCl versioned.Interface, vclient client.Client) *
 	c.serviceInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.CreateServiceMonitor,
 		UpdateFunc: c.UpdateServiceMonitor,
+		DeleteFunc: c.DeleteServiceMonitor,
 	})
 	c.probeInf = cache.NewSharedIndexInformer(
 		&cache.ListWatch{
@@ -132,6 +150,7 @@ func NewConverterController(promCl versioned.Interface, vclient client.Client) *
 	c.probeInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.CreateProbe,
 		UpdateFunc: c.UpdateProbe,
+		DeleteFunc: c.DeleteProbe,
 	})
 	return c
 }
@@ -213,14 +232,18 @@ func (c *ConverterController) CreatePrometheusRule(rule interface{}) {
 	l := log.WithValues("kind", "alertRule", "name", promRule.Name, "ns", promRule.Namespace)
 	l.Info("syncing prom rule with VMRule")
 	cr := converter.ConvertPromRule(promRule)
+	c.trackSource(&cr.ObjectMeta, v1.PrometheusRuleKind, promRule.ObjectMeta)
 
 	err := c.vclient.Create(context.Background(), cr)
 	if err != nil {
 		if errors.IsAlreadyExists(err) {
-			l.Info("AlertRule already exists")
+			// object could be changed, while operator was down
+			l.Info("AlertRule already exists, updating it")
+			c.UpdatePrometheusRule(nil, rule)
 			return
 		}
 		l.Error(err, "cannot create AlertRule from Prometheusrule")
+		c.recordConversionError(promRule, "VMRule", err)
 		return
 	}
 	l.Info("AlertRule was created")
@@ -236,40 +259,70 @@ func (c *ConverterController) UpdatePrometheusRule(old, new interface{}) {
 	existingVMRule := &v1beta1.VMRule{}
 	err := c.vclient.Get(ctx, types.NamespacedName{Name: VMRule.Name, Namespace: VMRule.Namespace}, existingVMRule)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			l.Info("VMRule doesnt exist, creating it")
+			c.trackSource(&VMRule.ObjectMeta, v1.PrometheusRuleKind, promRuleNew.ObjectMeta)
+			if err := c.vclient.Create(ctx, VMRule); err != nil {
+				l.Error(err, "cannot create VMRule")
+				c.recordConversionError(promRuleNew, "VMRule", err)
+			}
+			return
+		}
 		l.Error(err, "cannot get existing VMRule")
+		c.recordConversionError(promRuleNew, "VMRule", err)
 		return
 	}
 	if existingVMRule.Annotations[IgnoreConversionLabel] == IgnoreConversion {
 		l.Info("syncing for object was disabled by annotation", "annotation", IgnoreConversionLabel)
 		return
 	}
+	if !isConvertedFrom(existingVMRule, promRuleNew.UID) {
+		l.Info("VMRule already exists and wasnt converted from PrometheusRule, skipping it")
+		return
+	}
 	existingVMRule.Spec = VMRule.Spec
 	metaMergeStrategy := getMetaMergeStrategy(existingVMRule.Annotations)
 	existingVMRule.Annotations = mergeLabelsWithStrategy(existingVMRule.Annotations, VMRule.Annotations, metaMergeStrategy)
 	existingVMRule.Labels = mergeLabelsWithStrategy(existingVMRule.Labels, VMRule.Labels, metaMergeStrategy)
+	c.trackSource(&existingVMRule.ObjectMeta, v1.PrometheusRuleKind, promRuleNew.ObjectMeta)
 
 	err = c.vclient.Update(ctx, existingVMRule)
 	if err != nil {
 		l.Error(err, "cannot update VMRule")
+		c.recordConversionError(promRuleNew, "VMRule", err)
 		return
 	}
 	l.Info("VMRule was updated")
 
 }
 
+// DeletePrometheusRule deletes VMRule, converted from PrometheusRule
+func (c *ConverterController) DeletePrometheusRule(rule interface{}) {
+	promRule, ok := unwrapDeleted(rule).(*v1.PrometheusRule)
+	if !ok {
+		log.Info("unexpected object at PrometheusRule delete handler", "object", rule)
+		return
+	}
+	c.deleteConverted(promRule.ObjectMeta, &v1beta1.VMRule{}, "VMRule")
+}
+
 // CreateServiceMonitor converts ServiceMonitor to VMServiceScrape
 func (c *ConverterController) CreateServiceMonitor(service interface{}) {
 	serviceMon := service.(*v1.ServiceMonitor)
 	l := log.WithValues("kind", "vmServiceScrape", "name", serviceMon.Name, "ns", serviceMon.Namespace)
 	l.Info("syncing vmServiceScrape")
 	vmServiceScrape := converter.ConvertServiceMonitor(serviceMon)
+	c.trackSource(&vmServiceScrape.ObjectMeta, v1.ServiceMonitorsKind, serviceMon.ObjectMeta)
 	err := c.vclient.Create(context.Background(), vmServiceScrape)
 	if err != nil {
 		if errors.IsAlreadyExists(err) {
-			l.Info("vmServiceScrape exists")
+			// object could be changed, while operator was down
+			l.Info("vmServiceScrape exists, updating it")
+			c.UpdateServiceMonitor(nil, service)
 			return
 		}
 		l.Error(err, "cannot create vmServiceScrape")
+		c.recordConversionError(serviceMon, "VMServiceScrape", err)
 		return
 	}
 	l.Info("vmServiceScrape was created")
@@ -285,7 +338,17 @@ func (c *ConverterController) UpdateServiceMonitor(old, new interface{}) {
 	ctx := context.Background()
 	err := c.vclient.Get(ctx, types.NamespacedName{Name: vmServiceScrape.Name, Namespace: vmServiceScrape.Namespace}, existingVMServiceScrape)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			l.Info("vmServiceScrape doesnt exist, creating it")
+			c.trackSource(&vmServiceScrape.ObjectMeta, v1.ServiceMonitorsKind, serviceMonNew.ObjectMeta)
+			if err := c.vclient.Create(ctx, vmServiceScrape); err != nil {
+				l.Error(err, "cannot create vmServiceScrape")
+				c.recordConversionError(serviceMonNew, "VMServiceScrape", err)
+			}
+			return
+		}
 		l.Error(err, "cannot get existing vmServiceScrape")
+		c.recordConversionError(serviceMonNew, "VMServiceScrape", err)
 		return
 	}
 
@@ -293,32 +356,52 @@ func (c *ConverterController) UpdateServiceMonitor(old, new interface{}) {
 		l.Info("syncing for object was disabled by annotation", "annotation", IgnoreConversionLabel)
 		return
 	}
+	if !isConvertedFrom(existingVMServiceScrape, serviceMonNew.UID) {
+		l.Info("VMServiceScrape already exists and wasnt converted from ServiceMonitor, skipping it")
+		return
+	}
 	existingVMServiceScrape.Spec = vmServiceScrape.Spec
 
 	metaMergeStrategy := getMetaMergeStrategy(existingVMServiceScrape.Annotations)
 	existingVMServiceScrape.Annotations = mergeLabelsWithStrategy(existingVMServiceScrape.Annotations, vmServiceScrape.Annotations, metaMergeStrategy)
 	existingVMServiceScrape.Labels = mergeLabelsWithStrategy(existingVMServiceScrape.Labels, vmServiceScrape.Labels, metaMergeStrategy)
+	c.trackSource(&existingVMServiceScrape.ObjectMeta, v1.ServiceMonitorsKind, serviceMonNew.ObjectMeta)
 	err = c.vclient.Update(ctx, existingVMServiceScrape)
 	if err != nil {
 		l.Error(err, "cannot update")
+		c.recordConversionError(serviceMonNew, "VMServiceScrape", err)
 		return
 	}
 	l.Info("vmServiceScrape was updated")
 }
 
+// DeleteServiceMonitor deletes VMServiceScrape, converted from ServiceMonitor
+func (c *ConverterController) DeleteServiceMonitor(service interface{}) {
+	serviceMon, ok := unwrapDeleted(service).(*v1.ServiceMonitor)
+	if !ok {
+		log.Info("unexpected object at ServiceMonitor delete handler", "object", service)
+		return
+	}
+	c.deleteConverted(serviceMon.ObjectMeta, &v1beta1.VMServiceScrape{}, "VMServiceScrape")
+}
+
 // CreatePodMonitor converts PodMonitor to VMPodScrape
 func (c *ConverterController) CreatePodMonitor(pod interface{}) {
 	podMonitor := pod.(*v1.PodMonitor)
 	l := log.WithValues("kind", "podScrape", "name", podMonitor.Name, "ns", podMonitor.Namespace)
 	l.Info("syncing podScrape")
 	podScrape := converter.ConvertPodMonitor(podMonitor)
+	c.trackSource(&podScrape.ObjectMeta, v1.PodMonitorsKind, podMonitor.ObjectMeta)
 	err := c.vclient.Create(context.TODO(), podScrape)
 	if err != nil {
 		if errors.IsAlreadyExists(err) {
-			l.Info("podScrape already exists")
+			// object could be changed, while operator was down
+			l.Info("podScrape already exists, updating it")
+			c.UpdatePodMonitor(nil, pod)
 			return
 		}
 		l.Error(err, "cannot create podScrape")
+		c.recordConversionError(podMonitor, "VMPodScrape", err)
 		return
 	}
 	log.Info("podScrape was created")
@@ -334,28 +417,54 @@ func (c *ConverterController) UpdatePodMonitor(old, new interface{}) {
 	existingVMPodScrape := &v1beta1.VMPodScrape{}
 	err := c.vclient.Get(ctx, types.NamespacedName{Name: podScrape.Name, Namespace: podScrape.Namespace}, existingVMPodScrape)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			l.Info("podScrape doesnt exist, creating it")
+			c.trackSource(&podScrape.ObjectMeta, v1.PodMonitorsKind, podMonitorNew.ObjectMeta)
+			if err := c.vclient.Create(ctx, podScrape); err != nil {
+				l.Error(err, "cannot create podScrape")
+				c.recordConversionError(podMonitorNew, "VMPodScrape", err)
+			}
+			return
+		}
 		l.Error(err, "cannot get existing podMonitor")
+		c.recordConversionError(podMonitorNew, "VMPodScrape", err)
 		return
 	}
 	if existingVMPodScrape.Annotations[IgnoreConversionLabel] == IgnoreConversion {
 		l.Info("syncing for object was disabled by annotation", "annotation", IgnoreConversionLabel)
 		return
 	}
+	if !isConvertedFrom(existingVMPodScrape, podMonitorNew.UID) {
+		l.Info("VMPodScrape already exists and wasnt converted from PodMonitor, skipping it")
+		return
+	}
 
 	existingVMPodScrape.Spec = podScrape.Spec
 	mergeStrategy := getMetaMergeStrategy(existingVMPodScrape.Annotations)
 	existingVMPodScrape.Annotations = mergeLabelsWithStrategy(existingVMPodScrape.Annotations, podScrape.Annotations, mergeStrategy)
 	existingVMPodScrape.Labels = mergeLabelsWithStrategy(existingVMPodScrape.Labels, podScrape.Labels, mergeStrategy)
+	c.trackSource(&existingVMPodScrape.ObjectMeta, v1.PodMonitorsKind, podMonitorNew.ObjectMeta)
 
 	err = c.vclient.Update(ctx, existingVMPodScrape)
 	if err != nil {
 		l.Error(err, "cannot update podScrape")
+		c.recordConversionError(podMonitorNew, "VMPodScrape", err)
 		return
 	}
 	l.Info("podScrape was updated")
 
 }
 
+// DeletePodMonitor deletes VMPodScrape, converted from PodMonitor
+func (c *ConverterController) DeletePodMonitor(pod interface{}) {
+	podMonitor, ok := unwrapDeleted(pod).(*v1.PodMonitor)
+	if !ok {
+		log.Info("unexpected object at PodMonitor delete handler", "object", pod)
+		return
+	}
+	c.deleteConverted(podMonitor.ObjectMeta, &v1beta1.VMPodScrape{}, "VMPodScrape")
+}
+
 // default merge strategy - prefer-prometheus
 // old - from vm
 // new - from prometheus
@@ -402,13 +511,17 @@ func (c *ConverterController) CreateProbe(obj interface{}) {
 	l := log.WithValues("kind", "vmProbe", "name", probe.Name, "ns", probe.Namespace)
 	l.Info("syncing probes")
 	vmProbe := converter.ConvertProbe(probe)
+	c.trackSource(&vmProbe.ObjectMeta, v1.ProbesKind, probe.ObjectMeta)
 	err := c.vclient.Create(context.TODO(), vmProbe)
 	if err != nil {
 		if errors.IsAlreadyExists(err) {
-			l.Info("vmProbe already exists")
+			// object could be changed, while operator was down
+			l.Info("vmProbe already exists, updating it")
+			c.UpdateProbe(nil, obj)
 			return
 		}
 		l.Error(err, "cannot create vmProbe")
+		c.recordConversionError(probe, "VMProbe", err)
 		return
 	}
 	log.Info("vmProbe was created")
@@ -424,24 +537,141 @@ func (c *ConverterController) UpdateProbe(old, new interface{}) {
 	existingVMProbe := &v1beta1.VMProbe{}
 	err := c.vclient.Get(ctx, types.NamespacedName{Name: vmProbe.Name, Namespace: vmProbe.Namespace}, existingVMProbe)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			l.Info("vmProbe doesnt exist, creating it")
+			c.trackSource(&vmProbe.ObjectMeta, v1.ProbesKind, probeNew.ObjectMeta)
+			if err := c.vclient.Create(ctx, vmProbe); err != nil {
+				l.Error(err, "cannot create vmProbe")
+				c.recordConversionError(probeNew, "VMProbe", err)
+			}
+			return
+		}
 		l.Error(err, "cannot get existing vmProbe")
+		c.recordConversionError(probeNew, "VMProbe", err)
 		return
 	}
 	if existingVMProbe.Annotations[IgnoreConversionLabel] == IgnoreConversion {
 		l.Info("syncing for object was disabled by annotation", "annotation", IgnoreConversionLabel)
 		return
 	}
+	if !isConvertedFrom(existingVMProbe, probeNew.UID) {
+		l.Info("VMProbe already exists and wasnt converted from Probe, skipping it")
+		return
+	}
 
 	mergeStrategy := getMetaMergeStrategy(existingVMProbe.Annotations)
 	existingVMProbe.Annotations = mergeLabelsWithStrategy(existingVMProbe.Annotations, probeNew.Annotations, mergeStrategy)
 	existingVMProbe.Labels = mergeLabelsWithStrategy(existingVMProbe.Labels, probeNew.Labels, mergeStrategy)
+	c.trackSource(&existingVMProbe.ObjectMeta, v1.ProbesKind, probeNew.ObjectMeta)
 
 	existingVMProbe.Spec = vmProbe.Spec
 	err = c.vclient.Update(ctx, existingVMProbe)
 	if err != nil {
 		l.Error(err, "cannot update vmProbe")
+		c.recordConversionError(probeNew, "VMProbe", err)
 		return
 	}
 	l.Info("vmProbe was updated")
 
 }
+
+// DeleteProbe deletes VMProbe, converted from Probe
+func (c *ConverterController) DeleteProbe(obj interface{}) {
+	probe, ok := unwrapDeleted(obj).(*v1.Probe)
+	if !ok {
+		log.Info("unexpected object at Probe delete handler", "object", obj)
+		return
+	}
+	c.deleteConverted(probe.ObjectMeta, &v1beta1.VMProbe{}, "VMProbe")
+}
+
+// trackSource marks VMObject as converted from the given prometheus object.
+// Source uid is always saved into annotation, owner reference is added only if it was enabled by config,
+// in this case kubernetes garbage collector removes VMObject even if operator wasn't running.
+func (c *ConverterController) trackSource(vmMeta *metav1.ObjectMeta, kind string, source metav1.ObjectMeta) {
+	// annotations map may be shared with the informer cache, it must not be modified in place
+	annotations := make(map[string]string, len(vmMeta.Annotations)+1)
+	for k, v := range vmMeta.Annotations {
+		annotations[k] = v
+	}
+	annotations[PrometheusSourceUIDAnnotation] = string(source.UID)
+	vmMeta.Annotations = annotations
+
+	if !c.baseConf.EnabledPrometheusConverterOwnerReferences {
+		return
+	}
+	for _, ref := range vmMeta.OwnerReferences {
+		if ref.UID == source.UID {
+			return
+		}
+	}
+	vmMeta.OwnerReferences = append(vmMeta.OwnerReferences, metav1.OwnerReference{
+		APIVersion: v1.SchemeGroupVersion.String(),
+		Kind:       kind,
+		Name:       source.Name,
+		UID:        source.UID,
+	})
+}
+
+// isConvertedFrom checks if VMObject was converted from prometheus object with given uid.
+// VMObjects without source annotation or owner reference, created manually or converted by older operator versions,
+// are never updated or deleted. Such objects can be adopted by setting PrometheusSourceUIDAnnotation to the uid
+// of prometheus object or by deleting them, they are converted again at the next update or resync.
+func isConvertedFrom(vmMeta metav1.Object, uid types.UID) bool {
+	if vmMeta.GetAnnotations()[PrometheusSourceUIDAnnotation] == string(uid) {
+		return true
+	}
+	for _, ref := range vmMeta.GetOwnerReferences() {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteConverted removes VMObject with the same name as deleted prometheus object.
+// VMObjects, which wasn't converted from it or has disabled updates are kept.
+func (c *ConverterController) deleteConverted(source metav1.ObjectMeta, vmObject runtime.Object, kind string) {
+	l := log.WithValues("kind", kind, "name", source.Name, "ns", source.Namespace)
+	ctx := context.Background()
+	err := c.vclient.Get(ctx, types.NamespacedName{Name: source.Name, Namespace: source.Namespace}, vmObject)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return
+		}
+		l.Error(err, "cannot get converted object for deletion")
+		return
+	}
+	vmMeta, err := meta.Accessor(vmObject)
+	if err != nil {
+		l.Error(err, "cannot get object meta")
+		return
+	}
+	if vmMeta.GetAnnotations()[IgnoreConversionLabel] == IgnoreConversion {
+		l.Info("syncing for object was disabled by annotation", "annotation", IgnoreConversionLabel)
+		return
+	}
+	if !isConvertedFrom(vmMeta, source.UID) {
+		l.Info("object wasnt converted from deleted prometheus object, skipping it")
+		return
+	}
+	err = c.vclient.Delete(ctx, vmObject)
+	if err != nil && !errors.IsNotFound(err) {
+		l.Error(err, "cannot delete converted object")
+		return
+	}
+	l.Info("converted object was deleted")
+}
+
+// recordConversionError reports failed conversion as warning event for prometheus object.
+func (c *ConverterController) recordConversionError(source runtime.Object, kind string, err error) {
+	c.recorder.Eventf(source, corev1.EventTypeWarning, ConversionFailedReason, "cannot sync %s: %v", kind, err)
+}
+
+// unwrapDeleted returns deleted object from informer tombstone.
+func unwrapDeleted(obj interface{}) interface{} {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return tombstone.Obj
+	}
+	return obj
+}
@@ -1,8 +1,23 @@
 package controllers
 
 import (
+	"context"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/VictoriaMetrics/operator/api/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	v1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_mergeLabelsWithStrategy(t *testing.T) {
@@ -79,3 +94,309 @@ func Test_mergeLabelsWithStrategy(t *testing.T) {
 		})
 	}
 }
+
+func testConverterScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = v1beta1.AddToScheme(s)
+	return s
+}
+
+func TestConverterController_DeleteServiceMonitor(t *testing.T) {
+	serviceMon := &v1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-scrape", Namespace: "default", UID: "source-uid"},
+	}
+	tests := []struct {
+		name        string
+		deleted     interface{}
+		predefined  *v1beta1.VMServiceScrape
+		wantDeleted bool
+	}{
+		{
+			name:    "delete converted object",
+			deleted: serviceMon,
+			predefined: &v1beta1.VMServiceScrape{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-scrape", Namespace: "default",
+				Annotations: map[string]string{PrometheusSourceUIDAnnotation: "source-uid"},
+			}},
+			wantDeleted: true,
+		},
+		{
+			name:    "delete converted object from tombstone",
+			deleted: cache.DeletedFinalStateUnknown{Key: "default/test-scrape", Obj: serviceMon},
+			predefined: &v1beta1.VMServiceScrape{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-scrape", Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{Kind: v1.ServiceMonitorsKind, Name: "test-scrape", UID: "source-uid"}},
+			}},
+			wantDeleted: true,
+		},
+		{
+			name:    "keep object with disabled updates",
+			deleted: serviceMon,
+			predefined: &v1beta1.VMServiceScrape{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-scrape", Namespace: "default",
+				Annotations: map[string]string{PrometheusSourceUIDAnnotation: "source-uid", IgnoreConversionLabel: IgnoreConversion},
+			}},
+			wantDeleted: false,
+		},
+		{
+			name:    "keep object converted from another prometheus object",
+			deleted: serviceMon,
+			predefined: &v1beta1.VMServiceScrape{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-scrape", Namespace: "default",
+				Annotations: map[string]string{PrometheusSourceUIDAnnotation: "another-uid"},
+			}},
+			wantDeleted: false,
+		},
+		{
+			name:    "keep manually created object",
+			deleted: serviceMon,
+			predefined: &v1beta1.VMServiceScrape{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-scrape", Namespace: "default",
+			}},
+			wantDeleted: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fclient := fake.NewFakeClientWithScheme(testConverterScheme(), tt.predefined)
+			c := &ConverterController{vclient: fclient, recorder: record.NewFakeRecorder(10), baseConf: &config.BaseOperatorConf{}}
+			c.DeleteServiceMonitor(tt.deleted)
+
+			err := fclient.Get(context.TODO(), types.NamespacedName{Name: "test-scrape", Namespace: "default"}, &v1beta1.VMServiceScrape{})
+			if tt.wantDeleted && !errors.IsNotFound(err) {
+				t.Fatalf("expected VMServiceScrape to be deleted, got err: %v", err)
+			}
+			if !tt.wantDeleted && err != nil {
+				t.Fatalf("expected VMServiceScrape to be kept, got err: %v", err)
+			}
+		})
+	}
+}
+
+func TestConverterController_UpdatePrometheusRule(t *testing.T) {
+	promRule := &v1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-rule", Namespace: "default", UID: "source-uid",
+			Annotations: map[string]string{"key": "value"},
+		},
+		Spec: v1.PrometheusRuleSpec{Groups: []v1.RuleGroup{{Name: "group", Rules: []v1.Rule{{Record: "up:sum"}}}}},
+	}
+	tests := []struct {
+		name            string
+		ownerReferences bool
+		predefined      []runtime.Object
+		wantAnnotations map[string]string
+		wantOwners      int
+	}{
+		{
+			name:            "create missing VMRule",
+			wantAnnotations: map[string]string{"key": "value", PrometheusSourceUIDAnnotation: "source-uid"},
+		},
+		{
+			name:            "create missing VMRule with owner reference",
+			ownerReferences: true,
+			wantAnnotations: map[string]string{"key": "value", PrometheusSourceUIDAnnotation: "source-uid"},
+			wantOwners:      1,
+		},
+		{
+			name:            "update existing VMRule with owner reference",
+			ownerReferences: true,
+			predefined: []runtime.Object{&v1beta1.VMRule{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-rule", Namespace: "default",
+				Annotations: map[string]string{"manual": "value", PrometheusSourceUIDAnnotation: "source-uid"},
+			}}},
+			wantAnnotations: map[string]string{"key": "value", PrometheusSourceUIDAnnotation: "source-uid"},
+			wantOwners:      1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fclient := fake.NewFakeClientWithScheme(testConverterScheme(), tt.predefined...)
+			baseConf := &config.BaseOperatorConf{EnabledPrometheusConverterOwnerReferences: tt.ownerReferences}
+			c := &ConverterController{vclient: fclient, recorder: record.NewFakeRecorder(10), baseConf: baseConf}
+			c.UpdatePrometheusRule(nil, promRule)
+
+			got := &v1beta1.VMRule{}
+			if err := fclient.Get(context.TODO(), types.NamespacedName{Name: "test-rule", Namespace: "default"}, got); err != nil {
+				t.Fatalf("cannot get VMRule: %v", err)
+			}
+			if !reflect.DeepEqual(got.Annotations, tt.wantAnnotations) {
+				t.Errorf("unexpected annotations, got: %v, want: %v", got.Annotations, tt.wantAnnotations)
+			}
+			if len(got.OwnerReferences) != tt.wantOwners {
+				t.Errorf("unexpected owner references count, got: %d, want: %d", len(got.OwnerReferences), tt.wantOwners)
+			}
+			if len(got.Spec.Groups) != 1 {
+				t.Errorf("expected spec to be converted, got: %v", got.Spec)
+			}
+			if _, ok := promRule.Annotations[PrometheusSourceUIDAnnotation]; ok {
+				t.Errorf("prometheus object annotations must not be modified")
+			}
+		})
+	}
+}
+
+func TestConverterController_CreatePodMonitorRecordsEvent(t *testing.T) {
+	podMon := &v1.PodMonitor{ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", UID: "source-uid"}}
+	recorder := record.NewFakeRecorder(10)
+	// fake client doesn't validate objects, so use scheme without VM types to get an error
+	c := &ConverterController{vclient: fake.NewFakeClientWithScheme(runtime.NewScheme()), recorder: recorder, baseConf: &config.BaseOperatorConf{}}
+	c.CreatePodMonitor(podMon)
+	select {
+	case event := <-recorder.Events:
+		if !strings.HasPrefix(event, "Warning "+ConversionFailedReason) {
+			t.Fatalf("unexpected event: %s", event)
+		}
+	default:
+		t.Fatalf("expected conversion error event")
+	}
+}
+
+func TestConverterController_CreateServiceMonitorExisting(t *testing.T) {
+	serviceMon := &v1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-scrape", Namespace: "default", UID: "source-uid"},
+		Spec:       v1.ServiceMonitorSpec{JobLabel: "converted"},
+	}
+	tests := []struct {
+		name        string
+		predefined  *v1beta1.VMServiceScrape
+		wantUpdated bool
+	}{
+		{
+			name: "update converted object",
+			predefined: &v1beta1.VMServiceScrape{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-scrape", Namespace: "default",
+				Annotations: map[string]string{PrometheusSourceUIDAnnotation: "source-uid"},
+			}},
+			wantUpdated: true,
+		},
+		{
+			name: "skip object converted from another prometheus object",
+			predefined: &v1beta1.VMServiceScrape{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-scrape", Namespace: "default",
+				Annotations: map[string]string{PrometheusSourceUIDAnnotation: "another-uid"},
+			}},
+			wantUpdated: false,
+		},
+		{
+			name: "skip manually created object",
+			predefined: &v1beta1.VMServiceScrape{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-scrape", Namespace: "default",
+			}},
+			wantUpdated: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fclient := fake.NewFakeClientWithScheme(testConverterScheme(), tt.predefined)
+			c := &ConverterController{vclient: fclient, recorder: record.NewFakeRecorder(10), baseConf: &config.BaseOperatorConf{}}
+			c.CreateServiceMonitor(serviceMon)
+
+			got := &v1beta1.VMServiceScrape{}
+			if err := fclient.Get(context.TODO(), types.NamespacedName{Name: "test-scrape", Namespace: "default"}, got); err != nil {
+				t.Fatalf("cannot get VMServiceScrape: %v", err)
+			}
+			if updated := got.Spec.JobLabel == "converted"; updated != tt.wantUpdated {
+				t.Errorf("unexpected update result, got: %v, want: %v", updated, tt.wantUpdated)
+			}
+			if !tt.wantUpdated && !reflect.DeepEqual(got.Annotations, tt.predefined.Annotations) {
+				t.Errorf("annotations of skipped object must not be modified, got: %v", got.Annotations)
+			}
+		})
+	}
+}
+
+func TestConverterController_UpdateExisting(t *testing.T) {
+	source := metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "source-uid"}
+	kinds := []struct {
+		name   string
+		object func(meta metav1.ObjectMeta) runtime.Object
+		update func(c *ConverterController)
+		delete func(c *ConverterController)
+	}{
+		{
+			name:   "VMRule",
+			object: func(meta metav1.ObjectMeta) runtime.Object { return &v1beta1.VMRule{ObjectMeta: meta} },
+			update: func(c *ConverterController) { c.UpdatePrometheusRule(nil, &v1.PrometheusRule{ObjectMeta: source}) },
+			delete: func(c *ConverterController) { c.DeletePrometheusRule(&v1.PrometheusRule{ObjectMeta: source}) },
+		},
+		{
+			name:   "VMServiceScrape",
+			object: func(meta metav1.ObjectMeta) runtime.Object { return &v1beta1.VMServiceScrape{ObjectMeta: meta} },
+			update: func(c *ConverterController) { c.UpdateServiceMonitor(nil, &v1.ServiceMonitor{ObjectMeta: source}) },
+			delete: func(c *ConverterController) { c.DeleteServiceMonitor(&v1.ServiceMonitor{ObjectMeta: source}) },
+		},
+		{
+			name:   "VMPodScrape",
+			object: func(meta metav1.ObjectMeta) runtime.Object { return &v1beta1.VMPodScrape{ObjectMeta: meta} },
+			update: func(c *ConverterController) { c.UpdatePodMonitor(nil, &v1.PodMonitor{ObjectMeta: source}) },
+			delete: func(c *ConverterController) { c.DeletePodMonitor(&v1.PodMonitor{ObjectMeta: source}) },
+		},
+		{
+			name:   "VMProbe",
+			object: func(meta metav1.ObjectMeta) runtime.Object { return &v1beta1.VMProbe{ObjectMeta: meta} },
+			update: func(c *ConverterController) { c.UpdateProbe(nil, &v1.Probe{ObjectMeta: source}) },
+			delete: func(c *ConverterController) { c.DeleteProbe(&v1.Probe{ObjectMeta: source}) },
+		},
+	}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantUpdated bool
+	}{
+		{
+			name:        "update converted object",
+			annotations: map[string]string{PrometheusSourceUIDAnnotation: "source-uid"},
+			wantUpdated: true,
+		},
+		{
+			name:        "skip object converted from another prometheus object",
+			annotations: map[string]string{PrometheusSourceUIDAnnotation: "another-uid"},
+		},
+		{
+			name:        "skip manually created object",
+			annotations: map[string]string{"manual": "value"},
+		},
+	}
+	for _, kind := range kinds {
+		for _, tt := range tests {
+			t.Run(kind.name+" "+tt.name, func(t *testing.T) {
+				predefined := kind.object(metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: tt.annotations})
+				fclient := fake.NewFakeClientWithScheme(testConverterScheme(), predefined)
+				baseConf := &config.BaseOperatorConf{EnabledPrometheusConverterOwnerReferences: true}
+				c := &ConverterController{vclient: fclient, recorder: record.NewFakeRecorder(10), baseConf: baseConf}
+				key := types.NamespacedName{Name: "test", Namespace: "default"}
+				before := kind.object(metav1.ObjectMeta{})
+				if err := fclient.Get(context.TODO(), key, before); err != nil {
+					t.Fatalf("cannot get %s: %v", kind.name, err)
+				}
+
+				kind.update(c)
+				got := kind.object(metav1.ObjectMeta{})
+				if err := fclient.Get(context.TODO(), key, got); err != nil {
+					t.Fatalf("cannot get %s: %v", kind.name, err)
+				}
+				gotMeta, err := meta.Accessor(got)
+				if err != nil {
+					t.Fatalf("cannot get object meta: %v", err)
+				}
+				if tt.wantUpdated {
+					if len(gotMeta.GetOwnerReferences()) != 1 {
+						t.Errorf("expected owner reference to be added, got: %v", gotMeta.GetOwnerReferences())
+					}
+					return
+				}
+				if !reflect.DeepEqual(before, got) {
+					t.Fatalf("object not converted from the source must not be modified, got: %v, want: %v", got, before)
+				}
+
+				// object must be kept, when the source with the same name is deleted
+				kind.delete(c)
+				if err := fclient.Get(context.TODO(), key, kind.object(metav1.ObjectMeta{})); err != nil {
+					t.Fatalf("expected %s to be kept, got err: %v", kind.name, err)
+				}
+			})
+		}
+	}
+}
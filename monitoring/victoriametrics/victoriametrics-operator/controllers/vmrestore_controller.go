@@ -0,0 +1,112 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/VictoriaMetrics/operator/controllers/factory"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	victoriametricsv1beta1 "github.com/VictoriaMetrics/operator/api/v1beta1"
+)
+
+// restoreProgressCheckInterval - how often pods of restore target are checked
+const restoreProgressCheckInterval = 10 * time.Second
+
+// VMRestoreReconciler reconciles a VMRestore object
+type VMRestoreReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	BaseConf *config.BaseOperatorConf
+}
+
+// Reconcile - starts restore and tracks its progress,
+// vmrestore init container is added to target pods by VMSingle and VMCluster controllers
+// +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmrestores/status,verbs=get;update;patch
+func (r *VMRestoreReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("vmrestore", req.NamespacedName)
+	reqLogger.Info("Reconciling vmrestore")
+
+	ctx := context.Background()
+	instance := &victoriametricsv1beta1.VMRestore{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if instance.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	prevStatus := instance.Status.DeepCopy()
+	if instance.IsActive() {
+		err = factory.CheckVMRestoreProgress(ctx, instance, r, r.BaseConf, time.Now())
+	} else {
+		err = factory.StartVMRestore(ctx, instance, r, r.BaseConf, time.Now())
+	}
+	if err != nil {
+		reqLogger.Error(err, "cannot reconcile vmrestore")
+		return ctrl.Result{}, err
+	}
+	if !equality.Semantic.DeepEqual(prevStatus, &instance.Status) {
+		if err := r.Status().Update(ctx, instance); err != nil {
+			reqLogger.Error(err, "cannot update vmrestore status")
+			return ctrl.Result{}, err
+		}
+		reqLogger.Info("vmrestore status was updated", "phase", instance.Status.Phase, "reason", instance.Status.Reason)
+	}
+	if instance.IsActive() {
+		return ctrl.Result{RequeueAfter: restoreProgressCheckInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager general setup method
+func (r *VMRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&victoriametricsv1beta1.VMRestore{}).
+		Complete(r)
+}
+
+// restoreTargetRequests maps VMRestore into reconcile request for its target of given kind,
+// so target is restarted with vmrestore init container and restarted without it after restore finish.
+func restoreTargetRequests(kind string) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			restore, ok := o.Object.(*victoriametricsv1beta1.VMRestore)
+			if !ok || restore.Spec.Target.Kind != kind {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.Target.Name}}}
+		}),
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	victoriametricsv1beta1 "github.com/VictoriaMetrics/operator/api/v1beta1"
 )
@@ -95,5 +96,6 @@ func (r *VMSingleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&victoriametricsv1beta1.VMSingle{}).
 		Owns(&appsv1.Deployment{}).
+		Watches(&source.Kind{Type: &victoriametricsv1beta1.VMRestore{}}, restoreTargetRequests(victoriametricsv1beta1.BackupTargetVMSingle)).
 		Complete(r)
 }
@@ -148,6 +148,14 @@ type BaseOperatorConf struct {
 		LogLevel  string `default:"INFO"`
 		LogFormat string
 	}
+	// images for jobs, created by VMBackupSchedule and VMRestore
+	VMUtilsDefault struct {
+		BackupImage    string `default:"victoriametrics/vmbackup"`
+		RestoreImage   string `default:"victoriametrics/vmrestore"`
+		Version        string `default:"v1.40.0"`
+		FSCleanupImage string `default:"busybox:1.32"`
+		S3CleanupImage string `default:"amazon/aws-cli:2.0.43"`
+	}
 
 	EnabledPrometheusConverter struct {
 		PodMonitor     bool `default:"true"`
@@ -155,6 +163,10 @@ type BaseOperatorConf struct {
 		PrometheusRule bool `default:"true"`
 		Probe          bool `default:"true"`
 	}
+	// adds owner references to converted VMObjects,
+	// kubernetes removes them with corresponding prometheus objects
+	EnabledPrometheusConverterOwnerReferences bool `default:"false"`
+
 	Host                      string `default:"0.0.0.0"`
 	ListenAddress             string `default:"0.0.0.0"`
 	DefaultLabels             string `default:"managed-by=vm-operator"`
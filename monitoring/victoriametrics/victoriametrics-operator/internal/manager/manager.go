@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"github.com/VictoriaMetrics/operator/internal/config"
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/coreos/prometheus-operator/pkg/client/versioned"
 	"github.com/spf13/pflag"
 	"golang.org/x/sync/errgroup"
@@ -29,6 +30,8 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(victoriametricsv1beta1.AddToScheme(scheme))
+	// prometheus api objects must be known for events, produced by converter
+	utilruntime.Must(monitoringv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -155,6 +158,24 @@ func RunManager(ctx context.Context) error {
 		setupLog.Error(err, "unable to create controller", "controller", "VMProbe")
 		return err
 	}
+	if err = (&controllers.VMBackupScheduleReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("VMBackupSchedule"),
+		Scheme:   mgr.GetScheme(),
+		BaseConf: config.MustGetBaseConfig(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VMBackupSchedule")
+		return err
+	}
+	if err = (&controllers.VMRestoreReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("VMRestore"),
+		Scheme:   mgr.GetScheme(),
+		BaseConf: config.MustGetBaseConfig(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VMRestore")
+		return err
+	}
 
 	// +kubebuilder:scaffold:builder
 	setupLog.Info("starting vmconverter clients")
@@ -164,7 +185,7 @@ func RunManager(ctx context.Context) error {
 		setupLog.Error(err, "cannot build promClient")
 		return err
 	}
-	converterController := controllers.NewConverterController(prom, mgr.GetClient())
+	converterController := controllers.NewConverterController(prom, mgr.GetClient(), mgr.GetEventRecorderFor("vm-converter"), config.MustGetBaseConfig())
 
 	errG := &errgroup.Group{}
 	converterController.Run(ctx, errG, config.MustGetBaseConfig())